@@ -25,10 +25,17 @@ func (s *Server) routes() *chi.Mux {
 
 	// api v1 routes
 	mux.Route("/api/v1", func(r chi.Router) {
-		r.Get("/health", healthCheck)
+		r.Get("/health", s.healthCheck)
 		s.AuthRoutes(r)
 		s.UserRoutes(r)
 		s.ProductRoutes(r)
+		s.WebhookRoutes(r)
+		s.OrderRoutes(r)
+		s.InvoiceRoutes(r)
+		s.SecondChanceRoutes(r)
+		s.OfferRoutes(r)
+		s.MessageRoutes(r)
+		s.AdminRoutes(r)
 	})
 
 	return mux
@@ -45,13 +52,39 @@ func (s *Server) AuthRoutes(router chi.Router) {
 	})
 }
 
-// UserRoutes registers user endpoints (protected)
+// UserRoutes registers user endpoints (protected, except for public profiles and reputations)
 func (s *Server) UserRoutes(router chi.Router) {
 	userHandler := s.Dependencies.UserHandler
-	router.Group(func(r chi.Router) {
-		r.Use(middleware.AuthMiddleware(s.Dependencies.Services.AuthService))
-		r.Route("/users", func(r chi.Router) {
+	accessHandler := s.Dependencies.AccessHandler
+	walletHandler := s.Dependencies.WalletHandler
+	invoiceHandler := s.Dependencies.InvoiceHandler
+	addressHandler := s.Dependencies.AddressHandler
+	feedbackHandler := s.Dependencies.FeedbackHandler
+	router.Route("/users", func(r chi.Router) {
+		// Not protected routes
+		r.Get("/{userId}/reputation", feedbackHandler.GetReputation)
+		r.Get("/{userId}/feedback", feedbackHandler.ListFeedback)
+		r.Get("/{username}", userHandler.PublicProfile)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(s.Dependencies.Services.AuthService))
 			r.Get("/me", userHandler.Profile)
+			r.Patch("/me", userHandler.UpdateProfile)
+			r.Post("/me/email", userHandler.ChangeEmail)
+			r.Post("/me/email/verify", userHandler.VerifyEmail)
+			r.Post("/me/avatar", userHandler.UploadAvatar)
+			r.Post("/me/storefront/banner", userHandler.UploadBanner)
+			r.Put("/me/storefront/featured", userHandler.SetFeaturedListings)
+			r.Get("/me/blocked-bidders", accessHandler.ListBlockedBidders)
+			r.Post("/me/blocked-bidders", accessHandler.BlockBidder)
+			r.Delete("/me/blocked-bidders/{bidderId}", accessHandler.UnblockBidder)
+			r.Get("/me/wallet", walletHandler.GetWallet)
+			r.Post("/me/wallet/deposits", walletHandler.Deposit)
+			r.Post("/me/wallet/withdrawals", walletHandler.Withdraw)
+			r.Get("/me/payouts", invoiceHandler.ListPayouts)
+			r.Get("/me/addresses", addressHandler.ListAddresses)
+			r.Post("/me/addresses", addressHandler.CreateAddress)
+			r.Put("/me/addresses/{addressId}", addressHandler.UpdateAddress)
+			r.Delete("/me/addresses/{addressId}", addressHandler.DeleteAddress)
 		})
 	})
 }
@@ -59,33 +92,189 @@ func (s *Server) UserRoutes(router chi.Router) {
 // ProductRoutes registers product endpoints (protected)
 func (s *Server) ProductRoutes(router chi.Router) {
 	var productHandler = s.Dependencies.ProductHandler
+	var secondChanceHandler = s.Dependencies.SecondChanceHandler
+	var offerHandler = s.Dependencies.OfferHandler
+	var accessHandler = s.Dependencies.AccessHandler
+	var messageHandler = s.Dependencies.MessageHandler
 		// Not protected routes
 		router.Route("/products", func(r chi.Router) {
-			r.Get("/images", productHandler.GetProductImageUrls)
-			r.Get("/{productId}", productHandler.GetProductByID)
+			// Private listings are only shown to their seller and invited users
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.OptionalAuthMiddleware(s.Dependencies.Services.AuthService))
+				r.Get("/images", productHandler.GetProductImageUrls)
+				r.Get("/{productId}", productHandler.GetProductByID)
+				r.Get("/{productId}/live", productHandler.LiveFeed)
+				r.Get("/{productId}/bids", productHandler.GetProductBids)
+			})
 			r.Group(func(r chi.Router) {
 				r.Use(middleware.AuthMiddleware(s.Dependencies.Services.AuthService))
 				r.Post("/upload-images", productHandler.UploadImages)
 				r.Post("/", productHandler.CreateProduct)
 				r.Patch("/{productId}/bid", productHandler.PlaceBid)
+				r.Post("/{productId}/buy", productHandler.BuyNow)
+				r.Post("/{productId}/accept", productHandler.AcceptPrice)
+				r.Post("/{productId}/bids/{bidId}/retract", productHandler.RetractBid)
+				r.Post("/{productId}/bidders/{bidderId}/cancel-bids", productHandler.CancelBidderBids)
+				r.Patch("/{productId}", productHandler.UpdateProduct)
+				r.Post("/{productId}/publish", productHandler.PublishProduct)
+				r.Post("/{productId}/schedule", productHandler.ScheduleProduct)
+				r.Post("/{productId}/relist", productHandler.RelistProduct)
+				r.Post("/{productId}/second-chance", secondChanceHandler.CreateSecondChanceOffer)
+				r.Get("/{productId}/second-chance", secondChanceHandler.ListProductSecondChanceOffers)
+				r.Post("/{productId}/offers", offerHandler.MakeOffer)
+				r.Get("/{productId}/offers", offerHandler.ListProductOffers)
+				r.Post("/{productId}/invitations", accessHandler.InviteUser)
+				r.Get("/{productId}/invitations", accessHandler.ListInvitations)
+				r.Delete("/{productId}/invitations/{userId}", accessHandler.RemoveInvitation)
+				r.Get("/{productId}/access-code", accessHandler.GetAccessCode)
+				r.Post("/{productId}/access-code", accessHandler.RotateAccessCode)
+				r.Delete("/{productId}/access-code", accessHandler.RevokeAccessCode)
+				r.Post("/{productId}/access", accessHandler.RedeemAccessCode)
+				r.Post("/{productId}/conversations", messageHandler.StartProductConversation)
 				r.Get("/seller/{sellerId}", productHandler.ProductsBySellerID)
 			})
 		})
 }
 
+// WebhookRoutes registers seller webhook endpoints (protected)
+func (s *Server) WebhookRoutes(router chi.Router) {
+	webhookHandler := s.Dependencies.WebhookHandler
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware(s.Dependencies.Services.AuthService))
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Post("/", webhookHandler.CreateWebhook)
+			r.Get("/", webhookHandler.ListWebhooks)
+			r.Delete("/{webhookId}", webhookHandler.DeleteWebhook)
+			r.Get("/{webhookId}/deliveries", webhookHandler.ListDeliveries)
+			r.Get("/{webhookId}/deliveries/{deliveryId}", webhookHandler.GetDelivery)
+			r.Post("/{webhookId}/deliveries/{deliveryId}/redeliver", webhookHandler.RedeliverDelivery)
+		})
+	})
+}
+
+// OrderRoutes registers order, feedback and order conversation endpoints for buyers and sellers (protected)
+func (s *Server) OrderRoutes(router chi.Router) {
+	orderHandler := s.Dependencies.OrderHandler
+	feedbackHandler := s.Dependencies.FeedbackHandler
+	messageHandler := s.Dependencies.MessageHandler
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware(s.Dependencies.Services.AuthService))
+		r.Route("/orders", func(r chi.Router) {
+			r.Get("/", orderHandler.ListOrders)
+			r.Get("/{orderId}", orderHandler.GetOrder)
+			r.Put("/{orderId}/shipping", orderHandler.SetOrderShipping)
+			r.Post("/{orderId}/ship", orderHandler.ShipOrder)
+			r.Post("/{orderId}/deliver", orderHandler.ConfirmDelivery)
+			r.Post("/{orderId}/feedback", feedbackHandler.LeaveFeedback)
+			r.Post("/{orderId}/conversation", messageHandler.StartOrderConversation)
+		})
+		r.Post("/feedback/{feedbackId}/reply", feedbackHandler.ReplyToFeedback)
+	})
+}
+
+// InvoiceRoutes registers invoice and payment endpoints for buyers and sellers (protected)
+func (s *Server) InvoiceRoutes(router chi.Router) {
+	invoiceHandler := s.Dependencies.InvoiceHandler
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware(s.Dependencies.Services.AuthService))
+		r.Route("/invoices", func(r chi.Router) {
+			r.Get("/", invoiceHandler.ListInvoices)
+			r.Get("/{invoiceId}", invoiceHandler.GetInvoice)
+			r.Post("/{invoiceId}/pay", invoiceHandler.PayInvoice)
+		})
+	})
+}
+
+// SecondChanceRoutes registers the bidder side of second-chance offers (protected)
+func (s *Server) SecondChanceRoutes(router chi.Router) {
+	secondChanceHandler := s.Dependencies.SecondChanceHandler
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware(s.Dependencies.Services.AuthService))
+		r.Route("/second-chance-offers", func(r chi.Router) {
+			r.Get("/", secondChanceHandler.ListMySecondChanceOffers)
+			r.Post("/{offerId}/accept", secondChanceHandler.AcceptSecondChanceOffer)
+			r.Post("/{offerId}/decline", secondChanceHandler.DeclineSecondChanceOffer)
+		})
+	})
+}
+
+// OfferRoutes registers the negotiation endpoints of fixed-price offers for both parties (protected)
+func (s *Server) OfferRoutes(router chi.Router) {
+	offerHandler := s.Dependencies.OfferHandler
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware(s.Dependencies.Services.AuthService))
+		r.Route("/offers", func(r chi.Router) {
+			r.Get("/", offerHandler.ListOffers)
+			r.Post("/{offerId}/counter", offerHandler.CounterOffer)
+			r.Post("/{offerId}/accept", offerHandler.AcceptOffer)
+			r.Post("/{offerId}/decline", offerHandler.DeclineOffer)
+		})
+	})
+}
+
+// MessageRoutes registers the conversations of buyers and sellers (protected)
+func (s *Server) MessageRoutes(router chi.Router) {
+	messageHandler := s.Dependencies.MessageHandler
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware(s.Dependencies.Services.AuthService))
+		r.Route("/conversations", func(r chi.Router) {
+			r.Get("/", messageHandler.ListConversations)
+			r.Get("/{conversationId}/messages", messageHandler.ListMessages)
+			r.Post("/{conversationId}/messages", messageHandler.SendMessage)
+			r.Post("/{conversationId}/attachments", messageHandler.UploadAttachment)
+			r.Post("/{conversationId}/read", messageHandler.MarkRead)
+			r.Get("/{conversationId}/live", messageHandler.LiveFeed)
+		})
+	})
+}
+
+// AdminRoutes registers admin endpoints (protected, admin only)
+func (s *Server) AdminRoutes(router chi.Router) {
+	adminHandler := s.Dependencies.AdminHandler
+	messageHandler := s.Dependencies.MessageHandler
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware(s.Dependencies.Services.AuthService))
+		r.Use(middleware.AdminMiddleware(s.Dependencies.Services.AdminService))
+		r.Route("/admin/jobs", func(r chi.Router) {
+			r.Get("/", adminHandler.ListJobs)
+			r.Get("/dead-letter", adminHandler.ListDeadLetterJobs)
+			r.Get("/{jobId}", adminHandler.GetJob)
+			r.Post("/{jobId}/retry", adminHandler.RetryJob)
+			r.Post("/{jobId}/cancel", adminHandler.CancelJob)
+		})
+		r.Route("/admin/bid-increments", func(r chi.Router) {
+			r.Get("/", adminHandler.ListBidIncrements)
+			r.Put("/platform", adminHandler.SetPlatformBidIncrements)
+			r.Put("/categories/{category}", adminHandler.SetCategoryBidIncrements)
+		})
+		r.Route("/admin/fees", func(r chi.Router) {
+			r.Get("/", adminHandler.ListFees)
+			r.Get("/revenue", adminHandler.ExportFeeRevenue)
+			r.Put("/{kind}/platform", adminHandler.SetPlatformFees)
+			r.Put("/{kind}/categories/{category}", adminHandler.SetCategoryFees)
+		})
+		r.Route("/admin/messages", func(r chi.Router) {
+			r.Get("/held", messageHandler.ListHeldMessages)
+			r.Post("/{messageId}/approve", messageHandler.ApproveMessage)
+			r.Post("/{messageId}/remove", messageHandler.RemoveMessage)
+		})
+	})
+}
+
 // Healthcheck godoc
 // @Summary      Health Check
-// @Description  Check if the server is running
+// @Description  Check if the server is running and whether this replica runs the singleton workers
 // @Tags         Health
 // @Accept       json
 // @Produce      json
 // @Success      200  {object}  map[string]interface{}
 // @Router       /api/v1/health [get]
-func healthCheck(w http.ResponseWriter, r *http.Request) {
+func (s *Server) healthCheck(w http.ResponseWriter, r *http.Request) {
 
 	resp := map[string]any{
 		"message": "ok",
 		"time":    time.Now().Format(time.RFC3339),
+		"leader":  s.Dependencies.Elector.Status(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -7,10 +7,12 @@ import (
 	"log/slog"
 	"net/http"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/itsDrac/e-auc/internal/dependency"
+	"github.com/itsDrac/e-auc/internal/service"
 
 	"github.com/itsDrac/e-auc/pkg/utils"
 	_ "github.com/joho/godotenv/autoload"
@@ -58,6 +60,18 @@ func (s *Server) Run() error {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	// Start background workers, they stop once the worker context is cancelled
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+	for _, worker := range s.Dependencies.Workers {
+		workers.Add(1)
+		go func(w service.Worker) {
+			defer workers.Done()
+			w.Run(workerCtx)
+		}(worker)
+	}
+
 	// Run Server in the background
 	go func() {
 		if err := s.HTTPServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -79,6 +93,10 @@ func (s *Server) Run() error {
 		return err
 	}
 
+	// Stop background workers and wait for in-flight work to finish
+	stopWorkers()
+	workers.Wait()
+
 	// close cache
 	if err := s.Dependencies.Cache.Close(); err != nil {
 		slog.Error("[Cache] close failed ->", "error", err.Error())
@@ -86,10 +104,7 @@ func (s *Server) Run() error {
 	}
 
 	// close db
-	if err := s.Dependencies.Conn.Close(shutCtx); err != nil {
-		slog.Error("[DB] close failed -> ", "error", err.Error())
-		return err
-	}
+	s.Dependencies.Conn.Close()
 
 	slog.Info("[SERVER] shutdown complete.")
 	return nil
@@ -15,8 +15,15 @@ var (
 
 const (
 	TempImageListKey = "temp_image_names"
+	// EventChannelPrefix prefixes the pub/sub channel that carries the domain events of one aggregate.
+	EventChannelPrefix = "events:"
 )
 
+// EventChannel returns the pub/sub channel for the events of the given aggregate (e.g. a product).
+func EventChannel(aggregateID string) string {
+	return EventChannelPrefix + aggregateID
+}
+
 type Cacher interface {
 	Get(ctx context.Context, key string) (string, bool, error)
 	Set(ctx context.Context, key, val string, ttl time.Duration) error
@@ -25,8 +32,27 @@ type Cacher interface {
 	Close() error
 	AddImageNameToTempList(ctx context.Context, imageName string) error
 	RemoveImageNameFromTempList(ctx context.Context, imageName string) error
+	Publish(ctx context.Context, channel, message string) error
+	Subscribe(ctx context.Context, channel string) (<-chan string, func() error, error)
+	AcquireLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	RenewLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, key, owner string) error
 }
 
+// Lease scripts only touch the key while it is still held by the given owner.
+var (
+	renewLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
 type RedisCache struct {
 	client *redis.Client
 }
@@ -102,4 +128,56 @@ func (r *RedisCache) AddImageNameToTempList(ctx context.Context, imageName strin
 
 func (r *RedisCache) RemoveImageNameFromTempList(ctx context.Context, imageName string) error {
 	return r.client.LRem(ctx, TempImageListKey, 0, imageName).Err()
-}
\ No newline at end of file
+}
+
+func (r *RedisCache) Publish(ctx context.Context, channel, message string) error {
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe returns the messages published on channel until ctx is done or the returned close func is called.
+func (r *RedisCache) Subscribe(ctx context.Context, channel string) (<-chan string, func() error, error) {
+	sub := r.client.Subscribe(ctx, channel)
+	// Wait for the subscription to be confirmed so no message published afterwards is missed
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return nil, nil, err
+	}
+
+	messages := make(chan string)
+	go func() {
+		defer close(messages)
+		for msg := range sub.Channel() {
+			select {
+			case messages <- msg.Payload:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return messages, sub.Close, nil
+}
+
+// AcquireLease sets key to owner for ttl unless another owner already holds it.
+func (r *RedisCache) AcquireLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		return false, ErrInvalidTTL
+	}
+	return r.client.SetNX(ctx, key, owner, ttl).Result()
+}
+
+// RenewLease extends the lease, it returns false when owner no longer holds it.
+func (r *RedisCache) RenewLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		return false, ErrInvalidTTL
+	}
+	renewed, err := renewLeaseScript.Run(ctx, r.client, []string{key}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return renewed == 1, nil
+}
+
+// ReleaseLease deletes the lease if owner still holds it.
+func (r *RedisCache) ReleaseLease(ctx context.Context, key, owner string) error {
+	return releaseLeaseScript.Run(ctx, r.client, []string{key}, owner).Err()
+}
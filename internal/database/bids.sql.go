@@ -28,17 +28,19 @@ INSERT INTO bids (
     product_id,
     user_id,
     price,
-    comments
+    comments,
+    quantity
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5
 )
 `
 
 type CreateBidParams struct {
 	ProductID uuid.UUID `json:"product_id"`
 	UserID    uuid.UUID `json:"user_id"`
-	Price     int32     `json:"price"`
+	Price     int64     `json:"price"`
 	Comments  *string   `json:"comments"`
+	Quantity  int32     `json:"quantity"`
 }
 
 func (q *Queries) CreateBid(ctx context.Context, arg CreateBidParams) error {
@@ -47,6 +49,7 @@ func (q *Queries) CreateBid(ctx context.Context, arg CreateBidParams) error {
 		arg.UserID,
 		arg.Price,
 		arg.Comments,
+		arg.Quantity,
 	)
 	return err
 }
@@ -61,8 +64,63 @@ func (q *Queries) DeleteBid(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const getBidByID = `-- name: GetBidByID :one
+SELECT id, bid_at, product_id, user_id, price, is_valid, comments, quantity, retracted_at, retracted_by, retraction_reason FROM bids
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetBidByID(ctx context.Context, id uuid.UUID) (Bid, error) {
+	row := q.db.QueryRow(ctx, getBidByID, id)
+	var i Bid
+	err := row.Scan(
+		&i.ID,
+		&i.BidAt,
+		&i.ProductID,
+		&i.UserID,
+		&i.Price,
+		&i.IsValid,
+		&i.Comments,
+		&i.Quantity,
+		&i.RetractedAt,
+		&i.RetractedBy,
+		&i.RetractionReason,
+	)
+	return i, err
+}
+
+const getBidByProductAndUser = `-- name: GetBidByProductAndUser :one
+SELECT id, bid_at, product_id, user_id, price, is_valid, comments, quantity, retracted_at, retracted_by, retraction_reason FROM bids
+WHERE product_id = $1 AND user_id = $2 AND is_valid = true
+LIMIT 1
+`
+
+type GetBidByProductAndUserParams struct {
+	ProductID uuid.UUID `json:"product_id"`
+	UserID    uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetBidByProductAndUser(ctx context.Context, arg GetBidByProductAndUserParams) (Bid, error) {
+	row := q.db.QueryRow(ctx, getBidByProductAndUser, arg.ProductID, arg.UserID)
+	var i Bid
+	err := row.Scan(
+		&i.ID,
+		&i.BidAt,
+		&i.ProductID,
+		&i.UserID,
+		&i.Price,
+		&i.IsValid,
+		&i.Comments,
+		&i.Quantity,
+		&i.RetractedAt,
+		&i.RetractedBy,
+		&i.RetractionReason,
+	)
+	return i, err
+}
+
 const getBidsByProductID = `-- name: GetBidsByProductID :many
-SELECT id, bid_at, product_id, user_id, price, is_valid, comments FROM bids
+SELECT id, bid_at, product_id, user_id, price, is_valid, comments, quantity, retracted_at, retracted_by, retraction_reason FROM bids
 WHERE product_id = $1
 ORDER BY bid_at DESC
 `
@@ -84,6 +142,10 @@ func (q *Queries) GetBidsByProductID(ctx context.Context, productID uuid.UUID) (
 			&i.Price,
 			&i.IsValid,
 			&i.Comments,
+			&i.Quantity,
+			&i.RetractedAt,
+			&i.RetractedBy,
+			&i.RetractionReason,
 		); err != nil {
 			return nil, err
 		}
@@ -96,7 +158,7 @@ func (q *Queries) GetBidsByProductID(ctx context.Context, productID uuid.UUID) (
 }
 
 const getBidsByUserID = `-- name: GetBidsByUserID :many
-SELECT id, bid_at, product_id, user_id, price, is_valid, comments FROM bids
+SELECT id, bid_at, product_id, user_id, price, is_valid, comments, quantity, retracted_at, retracted_by, retraction_reason FROM bids
 WHERE user_id = $1
 ORDER BY bid_at DESC
 `
@@ -118,6 +180,10 @@ func (q *Queries) GetBidsByUserID(ctx context.Context, userID uuid.UUID) ([]Bid,
 			&i.Price,
 			&i.IsValid,
 			&i.Comments,
+			&i.Quantity,
+			&i.RetractedAt,
+			&i.RetractedBy,
+			&i.RetractionReason,
 		); err != nil {
 			return nil, err
 		}
@@ -130,7 +196,7 @@ func (q *Queries) GetBidsByUserID(ctx context.Context, userID uuid.UUID) ([]Bid,
 }
 
 const getLatestBidForProduct = `-- name: GetLatestBidForProduct :one
-SELECT id, bid_at, product_id, user_id, price, is_valid, comments FROM bids
+SELECT id, bid_at, product_id, user_id, price, is_valid, comments, quantity, retracted_at, retracted_by, retraction_reason FROM bids
 WHERE product_id = $1 AND is_valid = true
 ORDER BY bid_at DESC
 LIMIT 1
@@ -147,12 +213,16 @@ func (q *Queries) GetLatestBidForProduct(ctx context.Context, productID uuid.UUI
 		&i.Price,
 		&i.IsValid,
 		&i.Comments,
+		&i.Quantity,
+		&i.RetractedAt,
+		&i.RetractedBy,
+		&i.RetractionReason,
 	)
 	return i, err
 }
 
 const getValidBidsByProductID = `-- name: GetValidBidsByProductID :many
-SELECT id, bid_at, product_id, user_id, price, is_valid, comments FROM bids
+SELECT id, bid_at, product_id, user_id, price, is_valid, comments, quantity, retracted_at, retracted_by, retraction_reason FROM bids
 WHERE product_id = $1 AND is_valid = true
 ORDER BY bid_at DESC
 `
@@ -174,6 +244,10 @@ func (q *Queries) GetValidBidsByProductID(ctx context.Context, productID uuid.UU
 			&i.Price,
 			&i.IsValid,
 			&i.Comments,
+			&i.Quantity,
+			&i.RetractedAt,
+			&i.RetractedBy,
+			&i.RetractionReason,
 		); err != nil {
 			return nil, err
 		}
@@ -195,3 +269,130 @@ func (q *Queries) InvalidateBid(ctx context.Context, id uuid.UUID) error {
 	_, err := q.db.Exec(ctx, invalidateBid, id)
 	return err
 }
+
+const invalidateBidsByBidder = `-- name: InvalidateBidsByBidder :exec
+UPDATE bids
+SET is_valid = false
+WHERE product_id = $1 AND user_id = $2 AND is_valid = true
+`
+
+type InvalidateBidsByBidderParams struct {
+	ProductID uuid.UUID `json:"product_id"`
+	UserID    uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) InvalidateBidsByBidder(ctx context.Context, arg InvalidateBidsByBidderParams) error {
+	_, err := q.db.Exec(ctx, invalidateBidsByBidder, arg.ProductID, arg.UserID)
+	return err
+}
+
+const invalidateBidsForProduct = `-- name: InvalidateBidsForProduct :exec
+UPDATE bids
+SET is_valid = false
+WHERE product_id = $1 AND is_valid = true
+`
+
+func (q *Queries) InvalidateBidsForProduct(ctx context.Context, productID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, invalidateBidsForProduct, productID)
+	return err
+}
+
+const retractBid = `-- name: RetractBid :one
+UPDATE bids
+SET is_valid = false, retracted_at = NOW(), retracted_by = $2, retraction_reason = $3
+WHERE id = $1 AND is_valid = true
+RETURNING id, bid_at, product_id, user_id, price, is_valid, comments, quantity, retracted_at, retracted_by, retraction_reason
+`
+
+type RetractBidParams struct {
+	ID               uuid.UUID  `json:"id"`
+	RetractedBy      *uuid.UUID `json:"retracted_by"`
+	RetractionReason *string    `json:"retraction_reason"`
+}
+
+func (q *Queries) RetractBid(ctx context.Context, arg RetractBidParams) (Bid, error) {
+	row := q.db.QueryRow(ctx, retractBid, arg.ID, arg.RetractedBy, arg.RetractionReason)
+	var i Bid
+	err := row.Scan(
+		&i.ID,
+		&i.BidAt,
+		&i.ProductID,
+		&i.UserID,
+		&i.Price,
+		&i.IsValid,
+		&i.Comments,
+		&i.Quantity,
+		&i.RetractedAt,
+		&i.RetractedBy,
+		&i.RetractionReason,
+	)
+	return i, err
+}
+
+const retractBidsByBidder = `-- name: RetractBidsByBidder :many
+UPDATE bids
+SET is_valid = false, retracted_at = NOW(), retracted_by = $1, retraction_reason = $2
+WHERE product_id = $3 AND user_id = $4 AND is_valid = true
+RETURNING id, bid_at, product_id, user_id, price, is_valid, comments, quantity, retracted_at, retracted_by, retraction_reason
+`
+
+type RetractBidsByBidderParams struct {
+	RetractedBy      *uuid.UUID `json:"retracted_by"`
+	RetractionReason *string    `json:"retraction_reason"`
+	ProductID        uuid.UUID  `json:"product_id"`
+	UserID           uuid.UUID  `json:"user_id"`
+}
+
+func (q *Queries) RetractBidsByBidder(ctx context.Context, arg RetractBidsByBidderParams) ([]Bid, error) {
+	rows, err := q.db.Query(ctx, retractBidsByBidder,
+		arg.RetractedBy,
+		arg.RetractionReason,
+		arg.ProductID,
+		arg.UserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bid{}
+	for rows.Next() {
+		var i Bid
+		if err := rows.Scan(
+			&i.ID,
+			&i.BidAt,
+			&i.ProductID,
+			&i.UserID,
+			&i.Price,
+			&i.IsValid,
+			&i.Comments,
+			&i.Quantity,
+			&i.RetractedAt,
+			&i.RetractedBy,
+			&i.RetractionReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reviseBid = `-- name: ReviseBid :exec
+UPDATE bids
+SET price = $2, quantity = $3, bid_at = NOW()
+WHERE id = $1
+`
+
+type ReviseBidParams struct {
+	ID       uuid.UUID `json:"id"`
+	Price    int64     `json:"price"`
+	Quantity int32     `json:"quantity"`
+}
+
+func (q *Queries) ReviseBid(ctx context.Context, arg ReviseBidParams) error {
+	_, err := q.db.Exec(ctx, reviseBid, arg.ID, arg.Price, arg.Quantity)
+	return err
+}
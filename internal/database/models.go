@@ -10,36 +10,386 @@ import (
 	"github.com/google/uuid"
 )
 
+type Account struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    *uuid.UUID `json:"user_id"`
+	Kind      string     `json:"kind"`
+	CreatedAt time.Time  `json:"created_at"`
+	Currency  string     `json:"currency"`
+}
+
+type Address struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Name       string    `json:"name"`
+	Line1      string    `json:"line1"`
+	Line2      *string   `json:"line2"`
+	City       string    `json:"city"`
+	State      *string   `json:"state"`
+	PostalCode string    `json:"postal_code"`
+	Country    string    `json:"country"`
+	IsDefault  bool      `json:"is_default"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
 type Bid struct {
+	ID               uuid.UUID  `json:"id"`
+	BidAt            time.Time  `json:"bid_at"`
+	ProductID        uuid.UUID  `json:"product_id"`
+	UserID           uuid.UUID  `json:"user_id"`
+	Price            int64      `json:"price"`
+	IsValid          bool       `json:"is_valid"`
+	Comments         *string    `json:"comments"`
+	Quantity         int32      `json:"quantity"`
+	RetractedAt      *time.Time `json:"retracted_at"`
+	RetractedBy      *uuid.UUID `json:"retracted_by"`
+	RetractionReason *string    `json:"retraction_reason"`
+}
+
+type BidIncrementRule struct {
+	ID        uuid.UUID  `json:"id"`
+	Scope     string     `json:"scope"`
+	Category  *string    `json:"category"`
+	ProductID *uuid.UUID `json:"product_id"`
+	MinPrice  int64      `json:"min_price"`
+	Increment int64      `json:"increment"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type Conversation struct {
+	ID               uuid.UUID  `json:"id"`
+	ProductID        uuid.UUID  `json:"product_id"`
+	OrderID          *uuid.UUID `json:"order_id"`
+	SellerID         uuid.UUID  `json:"seller_id"`
+	BuyerID          uuid.UUID  `json:"buyer_id"`
+	SellerLastReadAt *time.Time `json:"seller_last_read_at"`
+	BuyerLastReadAt  *time.Time `json:"buyer_last_read_at"`
+	LastMessageAt    *time.Time `json:"last_message_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type DeadLetterJob struct {
 	ID        uuid.UUID `json:"id"`
-	BidAt     time.Time `json:"bid_at"`
+	JobID     uuid.UUID `json:"job_id"`
+	Kind      string    `json:"kind"`
+	Payload   []byte    `json:"payload"`
+	Attempts  int32     `json:"attempts"`
+	LastError string    `json:"last_error"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+type FeaturedListing struct {
+	SellerID  uuid.UUID `json:"seller_id"`
 	ProductID uuid.UUID `json:"product_id"`
+	Position  int32     `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FeeRule struct {
+	ID        uuid.UUID `json:"id"`
+	Kind      string    `json:"kind"`
+	Scope     string    `json:"scope"`
+	Category  *string   `json:"category"`
+	MinPrice  int64     `json:"min_price"`
+	RateBps   int32     `json:"rate_bps"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Feedback struct {
+	ID            uuid.UUID  `json:"id"`
+	OrderID       uuid.UUID  `json:"order_id"`
+	AuthorID      uuid.UUID  `json:"author_id"`
+	RecipientID   uuid.UUID  `json:"recipient_id"`
+	RecipientRole string     `json:"recipient_role"`
+	Rating        int16      `json:"rating"`
+	Comment       *string    `json:"comment"`
+	Reply         *string    `json:"reply"`
+	RepliedAt     *time.Time `json:"replied_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type Invoice struct {
+	ID             uuid.UUID  `json:"id"`
+	OrderID        uuid.UUID  `json:"order_id"`
+	ProductID      uuid.UUID  `json:"product_id"`
+	SellerID       uuid.UUID  `json:"seller_id"`
+	BuyerID        uuid.UUID  `json:"buyer_id"`
+	ItemPrice      int64      `json:"item_price"`
+	BuyerPremium   int64      `json:"buyer_premium"`
+	PlatformFee    int64      `json:"platform_fee"`
+	Shipping       int64      `json:"shipping"`
+	Total          int64      `json:"total"`
+	DepositApplied int64      `json:"deposit_applied"`
+	AmountDue      int64      `json:"amount_due"`
+	Status         string     `json:"status"`
+	DueAt          time.Time  `json:"due_at"`
+	PaidAt         *time.Time `json:"paid_at"`
+	ExpiredAt      *time.Time `json:"expired_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Currency       string     `json:"currency"`
+}
+
+type Job struct {
+	ID          uuid.UUID  `json:"id"`
+	Kind        string     `json:"kind"`
+	Payload     []byte     `json:"payload"`
+	Status      string     `json:"status"`
+	Attempts    int32      `json:"attempts"`
+	MaxAttempts int32      `json:"max_attempts"`
+	UniqueKey   *string    `json:"unique_key"`
+	RunAt       time.Time  `json:"run_at"`
+	LockedAt    *time.Time `json:"locked_at"`
+	LastError   *string    `json:"last_error"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+}
+
+type JournalEntry struct {
+	ID             uuid.UUID  `json:"id"`
+	IdempotencyKey string     `json:"idempotency_key"`
+	Kind           string     `json:"kind"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Amount         int64      `json:"amount"`
+	ReferenceID    *uuid.UUID `json:"reference_id"`
+	Description    *string    `json:"description"`
+	CreatedAt      time.Time  `json:"created_at"`
+	Currency       string     `json:"currency"`
+}
+
+type Message struct {
+	ID             uuid.UUID  `json:"id"`
+	ConversationID uuid.UUID  `json:"conversation_id"`
+	SenderID       uuid.UUID  `json:"sender_id"`
+	Body           string     `json:"body"`
+	AttachmentKey  *string    `json:"attachment_key"`
+	Status         string     `json:"status"`
+	Flags          []string   `json:"flags"`
+	ModeratedBy    *uuid.UUID `json:"moderated_by"`
+	ModeratedAt    *time.Time `json:"moderated_at"`
+	VisibleAt      *time.Time `json:"visible_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type NonPaymentStrike struct {
+	ID        uuid.UUID `json:"id"`
 	UserID    uuid.UUID `json:"user_id"`
-	Price     int32     `json:"price"`
-	IsValid   bool      `json:"is_valid"`
-	Comments  *string   `json:"comments"`
+	InvoiceID uuid.UUID `json:"invoice_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Offer struct {
+	ID          uuid.UUID  `json:"id"`
+	ProductID   uuid.UUID  `json:"product_id"`
+	BuyerID     uuid.UUID  `json:"buyer_id"`
+	SellerID    uuid.UUID  `json:"seller_id"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	MadeBy      string     `json:"made_by"`
+	Price       int64      `json:"price"`
+	Status      string     `json:"status"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RespondedAt *time.Time `json:"responded_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type Order struct {
+	ID               uuid.UUID  `json:"id"`
+	ProductID        uuid.UUID  `json:"product_id"`
+	BidID            *uuid.UUID `json:"bid_id"`
+	SellerID         uuid.UUID  `json:"seller_id"`
+	BuyerID          uuid.UUID  `json:"buyer_id"`
+	Quantity         int32      `json:"quantity"`
+	UnitPrice        int64      `json:"unit_price"`
+	TotalPrice       int64      `json:"total_price"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	FulfilmentStatus string     `json:"fulfilment_status"`
+	ShippingMethod   *string    `json:"shipping_method"`
+	ShippingCost     int64      `json:"shipping_cost"`
+	ShipToName       *string    `json:"ship_to_name"`
+	ShipToLine1      *string    `json:"ship_to_line1"`
+	ShipToLine2      *string    `json:"ship_to_line2"`
+	ShipToCity       *string    `json:"ship_to_city"`
+	ShipToState      *string    `json:"ship_to_state"`
+	ShipToPostalCode *string    `json:"ship_to_postal_code"`
+	ShipToCountry    *string    `json:"ship_to_country"`
+	Carrier          *string    `json:"carrier"`
+	TrackingNumber   *string    `json:"tracking_number"`
+	ShippedAt        *time.Time `json:"shipped_at"`
+	DeliveredAt      *time.Time `json:"delivered_at"`
+}
+
+type Outbox struct {
+	ID            int64      `json:"id"`
+	EventID       uuid.UUID  `json:"event_id"`
+	EventType     string     `json:"event_type"`
+	AggregateID   uuid.UUID  `json:"aggregate_id"`
+	Payload       []byte     `json:"payload"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Attempts      int32      `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     *string    `json:"last_error"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+type Payment struct {
+	ID                uuid.UUID `json:"id"`
+	InvoiceID         uuid.UUID `json:"invoice_id"`
+	Provider          string    `json:"provider"`
+	ProviderReference *string   `json:"provider_reference"`
+	Amount            int64     `json:"amount"`
+	Status            string    `json:"status"`
+	FailureReason     *string   `json:"failure_reason"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type Posting struct {
+	ID        uuid.UUID `json:"id"`
+	EntryID   uuid.UUID `json:"entry_id"`
+	AccountID uuid.UUID `json:"account_id"`
+	Direction string    `json:"direction"`
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Product struct {
-	ID           uuid.UUID  `json:"id"`
-	Title        string     `json:"title"`
-	Description  *string    `json:"description"`
-	SellerID     uuid.UUID  `json:"seller_id"`
-	Images       []string   `json:"images"`
-	MinPrice     int32      `json:"min_price"`
-	CurrentPrice int32      `json:"current_price"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
-	SoldAt       *time.Time `json:"sold_at"`
-	SoldTo       *uuid.UUID `json:"sold_to"`
+	ID                        uuid.UUID  `json:"id"`
+	Title                     string     `json:"title"`
+	Description               *string    `json:"description"`
+	SellerID                  uuid.UUID  `json:"seller_id"`
+	Images                    []string   `json:"images"`
+	MinPrice                  int64      `json:"min_price"`
+	CurrentPrice              int64      `json:"current_price"`
+	CreatedAt                 time.Time  `json:"created_at"`
+	UpdatedAt                 time.Time  `json:"updated_at"`
+	SoldAt                    *time.Time `json:"sold_at"`
+	SoldTo                    *uuid.UUID `json:"sold_to"`
+	EndsAt                    time.Time  `json:"ends_at"`
+	SoftCloseWindowMinutes    int32      `json:"soft_close_window_minutes"`
+	SoftCloseExtensionMinutes int32      `json:"soft_close_extension_minutes"`
+	MaxExtensions             *int32     `json:"max_extensions"`
+	ExtensionCount            int32      `json:"extension_count"`
+	Category                  *string    `json:"category"`
+	BuyNowPrice               *int64     `json:"buy_now_price"`
+	AuctionType               string     `json:"auction_type"`
+	ClosedAt                  *time.Time `json:"closed_at"`
+	DutchPriceStep            *int64     `json:"dutch_price_step"`
+	DutchIntervalSeconds      *int32     `json:"dutch_interval_seconds"`
+	NextPriceDropAt           *time.Time `json:"next_price_drop_at"`
+	Quantity                  int32      `json:"quantity"`
+	PricingRule               string     `json:"pricing_rule"`
+	Status                    string     `json:"status"`
+	StartsAt                  *time.Time `json:"starts_at"`
+	StartPrice                int64      `json:"start_price"`
+	RelistedFrom              *uuid.UUID `json:"relisted_from"`
+	AutoAcceptPrice           *int64     `json:"-"`
+	AutoDeclinePrice          *int64     `json:"-"`
+	Visibility                string     `json:"visibility"`
+	DepositType               string     `json:"deposit_type"`
+	DepositAmount             *int64     `json:"deposit_amount"`
+	SecondChanceOnNonPayment  bool       `json:"second_chance_on_non_payment"`
+	Currency                  string     `json:"currency"`
+	MinBidderReputation       *int32     `json:"min_bidder_reputation"`
+}
+
+type ProductAccessCode struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Code      string    `json:"code"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ProductInvitation struct {
+	ProductID uuid.UUID `json:"product_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SecondChanceOffer struct {
+	ID          uuid.UUID  `json:"id"`
+	ProductID   uuid.UUID  `json:"product_id"`
+	BidID       uuid.UUID  `json:"bid_id"`
+	SellerID    uuid.UUID  `json:"seller_id"`
+	BidderID    uuid.UUID  `json:"bidder_id"`
+	Price       int64      `json:"price"`
+	Status      string     `json:"status"`
+	AutoAdvance bool       `json:"auto_advance"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RespondedAt *time.Time `json:"responded_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type SellerBlockedBidder struct {
+	SellerID  uuid.UUID `json:"seller_id"`
+	BidderID  uuid.UUID `json:"bidder_id"`
+	Reason    *string   `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ShippingOption struct {
+	ID        uuid.UUID `json:"id"`
+	ProductID uuid.UUID `json:"product_id"`
+	Method    string    `json:"method"`
+	Region    *string   `json:"region"`
+	Cost      int64     `json:"cost"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type User struct {
-	ID        uuid.UUID  `json:"id"`
-	Username  string     `json:"username"`
-	Email     string     `json:"email"`
-	Password  string     `json:"-"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ID                         uuid.UUID  `json:"id"`
+	Username                   string     `json:"username"`
+	Email                      string     `json:"email"`
+	Password                   string     `json:"-"`
+	CreatedAt                  time.Time  `json:"created_at"`
+	UpdatedAt                  time.Time  `json:"updated_at"`
+	DeletedAt                  *time.Time `json:"deleted_at,omitempty"`
+	IsAdmin                    bool       `json:"is_admin"`
+	Bio                        *string    `json:"bio"`
+	AvatarKey                  *string    `json:"avatar_key"`
+	BannerKey                  *string    `json:"banner_key"`
+	PendingEmail               *string    `json:"pending_email"`
+	EmailVerificationTokenHash *string    `json:"-"`
+	EmailVerificationExpiresAt *time.Time `json:"email_verification_expires_at"`
+}
+
+type WebhookDelivery struct {
+	ID               uuid.UUID  `json:"id"`
+	EndpointID       uuid.UUID  `json:"endpoint_id"`
+	EventID          uuid.UUID  `json:"event_id"`
+	EventType        string     `json:"event_type"`
+	Payload          []byte     `json:"payload"`
+	Status           string     `json:"status"`
+	Attempts         int32      `json:"attempts"`
+	MaxAttempts      int32      `json:"max_attempts"`
+	NextAttemptAt    time.Time  `json:"next_attempt_at"`
+	LastResponseCode *int32     `json:"last_response_code"`
+	LastError        *string    `json:"last_error"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	DeliveredAt      *time.Time `json:"delivered_at"`
+}
+
+type WebhookDeliveryAttempt struct {
+	ID           uuid.UUID `json:"id"`
+	DeliveryID   uuid.UUID `json:"delivery_id"`
+	Attempt      int32     `json:"attempt"`
+	ResponseCode *int32    `json:"response_code"`
+	Error        *string   `json:"error"`
+	DurationMs   int32     `json:"duration_ms"`
+	AttemptedAt  time.Time `json:"attempted_at"`
+}
+
+type WebhookEndpoint struct {
+	ID         uuid.UUID `json:"id"`
+	SellerID   uuid.UUID `json:"seller_id"`
+	Url        string    `json:"url"`
+	Secret     string    `json:"-"`
+	EventTypes []string  `json:"event_types"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -18,19 +19,67 @@ INSERT INTO products (
     seller_id,
     images,
     min_price,
-    current_price
+    current_price,
+    ends_at,
+    soft_close_window_minutes,
+    soft_close_extension_minutes,
+    max_extensions,
+    category,
+    buy_now_price,
+    auction_type,
+    dutch_price_step,
+    dutch_interval_seconds,
+    next_price_drop_at,
+    quantity,
+    pricing_rule,
+    status,
+    starts_at,
+    start_price,
+    relisted_from,
+    auto_accept_price,
+    auto_decline_price,
+    visibility,
+    deposit_type,
+    deposit_amount,
+    second_chance_on_non_payment,
+    currency,
+    min_bidder_reputation
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
-) RETURNING id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30
+) RETURNING id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation
 `
 
 type AddProductParams struct {
-	Title        string    `json:"title"`
-	Description  *string   `json:"description"`
-	SellerID     uuid.UUID `json:"seller_id"`
-	Images       []string  `json:"images"`
-	MinPrice     int32     `json:"min_price"`
-	CurrentPrice int32     `json:"current_price"`
+	Title                     string     `json:"title"`
+	Description               *string    `json:"description"`
+	SellerID                  uuid.UUID  `json:"seller_id"`
+	Images                    []string   `json:"images"`
+	MinPrice                  int64      `json:"min_price"`
+	CurrentPrice              int64      `json:"current_price"`
+	EndsAt                    time.Time  `json:"ends_at"`
+	SoftCloseWindowMinutes    int32      `json:"soft_close_window_minutes"`
+	SoftCloseExtensionMinutes int32      `json:"soft_close_extension_minutes"`
+	MaxExtensions             *int32     `json:"max_extensions"`
+	Category                  *string    `json:"category"`
+	BuyNowPrice               *int64     `json:"buy_now_price"`
+	AuctionType               string     `json:"auction_type"`
+	DutchPriceStep            *int64     `json:"dutch_price_step"`
+	DutchIntervalSeconds      *int32     `json:"dutch_interval_seconds"`
+	NextPriceDropAt           *time.Time `json:"next_price_drop_at"`
+	Quantity                  int32      `json:"quantity"`
+	PricingRule               string     `json:"pricing_rule"`
+	Status                    string     `json:"status"`
+	StartsAt                  *time.Time `json:"starts_at"`
+	StartPrice                int64      `json:"start_price"`
+	RelistedFrom              *uuid.UUID `json:"relisted_from"`
+	AutoAcceptPrice           *int64     `json:"-"`
+	AutoDeclinePrice          *int64     `json:"-"`
+	Visibility                string     `json:"visibility"`
+	DepositType               string     `json:"deposit_type"`
+	DepositAmount             *int64     `json:"deposit_amount"`
+	SecondChanceOnNonPayment  bool       `json:"second_chance_on_non_payment"`
+	Currency                  string     `json:"currency"`
+	MinBidderReputation       *int32     `json:"min_bidder_reputation"`
 }
 
 func (q *Queries) AddProduct(ctx context.Context, arg AddProductParams) (Product, error) {
@@ -41,6 +90,30 @@ func (q *Queries) AddProduct(ctx context.Context, arg AddProductParams) (Product
 		arg.Images,
 		arg.MinPrice,
 		arg.CurrentPrice,
+		arg.EndsAt,
+		arg.SoftCloseWindowMinutes,
+		arg.SoftCloseExtensionMinutes,
+		arg.MaxExtensions,
+		arg.Category,
+		arg.BuyNowPrice,
+		arg.AuctionType,
+		arg.DutchPriceStep,
+		arg.DutchIntervalSeconds,
+		arg.NextPriceDropAt,
+		arg.Quantity,
+		arg.PricingRule,
+		arg.Status,
+		arg.StartsAt,
+		arg.StartPrice,
+		arg.RelistedFrom,
+		arg.AutoAcceptPrice,
+		arg.AutoDeclinePrice,
+		arg.Visibility,
+		arg.DepositType,
+		arg.DepositAmount,
+		arg.SecondChanceOnNonPayment,
+		arg.Currency,
+		arg.MinBidderReputation,
 	)
 	var i Product
 	err := row.Scan(
@@ -55,12 +128,298 @@ func (q *Queries) AddProduct(ctx context.Context, arg AddProductParams) (Product
 		&i.UpdatedAt,
 		&i.SoldAt,
 		&i.SoldTo,
+		&i.EndsAt,
+		&i.SoftCloseWindowMinutes,
+		&i.SoftCloseExtensionMinutes,
+		&i.MaxExtensions,
+		&i.ExtensionCount,
+		&i.Category,
+		&i.BuyNowPrice,
+		&i.AuctionType,
+		&i.ClosedAt,
+		&i.DutchPriceStep,
+		&i.DutchIntervalSeconds,
+		&i.NextPriceDropAt,
+		&i.Quantity,
+		&i.PricingRule,
+		&i.Status,
+		&i.StartsAt,
+		&i.StartPrice,
+		&i.RelistedFrom,
+		&i.AutoAcceptPrice,
+		&i.AutoDeclinePrice,
+		&i.Visibility,
+		&i.DepositType,
+		&i.DepositAmount,
+		&i.SecondChanceOnNonPayment,
+		&i.Currency,
+		&i.MinBidderReputation,
 	)
 	return i, err
 }
 
+const closeProduct = `-- name: CloseProduct :exec
+UPDATE products
+SET closed_at = NOW(), status = 'ended', updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) CloseProduct(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, closeProduct, id)
+	return err
+}
+
+const dropProductPrice = `-- name: DropProductPrice :exec
+UPDATE products
+SET current_price = $2, next_price_drop_at = $3, updated_at = NOW()
+WHERE id = $1
+`
+
+type DropProductPriceParams struct {
+	ID              uuid.UUID  `json:"id"`
+	CurrentPrice    int64      `json:"current_price"`
+	NextPriceDropAt *time.Time `json:"next_price_drop_at"`
+}
+
+func (q *Queries) DropProductPrice(ctx context.Context, arg DropProductPriceParams) error {
+	_, err := q.db.Exec(ctx, dropProductPrice, arg.ID, arg.CurrentPrice, arg.NextPriceDropAt)
+	return err
+}
+
+const extendProductEndsAt = `-- name: ExtendProductEndsAt :exec
+UPDATE products
+SET ends_at = $2, extension_count = extension_count + 1, updated_at = NOW()
+WHERE id = $1
+`
+
+type ExtendProductEndsAtParams struct {
+	ID     uuid.UUID `json:"id"`
+	EndsAt time.Time `json:"ends_at"`
+}
+
+func (q *Queries) ExtendProductEndsAt(ctx context.Context, arg ExtendProductEndsAtParams) error {
+	_, err := q.db.Exec(ctx, extendProductEndsAt, arg.ID, arg.EndsAt)
+	return err
+}
+
+const getAuctionsToSettle = `-- name: GetAuctionsToSettle :many
+SELECT id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation FROM products
+WHERE status = 'live' AND closed_at IS NULL AND ends_at <= $1
+    AND NOT (id = ANY($2::uuid[]))
+ORDER BY ends_at
+LIMIT $3
+`
+
+type GetAuctionsToSettleParams struct {
+	EndedBefore time.Time   `json:"ended_before"`
+	SkipIds     []uuid.UUID `json:"skip_ids"`
+	PageLimit   int32       `json:"page_limit"`
+}
+
+func (q *Queries) GetAuctionsToSettle(ctx context.Context, arg GetAuctionsToSettleParams) ([]Product, error) {
+	rows, err := q.db.Query(ctx, getAuctionsToSettle, arg.EndedBefore, arg.SkipIds, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Product{}
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.SellerID,
+			&i.Images,
+			&i.MinPrice,
+			&i.CurrentPrice,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SoldAt,
+			&i.SoldTo,
+			&i.EndsAt,
+			&i.SoftCloseWindowMinutes,
+			&i.SoftCloseExtensionMinutes,
+			&i.MaxExtensions,
+			&i.ExtensionCount,
+			&i.Category,
+			&i.BuyNowPrice,
+			&i.AuctionType,
+			&i.ClosedAt,
+			&i.DutchPriceStep,
+			&i.DutchIntervalSeconds,
+			&i.NextPriceDropAt,
+			&i.Quantity,
+			&i.PricingRule,
+			&i.Status,
+			&i.StartsAt,
+			&i.StartPrice,
+			&i.RelistedFrom,
+			&i.AutoAcceptPrice,
+			&i.AutoDeclinePrice,
+			&i.Visibility,
+			&i.DepositType,
+			&i.DepositAmount,
+			&i.SecondChanceOnNonPayment,
+			&i.Currency,
+			&i.MinBidderReputation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDueDutchPriceDrops = `-- name: GetDueDutchPriceDrops :many
+SELECT id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation FROM products
+WHERE auction_type = 'dutch' AND status = 'live' AND closed_at IS NULL AND next_price_drop_at <= $1::timestamp
+    AND NOT (id = ANY($2::uuid[]))
+ORDER BY next_price_drop_at
+LIMIT $3
+`
+
+type GetDueDutchPriceDropsParams struct {
+	DueBefore time.Time   `json:"due_before"`
+	SkipIds   []uuid.UUID `json:"skip_ids"`
+	PageLimit int32       `json:"page_limit"`
+}
+
+func (q *Queries) GetDueDutchPriceDrops(ctx context.Context, arg GetDueDutchPriceDropsParams) ([]Product, error) {
+	rows, err := q.db.Query(ctx, getDueDutchPriceDrops, arg.DueBefore, arg.SkipIds, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Product{}
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.SellerID,
+			&i.Images,
+			&i.MinPrice,
+			&i.CurrentPrice,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SoldAt,
+			&i.SoldTo,
+			&i.EndsAt,
+			&i.SoftCloseWindowMinutes,
+			&i.SoftCloseExtensionMinutes,
+			&i.MaxExtensions,
+			&i.ExtensionCount,
+			&i.Category,
+			&i.BuyNowPrice,
+			&i.AuctionType,
+			&i.ClosedAt,
+			&i.DutchPriceStep,
+			&i.DutchIntervalSeconds,
+			&i.NextPriceDropAt,
+			&i.Quantity,
+			&i.PricingRule,
+			&i.Status,
+			&i.StartsAt,
+			&i.StartPrice,
+			&i.RelistedFrom,
+			&i.AutoAcceptPrice,
+			&i.AutoDeclinePrice,
+			&i.Visibility,
+			&i.DepositType,
+			&i.DepositAmount,
+			&i.SecondChanceOnNonPayment,
+			&i.Currency,
+			&i.MinBidderReputation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDueScheduledProducts = `-- name: GetDueScheduledProducts :many
+SELECT id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation FROM products
+WHERE status = 'scheduled' AND starts_at <= $1::timestamp
+    AND NOT (id = ANY($2::uuid[]))
+ORDER BY starts_at
+LIMIT $3
+`
+
+type GetDueScheduledProductsParams struct {
+	DueBefore time.Time   `json:"due_before"`
+	SkipIds   []uuid.UUID `json:"skip_ids"`
+	PageLimit int32       `json:"page_limit"`
+}
+
+func (q *Queries) GetDueScheduledProducts(ctx context.Context, arg GetDueScheduledProductsParams) ([]Product, error) {
+	rows, err := q.db.Query(ctx, getDueScheduledProducts, arg.DueBefore, arg.SkipIds, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Product{}
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.SellerID,
+			&i.Images,
+			&i.MinPrice,
+			&i.CurrentPrice,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SoldAt,
+			&i.SoldTo,
+			&i.EndsAt,
+			&i.SoftCloseWindowMinutes,
+			&i.SoftCloseExtensionMinutes,
+			&i.MaxExtensions,
+			&i.ExtensionCount,
+			&i.Category,
+			&i.BuyNowPrice,
+			&i.AuctionType,
+			&i.ClosedAt,
+			&i.DutchPriceStep,
+			&i.DutchIntervalSeconds,
+			&i.NextPriceDropAt,
+			&i.Quantity,
+			&i.PricingRule,
+			&i.Status,
+			&i.StartsAt,
+			&i.StartPrice,
+			&i.RelistedFrom,
+			&i.AutoAcceptPrice,
+			&i.AutoDeclinePrice,
+			&i.Visibility,
+			&i.DepositType,
+			&i.DepositAmount,
+			&i.SecondChanceOnNonPayment,
+			&i.Currency,
+			&i.MinBidderReputation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProductByID = `-- name: GetProductByID :one
-SELECT id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to FROM products
+SELECT id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation FROM products
 WHERE id = $1
 LIMIT 1
 `
@@ -80,6 +439,83 @@ func (q *Queries) GetProductByID(ctx context.Context, id uuid.UUID) (Product, er
 		&i.UpdatedAt,
 		&i.SoldAt,
 		&i.SoldTo,
+		&i.EndsAt,
+		&i.SoftCloseWindowMinutes,
+		&i.SoftCloseExtensionMinutes,
+		&i.MaxExtensions,
+		&i.ExtensionCount,
+		&i.Category,
+		&i.BuyNowPrice,
+		&i.AuctionType,
+		&i.ClosedAt,
+		&i.DutchPriceStep,
+		&i.DutchIntervalSeconds,
+		&i.NextPriceDropAt,
+		&i.Quantity,
+		&i.PricingRule,
+		&i.Status,
+		&i.StartsAt,
+		&i.StartPrice,
+		&i.RelistedFrom,
+		&i.AutoAcceptPrice,
+		&i.AutoDeclinePrice,
+		&i.Visibility,
+		&i.DepositType,
+		&i.DepositAmount,
+		&i.SecondChanceOnNonPayment,
+		&i.Currency,
+		&i.MinBidderReputation,
+	)
+	return i, err
+}
+
+const getProductForUpdate = `-- name: GetProductForUpdate :one
+SELECT id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation FROM products
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetProductForUpdate(ctx context.Context, id uuid.UUID) (Product, error) {
+	row := q.db.QueryRow(ctx, getProductForUpdate, id)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.SellerID,
+		&i.Images,
+		&i.MinPrice,
+		&i.CurrentPrice,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SoldAt,
+		&i.SoldTo,
+		&i.EndsAt,
+		&i.SoftCloseWindowMinutes,
+		&i.SoftCloseExtensionMinutes,
+		&i.MaxExtensions,
+		&i.ExtensionCount,
+		&i.Category,
+		&i.BuyNowPrice,
+		&i.AuctionType,
+		&i.ClosedAt,
+		&i.DutchPriceStep,
+		&i.DutchIntervalSeconds,
+		&i.NextPriceDropAt,
+		&i.Quantity,
+		&i.PricingRule,
+		&i.Status,
+		&i.StartsAt,
+		&i.StartPrice,
+		&i.RelistedFrom,
+		&i.AutoAcceptPrice,
+		&i.AutoDeclinePrice,
+		&i.Visibility,
+		&i.DepositType,
+		&i.DepositAmount,
+		&i.SecondChanceOnNonPayment,
+		&i.Currency,
+		&i.MinBidderReputation,
 	)
 	return i, err
 }
@@ -98,20 +534,32 @@ func (q *Queries) GetProductImages(ctx context.Context, id uuid.UUID) ([]string,
 }
 
 const getProductsBySellerID = `-- name: GetProductsBySellerID :many
-SELECT id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to FROM products
-WHERE seller_id = $1
+SELECT id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation FROM products
+WHERE seller_id = $1 AND status = ANY($2::text[])
+    AND (visibility = 'public' OR seller_id = $3 OR EXISTS (
+        SELECT 1 FROM product_invitations i
+        WHERE i.product_id = products.id AND i.user_id = $3
+    ))
 ORDER BY created_at DESC
-LIMIT $2 OFFSET $3
+LIMIT $4 OFFSET $5
 `
 
 type GetProductsBySellerIDParams struct {
 	SellerID uuid.UUID `json:"seller_id"`
+	Statuses []string  `json:"statuses"`
+	ViewerID uuid.UUID `json:"viewer_id"`
 	Limit    int32     `json:"limit"`
 	Offset   int32     `json:"offset"`
 }
 
 func (q *Queries) GetProductsBySellerID(ctx context.Context, arg GetProductsBySellerIDParams) ([]Product, error) {
-	rows, err := q.db.Query(ctx, getProductsBySellerID, arg.SellerID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getProductsBySellerID,
+		arg.SellerID,
+		arg.Statuses,
+		arg.ViewerID,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +579,32 @@ func (q *Queries) GetProductsBySellerID(ctx context.Context, arg GetProductsBySe
 			&i.UpdatedAt,
 			&i.SoldAt,
 			&i.SoldTo,
+			&i.EndsAt,
+			&i.SoftCloseWindowMinutes,
+			&i.SoftCloseExtensionMinutes,
+			&i.MaxExtensions,
+			&i.ExtensionCount,
+			&i.Category,
+			&i.BuyNowPrice,
+			&i.AuctionType,
+			&i.ClosedAt,
+			&i.DutchPriceStep,
+			&i.DutchIntervalSeconds,
+			&i.NextPriceDropAt,
+			&i.Quantity,
+			&i.PricingRule,
+			&i.Status,
+			&i.StartsAt,
+			&i.StartPrice,
+			&i.RelistedFrom,
+			&i.AutoAcceptPrice,
+			&i.AutoDeclinePrice,
+			&i.Visibility,
+			&i.DepositType,
+			&i.DepositAmount,
+			&i.SecondChanceOnNonPayment,
+			&i.Currency,
+			&i.MinBidderReputation,
 		); err != nil {
 			return nil, err
 		}
@@ -144,15 +618,15 @@ func (q *Queries) GetProductsBySellerID(ctx context.Context, arg GetProductsBySe
 
 const markProductAsSold = `-- name: MarkProductAsSold :one
 UPDATE products
-SET sold_at = NOW(), sold_to = $2, current_price = $3, updated_at = NOW()
+SET sold_at = NOW(), sold_to = $2, current_price = $3, closed_at = NOW(), status = 'ended', updated_at = NOW()
 WHERE id = $1
-RETURNING id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to
+RETURNING id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation
 `
 
 type MarkProductAsSoldParams struct {
 	ID           uuid.UUID  `json:"id"`
 	SoldTo       *uuid.UUID `json:"sold_to"`
-	CurrentPrice int32      `json:"current_price"`
+	CurrentPrice int64      `json:"current_price"`
 }
 
 func (q *Queries) MarkProductAsSold(ctx context.Context, arg MarkProductAsSoldParams) (Product, error) {
@@ -170,6 +644,274 @@ func (q *Queries) MarkProductAsSold(ctx context.Context, arg MarkProductAsSoldPa
 		&i.UpdatedAt,
 		&i.SoldAt,
 		&i.SoldTo,
+		&i.EndsAt,
+		&i.SoftCloseWindowMinutes,
+		&i.SoftCloseExtensionMinutes,
+		&i.MaxExtensions,
+		&i.ExtensionCount,
+		&i.Category,
+		&i.BuyNowPrice,
+		&i.AuctionType,
+		&i.ClosedAt,
+		&i.DutchPriceStep,
+		&i.DutchIntervalSeconds,
+		&i.NextPriceDropAt,
+		&i.Quantity,
+		&i.PricingRule,
+		&i.Status,
+		&i.StartsAt,
+		&i.StartPrice,
+		&i.RelistedFrom,
+		&i.AutoAcceptPrice,
+		&i.AutoDeclinePrice,
+		&i.Visibility,
+		&i.DepositType,
+		&i.DepositAmount,
+		&i.SecondChanceOnNonPayment,
+		&i.Currency,
+		&i.MinBidderReputation,
+	)
+	return i, err
+}
+
+const markProductAsSoldToWinners = `-- name: MarkProductAsSoldToWinners :one
+UPDATE products
+SET sold_at = NOW(), sold_to = NULL, current_price = $2, closed_at = NOW(), status = 'ended', updated_at = NOW()
+WHERE id = $1
+RETURNING id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation
+`
+
+type MarkProductAsSoldToWinnersParams struct {
+	ID           uuid.UUID `json:"id"`
+	CurrentPrice int64     `json:"current_price"`
+}
+
+func (q *Queries) MarkProductAsSoldToWinners(ctx context.Context, arg MarkProductAsSoldToWinnersParams) (Product, error) {
+	row := q.db.QueryRow(ctx, markProductAsSoldToWinners, arg.ID, arg.CurrentPrice)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.SellerID,
+		&i.Images,
+		&i.MinPrice,
+		&i.CurrentPrice,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SoldAt,
+		&i.SoldTo,
+		&i.EndsAt,
+		&i.SoftCloseWindowMinutes,
+		&i.SoftCloseExtensionMinutes,
+		&i.MaxExtensions,
+		&i.ExtensionCount,
+		&i.Category,
+		&i.BuyNowPrice,
+		&i.AuctionType,
+		&i.ClosedAt,
+		&i.DutchPriceStep,
+		&i.DutchIntervalSeconds,
+		&i.NextPriceDropAt,
+		&i.Quantity,
+		&i.PricingRule,
+		&i.Status,
+		&i.StartsAt,
+		&i.StartPrice,
+		&i.RelistedFrom,
+		&i.AutoAcceptPrice,
+		&i.AutoDeclinePrice,
+		&i.Visibility,
+		&i.DepositType,
+		&i.DepositAmount,
+		&i.SecondChanceOnNonPayment,
+		&i.Currency,
+		&i.MinBidderReputation,
+	)
+	return i, err
+}
+
+const markProductRelisted = `-- name: MarkProductRelisted :exec
+UPDATE products
+SET status = 'relisted', updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkProductRelisted(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markProductRelisted, id)
+	return err
+}
+
+const reopenUnpaidProduct = `-- name: ReopenUnpaidProduct :one
+UPDATE products
+SET sold_at = NULL, sold_to = NULL, updated_at = NOW()
+WHERE id = $1
+RETURNING id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation
+`
+
+func (q *Queries) ReopenUnpaidProduct(ctx context.Context, id uuid.UUID) (Product, error) {
+	row := q.db.QueryRow(ctx, reopenUnpaidProduct, id)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.SellerID,
+		&i.Images,
+		&i.MinPrice,
+		&i.CurrentPrice,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SoldAt,
+		&i.SoldTo,
+		&i.EndsAt,
+		&i.SoftCloseWindowMinutes,
+		&i.SoftCloseExtensionMinutes,
+		&i.MaxExtensions,
+		&i.ExtensionCount,
+		&i.Category,
+		&i.BuyNowPrice,
+		&i.AuctionType,
+		&i.ClosedAt,
+		&i.DutchPriceStep,
+		&i.DutchIntervalSeconds,
+		&i.NextPriceDropAt,
+		&i.Quantity,
+		&i.PricingRule,
+		&i.Status,
+		&i.StartsAt,
+		&i.StartPrice,
+		&i.RelistedFrom,
+		&i.AutoAcceptPrice,
+		&i.AutoDeclinePrice,
+		&i.Visibility,
+		&i.DepositType,
+		&i.DepositAmount,
+		&i.SecondChanceOnNonPayment,
+		&i.Currency,
+		&i.MinBidderReputation,
+	)
+	return i, err
+}
+
+const scheduleProduct = `-- name: ScheduleProduct :one
+UPDATE products
+SET status = 'scheduled', starts_at = $2, ends_at = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation
+`
+
+type ScheduleProductParams struct {
+	ID       uuid.UUID  `json:"id"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   time.Time  `json:"ends_at"`
+}
+
+func (q *Queries) ScheduleProduct(ctx context.Context, arg ScheduleProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, scheduleProduct, arg.ID, arg.StartsAt, arg.EndsAt)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.SellerID,
+		&i.Images,
+		&i.MinPrice,
+		&i.CurrentPrice,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SoldAt,
+		&i.SoldTo,
+		&i.EndsAt,
+		&i.SoftCloseWindowMinutes,
+		&i.SoftCloseExtensionMinutes,
+		&i.MaxExtensions,
+		&i.ExtensionCount,
+		&i.Category,
+		&i.BuyNowPrice,
+		&i.AuctionType,
+		&i.ClosedAt,
+		&i.DutchPriceStep,
+		&i.DutchIntervalSeconds,
+		&i.NextPriceDropAt,
+		&i.Quantity,
+		&i.PricingRule,
+		&i.Status,
+		&i.StartsAt,
+		&i.StartPrice,
+		&i.RelistedFrom,
+		&i.AutoAcceptPrice,
+		&i.AutoDeclinePrice,
+		&i.Visibility,
+		&i.DepositType,
+		&i.DepositAmount,
+		&i.SecondChanceOnNonPayment,
+		&i.Currency,
+		&i.MinBidderReputation,
+	)
+	return i, err
+}
+
+const startProduct = `-- name: StartProduct :one
+UPDATE products
+SET status = 'live', starts_at = $2, ends_at = $3, next_price_drop_at = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation
+`
+
+type StartProductParams struct {
+	ID              uuid.UUID  `json:"id"`
+	StartsAt        *time.Time `json:"starts_at"`
+	EndsAt          time.Time  `json:"ends_at"`
+	NextPriceDropAt *time.Time `json:"next_price_drop_at"`
+}
+
+func (q *Queries) StartProduct(ctx context.Context, arg StartProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, startProduct,
+		arg.ID,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.NextPriceDropAt,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.SellerID,
+		&i.Images,
+		&i.MinPrice,
+		&i.CurrentPrice,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SoldAt,
+		&i.SoldTo,
+		&i.EndsAt,
+		&i.SoftCloseWindowMinutes,
+		&i.SoftCloseExtensionMinutes,
+		&i.MaxExtensions,
+		&i.ExtensionCount,
+		&i.Category,
+		&i.BuyNowPrice,
+		&i.AuctionType,
+		&i.ClosedAt,
+		&i.DutchPriceStep,
+		&i.DutchIntervalSeconds,
+		&i.NextPriceDropAt,
+		&i.Quantity,
+		&i.PricingRule,
+		&i.Status,
+		&i.StartsAt,
+		&i.StartPrice,
+		&i.RelistedFrom,
+		&i.AutoAcceptPrice,
+		&i.AutoDeclinePrice,
+		&i.Visibility,
+		&i.DepositType,
+		&i.DepositAmount,
+		&i.SecondChanceOnNonPayment,
+		&i.Currency,
+		&i.MinBidderReputation,
 	)
 	return i, err
 }
@@ -182,7 +924,7 @@ WHERE id = $1
 
 type UpdateProductCurrentPriceParams struct {
 	ID           uuid.UUID `json:"id"`
-	CurrentPrice int32     `json:"current_price"`
+	CurrentPrice int64     `json:"current_price"`
 }
 
 func (q *Queries) UpdateProductCurrentPrice(ctx context.Context, arg UpdateProductCurrentPriceParams) error {
@@ -194,7 +936,7 @@ const updateProductImages = `-- name: UpdateProductImages :one
 UPDATE products
 SET images = $2, updated_at = NOW()
 WHERE id = $1
-RETURNING id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to
+RETURNING id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation
 `
 
 type UpdateProductImagesParams struct {
@@ -217,6 +959,111 @@ func (q *Queries) UpdateProductImages(ctx context.Context, arg UpdateProductImag
 		&i.UpdatedAt,
 		&i.SoldAt,
 		&i.SoldTo,
+		&i.EndsAt,
+		&i.SoftCloseWindowMinutes,
+		&i.SoftCloseExtensionMinutes,
+		&i.MaxExtensions,
+		&i.ExtensionCount,
+		&i.Category,
+		&i.BuyNowPrice,
+		&i.AuctionType,
+		&i.ClosedAt,
+		&i.DutchPriceStep,
+		&i.DutchIntervalSeconds,
+		&i.NextPriceDropAt,
+		&i.Quantity,
+		&i.PricingRule,
+		&i.Status,
+		&i.StartsAt,
+		&i.StartPrice,
+		&i.RelistedFrom,
+		&i.AutoAcceptPrice,
+		&i.AutoDeclinePrice,
+		&i.Visibility,
+		&i.DepositType,
+		&i.DepositAmount,
+		&i.SecondChanceOnNonPayment,
+		&i.Currency,
+		&i.MinBidderReputation,
+	)
+	return i, err
+}
+
+const updateProductListing = `-- name: UpdateProductListing :one
+UPDATE products
+SET title = $2, description = $3, images = $4, min_price = $5, current_price = $6, start_price = $7,
+    ends_at = $8, category = $9, buy_now_price = $10, next_price_drop_at = $11, updated_at = NOW()
+WHERE id = $1
+RETURNING id, title, description, seller_id, images, min_price, current_price, created_at, updated_at, sold_at, sold_to, ends_at, soft_close_window_minutes, soft_close_extension_minutes, max_extensions, extension_count, category, buy_now_price, auction_type, closed_at, dutch_price_step, dutch_interval_seconds, next_price_drop_at, quantity, pricing_rule, status, starts_at, start_price, relisted_from, auto_accept_price, auto_decline_price, visibility, deposit_type, deposit_amount, second_chance_on_non_payment, currency, min_bidder_reputation
+`
+
+type UpdateProductListingParams struct {
+	ID              uuid.UUID  `json:"id"`
+	Title           string     `json:"title"`
+	Description     *string    `json:"description"`
+	Images          []string   `json:"images"`
+	MinPrice        int64      `json:"min_price"`
+	CurrentPrice    int64      `json:"current_price"`
+	StartPrice      int64      `json:"start_price"`
+	EndsAt          time.Time  `json:"ends_at"`
+	Category        *string    `json:"category"`
+	BuyNowPrice     *int64     `json:"buy_now_price"`
+	NextPriceDropAt *time.Time `json:"next_price_drop_at"`
+}
+
+func (q *Queries) UpdateProductListing(ctx context.Context, arg UpdateProductListingParams) (Product, error) {
+	row := q.db.QueryRow(ctx, updateProductListing,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.Images,
+		arg.MinPrice,
+		arg.CurrentPrice,
+		arg.StartPrice,
+		arg.EndsAt,
+		arg.Category,
+		arg.BuyNowPrice,
+		arg.NextPriceDropAt,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.SellerID,
+		&i.Images,
+		&i.MinPrice,
+		&i.CurrentPrice,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SoldAt,
+		&i.SoldTo,
+		&i.EndsAt,
+		&i.SoftCloseWindowMinutes,
+		&i.SoftCloseExtensionMinutes,
+		&i.MaxExtensions,
+		&i.ExtensionCount,
+		&i.Category,
+		&i.BuyNowPrice,
+		&i.AuctionType,
+		&i.ClosedAt,
+		&i.DutchPriceStep,
+		&i.DutchIntervalSeconds,
+		&i.NextPriceDropAt,
+		&i.Quantity,
+		&i.PricingRule,
+		&i.Status,
+		&i.StartsAt,
+		&i.StartPrice,
+		&i.RelistedFrom,
+		&i.AutoAcceptPrice,
+		&i.AutoDeclinePrice,
+		&i.Visibility,
+		&i.DepositType,
+		&i.DepositAmount,
+		&i.SecondChanceOnNonPayment,
+		&i.Currency,
+		&i.MinBidderReputation,
 	)
 	return i, err
 }
@@ -6,30 +6,208 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AddFeaturedListing(ctx context.Context, arg AddFeaturedListingParams) error
 	AddProduct(ctx context.Context, arg AddProductParams) (Product, error)
+	BlockBidder(ctx context.Context, arg BlockBidderParams) (SellerBlockedBidder, error)
+	CancelJob(ctx context.Context, id uuid.UUID) (Job, error)
+	CancelOrderFulfilment(ctx context.Context, id uuid.UUID) error
+	ClaimDueJobs(ctx context.Context, arg ClaimDueJobsParams) ([]Job, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, limit int32) ([]WebhookDelivery, error)
+	ClaimPendingOutboxEvents(ctx context.Context, limit int32) ([]Outbox, error)
+	ClearDefaultAddress(ctx context.Context, userID uuid.UUID) error
+	CloseProduct(ctx context.Context, id uuid.UUID) error
+	CompleteJob(ctx context.Context, id uuid.UUID) error
+	CompletePayment(ctx context.Context, arg CompletePaymentParams) (Payment, error)
+	ConfirmPendingEmail(ctx context.Context, id uuid.UUID) (User, error)
+	CopyProductInvitations(ctx context.Context, arg CopyProductInvitationsParams) error
+	CountAddressesByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 	CountBidsByProduct(ctx context.Context, productID uuid.UUID) (int64, error)
+	CountNonPaymentStrikes(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountRecentMessagesBySender(ctx context.Context, arg CountRecentMessagesBySenderParams) (int64, error)
+	CountUnreadMessages(ctx context.Context, userID uuid.UUID) (int64, error)
+	CreateAddress(ctx context.Context, arg CreateAddressParams) (Address, error)
 	CreateBid(ctx context.Context, arg CreateBidParams) error
+	CreateBidIncrementRule(ctx context.Context, arg CreateBidIncrementRuleParams) error
+	CreateConversation(ctx context.Context, arg CreateConversationParams) (Conversation, error)
+	CreateDeadLetterJob(ctx context.Context, arg CreateDeadLetterJobParams) error
+	CreateFeeRule(ctx context.Context, arg CreateFeeRuleParams) error
+	CreateFeedback(ctx context.Context, arg CreateFeedbackParams) (Feedback, error)
+	CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error)
+	CreateJournalEntry(ctx context.Context, arg CreateJournalEntryParams) (JournalEntry, error)
+	CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error)
+	CreateNonPaymentStrike(ctx context.Context, arg CreateNonPaymentStrikeParams) (NonPaymentStrike, error)
+	CreateOffer(ctx context.Context, arg CreateOfferParams) (Offer, error)
+	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
+	CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error)
+	CreatePosting(ctx context.Context, arg CreatePostingParams) error
+	CreateProductInvitation(ctx context.Context, arg CreateProductInvitationParams) (ProductInvitation, error)
+	CreateSecondChanceOffer(ctx context.Context, arg CreateSecondChanceOfferParams) (SecondChanceOffer, error)
+	CreateShippingOption(ctx context.Context, arg CreateShippingOptionParams) (ShippingOption, error)
+	CreateSystemAccount(ctx context.Context, arg CreateSystemAccountParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) error
+	CreateWebhookDeliveryAttempt(ctx context.Context, arg CreateWebhookDeliveryAttemptParams) error
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error)
+	DeclineOpenOffersForProduct(ctx context.Context, productID uuid.UUID) error
+	DeleteAddress(ctx context.Context, arg DeleteAddressParams) (Address, error)
 	DeleteBid(ctx context.Context, id uuid.UUID) error
+	DeleteCategoryBidIncrementRules(ctx context.Context, category *string) error
+	DeleteCategoryFeeRules(ctx context.Context, arg DeleteCategoryFeeRulesParams) error
+	DeleteFeaturedListings(ctx context.Context, sellerID uuid.UUID) error
+	DeleteFinishedJobs(ctx context.Context, finishedBefore time.Time) (int64, error)
+	DeletePlatformBidIncrementRules(ctx context.Context) error
+	DeletePlatformFeeRules(ctx context.Context, kind string) error
+	DeleteProductAccessCode(ctx context.Context, productID uuid.UUID) (int64, error)
+	DeleteProductInvitation(ctx context.Context, arg DeleteProductInvitationParams) (int64, error)
+	DeleteSentOutboxEvents(ctx context.Context, sentBefore time.Time) (int64, error)
+	DeleteWebhookEndpoint(ctx context.Context, arg DeleteWebhookEndpointParams) (int64, error)
+	DropProductPrice(ctx context.Context, arg DropProductPriceParams) error
+	EnqueueJob(ctx context.Context, arg EnqueueJobParams) (Job, error)
+	EnsureUserAccount(ctx context.Context, arg EnsureUserAccountParams) (Account, error)
+	ExtendProductEndsAt(ctx context.Context, arg ExtendProductEndsAtParams) error
+	FeedbackExists(ctx context.Context, arg FeedbackExistsParams) (bool, error)
+	GetAccountBalance(ctx context.Context, accountID uuid.UUID) (int64, error)
+	GetAccountForUpdate(ctx context.Context, id uuid.UUID) (Account, error)
+	GetActiveWebhookEndpointsForEvent(ctx context.Context, arg GetActiveWebhookEndpointsForEventParams) ([]WebhookEndpoint, error)
+	GetAddressByID(ctx context.Context, arg GetAddressByIDParams) (Address, error)
+	GetAddressesByUserID(ctx context.Context, userID uuid.UUID) ([]Address, error)
+	GetAuctionsToSettle(ctx context.Context, arg GetAuctionsToSettleParams) ([]Product, error)
+	GetBidByID(ctx context.Context, id uuid.UUID) (Bid, error)
+	GetBidByProductAndUser(ctx context.Context, arg GetBidByProductAndUserParams) (Bid, error)
+	GetBidIncrementRules(ctx context.Context) ([]BidIncrementRule, error)
+	GetBidIncrementRulesForProduct(ctx context.Context, arg GetBidIncrementRulesForProductParams) ([]BidIncrementRule, error)
 	GetBidsByProductID(ctx context.Context, productID uuid.UUID) ([]Bid, error)
 	GetBidsByUserID(ctx context.Context, userID uuid.UUID) ([]Bid, error)
+	GetBlockedBidders(ctx context.Context, arg GetBlockedBiddersParams) ([]SellerBlockedBidder, error)
+	GetConversationByID(ctx context.Context, id uuid.UUID) (Conversation, error)
+	GetConversationsByUserID(ctx context.Context, arg GetConversationsByUserIDParams) ([]GetConversationsByUserIDRow, error)
+	GetDeadLetterJobs(ctx context.Context, arg GetDeadLetterJobsParams) ([]DeadLetterJob, error)
+	GetDefaultAddress(ctx context.Context, userID uuid.UUID) (Address, error)
+	GetDueDutchPriceDrops(ctx context.Context, arg GetDueDutchPriceDropsParams) ([]Product, error)
+	GetDueScheduledProducts(ctx context.Context, arg GetDueScheduledProductsParams) ([]Product, error)
+	GetExpiredOffers(ctx context.Context, arg GetExpiredOffersParams) ([]Offer, error)
+	GetExpiredSecondChanceOffers(ctx context.Context, arg GetExpiredSecondChanceOffersParams) ([]SecondChanceOffer, error)
+	GetFeaturedListings(ctx context.Context, sellerID uuid.UUID) ([]Product, error)
+	GetFeeRevenue(ctx context.Context, arg GetFeeRevenueParams) ([]GetFeeRevenueRow, error)
+	GetFeeRules(ctx context.Context) ([]FeeRule, error)
+	GetFeeRulesForCategory(ctx context.Context, category *string) ([]FeeRule, error)
+	GetFeedbackByRecipientAndRole(ctx context.Context, arg GetFeedbackByRecipientAndRoleParams) ([]Feedback, error)
+	GetFeedbackByRecipientID(ctx context.Context, arg GetFeedbackByRecipientIDParams) ([]Feedback, error)
+	GetFeedbackForUpdate(ctx context.Context, id uuid.UUID) (Feedback, error)
+	GetHeldAmountsByReference(ctx context.Context, referenceID *uuid.UUID) ([]GetHeldAmountsByReferenceRow, error)
+	GetHeldMessages(ctx context.Context, arg GetHeldMessagesParams) ([]Message, error)
+	GetInvoiceByID(ctx context.Context, id uuid.UUID) (Invoice, error)
+	GetInvoiceByOrderID(ctx context.Context, orderID uuid.UUID) (Invoice, error)
+	GetInvoiceForUpdate(ctx context.Context, id uuid.UUID) (Invoice, error)
+	GetInvoicesByBuyerID(ctx context.Context, arg GetInvoicesByBuyerIDParams) ([]Invoice, error)
+	GetInvoicesBySellerID(ctx context.Context, arg GetInvoicesBySellerIDParams) ([]Invoice, error)
+	GetJobByID(ctx context.Context, id uuid.UUID) (Job, error)
+	GetJournalEntriesByUserID(ctx context.Context, arg GetJournalEntriesByUserIDParams) ([]JournalEntry, error)
+	GetJournalEntryByKey(ctx context.Context, idempotencyKey string) (JournalEntry, error)
 	GetLatestBidForProduct(ctx context.Context, productID uuid.UUID) (Bid, error)
+	GetMessageForUpdate(ctx context.Context, id uuid.UUID) (Message, error)
+	GetMessagesByConversationID(ctx context.Context, arg GetMessagesByConversationIDParams) ([]Message, error)
+	GetOfferByID(ctx context.Context, id uuid.UUID) (Offer, error)
+	GetOfferForUpdate(ctx context.Context, id uuid.UUID) (Offer, error)
+	GetOffersByBuyerID(ctx context.Context, arg GetOffersByBuyerIDParams) ([]Offer, error)
+	GetOffersByProductID(ctx context.Context, productID uuid.UUID) ([]Offer, error)
+	GetOffersBySellerID(ctx context.Context, arg GetOffersBySellerIDParams) ([]Offer, error)
+	GetOrderByID(ctx context.Context, id uuid.UUID) (Order, error)
+	GetOrderConversation(ctx context.Context, orderID *uuid.UUID) (Conversation, error)
+	GetOrderForUpdate(ctx context.Context, id uuid.UUID) (Order, error)
+	GetOrdersByBuyerID(ctx context.Context, arg GetOrdersByBuyerIDParams) ([]Order, error)
+	GetOrdersByProductID(ctx context.Context, productID uuid.UUID) ([]Order, error)
+	GetOrdersBySellerID(ctx context.Context, arg GetOrdersBySellerIDParams) ([]Order, error)
+	GetOverdueInvoices(ctx context.Context, arg GetOverdueInvoicesParams) ([]Invoice, error)
+	GetPaymentByID(ctx context.Context, id uuid.UUID) (Payment, error)
+	GetPaymentsByInvoiceID(ctx context.Context, invoiceID uuid.UUID) ([]Payment, error)
+	GetPendingOfferForBuyer(ctx context.Context, arg GetPendingOfferForBuyerParams) (Offer, error)
+	GetPendingSecondChanceOffersByBidder(ctx context.Context, bidderID uuid.UUID) ([]SecondChanceOffer, error)
+	GetProcessingPayment(ctx context.Context, invoiceID uuid.UUID) (Payment, error)
+	GetProductAccessCode(ctx context.Context, productID uuid.UUID) (ProductAccessCode, error)
 	GetProductByID(ctx context.Context, id uuid.UUID) (Product, error)
+	GetProductConversation(ctx context.Context, arg GetProductConversationParams) (Conversation, error)
+	GetProductForUpdate(ctx context.Context, id uuid.UUID) (Product, error)
 	GetProductImages(ctx context.Context, id uuid.UUID) ([]string, error)
+	GetProductInvitations(ctx context.Context, productID uuid.UUID) ([]ProductInvitation, error)
 	GetProductsBySellerID(ctx context.Context, arg GetProductsBySellerIDParams) ([]Product, error)
+	GetReputationByUserID(ctx context.Context, recipientID uuid.UUID) (GetReputationByUserIDRow, error)
+	GetSecondChanceOfferByID(ctx context.Context, id uuid.UUID) (SecondChanceOffer, error)
+	GetSecondChanceOfferForUpdate(ctx context.Context, id uuid.UUID) (SecondChanceOffer, error)
+	GetSecondChanceOffersByProductID(ctx context.Context, productID uuid.UUID) ([]SecondChanceOffer, error)
+	GetSellerPayouts(ctx context.Context, arg GetSellerPayoutsParams) ([]GetSellerPayoutsRow, error)
+	GetShippingOptionByID(ctx context.Context, id uuid.UUID) (ShippingOption, error)
+	GetShippingOptionsByProductID(ctx context.Context, productID uuid.UUID) ([]ShippingOption, error)
+	GetSoldProductsBySellerID(ctx context.Context, arg GetSoldProductsBySellerIDParams) ([]Product, error)
+	GetStaleProcessingPayments(ctx context.Context, arg GetStaleProcessingPaymentsParams) ([]Payment, error)
+	GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (Account, error)
+	GetUserAccountBalances(ctx context.Context, userID *uuid.UUID) ([]GetUserAccountBalancesRow, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserByUsername(ctx context.Context, username string) (User, error)
+	GetUserForUpdate(ctx context.Context, id uuid.UUID) (User, error)
 	GetValidBidsByProductID(ctx context.Context, productID uuid.UUID) ([]Bid, error)
+	GetWebhookDeliveriesByEndpointID(ctx context.Context, arg GetWebhookDeliveriesByEndpointIDParams) ([]WebhookDelivery, error)
+	GetWebhookDeliveryAttempts(ctx context.Context, deliveryID uuid.UUID) ([]WebhookDeliveryAttempt, error)
+	GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (WebhookDelivery, error)
+	GetWebhookEndpointByID(ctx context.Context, id uuid.UUID) (WebhookEndpoint, error)
+	GetWebhookEndpointsBySellerID(ctx context.Context, sellerID uuid.UUID) ([]WebhookEndpoint, error)
+	InsertOutboxEvent(ctx context.Context, arg InsertOutboxEventParams) error
 	InvalidateBid(ctx context.Context, id uuid.UUID) error
+	InvalidateBidsByBidder(ctx context.Context, arg InvalidateBidsByBidderParams) error
+	InvalidateBidsForProduct(ctx context.Context, productID uuid.UUID) error
+	IsBidderBlocked(ctx context.Context, arg IsBidderBlockedParams) (bool, error)
+	IsUserInvited(ctx context.Context, arg IsUserInvitedParams) (bool, error)
+	ListJobs(ctx context.Context, arg ListJobsParams) ([]Job, error)
+	MarkConversationRead(ctx context.Context, arg MarkConversationReadParams) (Conversation, error)
+	MarkInvoiceExpired(ctx context.Context, id uuid.UUID) (Invoice, error)
+	MarkInvoicePaid(ctx context.Context, id uuid.UUID) (Invoice, error)
+	MarkInvoicePending(ctx context.Context, id uuid.UUID) (Invoice, error)
+	MarkInvoiceProcessing(ctx context.Context, id uuid.UUID) (Invoice, error)
+	MarkJobDead(ctx context.Context, arg MarkJobDeadParams) error
+	MarkOrderDelivered(ctx context.Context, id uuid.UUID) (Order, error)
+	MarkOrderShipped(ctx context.Context, arg MarkOrderShippedParams) (Order, error)
+	MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error
+	MarkOutboxEventSent(ctx context.Context, id int64) error
 	MarkProductAsSold(ctx context.Context, arg MarkProductAsSoldParams) (Product, error)
+	MarkProductAsSoldToWinners(ctx context.Context, arg MarkProductAsSoldToWinnersParams) (Product, error)
+	MarkProductRelisted(ctx context.Context, id uuid.UUID) error
+	ModerateMessage(ctx context.Context, arg ModerateMessageParams) (Message, error)
+	PromoteOldestAddress(ctx context.Context, userID uuid.UUID) error
+	RecordWebhookDeliveryResult(ctx context.Context, arg RecordWebhookDeliveryResultParams) error
+	ReopenUnpaidProduct(ctx context.Context, id uuid.UUID) (Product, error)
+	ReplyToFeedback(ctx context.Context, arg ReplyToFeedbackParams) (Feedback, error)
+	RequeueJob(ctx context.Context, id uuid.UUID) (Job, error)
+	RequeueStaleJobs(ctx context.Context, lockedBefore time.Time) (int64, error)
+	RequeueStaleWebhookDeliveries(ctx context.Context) (int64, error)
+	ResetWebhookDelivery(ctx context.Context, id uuid.UUID) (WebhookDelivery, error)
+	RespondToOffer(ctx context.Context, arg RespondToOfferParams) (Offer, error)
+	RespondToSecondChanceOffer(ctx context.Context, arg RespondToSecondChanceOfferParams) (SecondChanceOffer, error)
+	RetractBid(ctx context.Context, arg RetractBidParams) (Bid, error)
+	RetractBidsByBidder(ctx context.Context, arg RetractBidsByBidderParams) ([]Bid, error)
+	RetryJobLater(ctx context.Context, arg RetryJobLaterParams) error
+	ReviseBid(ctx context.Context, arg ReviseBidParams) error
+	ScheduleProduct(ctx context.Context, arg ScheduleProductParams) (Product, error)
+	SetPendingEmail(ctx context.Context, arg SetPendingEmailParams) (User, error)
+	SetProductAccessCode(ctx context.Context, arg SetProductAccessCodeParams) (ProductAccessCode, error)
+	SetUserAvatar(ctx context.Context, arg SetUserAvatarParams) (User, error)
+	SetUserBanner(ctx context.Context, arg SetUserBannerParams) (User, error)
+	StartProduct(ctx context.Context, arg StartProductParams) (Product, error)
+	TouchConversation(ctx context.Context, id uuid.UUID) error
+	UnblockBidder(ctx context.Context, arg UnblockBidderParams) (int64, error)
+	UpdateAddress(ctx context.Context, arg UpdateAddressParams) (Address, error)
+	UpdateInvoiceShipping(ctx context.Context, arg UpdateInvoiceShippingParams) (Invoice, error)
+	UpdateOrderShipping(ctx context.Context, arg UpdateOrderShippingParams) (Order, error)
 	UpdateProductCurrentPrice(ctx context.Context, arg UpdateProductCurrentPriceParams) error
 	UpdateProductImages(ctx context.Context, arg UpdateProductImagesParams) (Product, error)
+	UpdateProductListing(ctx context.Context, arg UpdateProductListingParams) (Product, error)
+	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
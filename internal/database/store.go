@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Store extends the generated Querier with transaction support.
+// It is hand written, sqlc does not touch this file.
+type Store interface {
+	Querier
+	// ExecTx runs fn inside a single transaction, it is rolled back when fn returns an error.
+	ExecTx(ctx context.Context, fn func(Querier) error) error
+}
+
+type SQLStore struct {
+	*Queries
+	pool *pgxpool.Pool
+}
+
+func NewStore(pool *pgxpool.Pool) *SQLStore {
+	return &SQLStore{
+		Queries: New(pool),
+		pool:    pool,
+	}
+}
+
+func (s *SQLStore) ExecTx(ctx context.Context, fn func(Querier) error) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(s.WithTx(tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && rbErr != pgx.ErrTxClosed {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -7,10 +7,41 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const confirmPendingEmail = `-- name: ConfirmPendingEmail :one
+UPDATE users
+SET email = pending_email, pending_email = NULL, email_verification_token_hash = NULL,
+    email_verification_expires_at = NULL, updated_at = NOW()
+WHERE id = $1 AND pending_email IS NOT NULL
+RETURNING id, username, email, password, created_at, updated_at, deleted_at, is_admin, bio, avatar_key, banner_key, pending_email, email_verification_token_hash, email_verification_expires_at
+`
+
+func (q *Queries) ConfirmPendingEmail(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, confirmPendingEmail, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.Bio,
+		&i.AvatarKey,
+		&i.BannerKey,
+		&i.PendingEmail,
+		&i.EmailVerificationTokenHash,
+		&i.EmailVerificationExpiresAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
     username,
@@ -18,7 +49,7 @@ INSERT INTO users (
     password
 ) VALUES (
     $1, $2, $3
-) RETURNING id, username, email, password, created_at, updated_at, deleted_at
+) RETURNING id, username, email, password, created_at, updated_at, deleted_at, is_admin, bio, avatar_key, banner_key, pending_email, email_verification_token_hash, email_verification_expires_at
 `
 
 type CreateUserParams struct {
@@ -38,12 +69,19 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.Bio,
+		&i.AvatarKey,
+		&i.BannerKey,
+		&i.PendingEmail,
+		&i.EmailVerificationTokenHash,
+		&i.EmailVerificationExpiresAt,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, email, password, created_at, updated_at, deleted_at FROM users
+SELECT id, username, email, password, created_at, updated_at, deleted_at, is_admin, bio, avatar_key, banner_key, pending_email, email_verification_token_hash, email_verification_expires_at FROM users
 WHERE email = $1 AND deleted_at IS NULL
 LIMIT 1
 `
@@ -59,12 +97,19 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.Bio,
+		&i.AvatarKey,
+		&i.BannerKey,
+		&i.PendingEmail,
+		&i.EmailVerificationTokenHash,
+		&i.EmailVerificationExpiresAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, username, email, password, created_at, updated_at, deleted_at FROM users
+SELECT id, username, email, password, created_at, updated_at, deleted_at, is_admin, bio, avatar_key, banner_key, pending_email, email_verification_token_hash, email_verification_expires_at FROM users
 WHERE id = $1 AND deleted_at IS NULL
 LIMIT 1
 `
@@ -80,12 +125,19 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.Bio,
+		&i.AvatarKey,
+		&i.BannerKey,
+		&i.PendingEmail,
+		&i.EmailVerificationTokenHash,
+		&i.EmailVerificationExpiresAt,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, email, password, created_at, updated_at, deleted_at FROM users
+SELECT id, username, email, password, created_at, updated_at, deleted_at, is_admin, bio, avatar_key, banner_key, pending_email, email_verification_token_hash, email_verification_expires_at FROM users
 WHERE username = $1 AND deleted_at IS NULL
 LIMIT 1
 `
@@ -101,6 +153,185 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.Bio,
+		&i.AvatarKey,
+		&i.BannerKey,
+		&i.PendingEmail,
+		&i.EmailVerificationTokenHash,
+		&i.EmailVerificationExpiresAt,
+	)
+	return i, err
+}
+
+const getUserForUpdate = `-- name: GetUserForUpdate :one
+SELECT id, username, email, password, created_at, updated_at, deleted_at, is_admin, bio, avatar_key, banner_key, pending_email, email_verification_token_hash, email_verification_expires_at FROM users
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetUserForUpdate(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, getUserForUpdate, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.Bio,
+		&i.AvatarKey,
+		&i.BannerKey,
+		&i.PendingEmail,
+		&i.EmailVerificationTokenHash,
+		&i.EmailVerificationExpiresAt,
+	)
+	return i, err
+}
+
+const setPendingEmail = `-- name: SetPendingEmail :one
+UPDATE users
+SET pending_email = $2, email_verification_token_hash = $3, email_verification_expires_at = $4, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, username, email, password, created_at, updated_at, deleted_at, is_admin, bio, avatar_key, banner_key, pending_email, email_verification_token_hash, email_verification_expires_at
+`
+
+type SetPendingEmailParams struct {
+	ID                         uuid.UUID  `json:"id"`
+	PendingEmail               *string    `json:"pending_email"`
+	EmailVerificationTokenHash *string    `json:"-"`
+	EmailVerificationExpiresAt *time.Time `json:"email_verification_expires_at"`
+}
+
+func (q *Queries) SetPendingEmail(ctx context.Context, arg SetPendingEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, setPendingEmail,
+		arg.ID,
+		arg.PendingEmail,
+		arg.EmailVerificationTokenHash,
+		arg.EmailVerificationExpiresAt,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.Bio,
+		&i.AvatarKey,
+		&i.BannerKey,
+		&i.PendingEmail,
+		&i.EmailVerificationTokenHash,
+		&i.EmailVerificationExpiresAt,
+	)
+	return i, err
+}
+
+const setUserAvatar = `-- name: SetUserAvatar :one
+UPDATE users
+SET avatar_key = $2, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, username, email, password, created_at, updated_at, deleted_at, is_admin, bio, avatar_key, banner_key, pending_email, email_verification_token_hash, email_verification_expires_at
+`
+
+type SetUserAvatarParams struct {
+	ID        uuid.UUID `json:"id"`
+	AvatarKey *string   `json:"avatar_key"`
+}
+
+func (q *Queries) SetUserAvatar(ctx context.Context, arg SetUserAvatarParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserAvatar, arg.ID, arg.AvatarKey)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.Bio,
+		&i.AvatarKey,
+		&i.BannerKey,
+		&i.PendingEmail,
+		&i.EmailVerificationTokenHash,
+		&i.EmailVerificationExpiresAt,
+	)
+	return i, err
+}
+
+const setUserBanner = `-- name: SetUserBanner :one
+UPDATE users
+SET banner_key = $2, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, username, email, password, created_at, updated_at, deleted_at, is_admin, bio, avatar_key, banner_key, pending_email, email_verification_token_hash, email_verification_expires_at
+`
+
+type SetUserBannerParams struct {
+	ID        uuid.UUID `json:"id"`
+	BannerKey *string   `json:"banner_key"`
+}
+
+func (q *Queries) SetUserBanner(ctx context.Context, arg SetUserBannerParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserBanner, arg.ID, arg.BannerKey)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.Bio,
+		&i.AvatarKey,
+		&i.BannerKey,
+		&i.PendingEmail,
+		&i.EmailVerificationTokenHash,
+		&i.EmailVerificationExpiresAt,
+	)
+	return i, err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET username = $2, bio = $3, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, username, email, password, created_at, updated_at, deleted_at, is_admin, bio, avatar_key, banner_key, pending_email, email_verification_token_hash, email_verification_expires_at
+`
+
+type UpdateUserProfileParams struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	Bio      *string   `json:"bio"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile, arg.ID, arg.Username, arg.Bio)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.Bio,
+		&i.AvatarKey,
+		&i.BannerKey,
+		&i.PendingEmail,
+		&i.EmailVerificationTokenHash,
+		&i.EmailVerificationExpiresAt,
 	)
 	return i, err
 }
@@ -6,31 +6,61 @@ import (
 
 	"github.com/itsDrac/e-auc/internal/cache"
 	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
 	"github.com/itsDrac/e-auc/internal/handlers"
+	"github.com/itsDrac/e-auc/internal/jobs"
+	"github.com/itsDrac/e-auc/internal/leader"
+	"github.com/itsDrac/e-auc/internal/mailer"
+	"github.com/itsDrac/e-auc/internal/payments"
 	"github.com/itsDrac/e-auc/internal/service"
 	"github.com/itsDrac/e-auc/internal/storage"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Dependencies holds all the intialized instances required by the application.
 type Dependencies struct {
-	Services       *service.Services
-	Conn           *pgx.Conn
-	Cache          cache.Cacher
-	UserHandler    *handlers.UserHandler
-	ProductHandler *handlers.ProductHandler
+	Services            *service.Services
+	Conn                *pgxpool.Pool
+	Cache               cache.Cacher
+	Mailer              mailer.Mailer
+	UserHandler         *handlers.UserHandler
+	ProductHandler      *handlers.ProductHandler
+	WebhookHandler      *handlers.WebhookHandler
+	AdminHandler        *handlers.AdminHandler
+	OrderHandler        *handlers.OrderHandler
+	SecondChanceHandler *handlers.SecondChanceHandler
+	OfferHandler        *handlers.OfferHandler
+	AccessHandler       *handlers.AccessHandler
+	WalletHandler       *handlers.WalletHandler
+	InvoiceHandler      *handlers.InvoiceHandler
+	AddressHandler      *handlers.AddressHandler
+	FeedbackHandler     *handlers.FeedbackHandler
+	MessageHandler      *handlers.MessageHandler
+	Bus                 *events.Bus
+	OutboxRelay         *service.OutboxRelay
+	Jobs                *jobs.Queue
+	Elector             *leader.Elector
+	// Workers are started and stopped together with the HTTP server.
+	Workers []service.Worker
 }
 
 // NewDependencies connects to DB, and wires up all services
 func NewDependencies(ctx context.Context, dbDsn string) (*Dependencies, error) {
 
-	conn, err := pgx.Connect(ctx, dbDsn)
+	// A pool is required because background workers share the database with request handlers.
+	conn, err := pgxpool.New(ctx, dbDsn)
 	if err != nil {
 		slog.Error("[DB] connection failed -> ", "error", err.Error())
 		return nil, err
 	}
+	if err := conn.Ping(ctx); err != nil {
+		slog.Error("[DB] ping failed -> ", "error", err.Error())
+		return nil, err
+	}
 
-	querier := db.New(conn)
+	store := db.NewStore(conn)
+	bus := events.NewBus()
+	queue := jobs.NewQueue(store)
 
 	storage, err := storage.NewMinioStorage()
 	if err != nil {
@@ -38,9 +68,15 @@ func NewDependencies(ctx context.Context, dbDsn string) (*Dependencies, error) {
 		return nil, err
 	}
 
-	services, err := service.NewServices(querier, storage)
+	provider, err := payments.NewPaymentProvider()
 	if err != nil {
-		slog.Error("[Service] failed to initialized -> ", "error", err.Error())
+		slog.Error("[Payments] failed to initialize -> ", "error", err.Error())
+		return nil, err
+	}
+
+	mailer, err := mailer.NewMailer()
+	if err != nil {
+		slog.Error("[Mailer] failed to initialize -> ", "error", err.Error())
 		return nil, err
 	}
 
@@ -56,26 +92,125 @@ func NewDependencies(ctx context.Context, dbDsn string) (*Dependencies, error) {
 		slog.Info("[Cache] connected")
 	}
 
+	services, err := service.NewServices(store, storage, provider, bus, queue, cache, mailer)
+	if err != nil {
+		slog.Error("[Service] failed to initialized -> ", "error", err.Error())
+		return nil, err
+	}
+
 	userHandler, err := handlers.NewUserHandler(services.UserService, services.AuthService, cache)
 	if err != nil {
 		slog.Error("[User Handler] failed to initialized -> ", "error", err.Error())
 		return nil, err
 	}
 
-	productHandler, err := handlers.NewProductHandler(services.ProductService, cache)
+	productHandler, err := handlers.NewProductHandler(services.ProductService, services.FeedbackService, cache)
 	if err != nil {
 		slog.Error("[Product Handler] failed to initialized -> ", "error", err.Error())
 		return nil, err
 	}
 
-	
+	webhookHandler, err := handlers.NewWebhookHandler(services.WebhookService)
+	if err != nil {
+		slog.Error("[Webhook Handler] failed to initialized -> ", "error", err.Error())
+		return nil, err
+	}
+
+	adminHandler, err := handlers.NewAdminHandler(services.AdminService)
+	if err != nil {
+		slog.Error("[Admin Handler] failed to initialized -> ", "error", err.Error())
+		return nil, err
+	}
+
+	orderHandler, err := handlers.NewOrderHandler(services.OrderService)
+	if err != nil {
+		slog.Error("[Order Handler] failed to initialized -> ", "error", err.Error())
+		return nil, err
+	}
+
+	secondChanceHandler, err := handlers.NewSecondChanceHandler(services.SecondChanceService)
+	if err != nil {
+		slog.Error("[Second Chance Handler] failed to initialized -> ", "error", err.Error())
+		return nil, err
+	}
+
+	offerHandler, err := handlers.NewOfferHandler(services.OfferService)
+	if err != nil {
+		slog.Error("[Offer Handler] failed to initialized -> ", "error", err.Error())
+		return nil, err
+	}
+
+	accessHandler, err := handlers.NewAccessHandler(services.AccessService)
+	if err != nil {
+		slog.Error("[Access Handler] failed to initialized -> ", "error", err.Error())
+		return nil, err
+	}
+
+	walletHandler, err := handlers.NewWalletHandler(services.WalletService)
+	if err != nil {
+		slog.Error("[Wallet Handler] failed to initialized -> ", "error", err.Error())
+		return nil, err
+	}
+
+	invoiceHandler, err := handlers.NewInvoiceHandler(services.InvoiceService)
+	if err != nil {
+		slog.Error("[Invoice Handler] failed to initialized -> ", "error", err.Error())
+		return nil, err
+	}
+
+	addressHandler, err := handlers.NewAddressHandler(services.AddressService)
+	if err != nil {
+		slog.Error("[Address Handler] failed to initialized -> ", "error", err.Error())
+		return nil, err
+	}
+
+	feedbackHandler, err := handlers.NewFeedbackHandler(services.FeedbackService)
+	if err != nil {
+		slog.Error("[Feedback Handler] failed to initialized -> ", "error", err.Error())
+		return nil, err
+	}
+
+	messageHandler, err := handlers.NewMessageHandler(services.MessageService, cache)
+	if err != nil {
+		slog.Error("[Message Handler] failed to initialized -> ", "error", err.Error())
+		return nil, err
+	}
+
+	outboxRelay := service.NewOutboxRelay(store, bus, cache)
+	elector := leader.NewElector(cache, "workers", leader.DefaultTTL)
+
+	// Singleton workers only run on the replica that currently holds the lease. The job queue is one of them,
+	// so recurring jobs like settlement and expiries never run on two replicas at once
+	workers := []service.Worker{
+		elector,
+		leader.NewSingleton("outbox-relay", elector, outboxRelay),
+		leader.NewSingleton("jobs", elector, queue),
+		service.NewWebhookDispatcher(services.WebhookService),
+	}
 
 	return &Dependencies{
-		Services:       services,
-		Conn:           conn,
-		Cache:          cache,
-		ProductHandler: productHandler,
-		UserHandler:    userHandler,
+		Services:            services,
+		Conn:                conn,
+		Cache:               cache,
+		Mailer:              mailer,
+		ProductHandler:      productHandler,
+		UserHandler:         userHandler,
+		WebhookHandler:      webhookHandler,
+		AdminHandler:        adminHandler,
+		OrderHandler:        orderHandler,
+		SecondChanceHandler: secondChanceHandler,
+		OfferHandler:        offerHandler,
+		AccessHandler:       accessHandler,
+		WalletHandler:       walletHandler,
+		InvoiceHandler:      invoiceHandler,
+		AddressHandler:      addressHandler,
+		FeedbackHandler:     feedbackHandler,
+		MessageHandler:      messageHandler,
+		Bus:                 bus,
+		OutboxRelay:         outboxRelay,
+		Jobs:                queue,
+		Elector:             elector,
+		Workers:             workers,
 	}, nil
 
 }
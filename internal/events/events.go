@@ -0,0 +1,113 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain event types emitted by the service layer.
+const (
+	BidPlaced     = "bid.placed"
+	AuctionClosed = "auction.closed"
+	ItemSold      = "item.sold"
+	// BidsRetracted is emitted when a bidder retracts a bid or the seller cancels the bids of a bidder.
+	BidsRetracted = "bid.retracted"
+	// AuctionExtended is emitted when a late bid pushes out the end of an auction (soft close).
+	AuctionExtended = "auction.extended"
+	// AuctionPriceDropped is emitted when a dutch auction lowers its price.
+	AuctionPriceDropped = "auction.price_dropped"
+	// AuctionPriceUpdated is emitted when retracted or cancelled bids change the current price, for the live feed.
+	AuctionPriceUpdated = "auction.price_updated"
+	// AuctionStarted is emitted when a listing goes live, when published, at its scheduled start or as a relist.
+	AuctionStarted = "auction.started"
+	// SecondChanceOffered is emitted on the second-chance offer when an unsold item is offered to a runner-up bidder.
+	SecondChanceOffered = "second_chance.offered"
+	// OfferMade is emitted on the offer when a buyer makes one on a fixed-price listing or either party counters one.
+	OfferMade = "offer.made"
+	// OfferAnswered is emitted on the offer when it is accepted, declined, countered or expires.
+	OfferAnswered = "offer.answered"
+	// InvoiceCreated is emitted on the invoice of every order.
+	InvoiceCreated = "invoice.created"
+	// InvoicePaid is emitted on the invoice when the buyer pays it.
+	InvoicePaid = "invoice.paid"
+	// InvoiceExpired is emitted on the invoice when it was not paid by its deadline.
+	InvoiceExpired = "invoice.expired"
+	// OrderShipped is emitted on the order when the seller ships it.
+	OrderShipped = "order.shipped"
+	// OrderDelivered is emitted on the order when it is delivered or handed over at pickup.
+	OrderDelivered = "order.delivered"
+	// MessageSent is emitted on a conversation when a message reaches the recipient, as sent or once approved.
+	MessageSent = "message.sent"
+	// ConversationRead is emitted on a conversation when a party marks it as read.
+	ConversationRead = "conversation.read"
+)
+
+// Event is a domain event as stored in the outbox and published to subscribers.
+// ID doubles as the idempotency key, consumers must expect to see the same ID more than once.
+type Event struct {
+	ID          uuid.UUID       `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID uuid.UUID       `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+}
+
+// New builds an event with a fresh ID and data encoded as its payload.
+func New(eventType string, aggregateID uuid.UUID, data any) (Event, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	return Event{
+		ID:          uuid.New(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     payload,
+		OccurredAt:  time.Now().UTC(),
+	}, nil
+}
+
+// Handler consumes a published event. Returning an error makes the publisher retry the event later.
+type Handler func(ctx context.Context, e Event) error
+
+// Bus is an in-process publish/subscribe hub.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Subscribe registers h for every event of the given types.
+func (b *Bus) Subscribe(h Handler, eventTypes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, t := range eventTypes {
+		b.handlers[t] = append(b.handlers[t], h)
+	}
+}
+
+// Publish calls every subscribed handler synchronously and returns their joined errors.
+func (b *Bus) Publish(ctx context.Context, e Event) error {
+	b.mu.RLock()
+	handlers := b.handlers[e.Type]
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		if err := h(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,192 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Every product event payload carries the seller so consumers such as webhooks can route it.
+// For reverse auctions seller_id is the owner of the listing, which is the buyer, except in ItemSoldData.
+
+// BidPlacedData is the payload of a BidPlaced event.
+type BidPlacedData struct {
+	ProductID  uuid.UUID `json:"product_id"`
+	SellerID   uuid.UUID `json:"seller_id"`
+	BidderID   uuid.UUID `json:"bidder_id"`
+	BidAmount  int64     `json:"bid_amount"`
+	Quantity   int32     `json:"quantity"`
+	NextMinBid *int64    `json:"next_min_bid,omitempty"`
+	NextMaxBid *int64    `json:"next_max_bid,omitempty"`
+	EndsAt     time.Time `json:"ends_at"`
+}
+
+// AuctionExtendedData is the payload of an AuctionExtended event.
+type AuctionExtendedData struct {
+	ProductID      uuid.UUID `json:"product_id"`
+	SellerID       uuid.UUID `json:"seller_id"`
+	EndsAt         time.Time `json:"ends_at"`
+	ExtensionCount int32     `json:"extension_count"`
+}
+
+// BidsRetractedData is the payload of a BidsRetracted event. RetractedBy is the bidder or the seller,
+// CurrentPrice and LeadingBidderID are recomputed from the bids left. LeadingBidderID is nil when no
+// bid is left or the amounts are sealed.
+type BidsRetractedData struct {
+	ProductID       uuid.UUID   `json:"product_id"`
+	SellerID        uuid.UUID   `json:"seller_id"`
+	BidderID        uuid.UUID   `json:"bidder_id"`
+	BidIDs          []uuid.UUID `json:"bid_ids"`
+	RetractedBy     uuid.UUID   `json:"retracted_by"`
+	Reason          *string     `json:"reason,omitempty"`
+	CurrentPrice    int64       `json:"current_price"`
+	LeadingBidderID *uuid.UUID  `json:"leading_bidder_id"`
+}
+
+// AuctionPriceDroppedData is the payload of an AuctionPriceDropped event.
+// NextDropAt is nil once the price reached the floor.
+type AuctionPriceDroppedData struct {
+	ProductID  uuid.UUID  `json:"product_id"`
+	SellerID   uuid.UUID  `json:"seller_id"`
+	Price      int64      `json:"price"`
+	NextDropAt *time.Time `json:"next_drop_at"`
+}
+
+// AuctionPriceUpdatedData is the payload of an AuctionPriceUpdated event. It is public, unlike BidsRetractedData
+// it names neither the bidders nor the reason.
+type AuctionPriceUpdatedData struct {
+	ProductID uuid.UUID `json:"product_id"`
+	SellerID  uuid.UUID `json:"seller_id"`
+	Price     int64     `json:"price"`
+}
+
+// AuctionStartedData is the payload of an AuctionStarted event. RelistedFrom is the earlier auction of a relisted item.
+type AuctionStartedData struct {
+	ProductID    uuid.UUID  `json:"product_id"`
+	SellerID     uuid.UUID  `json:"seller_id"`
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       time.Time  `json:"ends_at"`
+	RelistedFrom *uuid.UUID `json:"relisted_from,omitempty"`
+}
+
+// SealedBidPlacedData is the payload of a BidPlaced event on a sealed-bid auction, it never carries the amount.
+// Revised is set when the bidder replaced their earlier bid.
+type SealedBidPlacedData struct {
+	ProductID uuid.UUID `json:"product_id"`
+	SellerID  uuid.UUID `json:"seller_id"`
+	BidderID  uuid.UUID `json:"bidder_id"`
+	Revised   bool      `json:"revised"`
+	EndsAt    time.Time `json:"ends_at"`
+}
+
+// AuctionClosedData is the payload of an AuctionClosed event. WinnerID is nil and Price is 0 when nothing was sold.
+// WinnerID is also nil when a multi-unit product was split between several winners, Winners lists all of them
+// and Price is the lowest unit price sold.
+type AuctionClosedData struct {
+	ProductID uuid.UUID       `json:"product_id"`
+	SellerID  uuid.UUID       `json:"seller_id"`
+	WinnerID  *uuid.UUID      `json:"winner_id"`
+	Winners   []AuctionWinner `json:"winners,omitempty"`
+	Price     int64           `json:"price"`
+	Reason    string          `json:"reason"`
+}
+
+// AuctionWinner is one winner of a closed auction and the units they won.
+type AuctionWinner struct {
+	BidderID  uuid.UUID `json:"bidder_id"`
+	Quantity  int32     `json:"quantity"`
+	UnitPrice int64     `json:"unit_price"`
+}
+
+// ItemSoldData is the payload of an ItemSold event, one per order. SellerID and BuyerID are the parties of the trade,
+// on a reverse auction the seller is the winning bidder. Price is the total of the order.
+type ItemSoldData struct {
+	ProductID uuid.UUID `json:"product_id"`
+	OrderID   uuid.UUID `json:"order_id"`
+	SellerID  uuid.UUID `json:"seller_id"`
+	BuyerID   uuid.UUID `json:"buyer_id"`
+	Quantity  int32     `json:"quantity"`
+	Price     int64     `json:"price"`
+}
+
+// SecondChanceOfferedData is the payload of a SecondChanceOffered event, Price is the bidder's own bid.
+type SecondChanceOfferedData struct {
+	OfferID   uuid.UUID `json:"offer_id"`
+	ProductID uuid.UUID `json:"product_id"`
+	SellerID  uuid.UUID `json:"seller_id"`
+	BidderID  uuid.UUID `json:"bidder_id"`
+	Price     int64     `json:"price"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// OfferMadeData is the payload of an OfferMade event. MadeBy is buyer or seller and
+// ParentID is the offer a counter-offer answers.
+type OfferMadeData struct {
+	OfferID   uuid.UUID  `json:"offer_id"`
+	ProductID uuid.UUID  `json:"product_id"`
+	SellerID  uuid.UUID  `json:"seller_id"`
+	BuyerID   uuid.UUID  `json:"buyer_id"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty"`
+	MadeBy    string     `json:"made_by"`
+	Price     int64      `json:"price"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+// OfferAnsweredData is the payload of an OfferAnswered event.
+type OfferAnsweredData struct {
+	OfferID   uuid.UUID `json:"offer_id"`
+	ProductID uuid.UUID `json:"product_id"`
+	SellerID  uuid.UUID `json:"seller_id"`
+	BuyerID   uuid.UUID `json:"buyer_id"`
+	Price     int64     `json:"price"`
+	Status    string    `json:"status"`
+}
+
+// InvoiceData is the payload of the InvoiceCreated, InvoicePaid and InvoiceExpired events.
+// AmountDue is what the buyer pays after the deposit captured when they won.
+type InvoiceData struct {
+	InvoiceID uuid.UUID `json:"invoice_id"`
+	OrderID   uuid.UUID `json:"order_id"`
+	ProductID uuid.UUID `json:"product_id"`
+	SellerID  uuid.UUID `json:"seller_id"`
+	BuyerID   uuid.UUID `json:"buyer_id"`
+	Currency  string    `json:"currency"`
+	Total     int64     `json:"total"`
+	AmountDue int64     `json:"amount_due"`
+	Status    string    `json:"status"`
+	DueAt     time.Time `json:"due_at"`
+}
+
+// OrderFulfilmentData is the payload of the OrderShipped and OrderDelivered events.
+// Carrier and TrackingNumber are set once the order shipped, pickup orders have neither.
+type OrderFulfilmentData struct {
+	OrderID          uuid.UUID `json:"order_id"`
+	ProductID        uuid.UUID `json:"product_id"`
+	SellerID         uuid.UUID `json:"seller_id"`
+	BuyerID          uuid.UUID `json:"buyer_id"`
+	ShippingMethod   string    `json:"shipping_method"`
+	FulfilmentStatus string    `json:"fulfilment_status"`
+	Carrier          *string   `json:"carrier,omitempty"`
+	TrackingNumber   *string   `json:"tracking_number,omitempty"`
+}
+
+// MessageSentData is the payload of a MessageSent event. OrderID is nil for threads about a listing.
+// AttachmentKey is the storage key of an attached image, clients fetch its URL with the message.
+type MessageSentData struct {
+	MessageID      uuid.UUID  `json:"message_id"`
+	ConversationID uuid.UUID  `json:"conversation_id"`
+	ProductID      uuid.UUID  `json:"product_id"`
+	OrderID        *uuid.UUID `json:"order_id,omitempty"`
+	SenderID       uuid.UUID  `json:"sender_id"`
+	RecipientID    uuid.UUID  `json:"recipient_id"`
+	Body           string     `json:"body"`
+	AttachmentKey  *string    `json:"attachment_key,omitempty"`
+	SentAt         time.Time  `json:"sent_at"`
+}
+
+// ConversationReadData is the payload of a ConversationRead event.
+type ConversationReadData struct {
+	ConversationID uuid.UUID `json:"conversation_id"`
+	ReaderID       uuid.UUID `json:"reader_id"`
+	ReadAt         time.Time `json:"read_at"`
+}
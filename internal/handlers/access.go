@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/itsDrac/e-auc/internal/model"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+const userParamKey string = "userId"
+
+type AccessHandler struct {
+	svc service.AccessServicer
+}
+
+func NewAccessHandler(svc service.AccessServicer) (*AccessHandler, error) {
+	return &AccessHandler{
+		svc: svc,
+	}, nil
+}
+
+// BlockBidder godoc
+//
+//	@Summary		Block a Bidder
+//	@Description	Refuse a bidder on all of your listings. Blocked bidders cannot bid, buy now or make offers, bids placed before the block stay until you cancel them.
+//	@Tags			Blocked Bidders
+//	@Accept			json
+//	@Produce		json
+//	@Param			block	body		model.BlockBidderRequest	true	"Bidder to block"
+//	@Success		201		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Router			/users/me/blocked-bidders [post]
+func (h *AccessHandler) BlockBidder(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.BlockBidderRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	blocked, err := h.svc.BlockBidder(r.Context(), claims.UserID, req.BidderID, req.Reason)
+	if err != nil {
+		respondAccessError(w, r, err, "bidder_id", req.BidderID)
+		return
+	}
+
+	resp := map[string]any{
+		"blocked_bidder": blocked,
+	}
+	RespondSuccessJSON(w, r, http.StatusCreated, "Bidder blocked successfully", resp)
+}
+
+// ListBlockedBidders godoc
+//
+//	@Summary		List blocked Bidders
+//	@Description	List the bidders you blocked, most recent first
+//	@Tags			Blocked Bidders
+//	@Produce		json
+//	@Param			limit	query		int	false	"Number of blocked bidders to return"
+//	@Param			offset	query		int	false	"Number of blocked bidders to skip"
+//	@Success		200		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Router			/users/me/blocked-bidders [get]
+func (h *AccessHandler) ListBlockedBidders(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	limit, offset := paginationParams(r)
+
+	blocked, err := h.svc.GetBlockedBidders(r.Context(), claims.UserID, limit, offset)
+	if err != nil {
+		slog.Error("[DB] failed to fetch blocked bidders", "seller_id", claims.UserID, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve blocked bidders", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"blocked_bidders": blocked,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Blocked bidders fetched successfully", resp)
+}
+
+// UnblockBidder godoc
+//
+//	@Summary		Unblock a Bidder
+//	@Description	Let a blocked bidder bid on your listings again
+//	@Tags			Blocked Bidders
+//	@Produce		json
+//	@Param			bidderId	path		string	true	"Bidder ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Router			/users/me/blocked-bidders/{bidderId} [delete]
+func (h *AccessHandler) UnblockBidder(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	bidderId := chi.URLParam(r, bidderParamKey)
+	if err := h.svc.UnblockBidder(r.Context(), claims.UserID, bidderId); err != nil {
+		respondAccessError(w, r, err, "bidder_id", bidderId)
+		return
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Bidder unblocked successfully", "")
+}
+
+// InviteUser godoc
+//
+//	@Summary		Invite a User to a private listing
+//	@Description	Let a user see and bid on your private listing. Inviting a user twice is a no-op.
+//	@Tags			Private Listings
+//	@Accept			json
+//	@Produce		json
+//	@Param			productId	path		string					true	"Product ID"
+//	@Param			invitation	body		model.InviteUserRequest	true	"User to invite"
+//	@Success		201			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/invitations [post]
+func (h *AccessHandler) InviteUser(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.InviteUserRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	invitation, err := h.svc.InviteUser(r.Context(), claims.UserID, productId, req.UserID)
+	if err != nil {
+		respondAccessError(w, r, err, "product_id", productId)
+		return
+	}
+
+	resp := map[string]any{
+		"invitation": invitation,
+	}
+	RespondSuccessJSON(w, r, http.StatusCreated, "User invited successfully", resp)
+}
+
+// ListInvitations godoc
+//
+//	@Summary		List the invitations of a private listing
+//	@Description	List the users invited to your private listing, including those who redeemed its access code
+//	@Tags			Private Listings
+//	@Produce		json
+//	@Param			productId	path		string	true	"Product ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/invitations [get]
+func (h *AccessHandler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	invitations, err := h.svc.GetInvitations(r.Context(), claims.UserID, productId)
+	if err != nil {
+		respondAccessError(w, r, err, "product_id", productId)
+		return
+	}
+
+	resp := map[string]any{
+		"invitations": invitations,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Invitations fetched successfully", resp)
+}
+
+// RemoveInvitation godoc
+//
+//	@Summary		Remove an invitation to a private listing
+//	@Description	Take a user off the invitation list of your private listing. Their bids stay valid.
+//	@Tags			Private Listings
+//	@Produce		json
+//	@Param			productId	path		string	true	"Product ID"
+//	@Param			userId		path		string	true	"User ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/invitations/{userId} [delete]
+func (h *AccessHandler) RemoveInvitation(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	userId := chi.URLParam(r, userParamKey)
+	if err := h.svc.RemoveInvitation(r.Context(), claims.UserID, productId, userId); err != nil {
+		respondAccessError(w, r, err, "product_id", productId)
+		return
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Invitation removed successfully", "")
+}
+
+// GetAccessCode godoc
+//
+//	@Summary		Get the access code of a private listing
+//	@Description	Show the access code to hand out for your private listing
+//	@Tags			Private Listings
+//	@Produce		json
+//	@Param			productId	path		string	true	"Product ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/access-code [get]
+func (h *AccessHandler) GetAccessCode(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	code, err := h.svc.GetAccessCode(r.Context(), claims.UserID, productId)
+	if err != nil {
+		respondAccessError(w, r, err, "product_id", productId)
+		return
+	}
+
+	resp := map[string]any{
+		"access_code": code,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Access code fetched successfully", resp)
+}
+
+// RotateAccessCode godoc
+//
+//	@Summary		Generate a new access code for a private listing
+//	@Description	Give your private listing a new access code, the previous one stops working. Users who already redeemed a code stay invited.
+//	@Tags			Private Listings
+//	@Produce		json
+//	@Param			productId	path		string	true	"Product ID"
+//	@Success		201			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/access-code [post]
+func (h *AccessHandler) RotateAccessCode(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	code, err := h.svc.RotateAccessCode(r.Context(), claims.UserID, productId)
+	if err != nil {
+		respondAccessError(w, r, err, "product_id", productId)
+		return
+	}
+
+	resp := map[string]any{
+		"access_code": code,
+	}
+	RespondSuccessJSON(w, r, http.StatusCreated, "Access code generated successfully", resp)
+}
+
+// RevokeAccessCode godoc
+//
+//	@Summary		Revoke the access code of a private listing
+//	@Description	Stop the access code of your private listing from working, only invited users keep access
+//	@Tags			Private Listings
+//	@Produce		json
+//	@Param			productId	path		string	true	"Product ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/access-code [delete]
+func (h *AccessHandler) RevokeAccessCode(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	if err := h.svc.RevokeAccessCode(r.Context(), claims.UserID, productId); err != nil {
+		respondAccessError(w, r, err, "product_id", productId)
+		return
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Access code revoked successfully", "")
+}
+
+// RedeemAccessCode godoc
+//
+//	@Summary		Redeem the access code of a private listing
+//	@Description	Get invited to a private listing with the access code its seller handed out
+//	@Tags			Private Listings
+//	@Accept			json
+//	@Produce		json
+//	@Param			productId	path		string							true	"Product ID"
+//	@Param			access		body		model.RedeemAccessCodeRequest	true	"Access code"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Router			/products/{productId}/access [post]
+func (h *AccessHandler) RedeemAccessCode(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.RedeemAccessCodeRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	invitation, err := h.svc.RedeemAccessCode(r.Context(), claims.UserID, productId, req.AccessCode)
+	if err != nil {
+		respondAccessError(w, r, err, "product_id", productId)
+		return
+	}
+
+	resp := map[string]any{
+		"invitation": invitation,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Access code redeemed successfully", resp)
+}
+
+func respondAccessError(w http.ResponseWriter, r *http.Request, err error, idKey string, id string) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+	case errors.Is(err, service.ErrUserNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrUserNotFound.Error(), "User not found", nil)
+	case errors.Is(err, service.ErrBlockNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrBlockNotFound.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrInvitationNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrInvitationNotFound.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrAccessCodeNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrAccessCodeNotFound.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrNotProductOwner):
+		RespondErrorJSON(w, r, http.StatusForbidden, ErrNotProductOwner.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidAccessCode):
+		RespondErrorJSON(w, r, http.StatusForbidden, ErrInvalidAccessCode.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrSelfBlocking):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrSelfBlocking.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrSelfInvitation):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrSelfInvitation.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrProductNotPrivate):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrProductNotPrivate.Error(), err.Error(), nil)
+	default:
+		slog.Error("[DB] failed to manage listing access", idKey, id, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+	}
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/model"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+const addressParamKey string = "addressId"
+
+type AddressHandler struct {
+	svc service.AddressServicer
+}
+
+func NewAddressHandler(svc service.AddressServicer) (*AddressHandler, error) {
+	return &AddressHandler{
+		svc: svc,
+	}, nil
+}
+
+// ListAddresses godoc
+//
+//	@Summary		List your Addresses
+//	@Description	List your address book, the default address first. Orders ship to the default address unless you choose another one.
+//	@Tags			Addresses
+//	@Produce		json
+//	@Success		200	{object}	map[string]any
+//	@Failure		401	{object}	map[string]any
+//	@Router			/users/me/addresses [get]
+func (h *AddressHandler) ListAddresses(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	addresses, err := h.svc.GetAddresses(r.Context(), claims.UserID)
+	if err != nil {
+		slog.Error("[DB] failed to fetch addresses", "user_id", claims.UserID, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve addresses", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"addresses": addresses,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Addresses fetched successfully", resp)
+}
+
+// CreateAddress godoc
+//
+//	@Summary		Add an Address
+//	@Description	Add an address to your address book. Your first address, or one marked is_default, becomes your default address.
+//	@Tags			Addresses
+//	@Accept			json
+//	@Produce		json
+//	@Param			address	body		model.AddressRequest	true	"Address"
+//	@Success		201		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Router			/users/me/addresses [post]
+func (h *AddressHandler) CreateAddress(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.AddressRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	address, err := h.svc.CreateAddress(r.Context(), claims.UserID, toAddress(req))
+	if err != nil {
+		respondAddressError(w, r, err, "")
+		return
+	}
+
+	resp := map[string]any{
+		"address": address,
+	}
+	RespondSuccessJSON(w, r, http.StatusCreated, "Address created successfully", resp)
+}
+
+// UpdateAddress godoc
+//
+//	@Summary		Update an Address
+//	@Description	Replace an address of your address book. Orders keep the address they were shipping to.
+//	@Tags			Addresses
+//	@Accept			json
+//	@Produce		json
+//	@Param			addressId	path		string					true	"Address ID"
+//	@Param			address		body		model.AddressRequest	true	"Address"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Router			/users/me/addresses/{addressId} [put]
+func (h *AddressHandler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.AddressRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	addressId := chi.URLParam(r, addressParamKey)
+	address, err := h.svc.UpdateAddress(r.Context(), claims.UserID, addressId, toAddress(req))
+	if err != nil {
+		respondAddressError(w, r, err, addressId)
+		return
+	}
+
+	resp := map[string]any{
+		"address": address,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Address updated successfully", resp)
+}
+
+// DeleteAddress godoc
+//
+//	@Summary		Delete an Address
+//	@Description	Remove an address from your address book. When it was your default address, your oldest remaining address becomes the default.
+//	@Tags			Addresses
+//	@Produce		json
+//	@Param			addressId	path		string	true	"Address ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Router			/users/me/addresses/{addressId} [delete]
+func (h *AddressHandler) DeleteAddress(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	addressId := chi.URLParam(r, addressParamKey)
+	if err := h.svc.DeleteAddress(r.Context(), claims.UserID, addressId); err != nil {
+		respondAddressError(w, r, err, addressId)
+		return
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Address deleted successfully", "")
+}
+
+func toAddress(req model.AddressRequest) db.Address {
+	return db.Address{
+		Name:       req.Name,
+		Line1:      req.Line1,
+		Line2:      req.Line2,
+		City:       req.City,
+		State:      req.State,
+		PostalCode: req.PostalCode,
+		Country:    req.Country,
+		IsDefault:  req.IsDefault,
+	}
+}
+
+func respondAddressError(w http.ResponseWriter, r *http.Request, err error, addressId string) {
+	switch {
+	case errors.Is(err, service.ErrAddressNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrAddressNotFound.Error(), "Address not found", nil)
+	case errors.Is(err, service.ErrInvalidAddress):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidAddress.Error(), err.Error(), nil)
+	default:
+		slog.Error("[DB] failed to manage address", "address_id", addressId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+	}
+}
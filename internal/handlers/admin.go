@@ -0,0 +1,479 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/itsDrac/e-auc/internal/model"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+const (
+	jobParamKey      string = "jobId"
+	categoryParamKey string = "category"
+	feeKindParamKey  string = "kind"
+)
+
+// feeRevenueDateLayout is the layout of dates accepted by the fee revenue export next to RFC 3339.
+const feeRevenueDateLayout string = "2006-01-02"
+
+type AdminHandler struct {
+	svc service.AdminServicer
+}
+
+func NewAdminHandler(svc service.AdminServicer) (*AdminHandler, error) {
+	return &AdminHandler{
+		svc: svc,
+	}, nil
+}
+
+// ListJobs godoc
+//
+//	@Summary		List background jobs
+//	@Description	List the most recent background jobs, optionally filtered by status and kind. Admin only.
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			status	query		string	false	"Job status (pending, running, succeeded, cancelled, dead)"
+//	@Param			kind	query		string	false	"Job kind"
+//	@Param			limit	query		int		false	"Number of jobs to return"
+//	@Param			offset	query		int		false	"Number of jobs to skip"
+//	@Success		200		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Router			/admin/jobs [get]
+func (h *AdminHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	limit, offset := paginationParams(r)
+	status := r.URL.Query().Get("status")
+	kind := r.URL.Query().Get("kind")
+
+	jobs, err := h.svc.ListJobs(r.Context(), status, kind, limit, offset)
+	if err != nil {
+		slog.Error("[DB] failed to fetch jobs", "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve jobs", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"jobs":   jobs,
+		"limit":  limit,
+		"offset": offset,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Jobs fetched successfully", resp)
+}
+
+// GetJob godoc
+//
+//	@Summary		Get a background job
+//	@Description	Get a single background job. Admin only.
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			jobId	path		string	true	"Job ID"
+//	@Success		200		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Router			/admin/jobs/{jobId} [get]
+func (h *AdminHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	jobId := chi.URLParam(r, jobParamKey)
+	job, err := h.svc.GetJob(r.Context(), jobId)
+	if err != nil {
+		h.respondJobError(w, r, jobId, err)
+		return
+	}
+
+	resp := map[string]any{
+		"job": job,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Job fetched successfully", resp)
+}
+
+// RetryJob godoc
+//
+//	@Summary		Retry a background job
+//	@Description	Run a pending, cancelled or dead job again right away with a fresh set of attempts. Admin only.
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			jobId	path		string	true	"Job ID"
+//	@Success		202		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Failure		409		{object}	map[string]any
+//	@Router			/admin/jobs/{jobId}/retry [post]
+func (h *AdminHandler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	jobId := chi.URLParam(r, jobParamKey)
+	job, err := h.svc.RetryJob(r.Context(), jobId)
+	if err != nil {
+		h.respondJobError(w, r, jobId, err)
+		return
+	}
+
+	resp := map[string]any{
+		"job": job,
+	}
+	RespondSuccessJSON(w, r, http.StatusAccepted, "Job queued for retry", resp)
+}
+
+// CancelJob godoc
+//
+//	@Summary		Cancel a background job
+//	@Description	Cancel a job that has not started yet. Admin only.
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			jobId	path		string	true	"Job ID"
+//	@Success		200		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Failure		409		{object}	map[string]any
+//	@Router			/admin/jobs/{jobId}/cancel [post]
+func (h *AdminHandler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	jobId := chi.URLParam(r, jobParamKey)
+	job, err := h.svc.CancelJob(r.Context(), jobId)
+	if err != nil {
+		h.respondJobError(w, r, jobId, err)
+		return
+	}
+
+	resp := map[string]any{
+		"job": job,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Job cancelled", resp)
+}
+
+// ListDeadLetterJobs godoc
+//
+//	@Summary		List dead-lettered jobs
+//	@Description	List jobs that ran out of attempts, most recent first. Admin only.
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			limit	query		int		false	"Number of entries to return"
+//	@Param			offset	query		int		false	"Number of entries to skip"
+//	@Success		200		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Router			/admin/jobs/dead-letter [get]
+func (h *AdminHandler) ListDeadLetterJobs(w http.ResponseWriter, r *http.Request) {
+	limit, offset := paginationParams(r)
+
+	deadLetters, err := h.svc.ListDeadLetterJobs(r.Context(), limit, offset)
+	if err != nil {
+		slog.Error("[DB] failed to fetch dead-letter jobs", "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve dead-letter jobs", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"jobs":   deadLetters,
+		"limit":  limit,
+		"offset": offset,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Dead-letter jobs fetched successfully", resp)
+}
+
+// ListBidIncrements godoc
+//
+//	@Summary		List bid increment ladders
+//	@Description	List the platform and category bid increment ladders. Admin only.
+//	@Tags			Admin
+//	@Produce		json
+//	@Success		200	{object}	map[string]any
+//	@Failure		403	{object}	map[string]any
+//	@Router			/admin/bid-increments [get]
+func (h *AdminHandler) ListBidIncrements(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.svc.GetBidIncrementLadders(r.Context())
+	if err != nil {
+		slog.Error("[DB] failed to fetch bid increment ladders", "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve bid increments", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"rules": rules,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Bid increments fetched successfully", resp)
+}
+
+// SetPlatformBidIncrements godoc
+//
+//	@Summary		Replace the platform bid increment ladder
+//	@Description	Replace the ladder used for products without a product or category ladder. Admin only.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			ladder	body		SetBidIncrementsRequest	true	"Ladder steps, the first must start at 0"
+//	@Success		200		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Router			/admin/bid-increments/platform [put]
+func (h *AdminHandler) SetPlatformBidIncrements(w http.ResponseWriter, r *http.Request) {
+	h.setBidIncrements(w, r, nil)
+}
+
+// SetCategoryBidIncrements godoc
+//
+//	@Summary		Replace a category bid increment ladder
+//	@Description	Replace the ladder used for products of the category that have no ladder of their own. Admin only.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			category	path		string					true	"Category"
+//	@Param			ladder		body		SetBidIncrementsRequest	true	"Ladder steps, the first must start at 0"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Router			/admin/bid-increments/categories/{category} [put]
+func (h *AdminHandler) SetCategoryBidIncrements(w http.ResponseWriter, r *http.Request) {
+	category := chi.URLParam(r, categoryParamKey)
+	h.setBidIncrements(w, r, &category)
+}
+
+func (h *AdminHandler) setBidIncrements(w http.ResponseWriter, r *http.Request, category *string) {
+	var req model.SetBidIncrementsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidJson.Error(), "invalid json format", nil)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		var details []model.ErrorDetails
+		if validErrs, ok := err.(validator.ValidationErrors); ok {
+			for _, vErr := range validErrs {
+				details = append(details, model.ErrorDetails{
+					Field: vErr.Field(),
+					Issue: fmt.Sprintf("failed on tag '%s' with param '%s'", vErr.Tag(), vErr.Param()),
+				})
+			}
+		}
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRequest.Error(), "Input validation failed", details)
+		return
+	}
+
+	steps := make([]service.IncrementStep, 0, len(req.Steps))
+	for _, step := range req.Steps {
+		steps = append(steps, service.IncrementStep{MinPrice: step.MinPrice, Increment: step.Increment})
+	}
+	if err := h.svc.SetBidIncrementLadder(r.Context(), category, steps); err != nil {
+		if errors.Is(err, service.ErrInvalidIncrementLadder) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidIncrementLadder.Error(), err.Error(), nil)
+			return
+		}
+		slog.Error("[DB] failed to set bid increment ladder", "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to update bid increments", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"steps": steps,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Bid increments updated successfully", resp)
+}
+
+// ListFees godoc
+//
+//	@Summary		List fee schedules
+//	@Description	List the platform and category commission and buyer premium schedules. Admin only.
+//	@Tags			Admin
+//	@Produce		json
+//	@Success		200	{object}	map[string]any
+//	@Failure		403	{object}	map[string]any
+//	@Router			/admin/fees [get]
+func (h *AdminHandler) ListFees(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.svc.GetFeeSchedules(r.Context())
+	if err != nil {
+		slog.Error("[DB] failed to fetch fee schedules", "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve fees", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"rules": rules,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Fees fetched successfully", resp)
+}
+
+// SetPlatformFees godoc
+//
+//	@Summary		Replace a platform fee schedule
+//	@Description	Replace the commission or buyer premium schedule used for products whose category has none. Each tier charges its rate on the part of the price inside it. Admin only.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			kind		path		string						true	"commission or buyer_premium"
+//	@Param			schedule	body		model.SetFeeScheduleRequest	true	"Fee tiers, the first must start at 0"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Router			/admin/fees/{kind}/platform [put]
+func (h *AdminHandler) SetPlatformFees(w http.ResponseWriter, r *http.Request) {
+	h.setFees(w, r, nil)
+}
+
+// SetCategoryFees godoc
+//
+//	@Summary		Replace a category fee schedule
+//	@Description	Replace the commission or buyer premium schedule used for products of the category. Each tier charges its rate on the part of the price inside it. Admin only.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			kind		path		string						true	"commission or buyer_premium"
+//	@Param			category	path		string						true	"Category"
+//	@Param			schedule	body		model.SetFeeScheduleRequest	true	"Fee tiers, the first must start at 0"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Router			/admin/fees/{kind}/categories/{category} [put]
+func (h *AdminHandler) SetCategoryFees(w http.ResponseWriter, r *http.Request) {
+	category := chi.URLParam(r, categoryParamKey)
+	h.setFees(w, r, &category)
+}
+
+func (h *AdminHandler) setFees(w http.ResponseWriter, r *http.Request, category *string) {
+	var req model.SetFeeScheduleRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	tiers := make([]service.FeeTier, 0, len(req.Tiers))
+	for _, tier := range req.Tiers {
+		tiers = append(tiers, service.FeeTier{MinPrice: tier.MinPrice, RateBps: tier.RateBps})
+	}
+	if err := h.svc.SetFeeSchedule(r.Context(), chi.URLParam(r, feeKindParamKey), category, tiers); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidFeeKind):
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidFeeKind.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrInvalidFeeSchedule):
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidFeeSchedule.Error(), err.Error(), nil)
+		default:
+			slog.Error("[DB] failed to set fee schedule", "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to update fees", nil)
+		}
+		return
+	}
+
+	resp := map[string]any{
+		"tiers": tiers,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Fees updated successfully", resp)
+}
+
+// ExportFeeRevenue godoc
+//
+//	@Summary		Export fee revenue
+//	@Description	Sum the commission and buyer premium booked per day, week or month, less the fees of invoices that expired unpaid. Returns CSV with format=csv. Admin only.
+//	@Tags			Admin
+//	@Produce		json
+//	@Produce		text/csv
+//	@Param			period	query		string	false	"day, week or month (default)"
+//	@Param			from	query		string	false	"Start date, YYYY-MM-DD or RFC 3339 (default one year before to)"
+//	@Param			to		query		string	false	"End date, exclusive, YYYY-MM-DD or RFC 3339 (default now)"
+//	@Param			format	query		string	false	"json (default) or csv"
+//	@Success		200		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Router			/admin/fees/revenue [get]
+func (h *AdminHandler) ExportFeeRevenue(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	period := query.Get("period")
+	if period == "" {
+		period = "month"
+	}
+	to, err := parseFeeRevenueDate(query.Get("to"), time.Now().UTC())
+	if err != nil {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidFeePeriod.Error(), "to must be a YYYY-MM-DD or RFC 3339 date", nil)
+		return
+	}
+	from, err := parseFeeRevenueDate(query.Get("from"), to.AddDate(-1, 0, 0))
+	if err != nil {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidFeePeriod.Error(), "from must be a YYYY-MM-DD or RFC 3339 date", nil)
+		return
+	}
+
+	revenue, err := h.svc.GetFeeRevenue(r.Context(), period, from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFeePeriod) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidFeePeriod.Error(), err.Error(), nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch fee revenue", "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve fee revenue", nil)
+		return
+	}
+
+	if query.Get("format") == "csv" {
+		writeFeeRevenueCSV(w, revenue)
+		return
+	}
+	resp := map[string]any{
+		"period":  period,
+		"from":    from,
+		"to":      to,
+		"revenue": revenue,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Fee revenue fetched successfully", resp)
+}
+
+// parseFeeRevenueDate parses a date query parameter, fallback is used when it is empty.
+func parseFeeRevenueDate(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	if t, err := time.Parse(feeRevenueDateLayout, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func writeFeeRevenueCSV(w http.ResponseWriter, revenue []service.FeeRevenue) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="fee-revenue.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	out := csv.NewWriter(w)
+	_ = out.Write([]string{"period_start", "commission", "buyer_premium", "reversed", "net", "sales"})
+	for _, row := range revenue {
+		_ = out.Write([]string{
+			row.PeriodStart.Format(feeRevenueDateLayout),
+			strconv.FormatInt(row.Commission, 10),
+			strconv.FormatInt(row.BuyerPremium, 10),
+			strconv.FormatInt(row.Reversed, 10),
+			strconv.FormatInt(row.Net, 10),
+			strconv.FormatInt(row.Sales, 10),
+		})
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		slog.Error("[Admin] failed to write fee revenue export", "error", err)
+	}
+}
+
+func (h *AdminHandler) respondJobError(w http.ResponseWriter, r *http.Request, jobId string, err error) {
+	switch {
+	case errors.Is(err, service.ErrJobNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrJobNotFound.Error(), "Job not found", nil)
+	case errors.Is(err, service.ErrJobNotRetryable):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrJobNotRetryable.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrJobNotCancellable):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrJobNotCancellable.Error(), err.Error(), nil)
+	default:
+		slog.Error("[DB] job operation failed", "job_id", jobId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "Internal server error", nil)
+	}
+}
+
+// paginationParams reads limit and offset from the query string, limit defaults to 20.
+func paginationParams(r *http.Request) (uint, uint) {
+	var limit uint = 20
+	var offset uint = 0
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		fmt.Sscanf(limitParam, "%d", &limit)
+	}
+	if offsetParam := r.URL.Query().Get("offset"); offsetParam != "" {
+		fmt.Sscanf(offsetParam, "%d", &offset)
+	}
+	return limit, offset
+}
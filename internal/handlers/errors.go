@@ -24,10 +24,56 @@ var (
 	ErrUserNotFound = errors.New("USER_NOT_FOUND")
 	ErrUserExists   = errors.New("USER_ALREADY_EXISTS")
 
+	// profile and storefront error code
+	ErrUsernameTaken            = errors.New("USERNAME_TAKEN")
+	ErrEmailTaken               = errors.New("EMAIL_TAKEN")
+	ErrEmailUnchanged           = errors.New("EMAIL_UNCHANGED")
+	ErrInvalidVerificationToken = errors.New("INVALID_VERIFICATION_TOKEN")
+	ErrTooManyFeaturedListings  = errors.New("TOO_MANY_FEATURED_LISTINGS")
+	ErrListingNotFeaturable     = errors.New("LISTING_NOT_FEATURABLE")
+
 	// bid error code
 	ErrBidLow          = errors.New("BID_TOO_LOW")
 	ErrSelfBidding     = errors.New("SELF_BIDDING_NOT_ALLOWED")
 	ErrBidCreateFailed = errors.New("BID_CREATION_FAILED")
+	ErrAuctionEnded    = errors.New("AUCTION_ENDED")
+
+	// bid retraction error codes
+	ErrBidNotFound              = errors.New("BID_NOT_FOUND")
+	ErrRetractionWindowPassed   = errors.New("RETRACTION_WINDOW_PASSED")
+	ErrRetractionFinalHour      = errors.New("RETRACTION_FINAL_HOUR")
+	ErrRetractionReasonRequired = errors.New("RETRACTION_REASON_REQUIRED")
+
+	// blocked bidder and private listing error codes
+	ErrBidderBlocked      = errors.New("BIDDER_BLOCKED")
+	ErrInvalidVisibility  = errors.New("INVALID_VISIBILITY")
+	ErrSelfBlocking       = errors.New("SELF_BLOCKING_NOT_ALLOWED")
+	ErrBlockNotFound      = errors.New("BLOCK_NOT_FOUND")
+	ErrProductNotPrivate  = errors.New("PRODUCT_NOT_PRIVATE")
+	ErrSelfInvitation     = errors.New("SELF_INVITATION_NOT_ALLOWED")
+	ErrInvitationNotFound = errors.New("INVITATION_NOT_FOUND")
+	ErrAccessCodeNotFound = errors.New("ACCESS_CODE_NOT_FOUND")
+	ErrInvalidAccessCode  = errors.New("INVALID_ACCESS_CODE")
+
+	// wallet error codes
+	ErrInsufficientFunds     = errors.New("INSUFFICIENT_FUNDS")
+	ErrMissingIdempotencyKey = errors.New("MISSING_IDEMPOTENCY_KEY")
+	ErrIdempotencyKeyReused  = errors.New("IDEMPOTENCY_KEY_REUSED")
+	ErrInvalidDeposit        = errors.New("INVALID_DEPOSIT")
+
+	// bid increment error code
+	ErrBidBelowIncrement      = errors.New("BID_BELOW_INCREMENT")
+	ErrBidAboveIncrement      = errors.New("BID_ABOVE_INCREMENT")
+	ErrInvalidIncrementLadder = errors.New("INVALID_INCREMENT_LADDER")
+
+	// fee error code
+	ErrInvalidFeeKind     = errors.New("INVALID_FEE_KIND")
+	ErrInvalidFeeSchedule = errors.New("INVALID_FEE_SCHEDULE")
+	ErrInvalidFeePeriod   = errors.New("INVALID_FEE_PERIOD")
+
+	// currency error code
+	ErrInvalidCurrency  = errors.New("INVALID_CURRENCY")
+	ErrCurrencyMismatch = errors.New("CURRENCY_MISMATCH")
 
 	// file error code
 	ErrInvalidForm   = errors.New("INVALID_FORM")
@@ -41,4 +87,103 @@ var (
 	//products error code
 	ErrProductNotFound = errors.New("PRODUCT_NOT_FOUND")
 	ErrUrlsNotFound    = errors.New("PRODUCT_URLS_NOT_FOUND")
+	ErrInvalidEndsAt   = errors.New("INVALID_END_TIME")
+
+	// buy now error code
+	ErrSelfBuying         = errors.New("SELF_BUYING_NOT_ALLOWED")
+	ErrBuyNowUnavailable  = errors.New("BUY_NOW_UNAVAILABLE")
+	ErrInvalidBuyNowPrice = errors.New("INVALID_BUY_NOW_PRICE")
+
+	// auction format error code
+	ErrInvalidAuctionType = errors.New("INVALID_AUCTION_TYPE")
+
+	// dutch auction error codes
+	ErrInvalidDutchSchedule = errors.New("INVALID_DUTCH_SCHEDULE")
+	ErrNotDutchAuction      = errors.New("NOT_DUTCH_AUCTION")
+	ErrBiddingNotSupported  = errors.New("BIDDING_NOT_SUPPORTED")
+
+	// listing lifecycle error codes
+	ErrInvalidStatus           = errors.New("INVALID_STATUS")
+	ErrInvalidStatusTransition = errors.New("INVALID_STATUS_TRANSITION")
+	ErrInvalidStartsAt         = errors.New("INVALID_START_TIME")
+	ErrProductNotLive          = errors.New("PRODUCT_NOT_LIVE")
+	ErrProductNotEditable      = errors.New("PRODUCT_NOT_EDITABLE")
+	ErrNotProductOwner         = errors.New("NOT_PRODUCT_OWNER")
+	ErrRelistSold              = errors.New("RELIST_SOLD_PRODUCT")
+
+	// multi-unit auction error codes
+	ErrInvalidQuantity    = errors.New("INVALID_QUANTITY")
+	ErrInvalidPricingRule = errors.New("INVALID_PRICING_RULE")
+	ErrInvalidBidQuantity = errors.New("INVALID_BID_QUANTITY")
+
+	// second-chance offer error codes
+	ErrSecondChanceUnavailable = errors.New("SECOND_CHANCE_UNAVAILABLE")
+	ErrSecondChancePending     = errors.New("SECOND_CHANCE_PENDING")
+	ErrBidNotEligible          = errors.New("BID_NOT_ELIGIBLE")
+	ErrInvalidOfferWindow      = errors.New("INVALID_OFFER_WINDOW")
+	ErrOfferNotFound           = errors.New("OFFER_NOT_FOUND")
+	ErrOfferNotPending         = errors.New("OFFER_NOT_PENDING")
+
+	// fixed-price offer error codes
+	ErrOffersNotSupported     = errors.New("OFFERS_NOT_SUPPORTED")
+	ErrInvalidOfferThresholds = errors.New("INVALID_OFFER_THRESHOLDS")
+	ErrInvalidOfferPrice      = errors.New("INVALID_OFFER_PRICE")
+	ErrOfferAlreadyOpen       = errors.New("OFFER_ALREADY_OPEN")
+	ErrOwnOffer               = errors.New("CANNOT_ANSWER_OWN_OFFER")
+
+	// order error code
+	ErrOrderNotFound = errors.New("ORDER_NOT_FOUND")
+	ErrInvalidRole   = errors.New("INVALID_ROLE")
+
+	// invoice error code
+	ErrInvoiceNotFound    = errors.New("INVOICE_NOT_FOUND")
+	ErrInvoiceAlreadyPaid = errors.New("INVOICE_ALREADY_PAID")
+	ErrInvoiceExpired     = errors.New("INVOICE_EXPIRED")
+	ErrPaymentDeclined    = errors.New("PAYMENT_DECLINED")
+
+	// shipping and fulfilment error code
+	ErrInvalidShippingOptions      = errors.New("INVALID_SHIPPING_OPTIONS")
+	ErrInvalidAddress              = errors.New("INVALID_ADDRESS")
+	ErrAddressNotFound             = errors.New("ADDRESS_NOT_FOUND")
+	ErrAddressRequired             = errors.New("ADDRESS_REQUIRED")
+	ErrShippingOptionNotFound      = errors.New("SHIPPING_OPTION_NOT_FOUND")
+	ErrShippingUnavailable         = errors.New("SHIPPING_UNAVAILABLE")
+	ErrShippingNotSelected         = errors.New("SHIPPING_NOT_SELECTED")
+	ErrShippingLocked              = errors.New("SHIPPING_LOCKED")
+	ErrOrderNotPaid                = errors.New("ORDER_NOT_PAID")
+	ErrInvalidFulfilmentTransition = errors.New("INVALID_FULFILMENT_TRANSITION")
+	ErrNotOrderParty               = errors.New("NOT_ORDER_PARTY")
+
+	// feedback and reputation error code
+	ErrInvalidRating          = errors.New("INVALID_RATING")
+	ErrFeedbackNotAllowed     = errors.New("FEEDBACK_NOT_ALLOWED")
+	ErrFeedbackWindowClosed   = errors.New("FEEDBACK_WINDOW_CLOSED")
+	ErrFeedbackAlreadyLeft    = errors.New("FEEDBACK_ALREADY_LEFT")
+	ErrFeedbackNotFound       = errors.New("FEEDBACK_NOT_FOUND")
+	ErrNotFeedbackRecipient   = errors.New("NOT_FEEDBACK_RECIPIENT")
+	ErrFeedbackAlreadyReplied = errors.New("FEEDBACK_ALREADY_REPLIED")
+	ErrReputationTooLow       = errors.New("REPUTATION_TOO_LOW")
+
+	// messaging error code
+	ErrConversationNotFound  = errors.New("CONVERSATION_NOT_FOUND")
+	ErrSelfMessaging         = errors.New("SELF_MESSAGING_NOT_ALLOWED")
+	ErrMessagingBlocked      = errors.New("MESSAGING_BLOCKED")
+	ErrEmptyMessage          = errors.New("EMPTY_MESSAGE")
+	ErrInvalidAttachment     = errors.New("INVALID_ATTACHMENT")
+	ErrMessageRateLimited    = errors.New("MESSAGE_RATE_LIMITED")
+	ErrMessageNotFound       = errors.New("MESSAGE_NOT_FOUND")
+	ErrMessageNotHeld        = errors.New("MESSAGE_NOT_HELD")
+	ErrMessageAlreadyRemoved = errors.New("MESSAGE_ALREADY_REMOVED")
+
+	// webhook error code
+	ErrWebhookNotFound         = errors.New("WEBHOOK_NOT_FOUND")
+	ErrWebhookDeliveryNotFound = errors.New("WEBHOOK_DELIVERY_NOT_FOUND")
+	ErrWebhookDeliveryInFlight = errors.New("WEBHOOK_DELIVERY_IN_FLIGHT")
+	ErrWebhookURLNotAllowed    = errors.New("WEBHOOK_URL_NOT_ALLOWED")
+
+	// admin error code
+	ErrAdminRequired     = errors.New("ADMIN_REQUIRED")
+	ErrJobNotFound       = errors.New("JOB_NOT_FOUND")
+	ErrJobNotRetryable   = errors.New("JOB_NOT_RETRYABLE")
+	ErrJobNotCancellable = errors.New("JOB_NOT_CANCELLABLE")
 )
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/itsDrac/e-auc/internal/model"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+const feedbackParamKey string = "feedbackId"
+
+type FeedbackHandler struct {
+	svc service.FeedbackServicer
+}
+
+func NewFeedbackHandler(svc service.FeedbackServicer) (*FeedbackHandler, error) {
+	return &FeedbackHandler{
+		svc: svc,
+	}, nil
+}
+
+// LeaveFeedback godoc
+//
+//	@Summary		Leave Feedback on an Order
+//	@Description	Rate the other party of an order you bought or sold from 1 to 5, with an optional comment. Feedback is left once per party, after the invoice is paid and within FEEDBACK_WINDOW_DAYS (default 60) of the sale.
+//	@Tags			Feedback
+//	@Accept			json
+//	@Produce		json
+//	@Param			orderId		path		string						true	"Order ID"
+//	@Param			feedback	body		model.LeaveFeedbackRequest	true	"Rating and comment"
+//	@Success		201			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/orders/{orderId}/feedback [post]
+func (h *FeedbackHandler) LeaveFeedback(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.LeaveFeedbackRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	orderId := chi.URLParam(r, orderParamKey)
+	feedback, err := h.svc.LeaveFeedback(r.Context(), claims.UserID, orderId, req.Rating, req.Comment)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound):
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrOrderNotFound.Error(), "Order not found", nil)
+		case errors.Is(err, service.ErrInvalidRating):
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRating.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrFeedbackNotAllowed):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrFeedbackNotAllowed.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrFeedbackWindowClosed):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrFeedbackWindowClosed.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrFeedbackAlreadyLeft):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrFeedbackAlreadyLeft.Error(), err.Error(), nil)
+		default:
+			slog.Error("[DB] failed to leave feedback", "order_id", orderId, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		}
+		return
+	}
+
+	resp := map[string]any{
+		"feedback": feedback,
+	}
+	RespondSuccessJSON(w, r, http.StatusCreated, "Feedback left successfully", resp)
+}
+
+// ReplyToFeedback godoc
+//
+//	@Summary		Reply to Feedback
+//	@Description	Answer feedback you received, once. The reply is shown next to the feedback and does not change your reputation.
+//	@Tags			Feedback
+//	@Accept			json
+//	@Produce		json
+//	@Param			feedbackId	path		string							true	"Feedback ID"
+//	@Param			reply		body		model.ReplyToFeedbackRequest	true	"Reply"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/feedback/{feedbackId}/reply [post]
+func (h *FeedbackHandler) ReplyToFeedback(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.ReplyToFeedbackRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	feedbackId := chi.URLParam(r, feedbackParamKey)
+	feedback, err := h.svc.ReplyToFeedback(r.Context(), claims.UserID, feedbackId, req.Reply)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrFeedbackNotFound):
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrFeedbackNotFound.Error(), "Feedback not found", nil)
+		case errors.Is(err, service.ErrNotFeedbackRecipient):
+			RespondErrorJSON(w, r, http.StatusForbidden, ErrNotFeedbackRecipient.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrFeedbackAlreadyReplied):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrFeedbackAlreadyReplied.Error(), err.Error(), nil)
+		default:
+			slog.Error("[DB] failed to reply to feedback", "feedback_id", feedbackId, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		}
+		return
+	}
+
+	resp := map[string]any{
+		"feedback": feedback,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Reply saved successfully", resp)
+}
+
+// ListFeedback godoc
+//
+//	@Summary		List Feedback of a User
+//	@Description	List the feedback a user received, newest first, optionally only as buyer or as seller.
+//	@Tags			Feedback
+//	@Produce		json
+//	@Param			userId	path		string	true	"User ID"
+//	@Param			role	query		string	false	"buyer or seller, both when omitted"
+//	@Param			limit	query		int		false	"Number of feedback entries to return"
+//	@Param			offset	query		int		false	"Number of feedback entries to skip"
+//	@Success		200		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Router			/users/{userId}/feedback [get]
+func (h *FeedbackHandler) ListFeedback(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, userParamKey)
+	limit, offset := paginationParams(r)
+
+	feedback, err := h.svc.GetFeedback(r.Context(), userId, r.URL.Query().Get("role"), limit, offset)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrUserNotFound.Error(), "User not found", nil)
+		case errors.Is(err, service.ErrInvalidOrderRole):
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRole.Error(), err.Error(), nil)
+		default:
+			slog.Error("[DB] failed to fetch feedback", "user_id", userId, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve feedback", nil)
+		}
+		return
+	}
+
+	resp := map[string]any{
+		"feedback": feedback,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Feedback fetched successfully", resp)
+}
+
+// GetReputation godoc
+//
+//	@Summary		Get the Reputation of a User
+//	@Description	Get the reputation a user built from feedback as buyer and seller. Ratings of 4 and 5 are positive, 3 neutral and 1 and 2 negative; the score is the positive minus the negative ratings.
+//	@Tags			Feedback
+//	@Produce		json
+//	@Param			userId	path		string	true	"User ID"
+//	@Success		200		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Router			/users/{userId}/reputation [get]
+func (h *FeedbackHandler) GetReputation(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, userParamKey)
+	reputation, err := h.svc.GetUserReputation(r.Context(), userId)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrUserNotFound.Error(), "User not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to compute reputation", "user_id", userId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve reputation", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"reputation": reputation,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Reputation fetched successfully", resp)
+}
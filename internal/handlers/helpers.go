@@ -3,10 +3,15 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/itsDrac/e-auc/internal/model"
 	"github.com/itsDrac/e-auc/pkg/config"
@@ -57,6 +62,15 @@ func GetUserClaims(ctx context.Context) *config.UserClaims {
 	return claims
 }
 
+// GetViewerID returns the ID of the signed in user, or uuid.Nil for anonymous requests to public routes.
+func GetViewerID(ctx context.Context) uuid.UUID {
+	claims := GetUserClaims(ctx)
+	if claims == nil {
+		return uuid.Nil
+	}
+	return claims.UserID
+}
+
 func RespondSuccessJSON[T any](w http.ResponseWriter, r *http.Request, status int, message string, data T) {
 
 	// fetch request ID , if not found generate new UUID
@@ -104,3 +118,68 @@ func RespondErrorJSON(w http.ResponseWriter, r *http.Request, status int, code s
 	}
 	writeJson(w, status, payload)
 }
+
+// decodeJSONRequest reads and validates the body into req, writing the error response when it fails.
+func decodeJSONRequest(w http.ResponseWriter, r *http.Request, req any) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidJson.Error(), "invalid json format", nil)
+		return false
+	}
+	if err := validate.Struct(req); err != nil {
+		var details []model.ErrorDetails
+		if validErrs, ok := err.(validator.ValidationErrors); ok {
+			for _, vErr := range validErrs {
+				details = append(details, model.ErrorDetails{
+					Field: vErr.Field(),
+					Issue: fmt.Sprintf("failed on tag '%s' with param '%s'", vErr.Tag(), vErr.Param()),
+				})
+			}
+		}
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRequest.Error(), "Input validation failed", details)
+		return false
+	}
+	return true
+}
+
+// readUploadedImage reads the single image of a multipart form field, up to 10MB, writing the error response
+// when it fails. It returns the image with a unique filename that keeps the original extension.
+func readUploadedImage(w http.ResponseWriter, r *http.Request, field string) ([]byte, string, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, 11<<20)
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidForm.Error(), "failed to parse multipart form", nil)
+		return nil, "", false
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	files := r.MultipartForm.File[field]
+	if len(files) != 1 {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrMissingFiles.Error(), fmt.Sprintf("exactly one image is expected in %s", field), nil)
+		return nil, "", false
+	}
+	fileHeader := files[0]
+	if fileHeader.Size > 10<<20 {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrLargeFile.Error(), fmt.Sprintf("File %s exceeds 10MB limit", fileHeader.Filename), nil)
+		return nil, "", false
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrFileOpen.Error(), "Failed to process uploaded file", nil)
+		return nil, "", false
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrFileReadError.Error(), "failed to read uploaded file", nil)
+		return nil, "", false
+	}
+	if !strings.HasPrefix(http.DetectContentType(data), "image/") {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidFile.Error(), fmt.Sprintf("File %s is not a valid image", fileHeader.Filename), nil)
+		return nil, "", false
+	}
+
+	ext := filepath.Ext(fileHeader.Filename)
+	if ext == "" {
+		ext = ".jpg"
+	}
+	return data, uuid.New().String() + ext, true
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/itsDrac/e-auc/internal/model"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+const invoiceParamKey string = "invoiceId"
+
+type InvoiceHandler struct {
+	svc service.InvoiceServicer
+}
+
+func NewInvoiceHandler(svc service.InvoiceServicer) (*InvoiceHandler, error) {
+	return &InvoiceHandler{
+		svc: svc,
+	}, nil
+}
+
+// ListInvoices godoc
+//
+//	@Summary		List Invoices
+//	@Description	Retrieve the invoices of the current user, newest first. Every order gets an invoice when the auction settles, due within 72 hours.
+//	@Tags			Invoices
+//	@Produce		json
+//	@Param			role	query		string	false	"buyer (default) or seller"
+//	@Param			limit	query		int		false	"Number of invoices to return"
+//	@Param			offset	query		int		false	"Number of invoices to skip"
+//	@Success		200		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Router			/invoices [get]
+func (h *InvoiceHandler) ListInvoices(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	role := r.URL.Query().Get("role")
+	if role == "" {
+		role = service.RoleBuyer
+	}
+	limit, offset := paginationParams(r)
+
+	invoices, err := h.svc.GetInvoices(r.Context(), claims.UserID, role, limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidOrderRole) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRole.Error(), err.Error(), nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch invoices", "user_id", claims.UserID, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve invoices", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"invoices": invoices,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Invoices fetched successfully", resp)
+}
+
+// GetInvoice godoc
+//
+//	@Summary		Get an Invoice
+//	@Description	Retrieve a single invoice the current user owes or is paid through, with its payment attempts
+//	@Tags			Invoices
+//	@Produce		json
+//	@Param			invoiceId	path		string	true	"Invoice ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Router			/invoices/{invoiceId} [get]
+func (h *InvoiceHandler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	invoiceId := chi.URLParam(r, invoiceParamKey)
+	invoice, attempts, err := h.svc.GetInvoice(r.Context(), claims.UserID, invoiceId)
+	if err != nil {
+		if errors.Is(err, service.ErrInvoiceNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrInvoiceNotFound.Error(), "Invoice not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch invoice", "invoice_id", invoiceId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve invoice", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"invoice":  invoice,
+		"payments": attempts,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Invoice fetched successfully", resp)
+}
+
+// PayInvoice godoc
+//
+//	@Summary		Pay an Invoice
+//	@Description	Charge the amount due of your invoice to a payment method tokenized by the payment provider. Declined payments are recorded and can be retried until the invoice is due. Orders without a shipping method need one first (PUT /orders/{orderId}/shipping).
+//	@Tags			Invoices
+//	@Accept			json
+//	@Produce		json
+//	@Param			invoiceId	path		string						true	"Invoice ID"
+//	@Param			payment		body		model.PayInvoiceRequest		true	"Payment method"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		402			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/invoices/{invoiceId}/pay [post]
+func (h *InvoiceHandler) PayInvoice(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.PayInvoiceRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	invoiceId := chi.URLParam(r, invoiceParamKey)
+	invoice, err := h.svc.PayInvoice(r.Context(), claims.UserID, invoiceId, req.PaymentToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvoiceNotFound):
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrInvoiceNotFound.Error(), "Invoice not found", nil)
+		case errors.Is(err, service.ErrPaymentDeclined):
+			RespondErrorJSON(w, r, http.StatusPaymentRequired, ErrPaymentDeclined.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrInvoiceAlreadyPaid):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrInvoiceAlreadyPaid.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrInvoiceExpired):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrInvoiceExpired.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrShippingNotSelected):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrShippingNotSelected.Error(), err.Error(), nil)
+		default:
+			slog.Error("[Payments] failed to pay invoice", "invoice_id", invoiceId, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		}
+		return
+	}
+
+	resp := map[string]any{
+		"invoice": invoice,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Invoice paid successfully", resp)
+}
+
+// ListPayouts godoc
+//
+//	@Summary		List your Payouts
+//	@Description	List what you are paid per item you sold, newest first: gross (item and shipping), the platform fee and net. Items whose invoice expired unpaid are left out.
+//	@Tags			Invoices
+//	@Produce		json
+//	@Param			limit	query		int	false	"Number of payouts to return"
+//	@Param			offset	query		int	false	"Number of payouts to skip"
+//	@Success		200		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Router			/users/me/payouts [get]
+func (h *InvoiceHandler) ListPayouts(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	limit, offset := paginationParams(r)
+
+	payouts, err := h.svc.GetPayouts(r.Context(), claims.UserID, limit, offset)
+	if err != nil {
+		slog.Error("[DB] failed to fetch payouts", "user_id", claims.UserID, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve payouts", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"payouts": payouts,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Payouts fetched successfully", resp)
+}
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/itsDrac/e-auc/internal/model"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+// UpdateProduct godoc
+//
+//	@Summary		Edit a Product
+//	@Description	Change a listing. Drafts can be changed freely, scheduled and live listings only accept a new description.
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Param			productId	path		string					true	"Product ID"
+//	@Param			product		body		UpdateProductRequest	true	"Fields to change"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId} [patch]
+func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	var req model.UpdateProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidJson.Error(), "invalid json format", nil)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		var details []model.ErrorDetails
+		if validErrs, ok := err.(validator.ValidationErrors); ok {
+			for _, vErr := range validErrs {
+				details = append(details, model.ErrorDetails{
+					Field: vErr.Field(),
+					Issue: fmt.Sprintf("failed on tag '%s' with param '%s'", vErr.Tag(), vErr.Param()),
+				})
+			}
+		}
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRequest.Error(), "Input validation failed", details)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	product, err := h.svc.EditProduct(r.Context(), claims.UserID, productId, service.ProductEdit{
+		Title:        req.Title,
+		Description:  req.Description,
+		Images:       req.Images,
+		MinPrice:     req.MinPrice,
+		CurrentPrice: req.CurrentPrice,
+		EndsAt:       req.EndsAt,
+		Category:     req.Category,
+		BuyNowPrice:  req.BuyNowPrice,
+	})
+	if err != nil {
+		respondListingError(w, r, err, productId, "update")
+		return
+	}
+
+	resp := map[string]any{
+		"product": product,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Product updated successfully", resp)
+}
+
+// PublishProduct godoc
+//
+//	@Summary		Publish a Product
+//	@Description	Put a draft or scheduled listing live right away
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Param			productId	path		string					true	"Product ID"
+//	@Param			publish		body		PublishProductRequest	false	"Optional new end time"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/publish [post]
+func (h *ProductHandler) PublishProduct(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	var req model.PublishProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidJson.Error(), "invalid json format", nil)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	product, err := h.svc.PublishProduct(r.Context(), claims.UserID, productId, req.EndsAt)
+	if err != nil {
+		respondListingError(w, r, err, productId, "publish")
+		return
+	}
+
+	resp := map[string]any{
+		"product": product,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Product published successfully", resp)
+}
+
+// ScheduleProduct godoc
+//
+//	@Summary		Schedule a Product
+//	@Description	Schedule a draft, or reschedule a scheduled listing, to go live at a later start time
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Param			productId	path		string					true	"Product ID"
+//	@Param			schedule	body		ScheduleProductRequest	true	"Start and optional end time"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/schedule [post]
+func (h *ProductHandler) ScheduleProduct(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	var req model.ScheduleProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidJson.Error(), "invalid json format", nil)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		var details []model.ErrorDetails
+		if validErrs, ok := err.(validator.ValidationErrors); ok {
+			for _, vErr := range validErrs {
+				details = append(details, model.ErrorDetails{
+					Field: vErr.Field(),
+					Issue: fmt.Sprintf("failed on tag '%s' with param '%s'", vErr.Tag(), vErr.Param()),
+				})
+			}
+		}
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRequest.Error(), "Input validation failed", details)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	product, err := h.svc.ScheduleProduct(r.Context(), claims.UserID, productId, req.StartsAt, req.EndsAt)
+	if err != nil {
+		respondListingError(w, r, err, productId, "schedule")
+		return
+	}
+
+	resp := map[string]any{
+		"product": product,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Product scheduled successfully", resp)
+}
+
+// RelistProduct godoc
+//
+//	@Summary		Relist a Product
+//	@Description	Start a new auction for an ended, unsold listing with the same images and settings
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Param			productId	path		string					true	"Product ID"
+//	@Param			relist		body		PublishProductRequest	false	"Optional end time of the new auction"
+//	@Success		201			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/relist [post]
+func (h *ProductHandler) RelistProduct(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	var req model.PublishProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidJson.Error(), "invalid json format", nil)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	product, err := h.svc.RelistProduct(r.Context(), claims.UserID, productId, req.EndsAt)
+	if err != nil {
+		respondListingError(w, r, err, productId, "relist")
+		return
+	}
+
+	resp := map[string]any{
+		"product_id": product.ID,
+		"product":    product,
+	}
+	RespondSuccessJSON(w, r, http.StatusCreated, "Product relisted successfully", resp)
+}
+
+// respondListingError writes the response for an error of a listing lifecycle operation.
+func respondListingError(w http.ResponseWriter, r *http.Request, err error, productId string, action string) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+	case errors.Is(err, service.ErrNotProductOwner):
+		RespondErrorJSON(w, r, http.StatusForbidden, ErrNotProductOwner.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidStatusTransition):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrInvalidStatusTransition.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrProductNotEditable):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrProductNotEditable.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrRelistSold):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrRelistSold.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidStartsAt):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidStartsAt.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidEndsAt):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidEndsAt.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidBuyNowPrice), errors.Is(err, service.ErrBuyNowNotSupported):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidBuyNowPrice.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidDutchSchedule):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidDutchSchedule.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrQuantityNotSupported):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidQuantity.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidOfferThresholds):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidOfferThresholds.Error(), err.Error(), nil)
+	default:
+		slog.Error("[DB] failed to "+action+" product", "product_id", productId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+	}
+}
@@ -0,0 +1,500 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/itsDrac/e-auc/internal/cache"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/model"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+const (
+	conversationParamKey string = "conversationId"
+	messageParamKey      string = "messageId"
+)
+
+type MessageHandler struct {
+	svc   service.MessageServicer
+	cache cache.Cacher
+}
+
+func NewMessageHandler(svc service.MessageServicer, c cache.Cacher) (*MessageHandler, error) {
+	return &MessageHandler{
+		svc:   svc,
+		cache: c,
+	}, nil
+}
+
+// StartProductConversation godoc
+//
+//	@Summary		Ask the Seller about a Listing
+//	@Description	Open your conversation with the seller of a listing you can see, or get it when it already exists. Users the seller blocked cannot message them.
+//	@Tags			Messages
+//	@Produce		json
+//	@Param			productId	path		string	true	"Product ID"
+//	@Success		200			{object}	map[string]any
+//	@Success		201			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/conversations [post]
+func (h *MessageHandler) StartProductConversation(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	conversation, created, err := h.svc.StartProductConversation(r.Context(), claims.UserID, productId)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrProductNotFound):
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+		case errors.Is(err, service.ErrSelfMessaging):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrSelfMessaging.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrMessagingBlocked):
+			RespondErrorJSON(w, r, http.StatusForbidden, ErrMessagingBlocked.Error(), err.Error(), nil)
+		default:
+			slog.Error("[DB] failed to start conversation", "product_id", productId, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		}
+		return
+	}
+	respondConversation(w, r, conversation, created)
+}
+
+// StartOrderConversation godoc
+//
+//	@Summary		Message the Other Party of an Order
+//	@Description	Open the conversation between the buyer and the seller of an order, or get it when it already exists.
+//	@Tags			Messages
+//	@Produce		json
+//	@Param			orderId	path		string	true	"Order ID"
+//	@Success		200		{object}	map[string]any
+//	@Success		201		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Router			/orders/{orderId}/conversation [post]
+func (h *MessageHandler) StartOrderConversation(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	orderId := chi.URLParam(r, orderParamKey)
+	conversation, created, err := h.svc.StartOrderConversation(r.Context(), claims.UserID, orderId)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrOrderNotFound.Error(), "Order not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to start conversation", "order_id", orderId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		return
+	}
+	respondConversation(w, r, conversation, created)
+}
+
+// ListConversations godoc
+//
+//	@Summary		List Your Conversations
+//	@Description	List the conversations you take part in, most recently active first, with the unread messages of each and in total.
+//	@Tags			Messages
+//	@Produce		json
+//	@Param			limit	query		int	false	"Number of conversations to return"
+//	@Param			offset	query		int	false	"Number of conversations to skip"
+//	@Success		200		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Router			/conversations [get]
+func (h *MessageHandler) ListConversations(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	limit, offset := paginationParams(r)
+
+	conversations, unread, err := h.svc.GetConversations(r.Context(), claims.UserID, limit, offset)
+	if err != nil {
+		slog.Error("[DB] failed to fetch conversations", "user_id", claims.UserID, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve conversations", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"conversations": conversations,
+		"unread_count":  unread,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Conversations fetched successfully", resp)
+}
+
+// ListMessages godoc
+//
+//	@Summary		List the Messages of a Conversation
+//	@Description	List the messages of a conversation you take part in, newest first. Your messages held for review are shown with their flags, the other party does not see them until they are approved.
+//	@Tags			Messages
+//	@Produce		json
+//	@Param			conversationId	path		string	true	"Conversation ID"
+//	@Param			limit			query		int		false	"Number of messages to return"
+//	@Param			offset			query		int		false	"Number of messages to skip"
+//	@Success		200				{object}	map[string]any
+//	@Failure		401				{object}	map[string]any
+//	@Failure		404				{object}	map[string]any
+//	@Router			/conversations/{conversationId}/messages [get]
+func (h *MessageHandler) ListMessages(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	limit, offset := paginationParams(r)
+
+	conversationId := chi.URLParam(r, conversationParamKey)
+	messages, err := h.svc.GetMessages(r.Context(), claims.UserID, conversationId, limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrConversationNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrConversationNotFound.Error(), "Conversation not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch messages", "conversation_id", conversationId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve messages", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"messages": messages,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Messages fetched successfully", resp)
+}
+
+// SendMessage godoc
+//
+//	@Summary		Send a Message
+//	@Description	Send a message, with an optional image uploaded to the conversation first. At most MESSAGE_RATE_LIMIT_PER_MINUTE (default 20) messages a minute are accepted. Messages that look like emails, phone numbers, links, messaging apps or payment outside the platform are held for review (status held) instead of delivered.
+//	@Tags			Messages
+//	@Accept			json
+//	@Produce		json
+//	@Param			conversationId	path		string						true	"Conversation ID"
+//	@Param			message			body		model.SendMessageRequest	true	"Message"
+//	@Success		201				{object}	map[string]any
+//	@Failure		400				{object}	map[string]any
+//	@Failure		401				{object}	map[string]any
+//	@Failure		403				{object}	map[string]any
+//	@Failure		404				{object}	map[string]any
+//	@Failure		429				{object}	map[string]any
+//	@Router			/conversations/{conversationId}/messages [post]
+func (h *MessageHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.SendMessageRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	conversationId := chi.URLParam(r, conversationParamKey)
+	message, err := h.svc.SendMessage(r.Context(), claims.UserID, conversationId, req.Body, req.AttachmentKey)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrConversationNotFound):
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrConversationNotFound.Error(), "Conversation not found", nil)
+		case errors.Is(err, service.ErrEmptyMessage):
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrEmptyMessage.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrInvalidAttachment):
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidAttachment.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrMessagingBlocked):
+			RespondErrorJSON(w, r, http.StatusForbidden, ErrMessagingBlocked.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrMessageRateLimited):
+			RespondErrorJSON(w, r, http.StatusTooManyRequests, ErrMessageRateLimited.Error(), err.Error(), nil)
+		default:
+			slog.Error("[DB] failed to send message", "conversation_id", conversationId, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		}
+		return
+	}
+
+	resp := map[string]any{
+		"message": message,
+	}
+	text := "Message sent successfully"
+	if message.Status == service.MessageHeld {
+		text = "Message held for review"
+	}
+	RespondSuccessJSON(w, r, http.StatusCreated, text, resp)
+}
+
+// UploadAttachment godoc
+//
+//	@Summary		Upload a Message Attachment
+//	@Description	Upload an image, up to 10MB, to a conversation you take part in. Send its attachment_key with a message to attach it.
+//	@Tags			Messages
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			conversationId	path		string	true	"Conversation ID"
+//	@Param			image			formData	file	true	"Image"
+//	@Success		200				{object}	map[string]any
+//	@Failure		400				{object}	map[string]any
+//	@Failure		401				{object}	map[string]any
+//	@Failure		404				{object}	map[string]any
+//	@Router			/conversations/{conversationId}/attachments [post]
+func (h *MessageHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	data, filename, ok := readUploadedImage(w, r, "image")
+	if !ok {
+		return
+	}
+
+	conversationId := chi.URLParam(r, conversationParamKey)
+	key, url, err := h.svc.UploadAttachment(r.Context(), claims.UserID, conversationId, filename, data)
+	if err != nil {
+		if errors.Is(err, service.ErrConversationNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrConversationNotFound.Error(), "Conversation not found", nil)
+			return
+		}
+		slog.Error("Error on uploading message attachment", "conversation_id", conversationId, "err:", err.Error())
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrUploadFailed.Error(), "failed to store image", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"attachment_key": key,
+		"attachment_url": url,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Attachment uploaded successfully", resp)
+}
+
+// MarkRead godoc
+//
+//	@Summary		Mark a Conversation as Read
+//	@Description	Mark every message of a conversation you take part in as read. The other party sees a conversation.read event on the live feed.
+//	@Tags			Messages
+//	@Produce		json
+//	@Param			conversationId	path		string	true	"Conversation ID"
+//	@Success		200				{object}	map[string]any
+//	@Failure		401				{object}	map[string]any
+//	@Failure		404				{object}	map[string]any
+//	@Router			/conversations/{conversationId}/read [post]
+func (h *MessageHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	conversationId := chi.URLParam(r, conversationParamKey)
+	conversation, err := h.svc.MarkRead(r.Context(), claims.UserID, conversationId)
+	if err != nil {
+		if errors.Is(err, service.ErrConversationNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrConversationNotFound.Error(), "Conversation not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to mark conversation read", "conversation_id", conversationId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"conversation": conversation,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Conversation marked as read", resp)
+}
+
+// LiveFeed godoc
+//
+//	@Summary		Live feed of a Conversation
+//	@Description	Stream the events of a conversation you take part in (message.sent, conversation.read) as Server-Sent Events, over the same channel as the live feed of products. The first event is a snapshot of the conversation.
+//	@Tags			Messages
+//	@Produce		text/event-stream
+//	@Param			conversationId	path		string	true	"Conversation ID"
+//	@Success		200				{string}	string
+//	@Failure		401				{object}	map[string]any
+//	@Failure		404				{object}	map[string]any
+//	@Router			/conversations/{conversationId}/live [get]
+func (h *MessageHandler) LiveFeed(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	conversationId := chi.URLParam(r, conversationParamKey)
+	conversation, err := h.svc.GetConversation(r.Context(), claims.UserID, conversationId)
+	if err != nil {
+		if errors.Is(err, service.ErrConversationNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrConversationNotFound.Error(), "Conversation not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch conversation", "conversation_id", conversationId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve conversation", nil)
+		return
+	}
+
+	// Subscribe before sending the snapshot so no event between the two is lost
+	messages, closeSub, err := h.cache.Subscribe(r.Context(), cache.EventChannel(conversation.ID.String()))
+	if err != nil {
+		slog.Error("[Cache] failed to subscribe to conversation events", "conversation_id", conversationId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		return
+	}
+	defer closeSub()
+
+	// The stream outlives the server write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	snapshot, _ := json.Marshal(map[string]any{
+		"conversation": conversation,
+		"server_time":  time.Now().UTC(),
+	})
+	fmt.Fprintf(w, "event: snapshot\ndata: %s\n\n", snapshot)
+	rc.Flush()
+
+	keepAlive := time.NewTicker(liveFeedKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var event struct {
+				ID   string `json:"id"`
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal([]byte(msg), &event); err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, msg)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// ListHeldMessages godoc
+//
+//	@Summary		List Messages Held for Review
+//	@Description	List the messages held back from their recipient because they matched the contact-info or scam screens, oldest first, with the flags they raised. Admin only.
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			limit	query		int	false	"Number of messages to return"
+//	@Param			offset	query		int	false	"Number of messages to skip"
+//	@Success		200		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Router			/admin/messages/held [get]
+func (h *MessageHandler) ListHeldMessages(w http.ResponseWriter, r *http.Request) {
+	limit, offset := paginationParams(r)
+	messages, err := h.svc.GetHeldMessages(r.Context(), limit, offset)
+	if err != nil {
+		slog.Error("[DB] failed to fetch held messages", "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve messages", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"messages": messages,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Held messages fetched successfully", resp)
+}
+
+// ApproveMessage godoc
+//
+//	@Summary		Approve a Held Message
+//	@Description	Deliver a message held for review to its recipient. Admin only.
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			messageId	path		string	true	"Message ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/admin/messages/{messageId}/approve [post]
+func (h *MessageHandler) ApproveMessage(w http.ResponseWriter, r *http.Request) {
+	h.moderateMessage(w, r, h.svc.ApproveMessage, "Message approved")
+}
+
+// RemoveMessage godoc
+//
+//	@Summary		Remove a Message
+//	@Description	Hide a message from both parties of its conversation, whether it was delivered or held. It is kept for the record. Admin only.
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			messageId	path		string	true	"Message ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/admin/messages/{messageId}/remove [post]
+func (h *MessageHandler) RemoveMessage(w http.ResponseWriter, r *http.Request) {
+	h.moderateMessage(w, r, h.svc.RemoveMessage, "Message removed")
+}
+
+func (h *MessageHandler) moderateMessage(w http.ResponseWriter, r *http.Request, moderate func(ctx context.Context, adminID uuid.UUID, messageId string) (db.Message, error), text string) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	messageId := chi.URLParam(r, messageParamKey)
+	message, err := moderate(r.Context(), claims.UserID, messageId)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrMessageNotFound):
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrMessageNotFound.Error(), "Message not found", nil)
+		case errors.Is(err, service.ErrMessageNotHeld):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrMessageNotHeld.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrMessageAlreadyRemoved):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrMessageAlreadyRemoved.Error(), err.Error(), nil)
+		default:
+			slog.Error("[DB] failed to moderate message", "message_id", messageId, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		}
+		return
+	}
+
+	resp := map[string]any{
+		"message": message,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, text, resp)
+}
+
+// respondConversation answers 201 for a conversation that was just started and 200 for an existing one.
+func respondConversation(w http.ResponseWriter, r *http.Request, conversation db.Conversation, created bool) {
+	resp := map[string]any{
+		"conversation": conversation,
+	}
+	if created {
+		RespondSuccessJSON(w, r, http.StatusCreated, "Conversation started successfully", resp)
+		return
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Conversation fetched successfully", resp)
+}
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/itsDrac/e-auc/internal/model"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+type OfferHandler struct {
+	svc service.OfferServicer
+}
+
+func NewOfferHandler(svc service.OfferServicer) (*OfferHandler, error) {
+	return &OfferHandler{
+		svc: svc,
+	}, nil
+}
+
+// decodeOfferRequest reads and validates the price of an offer, writing the error response when it fails.
+func decodeOfferRequest(w http.ResponseWriter, r *http.Request) (model.OfferRequest, bool) {
+	var req model.OfferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidJson.Error(), "invalid json format", nil)
+		return req, false
+	}
+	if err := validate.Struct(req); err != nil {
+		var details []model.ErrorDetails
+		if validErrs, ok := err.(validator.ValidationErrors); ok {
+			for _, vErr := range validErrs {
+				details = append(details, model.ErrorDetails{
+					Field: vErr.Field(),
+					Issue: fmt.Sprintf("failed on tag '%s' with param '%s'", vErr.Tag(), vErr.Param()),
+				})
+			}
+		}
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRequest.Error(), "Input validation failed", details)
+		return req, false
+	}
+	return req, true
+}
+
+// MakeOffer godoc
+//
+//	@Summary		Make an offer on a fixed-price listing
+//	@Description	Offer the seller a price below the asking price. The offer is open for 48 hours and may be accepted or declined right away by the seller's thresholds.
+//	@Tags			Offers
+//	@Accept			json
+//	@Produce		json
+//	@Param			productId	path		string				true	"Product ID"
+//	@Param			offer		body		model.OfferRequest	true	"Offered price"
+//	@Success		201			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/offers [post]
+func (h *OfferHandler) MakeOffer(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	req, ok := decodeOfferRequest(w, r)
+	if !ok {
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	offer, err := h.svc.MakeOffer(r.Context(), claims.UserID, productId, req.Price)
+	if err != nil {
+		respondOfferError(w, r, err, "product_id", productId)
+		return
+	}
+
+	resp := map[string]any{
+		"offer": offer,
+	}
+	RespondSuccessJSON(w, r, http.StatusCreated, "Offer sent successfully", resp)
+}
+
+// ListProductOffers godoc
+//
+//	@Summary		List offers on a Product
+//	@Description	Retrieve the offers and counter-offers on a fixed-price listing, oldest first. The seller sees every negotiation, buyers only their own.
+//	@Tags			Offers
+//	@Produce		json
+//	@Param			productId	path		string	true	"Product ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Router			/products/{productId}/offers [get]
+func (h *OfferHandler) ListProductOffers(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	offers, err := h.svc.GetProductOffers(r.Context(), claims.UserID, productId)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch offers", "product_id", productId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve offers", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"offers": offers,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Offers fetched successfully", resp)
+}
+
+// ListOffers godoc
+//
+//	@Summary		List my Offers
+//	@Description	Retrieve the offers and counter-offers the current user negotiates as buyer or seller, newest first
+//	@Tags			Offers
+//	@Produce		json
+//	@Param			role	query		string	false	"buyer (default) or seller"
+//	@Param			limit	query		int		false	"Number of offers to return"
+//	@Param			offset	query		int		false	"Number of offers to skip"
+//	@Success		200		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Router			/offers [get]
+func (h *OfferHandler) ListOffers(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	role := r.URL.Query().Get("role")
+	if role == "" {
+		role = service.RoleBuyer
+	}
+	limit, offset := paginationParams(r)
+
+	offers, err := h.svc.GetOffers(r.Context(), claims.UserID, role, limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidOrderRole) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRole.Error(), err.Error(), nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch offers", "user_id", claims.UserID, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve offers", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"offers": offers,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Offers fetched successfully", resp)
+}
+
+// CounterOffer godoc
+//
+//	@Summary		Counter an Offer
+//	@Description	Answer an offer or counter-offer with a new price, which the other party can accept, decline or counter in turn for 48 hours
+//	@Tags			Offers
+//	@Accept			json
+//	@Produce		json
+//	@Param			offerId	path		string				true	"Offer ID"
+//	@Param			offer	body		model.OfferRequest	true	"Counter price"
+//	@Success		201		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Failure		409		{object}	map[string]any
+//	@Router			/offers/{offerId}/counter [post]
+func (h *OfferHandler) CounterOffer(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	req, ok := decodeOfferRequest(w, r)
+	if !ok {
+		return
+	}
+
+	offerId := chi.URLParam(r, offerParamKey)
+	counter, err := h.svc.CounterOffer(r.Context(), claims.UserID, offerId, req.Price)
+	if err != nil {
+		respondOfferError(w, r, err, "offer_id", offerId)
+		return
+	}
+
+	resp := map[string]any{
+		"offer": counter,
+	}
+	RespondSuccessJSON(w, r, http.StatusCreated, "Counter-offer sent successfully", resp)
+}
+
+// AcceptOffer godoc
+//
+//	@Summary		Accept an Offer
+//	@Description	Accept an offer or counter-offer, which sells the product at its price
+//	@Tags			Offers
+//	@Produce		json
+//	@Param			offerId	path		string	true	"Offer ID"
+//	@Success		200		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Failure		409		{object}	map[string]any
+//	@Router			/offers/{offerId}/accept [post]
+func (h *OfferHandler) AcceptOffer(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	offerId := chi.URLParam(r, offerParamKey)
+	product, err := h.svc.AcceptOffer(r.Context(), claims.UserID, offerId)
+	if err != nil {
+		respondOfferError(w, r, err, "offer_id", offerId)
+		return
+	}
+
+	resp := map[string]any{
+		"product": product,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Offer accepted successfully", resp)
+}
+
+// DeclineOffer godoc
+//
+//	@Summary		Decline an Offer
+//	@Description	Turn down an offer or counter-offer, which ends the negotiation
+//	@Tags			Offers
+//	@Produce		json
+//	@Param			offerId	path		string	true	"Offer ID"
+//	@Success		200		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Failure		409		{object}	map[string]any
+//	@Router			/offers/{offerId}/decline [post]
+func (h *OfferHandler) DeclineOffer(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	offerId := chi.URLParam(r, offerParamKey)
+	offer, err := h.svc.DeclineOffer(r.Context(), claims.UserID, offerId)
+	if err != nil {
+		respondOfferError(w, r, err, "offer_id", offerId)
+		return
+	}
+
+	resp := map[string]any{
+		"offer": offer,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Offer declined successfully", resp)
+}
+
+func respondOfferError(w http.ResponseWriter, r *http.Request, err error, idKey string, id string) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+	case errors.Is(err, service.ErrOfferNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrOfferNotFound.Error(), "Offer not found", nil)
+	case errors.Is(err, service.ErrOffersNotSupported):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrOffersNotSupported.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidOfferPrice):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidOfferPrice.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrSelfBuying):
+		RespondErrorJSON(w, r, http.StatusForbidden, ErrSelfBuying.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrBidderBlocked):
+		RespondErrorJSON(w, r, http.StatusForbidden, ErrBidderBlocked.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrReputationTooLow):
+		RespondErrorJSON(w, r, http.StatusForbidden, ErrReputationTooLow.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrOwnOffer):
+		RespondErrorJSON(w, r, http.StatusForbidden, ErrOwnOffer.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrOfferAlreadyOpen):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrOfferAlreadyOpen.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrOfferNotPending):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrOfferNotPending.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrAuctionEnded):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrAuctionEnded.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrProductNotLive):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrProductNotLive.Error(), err.Error(), nil)
+	default:
+		slog.Error("[DB] failed to handle offer", idKey, id, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/itsDrac/e-auc/internal/model"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+const orderParamKey string = "orderId"
+
+type OrderHandler struct {
+	svc service.OrderServicer
+}
+
+func NewOrderHandler(svc service.OrderServicer) (*OrderHandler, error) {
+	return &OrderHandler{
+		svc: svc,
+	}, nil
+}
+
+// ListOrders godoc
+//
+//	@Summary		List Orders
+//	@Description	Retrieve the orders of the current user, newest first. Every winner of an auction gets an order for the units they won, with how it ships and its fulfilment status.
+//	@Tags			Orders
+//	@Produce		json
+//	@Param			role	query		string	false	"buyer (default) or seller"
+//	@Param			limit	query		int		false	"Number of orders to return"
+//	@Param			offset	query		int		false	"Number of orders to skip"
+//	@Success		200		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Router			/orders [get]
+func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	role := r.URL.Query().Get("role")
+	if role == "" {
+		role = service.RoleBuyer
+	}
+	limit, offset := paginationParams(r)
+
+	orders, err := h.svc.GetOrders(r.Context(), claims.UserID, role, limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidOrderRole) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRole.Error(), err.Error(), nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch orders", "user_id", claims.UserID, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve orders", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"orders": orders,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Orders fetched successfully", resp)
+}
+
+// GetOrder godoc
+//
+//	@Summary		Get an Order
+//	@Description	Retrieve a single order the current user bought or sold
+//	@Tags			Orders
+//	@Produce		json
+//	@Param			orderId	path		string	true	"Order ID"
+//	@Success		200		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Router			/orders/{orderId} [get]
+func (h *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	orderId := chi.URLParam(r, orderParamKey)
+	order, err := h.svc.GetOrder(r.Context(), claims.UserID, orderId)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrOrderNotFound.Error(), "Order not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch order", "order_id", orderId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve order", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"order": order,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Order fetched successfully", resp)
+}
+
+// SetOrderShipping godoc
+//
+//	@Summary		Choose how an Order ships
+//	@Description	Choose one of the listing's shipping options for an order you bought, and the address from your address book it ships to unless you pick it up. Settlement picks the cheapest option delivering to your default address, or pickup; this changes it while the invoice is unpaid and reprices the invoice.
+//	@Tags			Orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			orderId		path		string							true	"Order ID"
+//	@Param			shipping	body		model.SetOrderShippingRequest	true	"Shipping option and address"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/orders/{orderId}/shipping [put]
+func (h *OrderHandler) SetOrderShipping(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.SetOrderShippingRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	orderId := chi.URLParam(r, orderParamKey)
+	order, err := h.svc.SetOrderShipping(r.Context(), claims.UserID, orderId, req.ShippingOptionID, req.AddressID)
+	if err != nil {
+		respondFulfilmentError(w, r, err, orderId)
+		return
+	}
+
+	resp := map[string]any{
+		"order": order,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Order shipping updated successfully", resp)
+}
+
+// ShipOrder godoc
+//
+//	@Summary		Ship an Order
+//	@Description	Mark an order you sold as shipped with its carrier and tracking number, once its invoice is paid. Pickup orders are not shipped, confirm they were handed over instead.
+//	@Tags			Orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			orderId		path		string					true	"Order ID"
+//	@Param			shipment	body		model.ShipOrderRequest	true	"Carrier and tracking number"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/orders/{orderId}/ship [post]
+func (h *OrderHandler) ShipOrder(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.ShipOrderRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	orderId := chi.URLParam(r, orderParamKey)
+	order, err := h.svc.ShipOrder(r.Context(), claims.UserID, orderId, req.Carrier, req.TrackingNumber)
+	if err != nil {
+		respondFulfilmentError(w, r, err, orderId)
+		return
+	}
+
+	resp := map[string]any{
+		"order": order,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Order shipped successfully", resp)
+}
+
+// ConfirmDelivery godoc
+//
+//	@Summary		Confirm an Order was delivered
+//	@Description	As the buyer, confirm a shipped order arrived. Either party confirms a paid pickup order was handed over.
+//	@Tags			Orders
+//	@Produce		json
+//	@Param			orderId	path		string	true	"Order ID"
+//	@Success		200		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Failure		409		{object}	map[string]any
+//	@Router			/orders/{orderId}/deliver [post]
+func (h *OrderHandler) ConfirmDelivery(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	orderId := chi.URLParam(r, orderParamKey)
+	order, err := h.svc.ConfirmDelivery(r.Context(), claims.UserID, orderId)
+	if err != nil {
+		respondFulfilmentError(w, r, err, orderId)
+		return
+	}
+
+	resp := map[string]any{
+		"order": order,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Order delivered successfully", resp)
+}
+
+func respondFulfilmentError(w http.ResponseWriter, r *http.Request, err error, orderId string) {
+	switch {
+	case errors.Is(err, service.ErrOrderNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrOrderNotFound.Error(), "Order not found", nil)
+	case errors.Is(err, service.ErrAddressNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrAddressNotFound.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrShippingOptionNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrShippingOptionNotFound.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrAddressRequired):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrAddressRequired.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrShippingUnavailable):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrShippingUnavailable.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrNotOrderParty):
+		RespondErrorJSON(w, r, http.StatusForbidden, ErrNotOrderParty.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrShippingLocked):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrShippingLocked.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrOrderNotPaid):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrOrderNotPaid.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidFulfilmentTransition):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrInvalidFulfilmentTransition.Error(), err.Error(), nil)
+	default:
+		slog.Error("[DB] failed to update order fulfilment", "order_id", orderId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
@@ -17,6 +18,8 @@ import (
 	"github.com/itsDrac/e-auc/internal/model"
 	"github.com/itsDrac/e-auc/internal/service"
 	"github.com/itsDrac/e-auc/internal/cache"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/itsDrac/e-auc/pkg/money"
 )
 
 const (
@@ -26,12 +29,14 @@ const (
 
 type ProductHandler struct {
 	svc service.ProductServicer
+	feedback service.FeedbackServicer
 	cache cache.Cacher
 }
 
-func NewProductHandler(sevc service.ProductServicer, c cache.Cacher) (*ProductHandler, error) {
+func NewProductHandler(sevc service.ProductServicer, feedbackSvc service.FeedbackServicer, c cache.Cacher) (*ProductHandler, error) {
 	return &ProductHandler{
 		svc: sevc,
+		feedback: feedbackSvc,
 		cache: c,
 	}, nil
 }
@@ -39,7 +44,7 @@ func NewProductHandler(sevc service.ProductServicer, c cache.Cacher) (*ProductHa
 // CreateProduct godoc
 //
 //	@Summary		Create a new Product
-//	@Description	Create a new product listing
+//	@Description	Create a new product listing. Amounts are in minor units of the listing currency, USD unless given. Listings ship through their shipping_options, or at a flat shipping_cost when none are given
 //	@Tags			Products
 //	@Accept			json
 //	@Produce		json
@@ -79,16 +84,107 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 	}
 
 	product := db.Product{
-		Title:        req.Title,
-		Description:  req.Description,
-		SellerID:     claims.UserID,
-		Images:       req.Images,
-		MinPrice:     req.MinPrice,
-		CurrentPrice: req.CurrentPrice,
+		Title:                     req.Title,
+		Description:               req.Description,
+		SellerID:                  claims.UserID,
+		Images:                    req.Images,
+		MinPrice:                  req.MinPrice,
+		CurrentPrice:              req.CurrentPrice,
+		Currency:                  req.Currency,
+		SoftCloseWindowMinutes:    req.SoftCloseWindowMinutes,
+		SoftCloseExtensionMinutes: req.SoftCloseExtensionMinutes,
+		MaxExtensions:             req.MaxExtensions,
+		Category:                  req.Category,
+		BuyNowPrice:               req.BuyNowPrice,
+		AuctionType:               req.AuctionType,
+		DutchPriceStep:            req.DutchPriceStep,
+		DutchIntervalSeconds:      req.DutchIntervalSeconds,
+		Quantity:                  req.Quantity,
+		PricingRule:               req.PricingRule,
+		Status:                    req.Status,
+		StartsAt:                  req.StartsAt,
+		AutoAcceptPrice:           req.AutoAcceptPrice,
+		AutoDeclinePrice:          req.AutoDeclinePrice,
+		Visibility:                req.Visibility,
+		DepositType:               req.DepositType,
+		DepositAmount:             req.DepositAmount,
+		SecondChanceOnNonPayment:  req.SecondChanceOnNonPayment,
+		MinBidderReputation:       req.MinBidderReputation,
+	}
+	if req.EndsAt != nil {
+		product.EndsAt = *req.EndsAt
+	}
+
+	shipping := toShippingOptions(req.ShippingOptions)
+	if len(shipping) == 0 {
+		shipping = []service.ShippingOption{{Method: service.ShippingFlatRate, Cost: req.ShippingCost}}
+	} else if req.ShippingCost != 0 {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidShippingOptions.Error(), "shipping_cost cannot be combined with shipping_options", nil)
+		return
 	}
 
-	productId, err := h.svc.AddProduct(r.Context(), product)
+	productId, err := h.svc.AddProduct(r.Context(), product, toIncrementSteps(req.BidIncrements), shipping)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidEndsAt) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidEndsAt.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidIncrementLadder) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidIncrementLadder.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidBuyNowPrice) || errors.Is(err, service.ErrBuyNowNotSupported) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidBuyNowPrice.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidAuctionType) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidAuctionType.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidDutchSchedule) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidDutchSchedule.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidQuantity) || errors.Is(err, service.ErrQuantityNotSupported) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidQuantity.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidPricingRule) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidPricingRule.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidStartsAt) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidStartsAt.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrOffersNotSupported) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrOffersNotSupported.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidOfferThresholds) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidOfferThresholds.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidStatus) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidStatus.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidVisibility) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidVisibility.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidDeposit) || errors.Is(err, service.ErrDepositNotSupported) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidDeposit.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidCurrency) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidCurrency.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidShippingOptions) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidShippingOptions.Error(), err.Error(), nil)
+			return
+		}
 		if err.Error() == service.ErrInsufficientBid.Error() {
 			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrSelfBidding.Error(), "you cannot bid on your own product", nil)
 			return
@@ -213,6 +309,7 @@ func (h *ProductHandler) UploadImages(w http.ResponseWriter, r *http.Request) {
 //	@Param			productId	path		string	true	"Product ID"
 //	@Success		200			{object}	map[string]any
 //	@Failure		400			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
 //	@Failure		500			{object}	map[string]any
 //	@Router			/products/{productId}/images [get]
 func (h *ProductHandler) GetProductImageUrls(w http.ResponseWriter, r *http.Request) {
@@ -223,8 +320,12 @@ func (h *ProductHandler) GetProductImageUrls(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	imageUrls, err := h.svc.GetProductUrls(r.Context(), productId)
+	imageUrls, err := h.svc.GetProductUrls(r.Context(), productId, GetViewerID(r.Context()))
 	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+			return
+		}
 		if errors.Is(err, service.ErrUrlsNotFound) {
 			RespondErrorJSON(w, r, http.StatusNotFound, ErrUrlsNotFound.Error(), "Failed to retrieve images", nil)
 			return
@@ -248,7 +349,7 @@ func (h *ProductHandler) GetProductImageUrls(w http.ResponseWriter, r *http.Requ
 // GetProductByID godoc
 //
 //	@Summary		Get Product by ID
-//	@Description	Retrieve a specific product by the given product ID
+//	@Description	Retrieve a specific product by the given product ID with its shipping options and the reputation of its seller. Private listings are only found by their seller and invited users, who send their bearer token.
 //	@Tags			Products
 //	@Accept			json
 //	@Produce		json
@@ -264,7 +365,7 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	product, err := h.svc.GetProductByID(r.Context(), productId)
+	product, err := h.svc.GetProductByID(r.Context(), productId, GetViewerID(r.Context()))
 	if err != nil {
 		if errors.Is(err, service.ErrProductNotFound) {
 			RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
@@ -275,8 +376,31 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	state, err := h.svc.GetBiddingState(r.Context(), *product)
+	if err != nil {
+		slog.Error("[DB] failed to compute bidding state", "product_id", productId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve product", nil)
+		return
+	}
+
+	shipping, err := h.svc.GetShippingOptions(r.Context(), product.ID)
+	if err != nil {
+		slog.Error("[DB] failed to fetch shipping options", "product_id", productId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve product", nil)
+		return
+	}
+
+	sellerReputation, err := h.feedback.GetReputation(r.Context(), product.SellerID)
+	if err != nil {
+		slog.Error("[DB] failed to compute seller reputation", "product_id", productId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve product", nil)
+		return
+	}
+
 	resp := map[string]any{
-		"product": product,
+		"product":           toProductResponse(*product, state, GetViewerID(r.Context())),
+		"shipping_options":  shipping,
+		"seller_reputation": sellerReputation,
 	}
 	RespondSuccessJSON(w, r, http.StatusOK, "Product fetched successfully", resp)
 }
@@ -284,7 +408,7 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 // PlaceBid godoc
 //
 //	@Summary		Place a Bid on a Product
-//	@Description	Place a bid(update current price) on a specific product by the given product ID
+//	@Description	Place a bid(update current price) on a specific product by the given product ID. Products with a deposit requirement hold the deposit in your wallet while your bid can win. Bids in another currency than the product's are refused.
 //	@Tags			Products
 //	@Accept			json
 //	@Produce		json
@@ -293,6 +417,9 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 //	@Success		200			{object}	map[string]any
 //	@Failure		400			{object}	map[string]any
 //	@Failure		401			{object}	map[string]any
+//	@Failure		402			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
 //	@Failure		500			{object}	map[string]any
 //	@Router			/products/{productId}/bid [patch]
 func (h *ProductHandler) PlaceBid(w http.ResponseWriter, r *http.Request) {
@@ -315,12 +442,75 @@ func (h *ProductHandler) PlaceBid(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.svc.PlaceBid(r.Context(), productId, claims.UserID, req.BidAmount)
+	bid := money.Money{Amount: req.BidAmount}
+	if req.Currency != "" {
+		var err error
+		if bid, err = money.New(req.BidAmount, req.Currency); err != nil {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidCurrency.Error(), err.Error(), nil)
+			return
+		}
+	}
+
+	err := h.svc.PlaceBid(r.Context(), productId, claims.UserID, bid, req.Quantity)
 	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+			return
+		}
 		if errors.Is(err, service.ErrSelfBidding) { // Make sure this error is exported in service package
 			RespondErrorJSON(w, r, http.StatusForbidden, ErrSelfBidding.Error(), "You cannot bid on your own product", nil)
 			return
 		}
+		if errors.Is(err, service.ErrBidderBlocked) {
+			RespondErrorJSON(w, r, http.StatusForbidden, ErrBidderBlocked.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrReputationTooLow) {
+			RespondErrorJSON(w, r, http.StatusForbidden, ErrReputationTooLow.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrAuctionEnded) {
+			RespondErrorJSON(w, r, http.StatusConflict, ErrAuctionEnded.Error(), "The auction has already ended", nil)
+			return
+		}
+		if errors.Is(err, service.ErrProductNotLive) {
+			RespondErrorJSON(w, r, http.StatusConflict, ErrProductNotLive.Error(), "The product is not live", nil)
+			return
+		}
+		if errors.Is(err, service.ErrInsufficientFunds) {
+			RespondErrorJSON(w, r, http.StatusPaymentRequired, ErrInsufficientFunds.Error(), "Your wallet does not cover the deposit this product requires", nil)
+			return
+		}
+		if errors.Is(err, service.ErrBiddingNotSupported) {
+			RespondErrorJSON(w, r, http.StatusConflict, ErrBiddingNotSupported.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidBidQuantity) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidBidQuantity.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrCurrencyMismatch) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrCurrencyMismatch.Error(), err.Error(), nil)
+			return
+		}
+		var belowIncrement *service.BidBelowIncrementError
+		if errors.As(err, &belowIncrement) {
+			details := []model.ErrorDetails{{
+				Field: "bid_amount",
+				Issue: fmt.Sprintf("must be at least %d", belowIncrement.MinBid),
+			}}
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrBidBelowIncrement.Error(), fmt.Sprintf("Bid must be at least %d", belowIncrement.MinBid), details)
+			return
+		}
+		var aboveIncrement *service.BidAboveIncrementError
+		if errors.As(err, &aboveIncrement) {
+			details := []model.ErrorDetails{{
+				Field: "bid_amount",
+				Issue: fmt.Sprintf("must be at most %d", aboveIncrement.MaxBid),
+			}}
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrBidAboveIncrement.Error(), fmt.Sprintf("Bid must be at most %d", aboveIncrement.MaxBid), details)
+			return
+		}
 		// FIX: Add check for low bid if not already there
 		if errors.Is(err, service.ErrInsufficientBid) {
 			RespondErrorJSON(w, r, http.StatusBadRequest, ErrBidLow.Error(), "Bid must be higher than current price", nil)
@@ -334,6 +524,202 @@ func (h *ProductHandler) PlaceBid(w http.ResponseWriter, r *http.Request) {
 	RespondSuccessJSON(w, r, http.StatusOK, "Bid placed successfully", "")
 }
 
+// AcceptPrice godoc
+//
+//	@Summary		Accept the price of a Dutch auction
+//	@Description	Buy a product listed as a Dutch auction at its current price. The first buyer to accept wins.
+//	@Tags			Products
+//	@Produce		json
+//	@Param			productId	path		string	true	"Product ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/accept [post]
+func (h *ProductHandler) AcceptPrice(w http.ResponseWriter, r *http.Request) {
+	productId := chi.URLParam(r, productParamKey)
+	if productId == "" {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrMissingParam.Error(), "Product ID is required", nil)
+		return
+	}
+
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	product, err := h.svc.AcceptPrice(r.Context(), productId, claims.UserID)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+			return
+		}
+		if errors.Is(err, service.ErrSelfBuying) {
+			RespondErrorJSON(w, r, http.StatusForbidden, ErrSelfBuying.Error(), "You cannot buy your own product", nil)
+			return
+		}
+		if errors.Is(err, service.ErrBidderBlocked) {
+			RespondErrorJSON(w, r, http.StatusForbidden, ErrBidderBlocked.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrReputationTooLow) {
+			RespondErrorJSON(w, r, http.StatusForbidden, ErrReputationTooLow.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrNotDutchAuction) {
+			RespondErrorJSON(w, r, http.StatusConflict, ErrNotDutchAuction.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrAuctionEnded) {
+			RespondErrorJSON(w, r, http.StatusConflict, ErrAuctionEnded.Error(), "The auction has already ended", nil)
+			return
+		}
+		if errors.Is(err, service.ErrProductNotLive) {
+			RespondErrorJSON(w, r, http.StatusConflict, ErrProductNotLive.Error(), "The product is not live", nil)
+			return
+		}
+		slog.Error("[DB] failed to accept price", "product_id", productId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"product": product,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Price accepted successfully", resp)
+}
+
+// GetProductBids godoc
+//
+//	@Summary		Get Bids of a Product
+//	@Description	Retrieve the bids placed on a product, newest first, with the currently leading bid marked. Sealed-bid auctions return a null price until the auction is closed.
+//	@Tags			Products
+//	@Produce		json
+//	@Param			productId	path		string	true	"Product ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		500			{object}	map[string]any
+//	@Router			/products/{productId}/bids [get]
+func (h *ProductHandler) GetProductBids(w http.ResponseWriter, r *http.Request) {
+	productId := chi.URLParam(r, productParamKey)
+	product, err := h.svc.GetProductByID(r.Context(), productId, GetViewerID(r.Context()))
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch product", "product_id", productId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve bids", nil)
+		return
+	}
+
+	bids, err := h.svc.GetBidsByProductID(r.Context(), productId)
+	if err != nil {
+		slog.Error("[DB] failed to fetch bids", "product_id", productId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve bids", nil)
+		return
+	}
+
+	hidden := service.BidAmountsHidden(*product)
+	bidderRole := service.BidderRole(*product)
+	leading, hasLeading := service.LeadingBid(*product, bids)
+	bidResponses := make([]model.BidResponse, 0, len(bids))
+	for _, bid := range bids {
+		resp := model.BidResponse{
+			ID:               bid.ID.String(),
+			ProductID:        bid.ProductID.String(),
+			UserID:           bid.UserID.String(),
+			BidderRole:       bidderRole,
+			Quantity:         bid.Quantity,
+			IsValid:          bid.IsValid,
+			Leading:          hasLeading && bid.ID == leading.ID,
+			BidAt:            bid.BidAt,
+			RetractedAt:      bid.RetractedAt,
+			RetractionReason: bid.RetractionReason,
+		}
+		if !hidden {
+			price := bid.Price
+			amount := money.Money{Amount: bid.Price, Currency: product.Currency}
+			resp.Price, resp.Amount = &price, &amount
+		}
+		bidResponses = append(bidResponses, resp)
+	}
+
+	resp := map[string]any{
+		"bids": bidResponses,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Bids fetched successfully", resp)
+}
+
+// BuyNow godoc
+//
+//	@Summary		Buy a Product now
+//	@Description	Buy a product at its buy-now price, which ends the auction immediately. Buy-now is withdrawn once bidding gets close to the buy-now price.
+//	@Tags			Products
+//	@Produce		json
+//	@Param			productId	path		string	true	"Product ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/buy [post]
+func (h *ProductHandler) BuyNow(w http.ResponseWriter, r *http.Request) {
+	productId := chi.URLParam(r, productParamKey)
+	if productId == "" {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrMissingParam.Error(), "Product ID is required", nil)
+		return
+	}
+
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	product, err := h.svc.BuyNow(r.Context(), productId, claims.UserID)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+			return
+		}
+		if errors.Is(err, service.ErrSelfBuying) {
+			RespondErrorJSON(w, r, http.StatusForbidden, ErrSelfBuying.Error(), "You cannot buy your own product", nil)
+			return
+		}
+		if errors.Is(err, service.ErrBidderBlocked) {
+			RespondErrorJSON(w, r, http.StatusForbidden, ErrBidderBlocked.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrReputationTooLow) {
+			RespondErrorJSON(w, r, http.StatusForbidden, ErrReputationTooLow.Error(), err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrAuctionEnded) {
+			RespondErrorJSON(w, r, http.StatusConflict, ErrAuctionEnded.Error(), "The auction has already ended", nil)
+			return
+		}
+		if errors.Is(err, service.ErrProductNotLive) {
+			RespondErrorJSON(w, r, http.StatusConflict, ErrProductNotLive.Error(), "The product is not live", nil)
+			return
+		}
+		if errors.Is(err, service.ErrBuyNowUnavailable) {
+			RespondErrorJSON(w, r, http.StatusConflict, ErrBuyNowUnavailable.Error(), "Buy now is not available for this product", nil)
+			return
+		}
+		slog.Error("[DB] failed to buy product", "product_id", productId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"product": product,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Product bought successfully", resp)
+}
+
 // ProductsBySellerID godoc
 //
 //	@Summary		Get Products by Seller ID
@@ -344,6 +730,7 @@ func (h *ProductHandler) PlaceBid(w http.ResponseWriter, r *http.Request) {
 //	@Param			sellerId	path		string	false	"Seller ID"
 //	@Param			limit		query		int		false	"Number of products to return"
 //	@Param			offset		query		int		false	"Number of products to skip"
+//	@Param			status		query		string	false	"Only products with this status (draft, scheduled, live, ended, relisted), drafts are only listed for their seller"
 //	@Success		200			{object}	map[string]any
 //	@Failure		400			{object}	map[string]any
 //	@Failure		401			{object}	map[string]any
@@ -353,14 +740,18 @@ func (h *ProductHandler) ProductsBySellerID(w http.ResponseWriter, r *http.Reque
 	// If seller id is not given in the URL params, then set the seller id to the current user id
 	var sellerId string
 	sellerId = chi.URLParam(r, sellerParamKey)
+	claims := GetUserClaims(r.Context())
 	if sellerId == "" {
-		claims := GetUserClaims(r.Context())
 		if claims == nil {
 			RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
 			return
 		}
 		sellerId = claims.UserID.String()
 	}
+	var viewerID uuid.UUID
+	if claims != nil {
+		viewerID = claims.UserID
+	}
 	// Get limit and offset from query params for pagination
 	// Default limit is 10 and offset is 0
 	limitParam := r.URL.Query().Get("limit")
@@ -374,15 +765,175 @@ func (h *ProductHandler) ProductsBySellerID(w http.ResponseWriter, r *http.Reque
 		fmt.Sscanf(offsetParam, "%d", &offset)
 	}
 
-	products, err := h.svc.GetProductsBySellerID(r.Context(), sellerId, limit, offset)
+	products, err := h.svc.GetProductsBySellerID(r.Context(), sellerId, viewerID, r.URL.Query().Get("status"), limit, offset)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidStatusFilter) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidStatus.Error(), err.Error(), nil)
+			return
+		}
 		slog.Error("[DB] failed to found products -> ", "seller_id", sellerId, "error", err)
 		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve products", nil)
 		return
 	}
 
+	productResponses := make([]model.ProductResponse, 0, len(products))
+	for _, product := range products {
+		state, err := h.svc.GetBiddingState(r.Context(), product)
+		if err != nil {
+			slog.Error("[DB] failed to compute bidding state", "product_id", product.ID, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve products", nil)
+			return
+		}
+		productResponses = append(productResponses, toProductResponse(product, state, viewerID))
+	}
+
 	resp := map[string]any{
-		"products": products,
+		"products": productResponses,
 	}
 	RespondSuccessJSON(w, r, http.StatusOK, "products fetched successfully", resp)
 }
+
+// liveFeedKeepAlive is how often a comment is sent on an idle live feed so proxies keep the connection open.
+const liveFeedKeepAlive = 15 * time.Second
+
+// publicProductEvents are the only events the live feed of a product forwards. The feed is public, so any other
+// event on the product's channel is dropped in case it carries what only the parties of a sale may see.
+var publicProductEvents = map[string]bool{
+	events.BidPlaced:           true,
+	events.AuctionExtended:     true,
+	events.AuctionPriceDropped: true,
+	events.AuctionPriceUpdated: true,
+	events.AuctionStarted:      true,
+	events.AuctionClosed:       true,
+}
+
+// LiveFeed godoc
+//
+//	@Summary		Live feed of a Product
+//	@Description	Stream the public events of a product (bids, soft-close extensions, Dutch price drops, prices recomputed after retractions, start and close) as Server-Sent Events. The first event is a snapshot of the current price and end time.
+//	@Tags			Products
+//	@Produce		text/event-stream
+//	@Param			productId	path		string	true	"Product ID"
+//	@Success		200			{string}	string
+//	@Failure		404			{object}	map[string]any
+//	@Router			/products/{productId}/live [get]
+func (h *ProductHandler) LiveFeed(w http.ResponseWriter, r *http.Request) {
+	productId := chi.URLParam(r, productParamKey)
+	product, err := h.svc.GetProductByID(r.Context(), productId, GetViewerID(r.Context()))
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch product", "product_id", productId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve product", nil)
+		return
+	}
+
+	state, err := h.svc.GetBiddingState(r.Context(), *product)
+	if err != nil {
+		slog.Error("[DB] failed to compute bidding state", "product_id", productId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve product", nil)
+		return
+	}
+
+	// Subscribe before sending the snapshot so no event between the two is lost
+	messages, closeSub, err := h.cache.Subscribe(r.Context(), cache.EventChannel(product.ID.String()))
+	if err != nil {
+		slog.Error("[Cache] failed to subscribe to product events", "product_id", productId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		return
+	}
+	defer closeSub()
+
+	// The stream outlives the server write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	snapshot, _ := json.Marshal(map[string]any{
+		"product_id":         product.ID,
+		"auction_type":       product.AuctionType,
+		"owner_role":         service.OwnerRole(*product),
+		"current_price":      product.CurrentPrice,
+		"next_min_bid":       state.NextMinBid,
+		"next_max_bid":       state.NextMaxBid,
+		"buy_now_available":  state.BuyNowAvailable,
+		"ends_at":            product.EndsAt,
+		"extension_count":    product.ExtensionCount,
+		"next_price_drop_at": product.NextPriceDropAt,
+		"server_time":        time.Now().UTC(),
+	})
+	fmt.Fprintf(w, "event: snapshot\ndata: %s\n\n", snapshot)
+	rc.Flush()
+
+	keepAlive := time.NewTicker(liveFeedKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var event struct {
+				ID   string `json:"id"`
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal([]byte(msg), &event); err != nil || !publicProductEvents[event.Type] {
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, msg)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// toProductResponse shows the offer thresholds only when the viewer is the seller
+func toProductResponse(product db.Product, state service.BiddingState, viewerID uuid.UUID) model.ProductResponse {
+	resp := model.ProductResponse{
+		Product:         product,
+		Price:           money.Money{Amount: product.CurrentPrice, Currency: product.Currency},
+		OwnerRole:       service.OwnerRole(product),
+		NextMinBid:      state.NextMinBid,
+		NextMaxBid:      state.NextMaxBid,
+		BuyNowAvailable: state.BuyNowAvailable,
+	}
+	if viewerID == product.SellerID {
+		resp.AutoAcceptPrice = product.AutoAcceptPrice
+		resp.AutoDeclinePrice = product.AutoDeclinePrice
+	}
+	return resp
+}
+
+func toShippingOptions(options []model.ShippingOption) []service.ShippingOption {
+	shipping := make([]service.ShippingOption, 0, len(options))
+	for _, o := range options {
+		shipping = append(shipping, service.ShippingOption{
+			Method: o.Method,
+			Region: o.Region,
+			Cost:   o.Cost,
+		})
+	}
+	return shipping
+}
+
+func toIncrementSteps(steps []model.BidIncrementStep) []service.IncrementStep {
+	increments := make([]service.IncrementStep, 0, len(steps))
+	for _, step := range steps {
+		increments = append(increments, service.IncrementStep{
+			MinPrice:  step.MinPrice,
+			Increment: step.Increment,
+		})
+	}
+	return increments
+}
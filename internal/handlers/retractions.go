@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/itsDrac/e-auc/internal/model"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+const (
+	bidParamKey    string = "bidId"
+	bidderParamKey string = "bidderId"
+)
+
+// RetractBid godoc
+//
+//	@Summary		Retract a Bid
+//	@Description	Withdraw your own bid shortly after placing it, with a reason. Bids cannot be retracted in the final hour of an auction. The current price falls back to the best bid left.
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Param			productId	path		string					true	"Product ID"
+//	@Param			bidId		path		string					true	"Bid ID"
+//	@Param			retraction	body		model.RetractBidRequest	true	"Reason for the retraction"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/bids/{bidId}/retract [post]
+func (h *ProductHandler) RetractBid(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	var req model.RetractBidRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidJson.Error(), "invalid json format", nil)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		var details []model.ErrorDetails
+		if validErrs, ok := err.(validator.ValidationErrors); ok {
+			for _, vErr := range validErrs {
+				details = append(details, model.ErrorDetails{
+					Field: vErr.Field(),
+					Issue: fmt.Sprintf("failed on tag '%s' with param '%s'", vErr.Tag(), vErr.Param()),
+				})
+			}
+		}
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrRetractionReasonRequired.Error(), "Input validation failed", details)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	bidId := chi.URLParam(r, bidParamKey)
+	product, err := h.svc.RetractBid(r.Context(), claims.UserID, productId, bidId, req.Reason)
+	if err != nil {
+		respondRetractionError(w, r, err, productId)
+		return
+	}
+
+	resp := map[string]any{
+		"product_id":    product.ID,
+		"current_price": product.CurrentPrice,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Bid retracted successfully", resp)
+}
+
+// CancelBidderBids godoc
+//
+//	@Summary		Cancel the Bids of a bidder
+//	@Description	Cancel every valid bid of a bidder on your live auction. The current price falls back to the best bid left.
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Param			productId		path		string					true	"Product ID"
+//	@Param			bidderId		path		string					true	"Bidder ID"
+//	@Param			cancellation	body		model.CancelBidsRequest	false	"Optional reason"
+//	@Success		200				{object}	map[string]any
+//	@Failure		400				{object}	map[string]any
+//	@Failure		401				{object}	map[string]any
+//	@Failure		403				{object}	map[string]any
+//	@Failure		404				{object}	map[string]any
+//	@Failure		409				{object}	map[string]any
+//	@Router			/products/{productId}/bidders/{bidderId}/cancel-bids [post]
+func (h *ProductHandler) CancelBidderBids(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	var req model.CancelBidsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidJson.Error(), "invalid json format", nil)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRequest.Error(), "Input validation failed", nil)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	bidderId := chi.URLParam(r, bidderParamKey)
+	product, err := h.svc.CancelBidderBids(r.Context(), claims.UserID, productId, bidderId, req.Reason)
+	if err != nil {
+		respondRetractionError(w, r, err, productId)
+		return
+	}
+
+	resp := map[string]any{
+		"product_id":    product.ID,
+		"current_price": product.CurrentPrice,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Bids cancelled successfully", resp)
+}
+
+func respondRetractionError(w http.ResponseWriter, r *http.Request, err error, productId string) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+	case errors.Is(err, service.ErrBidNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrBidNotFound.Error(), "No valid bid found", nil)
+	case errors.Is(err, service.ErrNotProductOwner):
+		RespondErrorJSON(w, r, http.StatusForbidden, ErrNotProductOwner.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrRetractionReasonRequired):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrRetractionReasonRequired.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrRetractionWindowPassed):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrRetractionWindowPassed.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrRetractionFinalHour):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrRetractionFinalHour.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrAuctionEnded):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrAuctionEnded.Error(), "The auction has already ended", nil)
+	case errors.Is(err, service.ErrProductNotLive):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrProductNotLive.Error(), err.Error(), nil)
+	default:
+		slog.Error("[DB] failed to withdraw bids", "product_id", productId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+	}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/itsDrac/e-auc/internal/model"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+const offerParamKey string = "offerId"
+
+type SecondChanceHandler struct {
+	svc service.SecondChanceServicer
+}
+
+func NewSecondChanceHandler(svc service.SecondChanceServicer) (*SecondChanceHandler, error) {
+	return &SecondChanceHandler{
+		svc: svc,
+	}, nil
+}
+
+// CreateSecondChanceOffer godoc
+//
+//	@Summary		Offer an unsold Product to a runner-up
+//	@Description	Offer an ended, unsold product to one of its bidders at that bidder's own price. With auto_advance a declined or expired offer moves on to the next best bidder.
+//	@Tags			Second Chance Offers
+//	@Accept			json
+//	@Produce		json
+//	@Param			productId	path		string						true	"Product ID"
+//	@Param			offer		body		CreateSecondChanceRequest	true	"Bid to make the offer for"
+//	@Success		201			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/products/{productId}/second-chance [post]
+func (h *SecondChanceHandler) CreateSecondChanceOffer(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	var req model.CreateSecondChanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidJson.Error(), "invalid json format", nil)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		var details []model.ErrorDetails
+		if validErrs, ok := err.(validator.ValidationErrors); ok {
+			for _, vErr := range validErrs {
+				details = append(details, model.ErrorDetails{
+					Field: vErr.Field(),
+					Issue: fmt.Sprintf("failed on tag '%s' with param '%s'", vErr.Tag(), vErr.Param()),
+				})
+			}
+		}
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRequest.Error(), "Input validation failed", details)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	window := time.Duration(req.ExpiresInHours) * time.Hour
+	offer, err := h.svc.OfferToRunnerUp(r.Context(), claims.UserID, productId, req.BidID, window, req.AutoAdvance)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrProductNotFound):
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+		case errors.Is(err, service.ErrNotProductOwner):
+			RespondErrorJSON(w, r, http.StatusForbidden, ErrNotProductOwner.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrSecondChanceUnavailable):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrSecondChanceUnavailable.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrSecondChancePending):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrSecondChancePending.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrBidNotEligible):
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrBidNotEligible.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrInvalidOfferWindow):
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidOfferWindow.Error(), err.Error(), nil)
+		default:
+			slog.Error("[DB] failed to create second-chance offer", "product_id", productId, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		}
+		return
+	}
+
+	resp := map[string]any{
+		"offer": offer,
+	}
+	RespondSuccessJSON(w, r, http.StatusCreated, "Second-chance offer sent successfully", resp)
+}
+
+// ListProductSecondChanceOffers godoc
+//
+//	@Summary		List second-chance offers of a Product
+//	@Description	Retrieve every second-chance offer the seller made for a product, oldest first
+//	@Tags			Second Chance Offers
+//	@Produce		json
+//	@Param			productId	path		string	true	"Product ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		403			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Router			/products/{productId}/second-chance [get]
+func (h *SecondChanceHandler) ListProductSecondChanceOffers(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	productId := chi.URLParam(r, productParamKey)
+	offers, err := h.svc.GetProductOffers(r.Context(), claims.UserID, productId)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrProductNotFound):
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+		case errors.Is(err, service.ErrNotProductOwner):
+			RespondErrorJSON(w, r, http.StatusForbidden, ErrNotProductOwner.Error(), err.Error(), nil)
+		default:
+			slog.Error("[DB] failed to fetch second-chance offers", "product_id", productId, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve offers", nil)
+		}
+		return
+	}
+
+	resp := map[string]any{
+		"offers": offers,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Offers fetched successfully", resp)
+}
+
+// ListMySecondChanceOffers godoc
+//
+//	@Summary		List my open second-chance offers
+//	@Description	Retrieve the second-chance offers waiting for the current user's answer, the soonest to expire first
+//	@Tags			Second Chance Offers
+//	@Produce		json
+//	@Success		200	{object}	map[string]any
+//	@Failure		401	{object}	map[string]any
+//	@Router			/second-chance-offers [get]
+func (h *SecondChanceHandler) ListMySecondChanceOffers(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	offers, err := h.svc.GetPendingOffers(r.Context(), claims.UserID)
+	if err != nil {
+		slog.Error("[DB] failed to fetch second-chance offers", "user_id", claims.UserID, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve offers", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"offers": offers,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Offers fetched successfully", resp)
+}
+
+// AcceptSecondChanceOffer godoc
+//
+//	@Summary		Accept a second-chance offer
+//	@Description	Buy the offered product at the price of your bid
+//	@Tags			Second Chance Offers
+//	@Produce		json
+//	@Param			offerId	path		string	true	"Offer ID"
+//	@Success		200		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Failure		409		{object}	map[string]any
+//	@Router			/second-chance-offers/{offerId}/accept [post]
+func (h *SecondChanceHandler) AcceptSecondChanceOffer(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	offerId := chi.URLParam(r, offerParamKey)
+	product, err := h.svc.AcceptOffer(r.Context(), claims.UserID, offerId)
+	if err != nil {
+		respondSecondChanceError(w, r, err, offerId)
+		return
+	}
+
+	resp := map[string]any{
+		"product": product,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Offer accepted successfully", resp)
+}
+
+// DeclineSecondChanceOffer godoc
+//
+//	@Summary		Decline a second-chance offer
+//	@Description	Turn down a second-chance offer, the seller may have it move on to the next bidder
+//	@Tags			Second Chance Offers
+//	@Produce		json
+//	@Param			offerId	path		string	true	"Offer ID"
+//	@Success		200		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Failure		409		{object}	map[string]any
+//	@Router			/second-chance-offers/{offerId}/decline [post]
+func (h *SecondChanceHandler) DeclineSecondChanceOffer(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	offerId := chi.URLParam(r, offerParamKey)
+	offer, err := h.svc.DeclineOffer(r.Context(), claims.UserID, offerId)
+	if err != nil {
+		respondSecondChanceError(w, r, err, offerId)
+		return
+	}
+
+	resp := map[string]any{
+		"offer": offer,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Offer declined successfully", resp)
+}
+
+func respondSecondChanceError(w http.ResponseWriter, r *http.Request, err error, offerId string) {
+	switch {
+	case errors.Is(err, service.ErrOfferNotFound):
+		RespondErrorJSON(w, r, http.StatusNotFound, ErrOfferNotFound.Error(), "Offer not found", nil)
+	case errors.Is(err, service.ErrOfferNotPending):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrOfferNotPending.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrSecondChanceUnavailable):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrSecondChanceUnavailable.Error(), err.Error(), nil)
+	default:
+		slog.Error("[DB] failed to answer second-chance offer", "offer_id", offerId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+	}
+}
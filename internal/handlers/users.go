@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,7 +11,9 @@ import (
 	"time"
 
 	// "github.com/itsDrac/e-auc/internal/types"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/itsDrac/e-auc/internal/cache"
 	db "github.com/itsDrac/e-auc/internal/database"
 	"github.com/itsDrac/e-auc/internal/model"
@@ -21,6 +24,8 @@ import (
 
 var validate = valid.GetValidator()
 
+const usernameParamKey string = "username"
+
 type UserHandler struct {
 	userService service.UserServicer
 	authService service.AuthServicer
@@ -281,6 +286,276 @@ func (h *UserHandler) Profile(w http.ResponseWriter, r *http.Request) {
 	RespondSuccessJSON(w, r, http.StatusOK, "Profile data fetched successfully", user)
 }
 
+// PublicProfile godoc
+//
+//	@Summary		Get the Public Profile of a User
+//	@Description	Get the profile and storefront of a user by username: join date, bio, avatar and banner, reputation, featured listings and the public active and sold listings. Email and other account details are not shown.
+//	@Tags			Users
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Param			limit		query		int		false	"Number of active and sold listings to return"
+//	@Param			offset		query		int		false	"Number of active and sold listings to skip"
+//	@Success		200			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Router			/users/{username} [get]
+func (h *UserHandler) PublicProfile(w http.ResponseWriter, r *http.Request) {
+	username := chi.URLParam(r, usernameParamKey)
+	limit, offset := paginationParams(r)
+
+	profile, err := h.userService.GetPublicProfile(r.Context(), username, limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrUserNotFound.Error(), "User not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch public profile", "username", username, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "profile could not be retrieved", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"profile": profile,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Profile fetched successfully", resp)
+}
+
+// UpdateProfile godoc
+//
+//	@Summary		Update Your Profile
+//	@Description	Change your username and storefront bio. Omitted fields are left alone and an empty bio clears it.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			profile	body		model.UpdateProfileRequest	true	"Profile changes"
+//	@Success		200		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Failure		409		{object}	map[string]any
+//	@Router			/users/me [patch]
+func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.UpdateProfileRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(r.Context(), claims.UserID, req.Username, req.Bio)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrUserNotFound.Error(), "User not found", nil)
+		case errors.Is(err, service.ErrUsernameTaken):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrUsernameTaken.Error(), err.Error(), nil)
+		default:
+			slog.Error("[DB] failed to update profile", "user_id", claims.UserID, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		}
+		return
+	}
+
+	RespondSuccessJSON(w, r, http.StatusOK, "Profile updated successfully", user)
+}
+
+// ChangeEmail godoc
+//
+//	@Summary		Change Your Email
+//	@Description	Request a new email for your account. A verification code is mailed to it and the email only changes once the code is confirmed, within EMAIL_VERIFICATION_HOURS (default 24).
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			email	body		model.ChangeEmailRequest	true	"New email"
+//	@Success		202		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Failure		409		{object}	map[string]any
+//	@Router			/users/me/email [post]
+func (h *UserHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.ChangeEmailRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	if err := h.userService.RequestEmailChange(r.Context(), claims.UserID, req.Email); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrUserNotFound.Error(), "User not found", nil)
+		case errors.Is(err, service.ErrEmailUnchanged):
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrEmailUnchanged.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrEmailTaken):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrEmailTaken.Error(), err.Error(), nil)
+		default:
+			slog.Error("[Mailer] failed to request email change", "user_id", claims.UserID, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		}
+		return
+	}
+
+	resp := map[string]any{
+		"pending_email": req.Email,
+	}
+	RespondSuccessJSON(w, r, http.StatusAccepted, "Verification code sent to the new email", resp)
+}
+
+// VerifyEmail godoc
+//
+//	@Summary		Confirm Your New Email
+//	@Description	Confirm the pending email of your account with the code mailed to it, which makes it the email of the account.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	body		model.VerifyEmailRequest	true	"Verification code"
+//	@Success		200		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Failure		409		{object}	map[string]any
+//	@Router			/users/me/email/verify [post]
+func (h *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.VerifyEmailRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	user, err := h.userService.ConfirmEmailChange(r.Context(), claims.UserID, req.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrUserNotFound.Error(), "User not found", nil)
+		case errors.Is(err, service.ErrInvalidVerificationToken):
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidVerificationToken.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrEmailTaken):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrEmailTaken.Error(), err.Error(), nil)
+		default:
+			slog.Error("[DB] failed to confirm email change", "user_id", claims.UserID, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		}
+		return
+	}
+
+	RespondSuccessJSON(w, r, http.StatusOK, "Email changed successfully", user)
+}
+
+// UploadAvatar godoc
+//
+//	@Summary		Upload Your Avatar
+//	@Description	Replace your avatar with the uploaded image, up to 10MB.
+//	@Tags			Users
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			avatar	formData	file	true	"Avatar image"
+//	@Success		200		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Router			/users/me/avatar [post]
+func (h *UserHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	h.uploadProfileImage(w, r, "avatar", h.userService.UploadAvatar)
+}
+
+// UploadBanner godoc
+//
+//	@Summary		Upload Your Storefront Banner
+//	@Description	Replace the banner of your storefront with the uploaded image, up to 10MB.
+//	@Tags			Users
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			banner	formData	file	true	"Banner image"
+//	@Success		200		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Router			/users/me/storefront/banner [post]
+func (h *UserHandler) UploadBanner(w http.ResponseWriter, r *http.Request) {
+	h.uploadProfileImage(w, r, "banner", h.userService.UploadBanner)
+}
+
+// SetFeaturedListings godoc
+//
+//	@Summary		Set the Featured Listings of Your Storefront
+//	@Description	Replace the listings featured on your storefront, at most 6 in display order. Only your own public listings that are not drafts can be featured, an empty list clears them.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			featured	body		model.SetFeaturedListingsRequest	true	"Featured product IDs"
+//	@Success		200			{object}	map[string]any
+//	@Failure		400			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/users/me/storefront/featured [put]
+func (h *UserHandler) SetFeaturedListings(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	var req model.SetFeaturedListingsRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	featured, err := h.userService.SetFeaturedListings(r.Context(), claims.UserID, req.ProductIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTooManyFeaturedListings):
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrTooManyFeaturedListings.Error(), err.Error(), nil)
+		case errors.Is(err, service.ErrProductNotFound):
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrProductNotFound.Error(), "Product not found", nil)
+		case errors.Is(err, service.ErrListingNotFeaturable):
+			RespondErrorJSON(w, r, http.StatusConflict, ErrListingNotFeaturable.Error(), err.Error(), nil)
+		default:
+			slog.Error("[DB] failed to set featured listings", "user_id", claims.UserID, "error", err)
+			RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		}
+		return
+	}
+
+	resp := map[string]any{
+		"featured_listings": featured,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Featured listings saved successfully", resp)
+}
+
+// uploadProfileImage stores the image of the form field through upload and responds with its URL.
+func (h *UserHandler) uploadProfileImage(w http.ResponseWriter, r *http.Request, field string, upload func(ctx context.Context, userID uuid.UUID, filename string, data []byte) (string, error)) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	data, filename, ok := readUploadedImage(w, r, field)
+	if !ok {
+		return
+	}
+
+	url, err := upload(r.Context(), claims.UserID, filename, data)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrUserNotFound.Error(), "User not found", nil)
+			return
+		}
+		slog.Error("Error on uploading profile image", "field", field, "err:", err.Error())
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrUploadFailed.Error(), "failed to store image", nil)
+		return
+	}
+
+	resp := map[string]any{
+		field + "_url": url,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Image uploaded successfully", resp)
+}
+
 func setRefreshTokenCookie(w http.ResponseWriter, token string, expiry time.Time) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     config.RefreshTokenCookieName,
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/model"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+const idempotencyKeyHeader string = "Idempotency-Key"
+
+type WalletHandler struct {
+	svc service.WalletServicer
+}
+
+func NewWalletHandler(svc service.WalletServicer) (*WalletHandler, error) {
+	return &WalletHandler{
+		svc: svc,
+	}, nil
+}
+
+// GetWallet godoc
+//
+//	@Summary		Get your Wallet
+//	@Description	Get the available and held balance of your wallet with its transactions, newest first. Amounts are in minor units.
+//	@Tags			Wallet
+//	@Produce		json
+//	@Param			limit	query		int	false	"Number of transactions to return"
+//	@Param			offset	query		int	false	"Number of transactions to skip"
+//	@Success		200		{object}	model.WalletResponse
+//	@Failure		401		{object}	map[string]any
+//	@Router			/users/me/wallet [get]
+func (h *WalletHandler) GetWallet(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	limit, offset := paginationParams(r)
+
+	wallet, err := h.svc.GetWallet(r.Context(), claims.UserID)
+	if err != nil {
+		slog.Error("[DB] failed to fetch wallet", "user_id", claims.UserID, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve wallet", nil)
+		return
+	}
+	transactions, err := h.svc.GetTransactions(r.Context(), claims.UserID, limit, offset)
+	if err != nil {
+		slog.Error("[DB] failed to fetch wallet transactions", "user_id", claims.UserID, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve wallet transactions", nil)
+		return
+	}
+
+	resp := model.WalletResponse{
+		Available:    wallet.Available,
+		Held:         wallet.Held,
+		Total:        wallet.Available + wallet.Held,
+		Transactions: transactions,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Wallet fetched successfully", resp)
+}
+
+// Deposit godoc
+//
+//	@Summary		Deposit into your Wallet
+//	@Description	Add funds to your available balance, charged to your payment method through the payment provider. Retrying with the same Idempotency-Key returns the first deposit instead of charging and depositing twice.
+//	@Tags			Wallet
+//	@Accept			json
+//	@Produce		json
+//	@Param			Idempotency-Key	header		string						true	"Unique key of this deposit"
+//	@Param			deposit			body		model.WalletDepositRequest	true	"Amount in minor units and payment token"
+//	@Success		201				{object}	map[string]any
+//	@Failure		400				{object}	map[string]any
+//	@Failure		401				{object}	map[string]any
+//	@Failure		402				{object}	map[string]any
+//	@Failure		409				{object}	map[string]any
+//	@Router			/users/me/wallet/deposits [post]
+func (h *WalletHandler) Deposit(w http.ResponseWriter, r *http.Request) {
+	var req model.WalletDepositRequest
+	h.transact(w, r, &req, func(ctx context.Context, userID uuid.UUID, key string) (db.JournalEntry, error) {
+		return h.svc.Deposit(ctx, userID, req.Amount, key, req.PaymentToken)
+	}, "Deposit recorded successfully")
+}
+
+// Withdraw godoc
+//
+//	@Summary		Withdraw from your Wallet
+//	@Description	Take funds out of your available balance, held funds cannot be withdrawn. Retrying with the same Idempotency-Key returns the first withdrawal instead of withdrawing twice.
+//	@Tags			Wallet
+//	@Accept			json
+//	@Produce		json
+//	@Param			Idempotency-Key	header		string							true	"Unique key of this withdrawal"
+//	@Param			withdrawal		body		model.WalletTransactionRequest	true	"Amount in minor units"
+//	@Success		201				{object}	map[string]any
+//	@Failure		400				{object}	map[string]any
+//	@Failure		401				{object}	map[string]any
+//	@Failure		402				{object}	map[string]any
+//	@Failure		409				{object}	map[string]any
+//	@Router			/users/me/wallet/withdrawals [post]
+func (h *WalletHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	var req model.WalletTransactionRequest
+	h.transact(w, r, &req, func(ctx context.Context, userID uuid.UUID, key string) (db.JournalEntry, error) {
+		return h.svc.Withdraw(ctx, userID, req.Amount, key)
+	}, "Withdrawal recorded successfully")
+}
+
+// transact decodes req and posts a deposit or withdrawal of the current user keyed by the Idempotency-Key header
+func (h *WalletHandler) transact(w http.ResponseWriter, r *http.Request, req any, post func(context.Context, uuid.UUID, string) (db.JournalEntry, error), message string) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+	key := strings.TrimSpace(r.Header.Get(idempotencyKeyHeader))
+	if key == "" {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrMissingIdempotencyKey.Error(), "Idempotency-Key header is required", nil)
+		return
+	}
+	if !decodeJSONRequest(w, r, req) {
+		return
+	}
+
+	entry, err := post(r.Context(), claims.UserID, key)
+	if err != nil {
+		respondWalletError(w, r, err, claims.UserID)
+		return
+	}
+
+	resp := map[string]any{
+		"transaction": entry,
+	}
+	RespondSuccessJSON(w, r, http.StatusCreated, message, resp)
+}
+
+func respondWalletError(w http.ResponseWriter, r *http.Request, err error, userID uuid.UUID) {
+	switch {
+	case errors.Is(err, service.ErrInvalidAmount):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRequest.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrIdempotencyKeyRequired):
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrMissingIdempotencyKey.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrInsufficientFunds):
+		RespondErrorJSON(w, r, http.StatusPaymentRequired, ErrInsufficientFunds.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrPaymentDeclined):
+		RespondErrorJSON(w, r, http.StatusPaymentRequired, ErrPaymentDeclined.Error(), err.Error(), nil)
+	case errors.Is(err, service.ErrIdempotencyKeyReused):
+		RespondErrorJSON(w, r, http.StatusConflict, ErrIdempotencyKeyReused.Error(), err.Error(), nil)
+	default:
+		slog.Error("[DB] failed to post wallet transaction", "user_id", userID, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+	}
+}
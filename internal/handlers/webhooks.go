@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/model"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+const (
+	webhookParamKey  string = "webhookId"
+	deliveryParamKey string = "deliveryId"
+)
+
+type WebhookHandler struct {
+	svc service.WebhookServicer
+}
+
+func NewWebhookHandler(svc service.WebhookServicer) (*WebhookHandler, error) {
+	return &WebhookHandler{
+		svc: svc,
+	}, nil
+}
+
+// CreateWebhook godoc
+//
+//	@Summary		Register a Webhook endpoint
+//	@Description	Register an endpoint that receives signed event notifications for the seller's products. The signing secret is only returned once. URLs resolving to loopback, private or link-local addresses are rejected.
+//	@Tags			Webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			webhook	body		CreateWebhookRequest	true	"Webhook details"
+//	@Success		201		{object}	map[string]any
+//	@Failure		400		{object}	map[string]any
+//	@Failure		401		{object}	map[string]any
+//	@Router			/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidJson.Error(), "invalid json format", nil)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		var details []model.ErrorDetails
+		if validErrs, ok := err.(validator.ValidationErrors); ok {
+			for _, vErr := range validErrs {
+				details = append(details, model.ErrorDetails{
+					Field: vErr.Field(),
+					Issue: fmt.Sprintf("failed on tag '%s' with param '%s'", vErr.Tag(), vErr.Param()),
+				})
+			}
+		}
+		RespondErrorJSON(w, r, http.StatusBadRequest, ErrInvalidRequest.Error(), "Input validation failed", details)
+		return
+	}
+
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	endpoint, err := h.svc.RegisterEndpoint(r.Context(), claims.UserID, req.URL, req.EventTypes)
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookURLNotAllowed) {
+			RespondErrorJSON(w, r, http.StatusBadRequest, ErrWebhookURLNotAllowed.Error(), "Webhook URL must resolve to a public address", nil)
+			return
+		}
+		slog.Error("[DB] failed to create webhook endpoint", "seller_id", claims.UserID, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrInternalServer.Error(), "Internal server error", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"webhook": endpoint,
+		"secret":  endpoint.Secret,
+	}
+	RespondSuccessJSON(w, r, http.StatusCreated, "Webhook created successfully", resp)
+}
+
+// ListWebhooks godoc
+//
+//	@Summary		List Webhook endpoints
+//	@Description	List the webhook endpoints registered by the current user
+//	@Tags			Webhooks
+//	@Produce		json
+//	@Success		200	{object}	map[string]any
+//	@Failure		401	{object}	map[string]any
+//	@Router			/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	endpoints, err := h.svc.GetEndpoints(r.Context(), claims.UserID)
+	if err != nil {
+		slog.Error("[DB] failed to fetch webhook endpoints", "seller_id", claims.UserID, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve webhooks", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"webhooks": endpoints,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Webhooks fetched successfully", resp)
+}
+
+// DeleteWebhook godoc
+//
+//	@Summary		Delete a Webhook endpoint
+//	@Description	Delete a webhook endpoint together with its delivery log
+//	@Tags			Webhooks
+//	@Produce		json
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Router			/webhooks/{webhookId} [delete]
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	webhookId := chi.URLParam(r, webhookParamKey)
+	if err := h.svc.DeleteEndpoint(r.Context(), claims.UserID, webhookId); err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrWebhookNotFound.Error(), "Webhook not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to delete webhook endpoint", "webhook_id", webhookId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to delete webhook", nil)
+		return
+	}
+
+	RespondSuccessJSON(w, r, http.StatusOK, "Webhook deleted successfully", "")
+}
+
+// ListDeliveries godoc
+//
+//	@Summary		List Webhook deliveries
+//	@Description	Retrieve the delivery log of a webhook endpoint, newest first
+//	@Tags			Webhooks
+//	@Produce		json
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Param			limit		query		int		false	"Number of deliveries to return"
+//	@Param			offset		query		int		false	"Number of deliveries to skip"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Router			/webhooks/{webhookId}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	webhookId := chi.URLParam(r, webhookParamKey)
+	limit, offset := paginationParams(r)
+
+	deliveries, err := h.svc.GetDeliveries(r.Context(), claims.UserID, webhookId, limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrWebhookNotFound.Error(), "Webhook not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch webhook deliveries", "webhook_id", webhookId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve deliveries", nil)
+		return
+	}
+
+	items := make([]model.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		items = append(items, toWebhookDeliveryResponse(delivery, nil))
+	}
+	resp := map[string]any{
+		"deliveries": items,
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Deliveries fetched successfully", resp)
+}
+
+// GetDelivery godoc
+//
+//	@Summary		Get a Webhook delivery
+//	@Description	Retrieve a single delivery with every attempt and the response codes received
+//	@Tags			Webhooks
+//	@Produce		json
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Param			deliveryId	path		string	true	"Delivery ID"
+//	@Success		200			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Router			/webhooks/{webhookId}/deliveries/{deliveryId} [get]
+func (h *WebhookHandler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	webhookId := chi.URLParam(r, webhookParamKey)
+	deliveryId := chi.URLParam(r, deliveryParamKey)
+	delivery, attempts, err := h.svc.GetDelivery(r.Context(), claims.UserID, deliveryId)
+	if err == nil && delivery.EndpointID.String() != webhookId {
+		err = service.ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookDeliveryNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrWebhookDeliveryNotFound.Error(), "Delivery not found", nil)
+			return
+		}
+		slog.Error("[DB] failed to fetch webhook delivery", "delivery_id", deliveryId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to retrieve delivery", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"delivery": toWebhookDeliveryResponse(delivery, attempts),
+	}
+	RespondSuccessJSON(w, r, http.StatusOK, "Delivery fetched successfully", resp)
+}
+
+// RedeliverDelivery godoc
+//
+//	@Summary		Redeliver a Webhook delivery
+//	@Description	Queue a delivery to be sent again, regardless of its current status
+//	@Tags			Webhooks
+//	@Produce		json
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Param			deliveryId	path		string	true	"Delivery ID"
+//	@Success		202			{object}	map[string]any
+//	@Failure		401			{object}	map[string]any
+//	@Failure		404			{object}	map[string]any
+//	@Failure		409			{object}	map[string]any
+//	@Router			/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) RedeliverDelivery(w http.ResponseWriter, r *http.Request) {
+	claims := GetUserClaims(r.Context())
+	if claims == nil {
+		RespondErrorJSON(w, r, http.StatusUnauthorized, ErrAuthFailed.Error(), "user claims not found in context", nil)
+		return
+	}
+
+	webhookId := chi.URLParam(r, webhookParamKey)
+	deliveryId := chi.URLParam(r, deliveryParamKey)
+	delivery, _, err := h.svc.GetDelivery(r.Context(), claims.UserID, deliveryId)
+	if err == nil && delivery.EndpointID.String() != webhookId {
+		err = service.ErrWebhookDeliveryNotFound
+	}
+	if err == nil {
+		delivery, err = h.svc.Redeliver(r.Context(), claims.UserID, deliveryId)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookDeliveryNotFound) {
+			RespondErrorJSON(w, r, http.StatusNotFound, ErrWebhookDeliveryNotFound.Error(), "Delivery not found", nil)
+			return
+		}
+		if errors.Is(err, service.ErrWebhookDeliveryInFlight) {
+			RespondErrorJSON(w, r, http.StatusConflict, ErrWebhookDeliveryInFlight.Error(), "Delivery is currently being attempted", nil)
+			return
+		}
+		slog.Error("[DB] failed to redeliver webhook", "delivery_id", deliveryId, "error", err)
+		RespondErrorJSON(w, r, http.StatusInternalServerError, ErrDb.Error(), "failed to redeliver webhook", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"delivery": toWebhookDeliveryResponse(delivery, nil),
+	}
+	RespondSuccessJSON(w, r, http.StatusAccepted, "Delivery queued for redelivery", resp)
+}
+
+func toWebhookDeliveryResponse(d db.WebhookDelivery, attempts []db.WebhookDeliveryAttempt) model.WebhookDeliveryResponse {
+	resp := model.WebhookDeliveryResponse{
+		ID:               d.ID.String(),
+		EndpointID:       d.EndpointID.String(),
+		EventID:          d.EventID.String(),
+		EventType:        d.EventType,
+		Payload:          d.Payload,
+		Status:           d.Status,
+		Attempts:         d.Attempts,
+		MaxAttempts:      d.MaxAttempts,
+		NextAttemptAt:    d.NextAttemptAt,
+		LastResponseCode: d.LastResponseCode,
+		LastError:        d.LastError,
+		CreatedAt:        d.CreatedAt,
+		DeliveredAt:      d.DeliveredAt,
+	}
+	for _, a := range attempts {
+		resp.AttemptLog = append(resp.AttemptLog, model.WebhookAttemptResponse{
+			Attempt:      a.Attempt,
+			ResponseCode: a.ResponseCode,
+			Error:        a.Error,
+			DurationMs:   a.DurationMs,
+			AttemptedAt:  a.AttemptedAt,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,135 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/itsDrac/e-auc/pkg/utils"
+)
+
+// Job statuses, mirrored by the CHECK constraint on the jobs table.
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusCancelled = "cancelled"
+	StatusDead      = "dead"
+)
+
+var (
+	ErrUnknownKind = errors.New("no handler registered for job kind")
+	ErrDuplicate   = errors.New("a job with the same unique key already exists")
+)
+
+// Job is what a handler receives. Attempt starts at 1.
+type Job struct {
+	ID          uuid.UUID
+	Kind        string
+	Payload     json.RawMessage
+	Attempt     int32
+	MaxAttempts int32
+}
+
+// Handler runs one job. Returning an error schedules a retry until the job runs out of attempts,
+// after which it is moved to the dead-letter table.
+type Handler func(ctx context.Context, job Job) error
+
+// Typed adapts a handler that takes a decoded payload.
+// A payload that cannot be decoded is never retried.
+func Typed[T any](fn func(ctx context.Context, payload T) error) Handler {
+	return func(ctx context.Context, job Job) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return Permanent(fmt.Errorf("failed to decode %s payload: %w", job.Kind, err))
+		}
+		return fn(ctx, payload)
+	}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying, the job goes straight to the dead-letter table.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
+
+// Options configure how jobs of one kind are executed.
+type Options struct {
+	// MaxAttempts is used for jobs enqueued without their own limit.
+	MaxAttempts int32
+	// Concurrency is the number of jobs of this kind run at once by one process.
+	Concurrency int
+	// Timeout bounds a single attempt.
+	Timeout time.Duration
+	// Backoff returns the delay before the given attempt is retried.
+	Backoff func(attempt int32) time.Duration
+}
+
+const (
+	defaultMaxAttempts = 5
+	defaultConcurrency = 1
+	defaultTimeout     = 5 * time.Minute
+	defaultBaseBackoff = 10 * time.Second
+	defaultMaxBackoff  = time.Hour
+)
+
+func (o Options) withDefaults() Options {
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = defaultMaxAttempts
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = defaultConcurrency
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = defaultTimeout
+	}
+	if o.Backoff == nil {
+		o.Backoff = func(attempt int32) time.Duration {
+			return utils.ExponentialBackoff(defaultBaseBackoff, defaultMaxBackoff, attempt)
+		}
+	}
+	return o
+}
+
+type enqueueOptions struct {
+	runAt       time.Time
+	maxAttempts int32
+	uniqueKey   *string
+}
+
+// EnqueueOption customizes a single enqueued job.
+type EnqueueOption func(*enqueueOptions)
+
+// At schedules the job to run no earlier than t.
+func At(t time.Time) EnqueueOption {
+	return func(o *enqueueOptions) { o.runAt = t }
+}
+
+// After delays the job by d.
+func After(d time.Duration) EnqueueOption {
+	return func(o *enqueueOptions) { o.runAt = time.Now().Add(d) }
+}
+
+// MaxAttempts overrides the retry limit of the job kind.
+func MaxAttempts(n int32) EnqueueOption {
+	return func(o *enqueueOptions) { o.maxAttempts = n }
+}
+
+// UniqueKey makes enqueueing idempotent, a second job with the same key returns ErrDuplicate.
+func UniqueKey(key string) EnqueueOption {
+	return func(o *enqueueOptions) { o.uniqueKey = &key }
+}
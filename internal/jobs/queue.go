@@ -0,0 +1,265 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	pollInterval = time.Second
+	// Running jobs locked for longer than this are assumed to belong to a dead process.
+	staleAfter = 15 * time.Minute
+)
+
+type registration struct {
+	handler Handler
+	opts    Options
+	sem     chan struct{}
+}
+
+type schedule struct {
+	kind     string
+	interval time.Duration
+	last     time.Time
+}
+
+// Queue is a durable job queue backed by the jobs table.
+// Jobs are claimed with FOR UPDATE SKIP LOCKED, so any number of processes can work the same queue.
+type Queue struct {
+	store db.Store
+
+	mu        sync.RWMutex
+	kinds     map[string]*registration
+	schedules []*schedule
+
+	running sync.WaitGroup
+}
+
+func NewQueue(store db.Store) *Queue {
+	return &Queue{
+		store: store,
+		kinds: make(map[string]*registration),
+	}
+}
+
+// Register sets the handler for a job kind. It must be called before Run.
+func (q *Queue) Register(kind string, h Handler, opts Options) {
+	opts = opts.withDefaults()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.kinds[kind] = &registration{
+		handler: h,
+		opts:    opts,
+		sem:     make(chan struct{}, opts.Concurrency),
+	}
+}
+
+// Every enqueues a job of the given kind once per interval.
+// The run time is used as unique key, so replicas sharing the queue enqueue it only once.
+func (q *Queue) Every(kind string, interval time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.schedules = append(q.schedules, &schedule{kind: kind, interval: interval})
+}
+
+// Enqueue stores a new job, see EnqueueTx to enqueue as part of a transaction.
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload any, opts ...EnqueueOption) (db.Job, error) {
+	return q.EnqueueTx(ctx, q.store, kind, payload, opts...)
+}
+
+// EnqueueTx stores a new job using the given querier, so the job is only visible once the caller's transaction commits.
+func (q *Queue) EnqueueTx(ctx context.Context, tx db.Querier, kind string, payload any, opts ...EnqueueOption) (db.Job, error) {
+	q.mu.RLock()
+	reg, ok := q.kinds[kind]
+	q.mu.RUnlock()
+	if !ok {
+		return db.Job{}, fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+
+	o := enqueueOptions{runAt: time.Now(), maxAttempts: reg.opts.MaxAttempts}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return db.Job{}, fmt.Errorf("failed to encode %s payload: %w", kind, err)
+	}
+
+	job, err := tx.EnqueueJob(ctx, db.EnqueueJobParams{
+		Kind:        kind,
+		Payload:     body,
+		MaxAttempts: o.maxAttempts,
+		RunAt:       o.runAt,
+		UniqueKey:   o.uniqueKey,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Job{}, ErrDuplicate
+		}
+		return db.Job{}, err
+	}
+	return job, nil
+}
+
+// Run polls for due jobs until ctx is cancelled, then waits for running jobs to finish.
+func (q *Queue) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			q.running.Wait()
+			return
+		case <-ticker.C:
+			q.tick(ctx)
+		}
+	}
+}
+
+// RunDue claims every due job with free capacity, runs them and waits until they are done.
+// It returns the number of jobs that were run.
+func (q *Queue) RunDue(ctx context.Context) (int, error) {
+	var wg sync.WaitGroup
+	n, err := q.claimAndRun(ctx, &wg)
+	wg.Wait()
+	return n, err
+}
+
+func (q *Queue) tick(ctx context.Context) {
+	q.enqueueScheduled(ctx)
+	if _, err := q.store.RequeueStaleJobs(ctx, time.Now().Add(-staleAfter)); err != nil && ctx.Err() == nil {
+		slog.Error("[Jobs] failed to requeue stale jobs", "error", err)
+	}
+	if _, err := q.claimAndRun(ctx, &q.running); err != nil && ctx.Err() == nil {
+		slog.Error("[Jobs] failed to claim jobs", "error", err)
+	}
+}
+
+func (q *Queue) enqueueScheduled(ctx context.Context) {
+	q.mu.RLock()
+	schedules := q.schedules
+	q.mu.RUnlock()
+
+	now := time.Now()
+	for _, s := range schedules {
+		runAt := now.Truncate(s.interval)
+		if !runAt.After(s.last) {
+			continue
+		}
+		key := fmt.Sprintf("%s@%d", s.kind, runAt.Unix())
+		_, err := q.Enqueue(ctx, s.kind, struct{}{}, At(runAt), UniqueKey(key))
+		if err != nil && !errors.Is(err, ErrDuplicate) {
+			slog.Error("[Jobs] failed to enqueue scheduled job", "kind", s.kind, "error", err)
+			continue
+		}
+		s.last = runAt
+	}
+}
+
+// claimAndRun claims at most as many jobs per kind as that kind has free slots.
+func (q *Queue) claimAndRun(ctx context.Context, wg *sync.WaitGroup) (int, error) {
+	q.mu.RLock()
+	kinds := make(map[string]*registration, len(q.kinds))
+	for kind, reg := range q.kinds {
+		kinds[kind] = reg
+	}
+	q.mu.RUnlock()
+
+	started := 0
+	for kind, reg := range kinds {
+		free := cap(reg.sem) - len(reg.sem)
+		if free == 0 {
+			continue
+		}
+		claimed, err := q.store.ClaimDueJobs(ctx, db.ClaimDueJobsParams{
+			Kind:  kind,
+			Limit: int32(free),
+		})
+		if err != nil {
+			return started, err
+		}
+		for _, job := range claimed {
+			reg.sem <- struct{}{}
+			wg.Add(1)
+			started++
+			go func(job db.Job) {
+				defer wg.Done()
+				defer func() { <-reg.sem }()
+				q.execute(ctx, reg, job)
+			}(job)
+		}
+	}
+	return started, nil
+}
+
+func (q *Queue) execute(ctx context.Context, reg *registration, row db.Job) {
+	job := Job{
+		ID:          row.ID,
+		Kind:        row.Kind,
+		Payload:     row.Payload,
+		Attempt:     row.Attempts,
+		MaxAttempts: row.MaxAttempts,
+	}
+
+	// Results are recorded even when the queue is shutting down, otherwise the job stays locked until it goes stale.
+	runCtx, cancel := context.WithTimeout(ctx, reg.opts.Timeout)
+	err := runHandler(runCtx, reg.handler, job)
+	cancel()
+	recordCtx := context.WithoutCancel(ctx)
+
+	if err == nil {
+		if err := q.store.CompleteJob(recordCtx, job.ID); err != nil {
+			slog.Error("[Jobs] failed to complete job", "job_id", job.ID, "kind", job.Kind, "error", err)
+		}
+		return
+	}
+
+	msg := err.Error()
+	if isPermanent(err) || job.Attempt >= job.MaxAttempts {
+		slog.Warn("[Jobs] job moved to dead-letter table", "job_id", job.ID, "kind", job.Kind, "attempt", job.Attempt, "error", msg)
+		err = q.store.ExecTx(recordCtx, func(tx db.Querier) error {
+			if err := tx.MarkJobDead(recordCtx, db.MarkJobDeadParams{ID: job.ID, LastError: &msg}); err != nil {
+				return err
+			}
+			return tx.CreateDeadLetterJob(recordCtx, db.CreateDeadLetterJobParams{
+				JobID:     job.ID,
+				Kind:      job.Kind,
+				Payload:   job.Payload,
+				Attempts:  job.Attempt,
+				LastError: msg,
+			})
+		})
+		if err != nil {
+			slog.Error("[Jobs] failed to dead-letter job", "job_id", job.ID, "kind", job.Kind, "error", err)
+		}
+		return
+	}
+
+	slog.Warn("[Jobs] job failed, will retry", "job_id", job.ID, "kind", job.Kind, "attempt", job.Attempt, "error", msg)
+	err = q.store.RetryJobLater(recordCtx, db.RetryJobLaterParams{
+		ID:        job.ID,
+		LastError: &msg,
+		RunAt:     time.Now().Add(reg.opts.Backoff(job.Attempt)),
+	})
+	if err != nil {
+		slog.Error("[Jobs] failed to reschedule job", "job_id", job.ID, "kind", job.Kind, "error", err)
+	}
+}
+
+// runHandler turns a panicking handler into a failed attempt.
+func runHandler(ctx context.Context, h Handler, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return h(ctx, job)
+}
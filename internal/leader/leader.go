@@ -0,0 +1,149 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/itsDrac/e-auc/internal/cache"
+)
+
+const (
+	// KeyPrefix prefixes the Redis key holding the lease of an election.
+	KeyPrefix  = "leader:"
+	DefaultTTL = 6 * time.Second
+)
+
+// Elector campaigns for a Redis lease. The holder of the lease is the leader,
+// it renews the lease every ttl/3 and loses leadership when a renewal fails.
+// If the leader dies its lease expires and another replica takes over within ttl.
+type Elector struct {
+	cache cache.Cacher
+	key   string
+	id    string
+	ttl   time.Duration
+
+	mu      sync.RWMutex
+	leader  bool
+	since   time.Time
+	renewed time.Time
+}
+
+// Status describes the leadership of this replica.
+type Status struct {
+	ID       string     `json:"id"`
+	IsLeader bool       `json:"is_leader"`
+	Since    *time.Time `json:"since,omitempty"`
+}
+
+func NewElector(c cache.Cacher, name string, ttl time.Duration) *Elector {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return &Elector{
+		cache: c,
+		key:   KeyPrefix + name,
+		id:    fmt.Sprintf("%s-%s", host, uuid.NewString()),
+		ttl:   ttl,
+	}
+}
+
+// Run campaigns until ctx is cancelled, then gives up the lease so another replica can take over right away.
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+	for {
+		if _, err := e.Elect(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("[Leader] election failed", "key", e.key, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			if err := e.Resign(context.WithoutCancel(ctx)); err != nil {
+				slog.Error("[Leader] failed to release lease", "key", e.key, "error", err)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Elect runs one round of the election: the leader renews its lease, everyone else tries to acquire it.
+func (e *Elector) Elect(ctx context.Context) (bool, error) {
+	e.mu.RLock()
+	wasLeader := e.leader
+	e.mu.RUnlock()
+
+	var ok bool
+	var err error
+	if wasLeader {
+		ok, err = e.cache.RenewLease(ctx, e.key, e.id, e.ttl)
+	} else {
+		ok, err = e.cache.AcquireLease(ctx, e.key, e.id, e.ttl)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := time.Now()
+	switch {
+	case err != nil:
+		// Keep leading until the lease would have expired, a short Redis hiccup should not cause a failover.
+		if e.leader && now.Sub(e.renewed) >= e.ttl {
+			e.stepDown()
+		}
+		return e.leader, err
+	case ok:
+		if !e.leader {
+			e.leader = true
+			e.since = now
+			slog.Info("[Leader] acquired leadership", "key", e.key, "id", e.id)
+		}
+		e.renewed = now
+	case e.leader:
+		e.stepDown()
+	}
+	return e.leader, nil
+}
+
+// Resign gives up leadership and releases the lease if it is still held.
+func (e *Elector) Resign(ctx context.Context) error {
+	e.mu.Lock()
+	wasLeader := e.leader
+	if wasLeader {
+		e.stepDown()
+	}
+	e.mu.Unlock()
+	if !wasLeader {
+		return nil
+	}
+	return e.cache.ReleaseLease(ctx, e.key, e.id)
+}
+
+// IsLeader reports whether this replica holds a lease that has not expired yet.
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader && time.Since(e.renewed) < e.ttl
+}
+
+func (e *Elector) Status() Status {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	status := Status{ID: e.id}
+	if e.leader && time.Since(e.renewed) < e.ttl {
+		since := e.since
+		status.IsLeader = true
+		status.Since = &since
+	}
+	return status
+}
+
+// stepDown must be called with mu held.
+func (e *Elector) stepDown() {
+	e.leader = false
+	slog.Warn("[Leader] lost leadership", "key", e.key, "id", e.id)
+}
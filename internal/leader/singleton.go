@@ -0,0 +1,81 @@
+package leader
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const checkInterval = 500 * time.Millisecond
+
+// Runner is a background worker, it must block until its context is cancelled.
+type Runner interface {
+	Run(ctx context.Context)
+}
+
+// Singleton runs a worker only while the elector is the leader.
+// The worker is stopped as soon as leadership is lost and started again once it is regained.
+type Singleton struct {
+	name    string
+	elector *Elector
+	worker  Runner
+}
+
+func NewSingleton(name string, e *Elector, w Runner) *Singleton {
+	return &Singleton{
+		name:    name,
+		elector: e,
+		worker:  w,
+	}
+}
+
+func (s *Singleton) Run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	var current *runningWorker
+	defer func() { current.stop(s.name) }()
+
+	for {
+		leading := s.elector.IsLeader()
+		if leading && current == nil {
+			current = startWorker(ctx, s.worker)
+			slog.Info("[Leader] singleton worker started", "worker", s.name)
+		}
+		if !leading && current != nil {
+			current.stop(s.name)
+			current = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type runningWorker struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func startWorker(ctx context.Context, w Runner) *runningWorker {
+	workerCtx, cancel := context.WithCancel(ctx)
+	rw := &runningWorker{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(rw.done)
+		w.Run(workerCtx)
+	}()
+	return rw
+}
+
+// stop cancels the worker and waits for it to return, it is a no-op on nil.
+func (rw *runningWorker) stop(name string) {
+	if rw == nil {
+		return
+	}
+	rw.cancel()
+	<-rw.done
+	slog.Info("[Leader] singleton worker stopped", "worker", name)
+}
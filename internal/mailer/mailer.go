@@ -0,0 +1,65 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/itsDrac/e-auc/pkg/utils"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	// Send delivers the message, or returns an error when it could not be handed over for delivery.
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewMailer returns the mailer named by MAILER, the fake mailer by default.
+func NewMailer() (Mailer, error) {
+	switch name := utils.GetEnv("MAILER", "fake"); name {
+	case "fake":
+		return NewFakeMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer %q", name)
+	}
+}
+
+// FakeMailer logs and keeps every message instead of sending it, for tests and local use.
+type FakeMailer struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func NewFakeMailer() *FakeMailer {
+	return &FakeMailer{}
+}
+
+func (fm *FakeMailer) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return fmt.Errorf("message has no recipient")
+	}
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	fm.sent = append(fm.sent, msg)
+	slog.Info("[Mailer] message sent", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
+
+// LastTo returns the last message sent to the address, if any.
+func (fm *FakeMailer) LastTo(address string) (Message, bool) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	for i := len(fm.sent) - 1; i >= 0; i-- {
+		if fm.sent[i].To == address {
+			return fm.sent[i], true
+		}
+	}
+	return Message{}, false
+}
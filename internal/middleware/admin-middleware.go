@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/itsDrac/e-auc/internal/handlers"
+	"github.com/itsDrac/e-auc/internal/service"
+)
+
+// AdminMiddleware only lets admins through, it must run after AuthMiddleware.
+func AdminMiddleware(s service.AdminServicer) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := handlers.GetUserClaims(r.Context())
+			if claims == nil {
+				handlers.RespondErrorJSON(w, r, http.StatusUnauthorized, handlers.ErrAuthFailed.Error(), "user claims not found in context", nil)
+				return
+			}
+
+			isAdmin, err := s.IsAdmin(r.Context(), claims.UserID)
+			if err != nil {
+				slog.Error("[DB] failed to check admin role", "user_id", claims.UserID, "error", err)
+				handlers.RespondErrorJSON(w, r, http.StatusInternalServerError, handlers.ErrInternalServer.Error(), "Internal server error", nil)
+				return
+			}
+			if !isAdmin {
+				handlers.RespondErrorJSON(w, r, http.StatusForbidden, handlers.ErrAdminRequired.Error(), "Admin access required", nil)
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
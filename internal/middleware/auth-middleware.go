@@ -34,3 +34,31 @@ func AuthMiddleware(s service.AuthServicer) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// OptionalAuthMiddleware identifies the user on public routes. Requests without a bearer token pass
+// through anonymously, a token that is given must be valid.
+func OptionalAuthMiddleware(s service.AuthServicer) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				h.ServeHTTP(w, r)
+				return
+			}
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+				handlers.RespondErrorJSON(w, r, http.StatusUnauthorized, handlers.ErrMissingToken.Error(), "Missing token in the Authorization header", nil)
+				return
+			}
+
+			claims, err := s.ValidateAccessToken(parts[1])
+			if err != nil {
+				handlers.RespondErrorJSON(w, r, http.StatusUnauthorized, handlers.ErrToken.Error(), "Token is either revoked or invalid.", nil)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), config.UserClaimKey, claims)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -1,24 +1,240 @@
 package model
 
+import "time"
+
 type CreateUserRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8"`
 	Username string `json:"username" validate:"required"`
 }
 
+// Changes to the profile of the signed in user, omitted fields are left alone and an empty bio clears it.
+// Usernames appear in profile URLs, so they cannot contain URL delimiters
+type UpdateProfileRequest struct {
+	Username *string `json:"username" validate:"omitempty,min=3,max=50,excludesall=/?#%"`
+	Bio      *string `json:"bio" validate:"omitempty,max=2000"`
+}
+
+// New email of the account, which only takes effect once the token mailed to it is confirmed
+type ChangeEmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required,max=128"`
+}
+
+// Listings to feature on the storefront, in display order. An empty list clears them
+type SetFeaturedListingsRequest struct {
+	ProductIDs []string `json:"product_ids" validate:"max=6,dive,uuid"`
+}
+
 type LoginUserRequest struct {
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required,min=8"`
 }
 
 type CreateProductRequest struct {
-	Title        string   `json:"title" validate:"required,max=200,min=3"`
-	Description  *string  `json:"description"`
-	Images       []string `json:"images" validate:"required,min=1,max=5"`
-	MinPrice     int32    `json:"min_price" validate:"required,gte=0"`
-	CurrentPrice int32    `json:"current_price" validate:"required,gte=0"`
+	Title        string     `json:"title" validate:"required,max=200,min=3"`
+	Description  *string    `json:"description"`
+	Images       []string   `json:"images" validate:"required,min=1,max=5"`
+	MinPrice     int64      `json:"min_price" validate:"required,gte=0"`
+	CurrentPrice int64      `json:"current_price" validate:"required,gte=0"`
+	EndsAt       *time.Time `json:"ends_at"`
+	// ISO 4217 code of every amount of the listing, in minor units (cents for USD). Defaults to USD
+	Currency string `json:"currency" validate:"omitempty,len=3"`
+	// Soft close: a bid within the last SoftCloseWindowMinutes extends the auction by SoftCloseExtensionMinutes
+	SoftCloseWindowMinutes    int32   `json:"soft_close_window_minutes" validate:"gte=0,lte=60,required_with=SoftCloseExtensionMinutes"`
+	SoftCloseExtensionMinutes int32   `json:"soft_close_extension_minutes" validate:"gte=0,lte=60,required_with=SoftCloseWindowMinutes"`
+	MaxExtensions             *int32  `json:"max_extensions" validate:"omitempty,gte=0"`
+	Category                  *string `json:"category" validate:"omitempty,max=50"`
+	BuyNowPrice               *int64  `json:"buy_now_price" validate:"omitempty,gt=0"`
+	// Defaults to english, sealed formats hide bid amounts until the auction closes and
+	// reverse auctions are posted by a buyer and bid down by sellers. Fixed-price listings are not bid on,
+	// they sell at CurrentPrice through buy now or at a price agreed through offers
+	AuctionType string `json:"auction_type" validate:"omitempty,oneof=english sealed_first_price vickrey dutch reverse fixed_price"`
+	// Dutch auctions start at CurrentPrice and drop by DutchPriceStep every DutchIntervalSeconds down to MinPrice
+	DutchPriceStep       *int64 `json:"dutch_price_step" validate:"omitempty,gt=0"`
+	DutchIntervalSeconds *int32 `json:"dutch_interval_seconds" validate:"omitempty,gt=0"`
+	// Units on offer, english and sealed first-price auctions can sell more than one to several winners
+	// who pay their own bid (pay_as_bid, the default) or the lowest winning bid (uniform)
+	Quantity    int32  `json:"quantity" validate:"omitempty,gt=0"`
+	PricingRule string `json:"pricing_rule" validate:"omitempty,oneof=pay_as_bid uniform"`
+	// Defaults to live, or scheduled when StartsAt is given. Drafts are not visible to bidders until published
+	Status   string     `json:"status" validate:"omitempty,oneof=draft scheduled live"`
+	StartsAt *time.Time `json:"starts_at"`
+	// Offers on fixed-price listings at or above AutoAcceptPrice are accepted and below AutoDeclinePrice declined right away
+	AutoAcceptPrice  *int64 `json:"auto_accept_price" validate:"omitempty,gt=0"`
+	AutoDeclinePrice *int64 `json:"auto_decline_price" validate:"omitempty,gt=0"`
+	// Defaults to public. Private listings are only visible and open to the seller and users they invite
+	// or who redeem the listing's access code
+	Visibility string `json:"visibility" validate:"omitempty,oneof=public private"`
+	// Defaults to none. Bidders on english, sealed first-price and vickrey auctions can be asked to hold their
+	// bid amount (bid_amount) or DepositAmount (fixed) in their wallet while their bid can win
+	DepositType   string `json:"deposit_type" validate:"omitempty,oneof=none bid_amount fixed"`
+	DepositAmount *int64 `json:"deposit_amount" validate:"omitempty,gt=0"`
+	// How the listing ships, added to the invoice of every order. ShippingCost is a shorthand for a single
+	// flat rate when ShippingOptions is empty. With SecondChanceOnNonPayment a winner who does not
+	// pay in time is replaced by an offer to the runner-up
+	ShippingOptions          []ShippingOption `json:"shipping_options" validate:"omitempty,max=20,dive"`
+	ShippingCost             int64            `json:"shipping_cost" validate:"gte=0"`
+	SecondChanceOnNonPayment bool             `json:"second_chance_on_non_payment"`
+	// Bidders, buyers and offers need at least this reputation score, meant for high-value listings
+	MinBidderReputation *int32 `json:"min_bidder_reputation" validate:"omitempty,gt=0"`
+	// Product specific bid increment ladder, the category or platform ladder applies when empty
+	BidIncrements []BidIncrementStep `json:"bid_increments" validate:"omitempty,max=20,dive"`
+}
+
+// Changes to a listing, omitted fields are left alone. Only drafts accept anything but the description
+type UpdateProductRequest struct {
+	Title        *string    `json:"title" validate:"omitempty,max=200,min=3"`
+	Description  *string    `json:"description"`
+	Images       []string   `json:"images" validate:"omitempty,min=1,max=5"`
+	MinPrice     *int64     `json:"min_price" validate:"omitempty,gte=0"`
+	CurrentPrice *int64     `json:"current_price" validate:"omitempty,gte=0"`
+	EndsAt       *time.Time `json:"ends_at"`
+	Category     *string    `json:"category" validate:"omitempty,max=50"`
+	BuyNowPrice  *int64     `json:"buy_now_price" validate:"omitempty,gt=0"`
+}
+
+// Publishing and relisting keep the stored end, or the length of the old auction, unless EndsAt is given
+type PublishProductRequest struct {
+	EndsAt *time.Time `json:"ends_at"`
+}
+
+type ScheduleProductRequest struct {
+	StartsAt time.Time  `json:"starts_at" validate:"required"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+type BidIncrementStep struct {
+	MinPrice  int64 `json:"min_price" validate:"gte=0"`
+	Increment int64 `json:"increment" validate:"required,gt=0"`
+}
+
+// Local pickup (free), a flat rate anywhere or a regional rate for buyers in Region, an ISO 3166-1 alpha-2 country
+type ShippingOption struct {
+	Method string `json:"method" validate:"required,oneof=pickup flat_rate regional"`
+	Region string `json:"region" validate:"required_if=Method regional,omitempty,len=2"`
+	Cost   int64  `json:"cost" validate:"gte=0"`
+}
+
+type SetBidIncrementsRequest struct {
+	Steps []BidIncrementStep `json:"steps" validate:"required,min=1,max=20,dive"`
+}
+
+type FeeTier struct {
+	MinPrice int64 `json:"min_price" validate:"gte=0"`
+	// Rate charged on the part of the price inside the tier, in basis points (100 = 1%)
+	RateBps int32 `json:"rate_bps" validate:"gte=0,lte=10000"`
+}
+
+type SetFeeScheduleRequest struct {
+	Tiers []FeeTier `json:"tiers" validate:"required,min=1,max=20,dive"`
 }
 
 type PlaceBidRequest struct {
-	BidAmount int32 `json:"bid_amount" validate:"required,gt=0"`
+	BidAmount int64 `json:"bid_amount" validate:"required,gt=0"`
+	// Currency of BidAmount, bids are refused unless it is the product currency. Defaults to the product currency
+	Currency string `json:"currency" validate:"omitempty,len=3"`
+	// Units wanted at BidAmount each, defaults to 1
+	Quantity int32 `json:"quantity" validate:"omitempty,gt=0"`
+}
+
+// Bidders must say why they retract a bid
+type RetractBidRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=500"`
+}
+
+// Sellers may say why they cancel the bids of a bidder
+type CancelBidsRequest struct {
+	Reason *string `json:"reason" validate:"omitempty,max=500"`
+}
+
+// Offer or counter-offer on a fixed-price listing
+type OfferRequest struct {
+	Price int64 `json:"price" validate:"required,gt=0"`
+}
+
+// Payment method to pay an invoice with, as tokenized by the payment provider
+type PayInvoiceRequest struct {
+	PaymentToken string `json:"payment_token" validate:"required,max=255"`
+}
+
+// Amount to deposit into or withdraw from the wallet
+type WalletTransactionRequest struct {
+	Amount int64 `json:"amount" validate:"required,gt=0"`
+}
+
+// Amount to deposit into the wallet and the payment method it is charged to, as tokenized by the payment provider
+type WalletDepositRequest struct {
+	Amount       int64  `json:"amount" validate:"required,gt=0"`
+	PaymentToken string `json:"payment_token" validate:"required,max=255"`
+}
+
+// Bidder a seller refuses on all of their listings, with an optional note for themselves
+type BlockBidderRequest struct {
+	BidderID string  `json:"bidder_id" validate:"required,uuid"`
+	Reason   *string `json:"reason" validate:"omitempty,max=500"`
+}
+
+// User to invite to a private listing
+type InviteUserRequest struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+}
+
+// Access code of a private listing, handed out by its seller
+type RedeemAccessCodeRequest struct {
+	AccessCode string `json:"access_code" validate:"required,max=32"`
+}
+
+// Second-chance offer for the bidder of BidID, open for ExpiresInHours (default 24)
+type CreateSecondChanceRequest struct {
+	BidID          string `json:"bid_id" validate:"required,uuid"`
+	ExpiresInHours int    `json:"expires_in_hours" validate:"omitempty,gt=0,lte=72"`
+	AutoAdvance    bool   `json:"auto_advance"`
+}
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" validate:"required,http_url"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,oneof=bid.placed auction.closed item.sold invoice.paid invoice.expired order.shipped order.delivered"`
+}
+
+// Entry of the address book, Country is an ISO 3166-1 alpha-2 code
+type AddressRequest struct {
+	Name       string  `json:"name" validate:"required,max=100"`
+	Line1      string  `json:"line1" validate:"required,max=200"`
+	Line2      *string `json:"line2" validate:"omitempty,max=200"`
+	City       string  `json:"city" validate:"required,max=100"`
+	State      *string `json:"state" validate:"omitempty,max=100"`
+	PostalCode string  `json:"postal_code" validate:"required,max=20"`
+	Country    string  `json:"country" validate:"required,len=2"`
+	IsDefault  bool    `json:"is_default"`
+}
+
+// AddressID is required unless the shipping option is local pickup
+type SetOrderShippingRequest struct {
+	ShippingOptionID string `json:"shipping_option_id" validate:"required,uuid"`
+	AddressID        string `json:"address_id" validate:"omitempty,uuid"`
+}
+
+type ShipOrderRequest struct {
+	Carrier        string `json:"carrier" validate:"required,max=100"`
+	TrackingNumber string `json:"tracking_number" validate:"required,max=100"`
+}
+
+// Rating of the other party of an order, from 1 to 5
+type LeaveFeedbackRequest struct {
+	Rating  int16   `json:"rating" validate:"required,gte=1,lte=5"`
+	Comment *string `json:"comment" validate:"omitempty,max=1000"`
+}
+
+type ReplyToFeedbackRequest struct {
+	Reply string `json:"reply" validate:"required,max=1000"`
+}
+
+// Body may be empty when the message carries an attachment uploaded to the conversation
+type SendMessageRequest struct {
+	Body          string  `json:"body" validate:"max=2000"`
+	AttachmentKey *string `json:"attachment_key" validate:"omitempty,max=300"`
 }
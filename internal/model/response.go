@@ -1,6 +1,12 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/pkg/money"
+)
 
 // Metadata for the response
 type Metadata struct {
@@ -22,9 +28,69 @@ type APIError struct {
 
 // Bid data
 type BidData struct {
-	BidID     string  `json:"bid_id"`
-	ImageURL  string  `json:"image_url"`
-	BidAmount float64 `json:"bid_amount"`
+	BidID     string      `json:"bid_id"`
+	ImageURL  string      `json:"image_url"`
+	BidAmount money.Money `json:"bid_amount"`
+}
+
+// Product with its current bidding state, from the perspective of its owner: OwnerRole is "buyer" for
+// reverse auctions, which have a NextMaxBid instead of a NextMinBid. Amounts are in minor units of
+// the product currency, Price is the current price formatted in it. The offer thresholds of a fixed-price
+// listing are only set for its seller
+type ProductResponse struct {
+	db.Product
+	Price            money.Money `json:"price"`
+	OwnerRole        string      `json:"owner_role"`
+	NextMinBid       *int64      `json:"next_min_bid,omitempty"`
+	NextMaxBid       *int64      `json:"next_max_bid,omitempty"`
+	BuyNowAvailable  bool        `json:"buy_now_available"`
+	AutoAcceptPrice  *int64      `json:"auto_accept_price,omitempty"`
+	AutoDeclinePrice *int64      `json:"auto_decline_price,omitempty"`
+}
+
+// Bid on a product, Price and Amount are null while the amounts of a sealed-bid auction are hidden.
+// Amount is Price formatted in the product currency. BidderRole is "seller" on reverse auctions and
+// Leading marks the bid currently winning
+type BidResponse struct {
+	ID         string       `json:"id"`
+	ProductID  string       `json:"product_id"`
+	UserID     string       `json:"user_id"`
+	BidderRole string       `json:"bidder_role"`
+	Price      *int64       `json:"price"`
+	Amount     *money.Money `json:"amount"`
+	Quantity   int32        `json:"quantity"`
+	IsValid    bool         `json:"is_valid"`
+	Leading    bool         `json:"leading"`
+	BidAt      time.Time    `json:"bid_at"`
+	// Set when the bidder retracted the bid or the seller cancelled it
+	RetractedAt      *time.Time `json:"retracted_at,omitempty"`
+	RetractionReason *string    `json:"retraction_reason,omitempty"`
+}
+
+// Webhook delivery log entry
+type WebhookDeliveryResponse struct {
+	ID               string                   `json:"id"`
+	EndpointID       string                   `json:"endpoint_id"`
+	EventID          string                   `json:"event_id"`
+	EventType        string                   `json:"event_type"`
+	Payload          json.RawMessage          `json:"payload"`
+	Status           string                   `json:"status"`
+	Attempts         int32                    `json:"attempts"`
+	MaxAttempts      int32                    `json:"max_attempts"`
+	NextAttemptAt    time.Time                `json:"next_attempt_at"`
+	LastResponseCode *int32                   `json:"last_response_code"`
+	LastError        *string                  `json:"last_error"`
+	CreatedAt        time.Time                `json:"created_at"`
+	DeliveredAt      *time.Time               `json:"delivered_at"`
+	AttemptLog       []WebhookAttemptResponse `json:"attempt_log,omitempty"`
+}
+
+type WebhookAttemptResponse struct {
+	Attempt      int32     `json:"attempt"`
+	ResponseCode *int32    `json:"response_code"`
+	Error        *string   `json:"error"`
+	DurationMs   int32     `json:"duration_ms"`
+	AttemptedAt  time.Time `json:"attempted_at"`
 }
 
 type APIResponse[T any] struct {
@@ -34,3 +100,12 @@ type APIResponse[T any] struct {
 	Error    *APIError `json:"error,omitempty"`
 	Data     T         `json:"data,omitempty"`
 }
+
+// Wallet balance of the current user. Held funds are reserved and cannot be spent or withdrawn,
+// Transactions are the journal entries of the user, newest first
+type WalletResponse struct {
+	Available    int64             `json:"available"`
+	Held         int64             `json:"held"`
+	Total        int64             `json:"total"`
+	Transactions []db.JournalEntry `json:"transactions"`
+}
@@ -0,0 +1,109 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/itsDrac/e-auc/pkg/utils"
+)
+
+// FakeDeclineToken is a payment token the fake provider always declines.
+const FakeDeclineToken = "tok_declined"
+
+// FakeUnavailableToken is a payment token the fake provider cannot reach, the charge fails without a decline.
+const FakeUnavailableToken = "tok_unavailable"
+
+// ErrChargeNotFound is returned when the provider has no charge under an idempotency key.
+var ErrChargeNotFound = errors.New("charge not found")
+
+// ErrProviderUnavailable is returned by the fake provider for FakeUnavailableToken.
+var ErrProviderUnavailable = errors.New("payment provider unavailable")
+
+// ErrPaymentDeclined is returned when the provider refuses the charge, the buyer may try another payment method.
+var ErrPaymentDeclined = errors.New("payment declined")
+
+// ChargeRequest asks the provider to charge Amount, in minor units of Currency, to the payment method behind Token.
+// Providers charge a given IdempotencyKey at most once.
+type ChargeRequest struct {
+	IdempotencyKey string
+	Amount         int64
+	Currency       string
+	Token          string
+	Description    string
+}
+
+// Charge is a successful charge as recorded by the provider.
+type Charge struct {
+	Reference string
+	Amount    int64
+}
+
+type PaymentProvider interface {
+	// Name identifies the provider in stored payments.
+	Name() string
+	// Charge takes the payment, a declined payment method returns ErrPaymentDeclined.
+	Charge(ctx context.Context, req ChargeRequest) (Charge, error)
+	// LookupCharge returns the charge taken under idempotencyKey, ErrChargeNotFound when there is none.
+	LookupCharge(ctx context.Context, idempotencyKey string) (Charge, error)
+}
+
+// NewPaymentProvider returns the provider named by PAYMENT_PROVIDER, the fake provider by default.
+func NewPaymentProvider() (PaymentProvider, error) {
+	switch name := utils.GetEnv("PAYMENT_PROVIDER", "fake"); name {
+	case "fake":
+		return NewFakeProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", name)
+	}
+}
+
+// FakeProvider accepts every payment token but FakeDeclineToken and FakeUnavailableToken, for tests and local use.
+type FakeProvider struct {
+	mu      sync.Mutex
+	charges map[string]Charge
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		charges: make(map[string]Charge),
+	}
+}
+
+func (fp *FakeProvider) Name() string { return "fake" }
+
+func (fp *FakeProvider) Charge(ctx context.Context, req ChargeRequest) (Charge, error) {
+	if req.Amount <= 0 {
+		return Charge{}, fmt.Errorf("invalid charge amount %d", req.Amount)
+	}
+	if req.Token == "" || req.Token == FakeDeclineToken {
+		return Charge{}, ErrPaymentDeclined
+	}
+	if req.Token == FakeUnavailableToken {
+		return Charge{}, ErrProviderUnavailable
+	}
+
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+	if charge, ok := fp.charges[req.IdempotencyKey]; ok {
+		return charge, nil
+	}
+	charge := Charge{
+		Reference: "fake_" + uuid.NewString(),
+		Amount:    req.Amount,
+	}
+	fp.charges[req.IdempotencyKey] = charge
+	return charge, nil
+}
+
+func (fp *FakeProvider) LookupCharge(ctx context.Context, idempotencyKey string) (Charge, error) {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+	charge, ok := fp.charges[idempotencyKey]
+	if !ok {
+		return Charge{}, ErrChargeNotFound
+	}
+	return charge, nil
+}
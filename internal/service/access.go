@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// Listing visibilities, mirrored by the CHECK constraint on products.visibility.
+// Private listings are only visible and open to the seller and invited users.
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
+
+// How a user got invited to a private listing, mirrored by the CHECK constraint on product_invitations.source.
+const (
+	InvitationSourceSeller     = "seller"
+	InvitationSourceAccessCode = "access_code"
+)
+
+type AccessServicer interface {
+	BlockBidder(context.Context, uuid.UUID, string, *string) (db.SellerBlockedBidder, error)
+	UnblockBidder(context.Context, uuid.UUID, string) error
+	GetBlockedBidders(context.Context, uuid.UUID, uint, uint) ([]db.SellerBlockedBidder, error)
+	InviteUser(context.Context, uuid.UUID, string, string) (db.ProductInvitation, error)
+	RemoveInvitation(context.Context, uuid.UUID, string, string) error
+	GetInvitations(context.Context, uuid.UUID, string) ([]db.ProductInvitation, error)
+	GetAccessCode(context.Context, uuid.UUID, string) (db.ProductAccessCode, error)
+	RotateAccessCode(context.Context, uuid.UUID, string) (db.ProductAccessCode, error)
+	RevokeAccessCode(context.Context, uuid.UUID, string) error
+	RedeemAccessCode(context.Context, uuid.UUID, string, string) (db.ProductInvitation, error)
+}
+
+type AccessService struct {
+	db db.Store
+}
+
+func NewAccessService(db db.Store) (*AccessService, error) {
+	return &AccessService{
+		db: db,
+	}, nil
+}
+
+// BlockBidder stops the bidder from bidding on, buying or making offers on any listing of the seller.
+// Blocking an already blocked bidder updates the reason. Bids placed before the block are left alone,
+// the seller cancels them separately.
+func (as *AccessService) BlockBidder(ctx context.Context, sellerID uuid.UUID, bidderId string, reason *string) (db.SellerBlockedBidder, error) {
+	bidderUUID, err := uuid.Parse(bidderId)
+	if err != nil {
+		return db.SellerBlockedBidder{}, ErrUserNotFound
+	}
+	if bidderUUID == sellerID {
+		return db.SellerBlockedBidder{}, ErrSelfBlocking
+	}
+	if _, err := as.db.GetUserByID(ctx, bidderUUID); err != nil {
+		if err == pgx.ErrNoRows {
+			return db.SellerBlockedBidder{}, ErrUserNotFound
+		}
+		return db.SellerBlockedBidder{}, err
+	}
+	if reason != nil {
+		trimmed := strings.TrimSpace(*reason)
+		reason = &trimmed
+		if trimmed == "" {
+			reason = nil
+		}
+	}
+	return as.db.BlockBidder(ctx, db.BlockBidderParams{
+		SellerID: sellerID,
+		BidderID: bidderUUID,
+		Reason:   reason,
+	})
+}
+
+// UnblockBidder lifts the seller's block on the bidder.
+func (as *AccessService) UnblockBidder(ctx context.Context, sellerID uuid.UUID, bidderId string) error {
+	bidderUUID, err := uuid.Parse(bidderId)
+	if err != nil {
+		return ErrBlockNotFound
+	}
+	rows, err := as.db.UnblockBidder(ctx, db.UnblockBidderParams{SellerID: sellerID, BidderID: bidderUUID})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrBlockNotFound
+	}
+	return nil
+}
+
+// GetBlockedBidders lists the bidders the seller blocked, most recent first.
+func (as *AccessService) GetBlockedBidders(ctx context.Context, sellerID uuid.UUID, limit uint, offset uint) ([]db.SellerBlockedBidder, error) {
+	blocked, err := as.db.GetBlockedBidders(ctx, db.GetBlockedBiddersParams{
+		SellerID: sellerID,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if blocked == nil {
+		blocked = []db.SellerBlockedBidder{}
+	}
+	return blocked, nil
+}
+
+// InviteUser lets the user see and bid on the seller's private listing. Inviting a user twice is a no-op.
+func (as *AccessService) InviteUser(ctx context.Context, sellerID uuid.UUID, productId string, userId string) (db.ProductInvitation, error) {
+	userUUID, err := uuid.Parse(userId)
+	if err != nil {
+		return db.ProductInvitation{}, ErrUserNotFound
+	}
+	var invitation db.ProductInvitation
+	err = as.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := privateProductForOwner(ctx, q, sellerID, productId)
+		if err != nil {
+			return err
+		}
+		if userUUID == sellerID {
+			return ErrSelfInvitation
+		}
+		if _, err := q.GetUserByID(ctx, userUUID); err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		invitation, err = q.CreateProductInvitation(ctx, db.CreateProductInvitationParams{
+			ProductID: product.ID,
+			UserID:    userUUID,
+			Source:    InvitationSourceSeller,
+		})
+		return err
+	})
+	if err != nil {
+		return db.ProductInvitation{}, err
+	}
+	return invitation, nil
+}
+
+// RemoveInvitation takes the user off the invitation list of the seller's private listing,
+// whether the seller invited them or they redeemed the access code. Their bids stay valid.
+func (as *AccessService) RemoveInvitation(ctx context.Context, sellerID uuid.UUID, productId string, userId string) error {
+	userUUID, err := uuid.Parse(userId)
+	if err != nil {
+		return ErrInvitationNotFound
+	}
+	return as.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := privateProductForOwner(ctx, q, sellerID, productId)
+		if err != nil {
+			return err
+		}
+		rows, err := q.DeleteProductInvitation(ctx, db.DeleteProductInvitationParams{ProductID: product.ID, UserID: userUUID})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrInvitationNotFound
+		}
+		return nil
+	})
+}
+
+// GetInvitations lists the users invited to the seller's private listing, oldest first.
+func (as *AccessService) GetInvitations(ctx context.Context, sellerID uuid.UUID, productId string) ([]db.ProductInvitation, error) {
+	var invitations []db.ProductInvitation
+	err := as.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := privateProductForOwner(ctx, q, sellerID, productId)
+		if err != nil {
+			return err
+		}
+		invitations, err = q.GetProductInvitations(ctx, product.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if invitations == nil {
+		invitations = []db.ProductInvitation{}
+	}
+	return invitations, nil
+}
+
+// GetAccessCode returns the access code of the seller's private listing.
+func (as *AccessService) GetAccessCode(ctx context.Context, sellerID uuid.UUID, productId string) (db.ProductAccessCode, error) {
+	var code db.ProductAccessCode
+	err := as.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := privateProductForOwner(ctx, q, sellerID, productId)
+		if err != nil {
+			return err
+		}
+		code, err = q.GetProductAccessCode(ctx, product.ID)
+		if err == pgx.ErrNoRows {
+			return ErrAccessCodeNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return db.ProductAccessCode{}, err
+	}
+	return code, nil
+}
+
+// RotateAccessCode gives the seller's private listing a new access code, the previous one stops working.
+// Users who already redeemed a code stay invited.
+func (as *AccessService) RotateAccessCode(ctx context.Context, sellerID uuid.UUID, productId string) (db.ProductAccessCode, error) {
+	code, err := newAccessCode()
+	if err != nil {
+		return db.ProductAccessCode{}, err
+	}
+	var accessCode db.ProductAccessCode
+	err = as.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := privateProductForOwner(ctx, q, sellerID, productId)
+		if err != nil {
+			return err
+		}
+		accessCode, err = q.SetProductAccessCode(ctx, db.SetProductAccessCodeParams{ProductID: product.ID, Code: code})
+		return err
+	})
+	if err != nil {
+		return db.ProductAccessCode{}, err
+	}
+	return accessCode, nil
+}
+
+// RevokeAccessCode removes the access code of the seller's private listing.
+func (as *AccessService) RevokeAccessCode(ctx context.Context, sellerID uuid.UUID, productId string) error {
+	return as.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := privateProductForOwner(ctx, q, sellerID, productId)
+		if err != nil {
+			return err
+		}
+		rows, err := q.DeleteProductAccessCode(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrAccessCodeNotFound
+		}
+		return nil
+	})
+}
+
+// RedeemAccessCode invites the user to a private listing when code matches its access code.
+// Codes are compared case-insensitively, redeeming again keeps the existing invitation. Missing and public
+// listings get ErrInvalidAccessCode too, so the answer does not tell which IDs are private listings.
+func (as *AccessService) RedeemAccessCode(ctx context.Context, userID uuid.UUID, productId string, code string) (db.ProductInvitation, error) {
+	productUUID, err := uuid.Parse(productId)
+	if err != nil {
+		return db.ProductInvitation{}, ErrInvalidAccessCode
+	}
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	var invitation db.ProductInvitation
+	err = as.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := q.GetProductByID(ctx, productUUID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrInvalidAccessCode
+			}
+			return err
+		}
+		if product.Visibility != VisibilityPrivate {
+			return ErrInvalidAccessCode
+		}
+		if product.SellerID == userID {
+			return ErrSelfInvitation
+		}
+		accessCode, err := q.GetProductAccessCode(ctx, product.ID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrInvalidAccessCode
+			}
+			return err
+		}
+		if subtle.ConstantTimeCompare([]byte(accessCode.Code), []byte(code)) != 1 {
+			return ErrInvalidAccessCode
+		}
+		invitation, err = q.CreateProductInvitation(ctx, db.CreateProductInvitationParams{
+			ProductID: product.ID,
+			UserID:    userID,
+			Source:    InvitationSourceAccessCode,
+		})
+		return err
+	})
+	if err != nil {
+		return db.ProductInvitation{}, err
+	}
+	return invitation, nil
+}
+
+// privateProductForOwner locks a private listing managed by ownerID.
+func privateProductForOwner(ctx context.Context, q db.Querier, ownerID uuid.UUID, productId string) (db.Product, error) {
+	product, err := ownedProductForUpdate(ctx, q, ownerID, productId)
+	if err != nil {
+		return db.Product{}, err
+	}
+	if product.Visibility != VisibilityPrivate {
+		return db.Product{}, ErrProductNotPrivate
+	}
+	return product, nil
+}
+
+// canViewProduct reports whether the viewer, uuid.Nil for anonymous visitors, may see the product.
+// Public listings are visible to everyone, private ones to the seller and invited users.
+func canViewProduct(ctx context.Context, q db.Querier, product db.Product, viewerID uuid.UUID) (bool, error) {
+	if product.Visibility != VisibilityPrivate || product.SellerID == viewerID {
+		return true, nil
+	}
+	if viewerID == uuid.Nil {
+		return false, nil
+	}
+	return q.IsUserInvited(ctx, db.IsUserInvitedParams{ProductID: product.ID, UserID: viewerID})
+}
+
+// checkBuyerAccess returns ErrProductNotFound when the buyer may not see the product, ErrBidderBlocked
+// when its seller blocked the buyer and ErrReputationTooLow when the buyer lacks the reputation it asks for.
+func checkBuyerAccess(ctx context.Context, q db.Querier, product db.Product, buyerID uuid.UUID) error {
+	visible, err := canViewProduct(ctx, q, product, buyerID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return ErrProductNotFound
+	}
+	blocked, err := q.IsBidderBlocked(ctx, db.IsBidderBlockedParams{SellerID: product.SellerID, BidderID: buyerID})
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrBidderBlocked
+	}
+	return checkBidderReputation(ctx, q, product, buyerID)
+}
+
+// copyPrivateAccess carries the invitations and the access code of a private listing over to its relisting.
+func copyPrivateAccess(ctx context.Context, q db.Querier, from db.Product, to db.Product) error {
+	if from.Visibility != VisibilityPrivate {
+		return nil
+	}
+	err := q.CopyProductInvitations(ctx, db.CopyProductInvitationsParams{ToProductID: to.ID, FromProductID: from.ID})
+	if err != nil {
+		return err
+	}
+	code, err := q.GetProductAccessCode(ctx, from.ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	_, err = q.SetProductAccessCode(ctx, db.SetProductAccessCodeParams{ProductID: to.ID, Code: code.Code})
+	return err
+}
+
+// newAccessCode returns a random 8 character code that is easy to read out and type.
+func newAccessCode() (string, error) {
+	buf := make([]byte, 5)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate access code: %w", err)
+	}
+	return base32.StdEncoding.EncodeToString(buf), nil
+}
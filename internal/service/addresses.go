@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type AddressServicer interface {
+	GetAddresses(context.Context, uuid.UUID) ([]db.Address, error)
+	CreateAddress(context.Context, uuid.UUID, db.Address) (db.Address, error)
+	UpdateAddress(context.Context, uuid.UUID, string, db.Address) (db.Address, error)
+	DeleteAddress(context.Context, uuid.UUID, string) error
+}
+
+type AddressService struct {
+	db db.Store
+}
+
+func NewAddressService(db db.Store) (*AddressService, error) {
+	return &AddressService{
+		db: db,
+	}, nil
+}
+
+// GetAddresses returns the address book of the user, the default address first.
+func (as *AddressService) GetAddresses(ctx context.Context, userID uuid.UUID) ([]db.Address, error) {
+	addresses, err := as.db.GetAddressesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if addresses == nil {
+		addresses = []db.Address{}
+	}
+	return addresses, nil
+}
+
+// CreateAddress adds an address to the user's address book. The first address, or one marked default,
+// becomes the default address that orders ship to.
+func (as *AddressService) CreateAddress(ctx context.Context, userID uuid.UUID, a db.Address) (db.Address, error) {
+	country, err := normalizeCountry(a.Country)
+	if err != nil {
+		return db.Address{}, err
+	}
+
+	var created db.Address
+	err = as.db.ExecTx(ctx, func(q db.Querier) error {
+		count, err := q.CountAddressesByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		isDefault := a.IsDefault || count == 0
+		if isDefault {
+			if err := q.ClearDefaultAddress(ctx, userID); err != nil {
+				return err
+			}
+		}
+		created, err = q.CreateAddress(ctx, db.CreateAddressParams{
+			UserID:     userID,
+			Name:       a.Name,
+			Line1:      a.Line1,
+			Line2:      a.Line2,
+			City:       a.City,
+			State:      a.State,
+			PostalCode: a.PostalCode,
+			Country:    country,
+			IsDefault:  isDefault,
+		})
+		return err
+	})
+	if err != nil {
+		return db.Address{}, err
+	}
+	return created, nil
+}
+
+// UpdateAddress replaces an address of the user. Marking it default moves the default away from the previous one,
+// the default address itself stays the default until another one is. Orders keep the address they shipped to.
+func (as *AddressService) UpdateAddress(ctx context.Context, userID uuid.UUID, addressId string, a db.Address) (db.Address, error) {
+	addressUUID, err := uuid.Parse(addressId)
+	if err != nil {
+		return db.Address{}, ErrAddressNotFound
+	}
+	country, err := normalizeCountry(a.Country)
+	if err != nil {
+		return db.Address{}, err
+	}
+
+	var updated db.Address
+	err = as.db.ExecTx(ctx, func(q db.Querier) error {
+		current, err := q.GetAddressByID(ctx, db.GetAddressByIDParams{ID: addressUUID, UserID: userID})
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrAddressNotFound
+			}
+			return err
+		}
+		isDefault := a.IsDefault || current.IsDefault
+		if isDefault && !current.IsDefault {
+			if err := q.ClearDefaultAddress(ctx, userID); err != nil {
+				return err
+			}
+		}
+		updated, err = q.UpdateAddress(ctx, db.UpdateAddressParams{
+			ID:         addressUUID,
+			UserID:     userID,
+			Name:       a.Name,
+			Line1:      a.Line1,
+			Line2:      a.Line2,
+			City:       a.City,
+			State:      a.State,
+			PostalCode: a.PostalCode,
+			Country:    country,
+			IsDefault:  isDefault,
+		})
+		return err
+	})
+	if err != nil {
+		return db.Address{}, err
+	}
+	return updated, nil
+}
+
+// DeleteAddress removes an address from the user's address book. When it was the default,
+// the oldest remaining address becomes the default.
+func (as *AddressService) DeleteAddress(ctx context.Context, userID uuid.UUID, addressId string) error {
+	addressUUID, err := uuid.Parse(addressId)
+	if err != nil {
+		return ErrAddressNotFound
+	}
+	return as.db.ExecTx(ctx, func(q db.Querier) error {
+		deleted, err := q.DeleteAddress(ctx, db.DeleteAddressParams{ID: addressUUID, UserID: userID})
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrAddressNotFound
+			}
+			return err
+		}
+		if !deleted.IsDefault {
+			return nil
+		}
+		return q.PromoteOldestAddress(ctx, userID)
+	})
+}
+
+// normalizeCountry upper-cases an ISO 3166-1 alpha-2 country code and checks its shape.
+func normalizeCountry(country string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(country))
+	if !isCountryCode(code) {
+		return "", ErrInvalidAddress
+	}
+	return code, nil
+}
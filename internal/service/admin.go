@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/jobs"
+	"github.com/jackc/pgx/v5"
+)
+
+type AdminServicer interface {
+	IsAdmin(ctx context.Context, userID uuid.UUID) (bool, error)
+	ListJobs(ctx context.Context, status string, kind string, limit uint, offset uint) ([]db.Job, error)
+	GetJob(ctx context.Context, jobId string) (db.Job, error)
+	RetryJob(ctx context.Context, jobId string) (db.Job, error)
+	CancelJob(ctx context.Context, jobId string) (db.Job, error)
+	ListDeadLetterJobs(ctx context.Context, limit uint, offset uint) ([]db.DeadLetterJob, error)
+	GetBidIncrementLadders(ctx context.Context) ([]db.BidIncrementRule, error)
+	SetBidIncrementLadder(ctx context.Context, category *string, steps []IncrementStep) error
+	GetFeeSchedules(ctx context.Context) ([]db.FeeRule, error)
+	SetFeeSchedule(ctx context.Context, kind string, category *string, tiers []FeeTier) error
+	GetFeeRevenue(ctx context.Context, period string, from time.Time, to time.Time) ([]FeeRevenue, error)
+}
+
+type AdminService struct {
+	db db.Store
+}
+
+func NewAdminService(db db.Store) (*AdminService, error) {
+	return &AdminService{
+		db: db,
+	}, nil
+}
+
+func (as *AdminService) IsAdmin(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := as.db.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return user.IsAdmin, nil
+}
+
+// ListJobs returns the most recent jobs, optionally filtered by status and kind.
+func (as *AdminService) ListJobs(ctx context.Context, status string, kind string, limit uint, offset uint) ([]db.Job, error) {
+	params := db.ListJobsParams{
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	}
+	if status != "" {
+		params.Status = &status
+	}
+	if kind != "" {
+		params.Kind = &kind
+	}
+	return as.db.ListJobs(ctx, params)
+}
+
+func (as *AdminService) GetJob(ctx context.Context, jobId string) (db.Job, error) {
+	jobUUID, err := uuid.Parse(jobId)
+	if err != nil {
+		return db.Job{}, ErrJobNotFound
+	}
+	job, err := as.db.GetJobByID(ctx, jobUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Job{}, ErrJobNotFound
+		}
+		return db.Job{}, err
+	}
+	return job, nil
+}
+
+// RetryJob runs a pending, cancelled or dead job again right away with a fresh set of attempts.
+func (as *AdminService) RetryJob(ctx context.Context, jobId string) (db.Job, error) {
+	job, err := as.GetJob(ctx, jobId)
+	if err != nil {
+		return db.Job{}, err
+	}
+	job, err = as.db.RequeueJob(ctx, job.ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Job{}, ErrJobNotRetryable
+		}
+		return db.Job{}, err
+	}
+	return job, nil
+}
+
+// CancelJob cancels a job that has not started yet.
+func (as *AdminService) CancelJob(ctx context.Context, jobId string) (db.Job, error) {
+	job, err := as.GetJob(ctx, jobId)
+	if err != nil {
+		return db.Job{}, err
+	}
+	if job.Status != jobs.StatusPending {
+		return db.Job{}, ErrJobNotCancellable
+	}
+	job, err = as.db.CancelJob(ctx, job.ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Job{}, ErrJobNotCancellable
+		}
+		return db.Job{}, err
+	}
+	return job, nil
+}
+
+func (as *AdminService) ListDeadLetterJobs(ctx context.Context, limit uint, offset uint) ([]db.DeadLetterJob, error) {
+	return as.db.GetDeadLetterJobs(ctx, db.GetDeadLetterJobsParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+}
+
+// GetBidIncrementLadders returns the platform and category ladders, product ladders are part of their product.
+func (as *AdminService) GetBidIncrementLadders(ctx context.Context) ([]db.BidIncrementRule, error) {
+	return as.db.GetBidIncrementRules(ctx)
+}
+
+// SetBidIncrementLadder replaces the ladder of a category, or the platform ladder when category is nil.
+func (as *AdminService) SetBidIncrementLadder(ctx context.Context, category *string, steps []IncrementStep) error {
+	if err := validateLadder(steps); err != nil {
+		return err
+	}
+	scope := IncrementScopePlatform
+	if category != nil {
+		category = normalizeCategory(category)
+		if category == nil {
+			return ErrInvalidIncrementLadder
+		}
+		scope = IncrementScopeCategory
+	}
+
+	return as.db.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		if scope == IncrementScopePlatform {
+			err = q.DeletePlatformBidIncrementRules(ctx)
+		} else {
+			err = q.DeleteCategoryBidIncrementRules(ctx, category)
+		}
+		if err != nil {
+			return err
+		}
+		for _, step := range steps {
+			err := q.CreateBidIncrementRule(ctx, db.CreateBidIncrementRuleParams{
+				Scope:     scope,
+				Category:  category,
+				MinPrice:  step.MinPrice,
+				Increment: step.Increment,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetFeeSchedules returns the platform and category fee schedules of every kind.
+func (as *AdminService) GetFeeSchedules(ctx context.Context) ([]db.FeeRule, error) {
+	return as.db.GetFeeRules(ctx)
+}
+
+// SetFeeSchedule replaces the fee schedule of kind for a category, or the platform schedule when category is nil.
+// Sales that already settled keep the fees they were invoiced.
+func (as *AdminService) SetFeeSchedule(ctx context.Context, kind string, category *string, tiers []FeeTier) error {
+	if kind != FeeCommission && kind != FeeBuyerPremium {
+		return ErrInvalidFeeKind
+	}
+	if err := validateFeeSchedule(tiers); err != nil {
+		return err
+	}
+	scope := FeeScopePlatform
+	if category != nil {
+		category = normalizeCategory(category)
+		if category == nil {
+			return ErrInvalidFeeSchedule
+		}
+		scope = FeeScopeCategory
+	}
+
+	return as.db.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		if scope == FeeScopePlatform {
+			err = q.DeletePlatformFeeRules(ctx, kind)
+		} else {
+			err = q.DeleteCategoryFeeRules(ctx, db.DeleteCategoryFeeRulesParams{Kind: kind, Category: category})
+		}
+		if err != nil {
+			return err
+		}
+		for _, tier := range tiers {
+			err := q.CreateFeeRule(ctx, db.CreateFeeRuleParams{
+				Kind:     kind,
+				Scope:    scope,
+				Category: category,
+				MinPrice: tier.MinPrice,
+				RateBps:  tier.RateBps,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetFeeRevenue sums the fees booked from from until to per day, week or month, oldest period first.
+func (as *AdminService) GetFeeRevenue(ctx context.Context, period string, from time.Time, to time.Time) ([]FeeRevenue, error) {
+	if !feePeriods[period] || !from.Before(to) {
+		return nil, ErrInvalidFeePeriod
+	}
+	rows, err := as.db.GetFeeRevenue(ctx, db.GetFeeRevenueParams{
+		Period:   period,
+		FromTime: from.UTC(),
+		ToTime:   to.UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	revenue := make([]FeeRevenue, 0, len(rows))
+	for _, row := range rows {
+		revenue = append(revenue, FeeRevenue{
+			PeriodStart:  row.PeriodStart,
+			Currency:     row.Currency,
+			Commission:   row.Commission,
+			BuyerPremium: row.BuyerPremium,
+			Reversed:     row.Reversed,
+			Net:          row.Commission + row.BuyerPremium - row.Reversed,
+			Sales:        row.Sales,
+		})
+	}
+	return revenue, nil
+}
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/jackc/pgx/v5"
+)
+
+// Auction formats, mirrored by the CHECK constraint on products.auction_type.
+const (
+	AuctionTypeEnglish          = "english"
+	AuctionTypeSealedFirstPrice = "sealed_first_price"
+	AuctionTypeVickrey          = "vickrey"
+	AuctionTypeDutch            = "dutch"
+	AuctionTypeReverse          = "reverse"
+	AuctionTypeFixedPrice       = "fixed_price"
+)
+
+// Roles of the owner of a product and of its bidders. Reverse auctions are posted by a buyer
+// and bid on by sellers, every other format is posted by a seller and bid on by buyers.
+// The owner is always stored in products.seller_id.
+const (
+	RoleSeller = "seller"
+	RoleBuyer  = "buyer"
+)
+
+// auctionFormat holds the rules that differ between auction formats.
+// Locking the product, ownership and end-of-auction checks are shared and done by the caller.
+type auctionFormat interface {
+	// prepare checks the format specific settings of a new product and fills in the columns they derive.
+	prepare(p db.Product, arg *db.AddProductParams, now time.Time) error
+	// sealed formats hide bid amounts until the auction is closed.
+	sealed() bool
+	// bidLimits returns the lowest bid the product currently accepts, or the highest for reverse auctions.
+	bidLimits(ctx context.Context, q db.Querier, product db.Product) (BiddingState, error)
+	// placeBid records a bid for quantity units on a product whose row is locked by the caller.
+	// The caller checks that quantity is within what the product offers.
+	placeBid(ctx context.Context, q db.Querier, product db.Product, bidderId uuid.UUID, amount int64, quantity int32, now time.Time) error
+	// rank orders bids from best to worst for the owner of the product.
+	rank(bids []db.Bid) []db.Bid
+	// clearingPrice is what the best of the ranked bids pays, ok is false when the reserve was not met.
+	clearingPrice(product db.Product, ranked []db.Bid) (price int64, ok bool)
+	// currentPrice recomputes the current price from the valid bids left after bids were withdrawn.
+	currentPrice(product db.Product, valid []db.Bid) int64
+}
+
+var auctionFormats = map[string]auctionFormat{
+	AuctionTypeEnglish:          englishAuction{},
+	AuctionTypeSealedFirstPrice: sealedFirstPriceAuction{},
+	AuctionTypeVickrey:          vickreyAuction{},
+	AuctionTypeDutch:            dutchAuction{},
+	AuctionTypeReverse:          reverseAuction{},
+	AuctionTypeFixedPrice:       fixedPriceListing{},
+}
+
+func formatFor(product db.Product) auctionFormat {
+	if f, ok := auctionFormats[product.AuctionType]; ok {
+		return f
+	}
+	return englishAuction{}
+}
+
+// BidAmountsHidden reports whether bid amounts on the product must not be shown yet.
+func BidAmountsHidden(product db.Product) bool {
+	return formatFor(product).sealed() && product.ClosedAt == nil
+}
+
+// OwnerRole returns whether the owner of the product sells or buys it.
+func OwnerRole(product db.Product) string {
+	if product.AuctionType == AuctionTypeReverse {
+		return RoleBuyer
+	}
+	return RoleSeller
+}
+
+// BidderRole returns whether the bidders on the product buy or sell it.
+func BidderRole(product db.Product) string {
+	if OwnerRole(product) == RoleBuyer {
+		return RoleSeller
+	}
+	return RoleBuyer
+}
+
+// LeadingBid returns the best valid bid on the product for its format.
+// Nothing leads while the amounts are hidden.
+func LeadingBid(product db.Product, bids []db.Bid) (db.Bid, bool) {
+	if BidAmountsHidden(product) {
+		return db.Bid{}, false
+	}
+	valid := make([]db.Bid, 0, len(bids))
+	for _, bid := range bids {
+		if bid.IsValid {
+			valid = append(valid, bid)
+		}
+	}
+	ranked := formatFor(product).rank(valid)
+	if len(ranked) == 0 {
+		return db.Bid{}, false
+	}
+	return ranked[0], true
+}
+
+// tradeParties returns the seller and the buyer of a product won by winner.
+func tradeParties(product db.Product, winner uuid.UUID) (seller uuid.UUID, buyer uuid.UUID) {
+	if OwnerRole(product) == RoleBuyer {
+		return winner, product.SellerID
+	}
+	return product.SellerID, winner
+}
+
+// englishAuction is an open ascending auction: every bid must beat the current price by the increment ladder
+// and the highest bidder pays their bid.
+type englishAuction struct{ highestBidWins }
+
+func (englishAuction) prepare(p db.Product, arg *db.AddProductParams, now time.Time) error {
+	if p.BuyNowPrice != nil && *p.BuyNowPrice <= p.CurrentPrice {
+		return ErrInvalidBuyNowPrice
+	}
+	arg.BuyNowPrice = p.BuyNowPrice
+	return nil
+}
+
+func (englishAuction) sealed() bool { return false }
+
+func (englishAuction) bidLimits(ctx context.Context, q db.Querier, product db.Product) (BiddingState, error) {
+	rules, err := incrementRulesFor(ctx, q, product)
+	if err != nil {
+		return BiddingState{}, err
+	}
+	minBid := nextMinBid(rules, product.CurrentPrice)
+	return BiddingState{NextMinBid: &minBid}, nil
+}
+
+func (englishAuction) placeBid(ctx context.Context, q db.Querier, product db.Product, bidderId uuid.UUID, bidAmount int64, quantity int32, now time.Time) error {
+	// TODO: Add check for threshold bidding amount for the product
+	rules, err := incrementRulesFor(ctx, q, product)
+	if err != nil {
+		return err
+	}
+	if minBid := nextMinBid(rules, product.CurrentPrice); bidAmount < minBid {
+		return &BidBelowIncrementError{MinBid: minBid}
+	}
+	if product.Quantity > 1 {
+		return placeLotBid(ctx, q, product, rules, bidderId, bidAmount, quantity, now)
+	}
+
+	// Check if the last valid bidder is not the current bidder
+	lastBid, err := q.GetLatestBidForProduct(ctx, product.ID)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+	if err == nil && lastBid.UserID == bidderId {
+		return ErrConsecutiveBid
+	}
+
+	// Store the bid in bids table
+	err = q.CreateBid(ctx, db.CreateBidParams{
+		ProductID: product.ID,
+		UserID:    bidderId,
+		Price:     bidAmount,
+		Comments:  nil,
+		Quantity:  quantity,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = q.UpdateProductCurrentPrice(ctx, db.UpdateProductCurrentPriceParams{
+		ID:           product.ID,
+		CurrentPrice: bidAmount,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := extendOnLateBid(ctx, q, &product, now); err != nil {
+		return err
+	}
+
+	// TODO: Add code to check for seller threshold on bidding of its products.
+	// TODO: If the bidding amount is higher than the threshold, notify the seller via email.
+	minBid := nextMinBid(rules, bidAmount)
+	return emitEvent(ctx, q, events.BidPlaced, product.ID, events.BidPlacedData{
+		ProductID:  product.ID,
+		SellerID:   product.SellerID,
+		BidderID:   bidderId,
+		BidAmount:  bidAmount,
+		Quantity:   quantity,
+		NextMinBid: &minBid,
+		EndsAt:     product.EndsAt,
+	})
+}
+
+// placeLotBid records a bid on a multi-unit english auction. Bids do not outbid each other as long as
+// units are left, so every bidder holds one bid they can raise, and the price to beat is the lowest
+// winning bid once every unit is taken.
+func placeLotBid(ctx context.Context, q db.Querier, product db.Product, rules []db.BidIncrementRule, bidderId uuid.UUID, bidAmount int64, quantity int32, now time.Time) error {
+	existing, err := q.GetBidByProductAndUser(ctx, db.GetBidByProductAndUserParams{
+		ProductID: product.ID,
+		UserID:    bidderId,
+	})
+	switch {
+	case err == pgx.ErrNoRows:
+		err = q.CreateBid(ctx, db.CreateBidParams{
+			ProductID: product.ID,
+			UserID:    bidderId,
+			Price:     bidAmount,
+			Quantity:  quantity,
+		})
+	case err == nil:
+		if minBid := nextMinBid(rules, existing.Price); bidAmount < minBid {
+			return &BidBelowIncrementError{MinBid: minBid}
+		}
+		err = q.ReviseBid(ctx, db.ReviseBidParams{
+			ID:       existing.ID,
+			Price:    bidAmount,
+			Quantity: quantity,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	bids, err := q.GetValidBidsByProductID(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	allocs := allocateUnits(product.Quantity, highestBidWins{}.rank(bids))
+	var allocated int32
+	for _, a := range allocs {
+		allocated += a.Quantity
+	}
+	if allocated == product.Quantity {
+		if lowest := allocs[len(allocs)-1].UnitPrice; lowest > product.CurrentPrice {
+			err := q.UpdateProductCurrentPrice(ctx, db.UpdateProductCurrentPriceParams{
+				ID:           product.ID,
+				CurrentPrice: lowest,
+			})
+			if err != nil {
+				return err
+			}
+			product.CurrentPrice = lowest
+		}
+	}
+
+	if err := extendOnLateBid(ctx, q, &product, now); err != nil {
+		return err
+	}
+	minBid := nextMinBid(rules, product.CurrentPrice)
+	return emitEvent(ctx, q, events.BidPlaced, product.ID, events.BidPlacedData{
+		ProductID:  product.ID,
+		SellerID:   product.SellerID,
+		BidderID:   bidderId,
+		BidAmount:  bidAmount,
+		Quantity:   quantity,
+		NextMinBid: &minBid,
+		EndsAt:     product.EndsAt,
+	})
+}
+
+func (englishAuction) clearingPrice(product db.Product, ranked []db.Bid) (int64, bool) {
+	return ranked[0].Price, ranked[0].Price >= product.MinPrice
+}
+
+// currentPrice is the highest bid, or the lowest winning bid of a fully taken lot,
+// and the starting price otherwise.
+func (englishAuction) currentPrice(product db.Product, valid []db.Bid) int64 {
+	ranked := highestBidWins{}.rank(valid)
+	if product.Quantity <= 1 {
+		if len(ranked) == 0 {
+			return product.StartPrice
+		}
+		return ranked[0].Price
+	}
+	allocs := allocateUnits(product.Quantity, ranked)
+	var allocated int32
+	for _, a := range allocs {
+		allocated += a.Quantity
+	}
+	if allocated < product.Quantity {
+		return product.StartPrice
+	}
+	return allocs[len(allocs)-1].UnitPrice
+}
+
+// extendOnLateBid applies the soft close of the product to a bid placed at now: a late bid pushes out
+// the end of the auction in the same transaction. product is updated with the new end.
+func extendOnLateBid(ctx context.Context, q db.Querier, product *db.Product, now time.Time) error {
+	endsAt, ok := softCloseExtension(*product, now)
+	if !ok {
+		return nil
+	}
+	err := q.ExtendProductEndsAt(ctx, db.ExtendProductEndsAtParams{
+		ID:     product.ID,
+		EndsAt: endsAt,
+	})
+	if err != nil {
+		return err
+	}
+	product.EndsAt = endsAt
+	product.ExtensionCount++
+	return emitEvent(ctx, q, events.AuctionExtended, product.ID, events.AuctionExtendedData{
+		ProductID:      product.ID,
+		SellerID:       product.SellerID,
+		EndsAt:         endsAt,
+		ExtensionCount: product.ExtensionCount,
+	})
+}
+
+// sealedAuction holds the bidding rules shared by the sealed formats: every bidder has one bid
+// they can revise until the close, bids only need to reach the starting price and nothing about
+// the amounts is published. There is no soft close since late bids are invisible anyway.
+type sealedAuction struct{ highestBidWins }
+
+func (sealedAuction) prepare(p db.Product, arg *db.AddProductParams, now time.Time) error {
+	if p.BuyNowPrice != nil {
+		return ErrBuyNowNotSupported
+	}
+	return nil
+}
+
+func (sealedAuction) sealed() bool { return true }
+
+func (sealedAuction) currentPrice(product db.Product, valid []db.Bid) int64 {
+	return product.CurrentPrice
+}
+
+func (sealedAuction) bidLimits(ctx context.Context, q db.Querier, product db.Product) (BiddingState, error) {
+	minBid := sealedMinBid(product)
+	return BiddingState{NextMinBid: &minBid}, nil
+}
+
+func (sealedAuction) placeBid(ctx context.Context, q db.Querier, product db.Product, bidderId uuid.UUID, bidAmount int64, quantity int32, now time.Time) error {
+	if minBid := sealedMinBid(product); bidAmount < minBid {
+		return &BidBelowIncrementError{MinBid: minBid}
+	}
+
+	revised := true
+	existing, err := q.GetBidByProductAndUser(ctx, db.GetBidByProductAndUserParams{
+		ProductID: product.ID,
+		UserID:    bidderId,
+	})
+	switch {
+	case err == pgx.ErrNoRows:
+		revised = false
+		err = q.CreateBid(ctx, db.CreateBidParams{
+			ProductID: product.ID,
+			UserID:    bidderId,
+			Price:     bidAmount,
+			Quantity:  quantity,
+		})
+	case err == nil:
+		err = q.ReviseBid(ctx, db.ReviseBidParams{
+			ID:       existing.ID,
+			Price:    bidAmount,
+			Quantity: quantity,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return emitEvent(ctx, q, events.BidPlaced, product.ID, events.SealedBidPlacedData{
+		ProductID: product.ID,
+		SellerID:  product.SellerID,
+		BidderID:  bidderId,
+		Revised:   revised,
+		EndsAt:    product.EndsAt,
+	})
+}
+
+// sealedMinBid is the starting price, or the reserve when it is higher.
+// The current price of a sealed auction never moves, so it always holds the starting price.
+func sealedMinBid(product db.Product) int64 {
+	return max(product.CurrentPrice, product.MinPrice)
+}
+
+// sealedFirstPriceAuction is a sealed tender where the highest bidder pays their own bid.
+type sealedFirstPriceAuction struct{ sealedAuction }
+
+func (sealedFirstPriceAuction) clearingPrice(product db.Product, ranked []db.Bid) (int64, bool) {
+	return ranked[0].Price, true
+}
+
+// vickreyAuction is a sealed tender where the highest bidder pays the second highest bid,
+// or the minimum bid when they were the only bidder.
+type vickreyAuction struct{ sealedAuction }
+
+func (vickreyAuction) clearingPrice(product db.Product, ranked []db.Bid) (int64, bool) {
+	if len(ranked) < 2 {
+		return sealedMinBid(product), true
+	}
+	return max(ranked[1].Price, sealedMinBid(product)), true
+}
+
+// highestBidWins ranks bids from highest to lowest.
+type highestBidWins struct{}
+
+func (highestBidWins) rank(bids []db.Bid) []db.Bid {
+	return rankBids(bids, func(a, b int64) bool { return a > b })
+}
+
+// rankBids orders bids by price using better, earlier bids win ties.
+func rankBids(bids []db.Bid, better func(a, b int64) bool) []db.Bid {
+	ranked := append([]db.Bid(nil), bids...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Price != ranked[j].Price {
+			return better(ranked[i].Price, ranked[j].Price)
+		}
+		return ranked[i].BidAt.Before(ranked[j].BidAt)
+	})
+	return ranked
+}
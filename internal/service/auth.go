@@ -45,6 +45,13 @@ func (as *AuthService) AddUser(ctx context.Context, u db.User) (uuid.UUID, error
 	if exists.ID != uuid.Nil {
 		return uuid.Nil, ErrUserExists
 	}
+	// Usernames are unique as well, they address public profiles
+	if err := checkUsernameFree(ctx, as.db, u.Username); err != nil {
+		if err == ErrUsernameTaken {
+			return uuid.Nil, ErrUserExists
+		}
+		return uuid.Nil, err
+	}
 
 	hash, err := utils.HashPassword(u.Password)
 	if err != nil {
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// processDue runs process on the rows due returns, at most limit of them per run. due is asked for a page of
+// rows without the ones this run already tried, so rows that keep failing are paged past instead of filling
+// every page and holding back the rows due after them. The failures are returned joined, which makes the job
+// record the run as failed and retry it; the failing rows are due again on the next run.
+func processDue[T any](limit int32, due func(skip []uuid.UUID, pageLimit int32) ([]T, error), id func(T) uuid.UUID, process func(T) error) (int, error) {
+	// Never nil, a NULL array would filter out every row
+	skip := []uuid.UUID{}
+	done := 0
+	var errs []error
+	for pageLimit := limit; pageLimit > 0; pageLimit = limit - int32(done) {
+		rows, err := due(skip, pageLimit)
+		if err != nil {
+			return done, errors.Join(append(errs, err)...)
+		}
+		for _, row := range rows {
+			skip = append(skip, id(row))
+			if err := process(row); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			done++
+		}
+		if int32(len(rows)) < pageLimit {
+			break
+		}
+	}
+	return done, errors.Join(errs...)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/pkg/money"
+)
+
+// Deposit requirements of products, mirrored by the CHECK constraint on products.deposit_type.
+// Bidders hold their bid amount, or the fixed deposit_amount, in their wallet while their bid can win.
+const (
+	DepositNone      = "none"
+	DepositBidAmount = "bid_amount"
+	DepositFixed     = "fixed"
+)
+
+// depositFormats are the auction formats that can ask bidders for a deposit.
+// Reverse auctions are bid on by sellers and the other formats take no bids.
+var depositFormats = map[string]bool{
+	AuctionTypeEnglish:          true,
+	AuctionTypeSealedFirstPrice: true,
+	AuctionTypeVickrey:          true,
+}
+
+// prepareDeposit checks the deposit requirement of a new product.
+func prepareDeposit(p db.Product, auctionType string, arg *db.AddProductParams) error {
+	arg.DepositType = p.DepositType
+	if arg.DepositType == "" {
+		arg.DepositType = DepositNone
+	}
+	switch arg.DepositType {
+	case DepositNone, DepositBidAmount:
+		if p.DepositAmount != nil {
+			return ErrInvalidDeposit
+		}
+	case DepositFixed:
+		if p.DepositAmount == nil || *p.DepositAmount <= 0 {
+			return ErrInvalidDeposit
+		}
+		arg.DepositAmount = p.DepositAmount
+	default:
+		return ErrInvalidDeposit
+	}
+	// Wallets hold the default currency only
+	if arg.DepositType != DepositNone && (!depositFormats[auctionType] || arg.Currency != money.DefaultCurrency) {
+		return ErrDepositNotSupported
+	}
+	return nil
+}
+
+// requiredHolds returns what each bidder on the open product must hold, given its valid bids.
+// Bidders of open formats hold while their bid is winning and are released once outbid. Bidders of
+// sealed formats hold until the auction closes, as nobody knows before then whether they were outbid.
+func requiredHolds(product db.Product, valid []db.Bid) map[uuid.UUID]int64 {
+	format := formatFor(product)
+	var allocs []allocation
+	if format.sealed() {
+		for _, bid := range valid {
+			allocs = append(allocs, allocation{WinnerID: bid.UserID, Quantity: bid.Quantity, UnitPrice: bid.Price})
+		}
+	} else {
+		allocs = allocateUnits(product.Quantity, format.rank(valid))
+	}
+
+	required := map[uuid.UUID]int64{}
+	for _, a := range allocs {
+		amount := int64(a.Quantity) * a.UnitPrice
+		if product.DepositType == DepositFixed {
+			amount = *product.DepositAmount
+		}
+		required[a.WinnerID] = amount
+	}
+	return required
+}
+
+// syncBidHolds holds and releases wallet funds, referenced by the product whose row is locked by the caller,
+// until every bidder holds what requiredHolds asks of them. bidderID, who just bid, gets ErrInsufficientFunds
+// when they cannot cover their hold. Other bidders only need more after bids were withdrawn and keep what
+// they could hold otherwise.
+func syncBidHolds(ctx context.Context, q db.Querier, product db.Product, bidderID uuid.UUID) error {
+	if product.DepositType == DepositNone {
+		return nil
+	}
+	valid, err := q.GetValidBidsByProductID(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	required := requiredHolds(product, valid)
+	held, err := heldForProduct(ctx, q, product)
+	if err != nil {
+		return err
+	}
+
+	// Wallet accounts are locked in the same order by every transaction
+	users := make([]uuid.UUID, 0, len(required)+len(held))
+	for userID := range required {
+		users = append(users, userID)
+	}
+	for userID := range held {
+		if _, ok := required[userID]; !ok {
+			users = append(users, userID)
+		}
+	}
+	slices.SortFunc(users, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+
+	for _, userID := range users {
+		diff := required[userID] - held[userID]
+		if diff == 0 {
+			continue
+		}
+		e := ledgerEntry{Kind: EntryHold, UserID: userID, Amount: diff, Key: uuid.NewString(), ReferenceID: &product.ID}
+		if diff < 0 {
+			e.Kind, e.Amount = EntryRelease, -diff
+		}
+		_, err := postEntry(ctx, q, e)
+		if errors.Is(err, ErrInsufficientFunds) && userID != bidderID {
+			slog.Warn("[Deposits] bidder cannot cover their hold", "product_id", product.ID, "bidder_id", userID, "amount", diff)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// settleBidHolds captures what the winners of the closing product hold, up to what they owe, and releases
+// everything else. It returns the amount captured from each winner.
+func settleBidHolds(ctx context.Context, q db.Querier, product db.Product, owed map[uuid.UUID]int64) (map[uuid.UUID]int64, error) {
+	captured := map[uuid.UUID]int64{}
+	if product.DepositType == DepositNone {
+		return captured, nil
+	}
+	held, err := q.GetHeldAmountsByReference(ctx, &product.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Rows come ordered by user, which keeps the lock order of syncBidHolds
+	for _, h := range held {
+		capture := min(h.Held, owed[h.UserID])
+		entries := []ledgerEntry{
+			{Kind: EntryCapture, UserID: h.UserID, Amount: capture, Key: product.ID.String(), ReferenceID: &product.ID},
+			{Kind: EntryRelease, UserID: h.UserID, Amount: h.Held - capture, Key: product.ID.String(), ReferenceID: &product.ID},
+		}
+		for _, e := range entries {
+			if e.Amount == 0 {
+				continue
+			}
+			if _, err := postEntry(ctx, q, e); err != nil {
+				return nil, err
+			}
+		}
+		captured[h.UserID] = capture
+	}
+	return captured, nil
+}
+
+// heldForProduct returns what each bidder currently holds for the product.
+func heldForProduct(ctx context.Context, q db.Querier, product db.Product) (map[uuid.UUID]int64, error) {
+	rows, err := q.GetHeldAmountsByReference(ctx, &product.ID)
+	if err != nil {
+		return nil, err
+	}
+	held := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		held[row.UserID] = row.Held
+	}
+	return held, nil
+}
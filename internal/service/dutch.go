@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/jackc/pgx/v5"
+)
+
+// JobDutchPriceDrops lowers the price of dutch auctions whose next drop is due.
+const JobDutchPriceDrops = "auctions.dutch_price_drops"
+
+const (
+	dutchDropInterval  = 5 * time.Second
+	dutchDropBatchSize = 100
+)
+
+// dutchAuction is a descending auction: the price starts high and drops by a fixed step at a fixed
+// interval until someone accepts it or it reaches the floor (min_price). There is no bidding,
+// the first buyer to accept the current price wins.
+type dutchAuction struct{ highestBidWins }
+
+func (dutchAuction) prepare(p db.Product, arg *db.AddProductParams, now time.Time) error {
+	if p.BuyNowPrice != nil {
+		return ErrBuyNowNotSupported
+	}
+	if p.DutchPriceStep == nil || *p.DutchPriceStep <= 0 || p.DutchIntervalSeconds == nil || *p.DutchIntervalSeconds <= 0 {
+		return ErrInvalidDutchSchedule
+	}
+	if p.CurrentPrice <= p.MinPrice {
+		return ErrInvalidDutchSchedule
+	}
+	nextDrop := now.Add(time.Duration(*p.DutchIntervalSeconds) * time.Second)
+	arg.DutchPriceStep = p.DutchPriceStep
+	arg.DutchIntervalSeconds = p.DutchIntervalSeconds
+	arg.NextPriceDropAt = &nextDrop
+	return nil
+}
+
+func (dutchAuction) sealed() bool { return false }
+
+func (dutchAuction) bidLimits(ctx context.Context, q db.Querier, product db.Product) (BiddingState, error) {
+	price, _ := dutchPriceAt(product, time.Now().UTC())
+	return BiddingState{NextMinBid: &price}, nil
+}
+
+func (dutchAuction) placeBid(ctx context.Context, q db.Querier, product db.Product, bidderId uuid.UUID, amount int64, quantity int32, now time.Time) error {
+	return ErrBiddingNotSupported
+}
+
+func (dutchAuction) clearingPrice(product db.Product, ranked []db.Bid) (int64, bool) {
+	return ranked[0].Price, true
+}
+
+func (dutchAuction) currentPrice(product db.Product, valid []db.Bid) int64 {
+	return product.CurrentPrice
+}
+
+// dutchPriceAt returns the price of a dutch auction at now, catching up on every drop that is due,
+// and when the price drops next. The next drop is nil once the price reached the floor.
+func dutchPriceAt(product db.Product, now time.Time) (int64, *time.Time) {
+	next := product.NextPriceDropAt
+	if next == nil || product.DutchPriceStep == nil || product.DutchIntervalSeconds == nil || now.Before(*next) {
+		return product.CurrentPrice, next
+	}
+	interval := time.Duration(*product.DutchIntervalSeconds) * time.Second
+	steps := int64(now.Sub(*next)/interval) + 1
+	price := product.CurrentPrice - steps**product.DutchPriceStep
+	if price <= product.MinPrice {
+		return product.MinPrice, nil
+	}
+	nextDrop := next.Add(time.Duration(steps) * interval)
+	return price, &nextDrop
+}
+
+// DropDutchPrices lowers the price of every dutch auction with a due drop and returns how many were lowered.
+func (ps *ProductService) DropDutchPrices(ctx context.Context) (int, error) {
+	dueBefore := time.Now().UTC()
+	return processDue(dutchDropBatchSize, func(skip []uuid.UUID, pageLimit int32) ([]db.Product, error) {
+		return ps.db.GetDueDutchPriceDrops(ctx, db.GetDueDutchPriceDropsParams{
+			DueBefore: dueBefore,
+			SkipIds:   skip,
+			PageLimit: pageLimit,
+		})
+	}, func(product db.Product) uuid.UUID {
+		return product.ID
+	}, func(product db.Product) error {
+		if err := ps.dropDutchPrice(ctx, product.ID); err != nil {
+			return fmt.Errorf("failed to drop price of product %s: %w", product.ID, err)
+		}
+		return nil
+	})
+}
+
+// dropDutchPrice applies the due drops of one auction under the product row lock,
+// so a drop never races an accept.
+func (ps *ProductService) dropDutchPrice(ctx context.Context, productID uuid.UUID) error {
+	return ps.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := q.GetProductForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if product.ClosedAt != nil || product.NextPriceDropAt == nil || now.Before(*product.NextPriceDropAt) {
+			return nil
+		}
+
+		price, nextDrop := dutchPriceAt(product, now)
+		err = q.DropProductPrice(ctx, db.DropProductPriceParams{
+			ID:              productID,
+			CurrentPrice:    price,
+			NextPriceDropAt: nextDrop,
+		})
+		if err != nil {
+			return err
+		}
+		return emitEvent(ctx, q, events.AuctionPriceDropped, productID, events.AuctionPriceDroppedData{
+			ProductID:  productID,
+			SellerID:   product.SellerID,
+			Price:      price,
+			NextDropAt: nextDrop,
+		})
+	})
+}
+
+// AcceptPrice sells a dutch auction to the buyer at its current price.
+// The product row is locked, so only the first of several concurrent accepts wins.
+func (ps *ProductService) AcceptPrice(ctx context.Context, productId string, buyerId uuid.UUID) (db.Product, error) {
+	productUUID, err := uuid.Parse(productId)
+	if err != nil {
+		return db.Product{}, ErrProductNotFound
+	}
+
+	var sold db.Product
+	err = ps.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := q.GetProductForUpdate(ctx, productUUID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		if product.AuctionType != AuctionTypeDutch {
+			return ErrNotDutchAuction
+		}
+		if product.SellerID == buyerId {
+			return ErrSelfBuying
+		}
+		if err := checkBuyerAccess(ctx, q, product, buyerId); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if err := checkProductOpen(product, now); err != nil {
+			return err
+		}
+
+		// Drops the scheduler has not applied yet still count for the buyer
+		price, _ := dutchPriceAt(product, now)
+		err = q.CreateBid(ctx, db.CreateBidParams{
+			ProductID: productUUID,
+			UserID:    buyerId,
+			Price:     price,
+			Quantity:  1,
+		})
+		if err != nil {
+			return err
+		}
+		sold, err = sellProduct(ctx, q, product, []allocation{{WinnerID: buyerId, Quantity: 1, UnitPrice: price}}, CloseReasonAccept)
+		return err
+	})
+	if err != nil {
+		return db.Product{}, err
+	}
+	return sold, nil
+}
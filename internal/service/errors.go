@@ -7,10 +7,160 @@ var (
 	ErrUserNotFound = errors.New("user not found")
 	ErrIDMissing    = errors.New("user id is missing")
 
+	// profiles and storefronts
+	ErrUsernameTaken            = errors.New("username is already taken")
+	ErrEmailTaken               = errors.New("email is already used by another account")
+	ErrEmailUnchanged           = errors.New("email is already the email of the account")
+	ErrInvalidVerificationToken = errors.New("email verification token is invalid or has expired")
+	ErrTooManyFeaturedListings  = errors.New("a storefront features at most 6 listings")
+	ErrListingNotFeaturable     = errors.New("only your own public listings that are not drafts can be featured")
+
 	// products
 	ErrSelfBidding     = errors.New("seller cannot bid on their own product")
 	ErrProductNotFound = errors.New("product not found")
 	ErrInsufficientBid = errors.New("bid must be greater than current price")
 	ErrConsecutiveBid  = errors.New("cannot place consecutive bids on the same product")
 	ErrUrlsNotFound    = errors.New("Image Urls not found")
+	ErrAuctionEnded    = errors.New("auction has already ended")
+	ErrInvalidEndsAt   = errors.New("auction end time must be in the future and at most 30 days away")
+
+	// buy now
+	ErrSelfBuying         = errors.New("seller cannot buy their own product")
+	ErrBuyNowUnavailable  = errors.New("buy now is not available for this product")
+	ErrInvalidBuyNowPrice = errors.New("buy now price must be higher than the starting price")
+	ErrBuyNowNotSupported = errors.New("buy now is only available for english auctions")
+
+	// auction formats
+	ErrInvalidAuctionType = errors.New("auction type must be one of english, sealed_first_price, vickrey, dutch, reverse or fixed_price")
+
+	// dutch auctions
+	ErrInvalidDutchSchedule = errors.New("dutch auctions need a price step, an interval and a starting price above the floor")
+	ErrNotDutchAuction      = errors.New("only dutch auctions can be accepted at the current price")
+	ErrBiddingNotSupported  = errors.New("dutch auctions and fixed-price listings are not sold by bidding")
+
+	// multi-unit auctions
+	ErrInvalidQuantity      = errors.New("quantity must be at least 1")
+	ErrQuantityNotSupported = errors.New("only english and sealed first-price auctions without buy now can sell more than one unit")
+	ErrInvalidPricingRule   = errors.New("pricing rule must be pay_as_bid or uniform")
+	ErrInvalidBidQuantity   = errors.New("bid quantity must be between 1 and the quantity on offer")
+
+	// listing lifecycle
+	ErrInvalidStatus           = errors.New("status must be one of draft, scheduled or live")
+	ErrInvalidStatusFilter     = errors.New("status must be one of draft, scheduled, live, ended or relisted")
+	ErrInvalidStatusTransition = errors.New("the listing cannot move to that status from its current status")
+	ErrInvalidStartsAt         = errors.New("start time must be in the future")
+	ErrProductNotLive          = errors.New("product is not live")
+	ErrProductNotEditable      = errors.New("only drafts can be edited freely, live and scheduled listings only allow description changes")
+	ErrNotProductOwner         = errors.New("only the owner of the product can manage it")
+	ErrRelistSold              = errors.New("sold products cannot be relisted")
+
+	// second-chance offers
+	ErrSecondChanceUnavailable = errors.New("second-chance offers are only available for ended, unsold single-unit products")
+	ErrSecondChancePending     = errors.New("the product already has an open second-chance offer")
+	ErrBidNotEligible          = errors.New("the bid is not a valid bid on the product from a bidder who was not offered it yet")
+	ErrInvalidOfferWindow      = errors.New("offers can be open for at most 72 hours")
+	ErrOfferNotFound           = errors.New("offer not found")
+	ErrOfferNotPending         = errors.New("the offer is no longer open")
+
+	// offers on fixed-price listings
+	ErrOffersNotSupported     = errors.New("offers can only be made on fixed-price listings")
+	ErrInvalidOfferThresholds = errors.New("auto-accept and auto-decline prices must not exceed the asking price and auto-decline must be below auto-accept")
+	ErrInvalidOfferPrice      = errors.New("offers must be above zero and below the asking price, sellers may counter at the asking price")
+	ErrOfferAlreadyOpen       = errors.New("you already have an open offer on this product")
+	ErrOwnOffer               = errors.New("offers are answered by the other party")
+
+	// orders
+	ErrOrderNotFound    = errors.New("order not found")
+	ErrInvalidOrderRole = errors.New("role must be buyer or seller")
+
+	// bid retractions
+	ErrBidNotFound              = errors.New("bid not found")
+	ErrRetractionWindowPassed   = errors.New("bids can only be retracted shortly after they were placed")
+	ErrRetractionFinalHour      = errors.New("bids cannot be retracted in the final hour of an auction")
+	ErrRetractionReasonRequired = errors.New("a reason is required to retract a bid")
+
+	// private listings and blocked bidders
+	ErrInvalidVisibility  = errors.New("visibility must be public or private")
+	ErrBidderBlocked      = errors.New("the seller does not accept bids from you")
+	ErrSelfBlocking       = errors.New("sellers cannot block themselves")
+	ErrBlockNotFound      = errors.New("the bidder is not blocked")
+	ErrProductNotPrivate  = errors.New("only private listings have invitations and access codes")
+	ErrSelfInvitation     = errors.New("sellers always have access to their own listings")
+	ErrInvitationNotFound = errors.New("the user is not invited to this listing")
+	ErrAccessCodeNotFound = errors.New("the listing has no access code")
+	ErrInvalidAccessCode  = errors.New("the access code is not valid for this listing")
+
+	// wallet and ledger
+	ErrInvalidAmount          = errors.New("amount must be greater than zero")
+	ErrInsufficientFunds      = errors.New("insufficient available funds")
+	ErrIdempotencyKeyRequired = errors.New("an idempotency key is required")
+	ErrIdempotencyKeyReused   = errors.New("the idempotency key was already used for a different transaction")
+	ErrInvalidDeposit         = errors.New("a fixed deposit needs a deposit amount greater than zero, other deposit types take none")
+	ErrDepositNotSupported    = errors.New("deposits are only supported on english, sealed first-price and vickrey auctions in the wallet currency")
+	ErrWalletCurrency         = errors.New("wallets only hold the wallet currency")
+
+	// currencies
+	ErrInvalidCurrency  = errors.New("currency must be a supported ISO 4217 code")
+	ErrCurrencyMismatch = errors.New("bid currency does not match the listing currency")
+
+	// invoices and payments
+	ErrInvoiceNotFound    = errors.New("invoice not found")
+	ErrInvoiceAlreadyPaid = errors.New("invoice is already paid")
+	ErrInvoiceExpired     = errors.New("invoice expired before it was paid")
+	ErrPaymentDeclined    = errors.New("the payment was declined")
+
+	// shipping and fulfilment
+	ErrInvalidShippingOptions      = errors.New("a listing needs at least one shipping option, at most one per method and region, with free pickup and 2-letter country regions")
+	ErrInvalidAddress              = errors.New("country must be an ISO 3166-1 alpha-2 code")
+	ErrAddressNotFound             = errors.New("address not found")
+	ErrAddressRequired             = errors.New("an address is required unless the order is picked up")
+	ErrShippingOptionNotFound      = errors.New("shipping option not found")
+	ErrShippingUnavailable         = errors.New("the shipping option does not deliver to the address")
+	ErrShippingNotSelected         = errors.New("choose how the order ships before paying")
+	ErrShippingLocked              = errors.New("shipping can only be changed while the order awaits shipment and its invoice is unpaid")
+	ErrOrderNotPaid                = errors.New("the invoice of the order is not paid")
+	ErrInvalidFulfilmentTransition = errors.New("the order cannot move to that fulfilment status")
+	ErrNotOrderParty               = errors.New("only the other party of the order can do this")
+
+	// feedback and reputation
+	ErrInvalidRating          = errors.New("rating must be between 1 and 5")
+	ErrFeedbackNotAllowed     = errors.New("feedback can only be left on paid orders that were not cancelled")
+	ErrFeedbackWindowClosed   = errors.New("the feedback window of the order has closed")
+	ErrFeedbackAlreadyLeft    = errors.New("you already left feedback on this order")
+	ErrFeedbackNotFound       = errors.New("feedback not found")
+	ErrNotFeedbackRecipient   = errors.New("only the recipient of feedback can reply to it")
+	ErrFeedbackAlreadyReplied = errors.New("the feedback already has a reply")
+	ErrReputationTooLow       = errors.New("your reputation is below the minimum this listing requires")
+
+	// messages
+	ErrConversationNotFound  = errors.New("conversation not found")
+	ErrSelfMessaging         = errors.New("sellers cannot start a conversation about their own listing")
+	ErrMessagingBlocked      = errors.New("the seller does not accept messages from you")
+	ErrEmptyMessage          = errors.New("a message needs a body or an attachment")
+	ErrInvalidAttachment     = errors.New("attachments must be uploaded to the conversation first")
+	ErrMessageRateLimited    = errors.New("you are sending messages too quickly, try again in a minute")
+	ErrMessageNotFound       = errors.New("message not found")
+	ErrMessageNotHeld        = errors.New("only messages held for review can be approved")
+	ErrMessageAlreadyRemoved = errors.New("the message was already removed")
+
+	// fees
+	ErrInvalidFeeKind     = errors.New("fee kind must be commission or buyer_premium")
+	ErrInvalidFeeSchedule = errors.New("fee schedule must start at 0 with strictly increasing prices and rates between 0 and 10000 basis points")
+	ErrInvalidFeePeriod   = errors.New("period must be day, week or month and from must be before to")
+
+	// bid increments
+	ErrBidBelowIncrement      = errors.New("bid is below the minimum increment")
+	ErrBidAboveIncrement      = errors.New("bid does not undercut the current price by the minimum increment")
+	ErrInvalidIncrementLadder = errors.New("increment ladder must start at 0 with strictly increasing prices and positive increments")
+
+	// webhooks
+	ErrWebhookNotFound         = errors.New("webhook endpoint not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrWebhookDeliveryInFlight = errors.New("webhook delivery is currently being attempted")
+	ErrWebhookURLNotAllowed    = errors.New("webhook url must resolve to a public address")
+
+	// jobs
+	ErrJobNotFound       = errors.New("job not found")
+	ErrJobNotRetryable   = errors.New("only pending, cancelled or dead jobs can be retried")
+	ErrJobNotCancellable = errors.New("only pending jobs can be cancelled")
 )
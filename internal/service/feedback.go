@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/itsDrac/e-auc/internal/cache"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/pkg/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// Feedback can be left this long after the order was placed
+	defaultFeedbackWindowDays = 60
+	reputationCacheTTL        = 10 * time.Minute
+	reputationKeyPrefix       = "reputation:"
+)
+
+// Reputation sums up the feedback a user received as buyer and seller. Ratings of 4 and 5 are positive,
+// 3 neutral and 1 and 2 negative; Score is the positive minus the negative ratings. PositivePercent leaves
+// neutral ratings out, it and AverageRating are nil until the user has feedback counting towards them.
+type Reputation struct {
+	UserID          uuid.UUID `json:"user_id"`
+	Score           int64     `json:"score"`
+	FeedbackCount   int64     `json:"feedback_count"`
+	Positive        int64     `json:"positive"`
+	Neutral         int64     `json:"neutral"`
+	Negative        int64     `json:"negative"`
+	AsSellerCount   int64     `json:"as_seller_count"`
+	AsBuyerCount    int64     `json:"as_buyer_count"`
+	PositivePercent *float64  `json:"positive_percent"`
+	AverageRating   *float64  `json:"average_rating"`
+}
+
+type FeedbackServicer interface {
+	LeaveFeedback(ctx context.Context, authorID uuid.UUID, orderId string, rating int16, comment *string) (db.Feedback, error)
+	ReplyToFeedback(ctx context.Context, recipientID uuid.UUID, feedbackId string, reply string) (db.Feedback, error)
+	GetFeedback(ctx context.Context, userId string, role string, limit uint, offset uint) ([]db.Feedback, error)
+	GetReputation(ctx context.Context, userID uuid.UUID) (Reputation, error)
+	GetUserReputation(ctx context.Context, userId string) (Reputation, error)
+}
+
+type FeedbackService struct {
+	db             db.Store
+	cache          cache.Cacher
+	feedbackWindow time.Duration
+}
+
+func NewFeedbackService(db db.Store, c cache.Cacher) (*FeedbackService, error) {
+	return &FeedbackService{
+		db:             db,
+		cache:          c,
+		feedbackWindow: time.Duration(utils.GetIntEnv("FEEDBACK_WINDOW_DAYS", defaultFeedbackWindowDays)) * 24 * time.Hour,
+	}, nil
+}
+
+// LeaveFeedback rates the other party of a paid order, once per party and only within the feedback window.
+// The order is locked so both checks hold until the feedback is stored.
+func (fs *FeedbackService) LeaveFeedback(ctx context.Context, authorID uuid.UUID, orderId string, rating int16, comment *string) (db.Feedback, error) {
+	if rating < 1 || rating > 5 {
+		return db.Feedback{}, ErrInvalidRating
+	}
+
+	var feedback db.Feedback
+	err := fs.db.ExecTx(ctx, func(q db.Querier) error {
+		order, err := orderForUpdate(ctx, q, authorID, orderId)
+		if err != nil {
+			return err
+		}
+		if order.FulfilmentStatus == FulfilmentCancelled {
+			return ErrFeedbackNotAllowed
+		}
+		if err := checkOrderPaid(ctx, q, order); err != nil {
+			if err == ErrOrderNotPaid {
+				return ErrFeedbackNotAllowed
+			}
+			return err
+		}
+		if time.Since(order.CreatedAt) > fs.feedbackWindow {
+			return ErrFeedbackWindowClosed
+		}
+		left, err := q.FeedbackExists(ctx, db.FeedbackExistsParams{OrderID: order.ID, AuthorID: authorID})
+		if err != nil {
+			return err
+		}
+		if left {
+			return ErrFeedbackAlreadyLeft
+		}
+
+		arg := db.CreateFeedbackParams{
+			OrderID:       order.ID,
+			AuthorID:      authorID,
+			RecipientID:   order.SellerID,
+			RecipientRole: RoleSeller,
+			Rating:        rating,
+			Comment:       comment,
+		}
+		if authorID == order.SellerID {
+			arg.RecipientID = order.BuyerID
+			arg.RecipientRole = RoleBuyer
+		}
+		feedback, err = q.CreateFeedback(ctx, arg)
+		return err
+	})
+	if err != nil {
+		return db.Feedback{}, err
+	}
+	fs.forgetReputation(ctx, feedback.RecipientID)
+	return feedback, nil
+}
+
+// ReplyToFeedback lets the recipient of feedback answer it once. Replies do not change the reputation.
+func (fs *FeedbackService) ReplyToFeedback(ctx context.Context, recipientID uuid.UUID, feedbackId string, reply string) (db.Feedback, error) {
+	feedbackUUID, err := uuid.Parse(feedbackId)
+	if err != nil {
+		return db.Feedback{}, ErrFeedbackNotFound
+	}
+
+	var replied db.Feedback
+	err = fs.db.ExecTx(ctx, func(q db.Querier) error {
+		feedback, err := q.GetFeedbackForUpdate(ctx, feedbackUUID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrFeedbackNotFound
+			}
+			return err
+		}
+		if feedback.RecipientID != recipientID {
+			return ErrNotFeedbackRecipient
+		}
+		if feedback.Reply != nil {
+			return ErrFeedbackAlreadyReplied
+		}
+		replied, err = q.ReplyToFeedback(ctx, db.ReplyToFeedbackParams{ID: feedback.ID, Reply: &reply})
+		return err
+	})
+	if err != nil {
+		return db.Feedback{}, err
+	}
+	return replied, nil
+}
+
+// GetFeedback returns the feedback a user received, newest first, as buyer or seller only when role is given.
+func (fs *FeedbackService) GetFeedback(ctx context.Context, userId string, role string, limit uint, offset uint) ([]db.Feedback, error) {
+	userUUID, err := fs.existingUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	var feedback []db.Feedback
+	switch role {
+	case "":
+		feedback, err = fs.db.GetFeedbackByRecipientID(ctx, db.GetFeedbackByRecipientIDParams{
+			RecipientID: userUUID,
+			Limit:       int32(limit),
+			Offset:      int32(offset),
+		})
+	case RoleBuyer, RoleSeller:
+		feedback, err = fs.db.GetFeedbackByRecipientAndRole(ctx, db.GetFeedbackByRecipientAndRoleParams{
+			RecipientID:   userUUID,
+			RecipientRole: role,
+			Limit:         int32(limit),
+			Offset:        int32(offset),
+		})
+	default:
+		return nil, ErrInvalidOrderRole
+	}
+	if err != nil {
+		return nil, err
+	}
+	if feedback == nil {
+		feedback = []db.Feedback{}
+	}
+	return feedback, nil
+}
+
+// GetReputation returns the reputation of a user from the cache, computing and caching it on a miss.
+// A failing cache only costs the query.
+func (fs *FeedbackService) GetReputation(ctx context.Context, userID uuid.UUID) (Reputation, error) {
+	key := reputationKeyPrefix + userID.String()
+	cached, ok, err := fs.cache.Get(ctx, key)
+	if err != nil {
+		slog.Warn("[Reputation] cache read failed", "user_id", userID, "error", err)
+	}
+	if ok {
+		var reputation Reputation
+		if err := json.Unmarshal([]byte(cached), &reputation); err == nil {
+			return reputation, nil
+		}
+	}
+
+	reputation, err := reputationOf(ctx, fs.db, userID)
+	if err != nil {
+		return Reputation{}, err
+	}
+	if data, err := json.Marshal(reputation); err == nil {
+		if err := fs.cache.Set(ctx, key, string(data), reputationCacheTTL); err != nil {
+			slog.Warn("[Reputation] cache write failed", "user_id", userID, "error", err)
+		}
+	}
+	return reputation, nil
+}
+
+// GetUserReputation returns the reputation of the user with the given ID, ErrUserNotFound when there is none.
+func (fs *FeedbackService) GetUserReputation(ctx context.Context, userId string) (Reputation, error) {
+	userUUID, err := fs.existingUser(ctx, userId)
+	if err != nil {
+		return Reputation{}, err
+	}
+	return fs.GetReputation(ctx, userUUID)
+}
+
+// existingUser parses the ID of a user who has not been deleted.
+func (fs *FeedbackService) existingUser(ctx context.Context, userId string) (uuid.UUID, error) {
+	userUUID, err := uuid.Parse(userId)
+	if err != nil {
+		return uuid.Nil, ErrUserNotFound
+	}
+	if _, err := fs.db.GetUserByID(ctx, userUUID); err != nil {
+		if err == pgx.ErrNoRows {
+			return uuid.Nil, ErrUserNotFound
+		}
+		return uuid.Nil, err
+	}
+	return userUUID, nil
+}
+
+// forgetReputation drops the cached reputation of a user after they received feedback.
+// Should that fail, the cached reputation is stale until it expires.
+func (fs *FeedbackService) forgetReputation(ctx context.Context, userID uuid.UUID) {
+	if err := fs.cache.Delete(ctx, reputationKeyPrefix+userID.String()); err != nil {
+		slog.Warn("[Reputation] cache invalidation failed", "user_id", userID, "error", err)
+	}
+}
+
+// reputationOf computes the reputation of a user from the feedback they received.
+func reputationOf(ctx context.Context, q db.Querier, userID uuid.UUID) (Reputation, error) {
+	row, err := q.GetReputationByUserID(ctx, userID)
+	if err != nil {
+		return Reputation{}, err
+	}
+	reputation := Reputation{
+		UserID:        userID,
+		Score:         row.Positive - row.Negative,
+		FeedbackCount: row.FeedbackCount,
+		Positive:      row.Positive,
+		Neutral:       row.Neutral,
+		Negative:      row.Negative,
+		AsSellerCount: row.AsSeller,
+		AsBuyerCount:  row.FeedbackCount - row.AsSeller,
+	}
+	if rated := row.Positive + row.Negative; rated > 0 {
+		percent := math.Round(float64(row.Positive)*1000/float64(rated)) / 10
+		reputation.PositivePercent = &percent
+	}
+	if row.FeedbackCount > 0 {
+		average := math.Round(float64(row.RatingSum)*100/float64(row.FeedbackCount)) / 100
+		reputation.AverageRating = &average
+	}
+	return reputation, nil
+}
+
+// checkBidderReputation returns ErrReputationTooLow when the product asks for a minimum bidder reputation
+// the user does not have. It reads the feedback itself rather than the cached reputation.
+func checkBidderReputation(ctx context.Context, q db.Querier, product db.Product, userID uuid.UUID) error {
+	if product.MinBidderReputation == nil {
+		return nil
+	}
+	reputation, err := reputationOf(ctx, q, userID)
+	if err != nil {
+		return err
+	}
+	if reputation.Score < int64(*product.MinBidderReputation) {
+		return ErrReputationTooLow
+	}
+	return nil
+}
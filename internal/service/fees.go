@@ -0,0 +1,134 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"time"
+
+	db "github.com/itsDrac/e-auc/internal/database"
+)
+
+// Fee kinds, mirrored by the CHECK constraint on fee_rules.kind. The commission is taken from the seller's
+// final value, the buyer premium is added to the buyer's invoice.
+const (
+	FeeCommission   = "commission"
+	FeeBuyerPremium = "buyer_premium"
+)
+
+// Fee schedule scopes, a category schedule beats the platform schedule of the same kind.
+const (
+	FeeScopePlatform = "platform"
+	FeeScopeCategory = "category"
+)
+
+// Periods fee revenue can be reported by, as understood by date_trunc.
+var feePeriods = map[string]bool{
+	"day":   true,
+	"week":  true,
+	"month": true,
+}
+
+// FeeTier is one tier of a fee schedule: the part of the price from MinPrice up to the next tier is charged
+// RateBps basis points. A schedule of a single tier is a flat percentage.
+type FeeTier struct {
+	MinPrice int64 `json:"min_price"`
+	RateBps  int32 `json:"rate_bps"`
+}
+
+// FeeRevenue is the fee revenue of one period in one currency. Reversed are the fees of invoices that expired unpaid.
+type FeeRevenue struct {
+	PeriodStart  time.Time `json:"period_start"`
+	Currency     string    `json:"currency"`
+	Commission   int64     `json:"commission"`
+	BuyerPremium int64     `json:"buyer_premium"`
+	Reversed     int64     `json:"reversed"`
+	Net          int64     `json:"net"`
+	Sales        int64     `json:"sales"`
+}
+
+// validateFeeSchedule checks that a schedule starts at 0, that its tiers are strictly increasing and its rates valid.
+func validateFeeSchedule(tiers []FeeTier) error {
+	if len(tiers) == 0 || tiers[0].MinPrice != 0 {
+		return ErrInvalidFeeSchedule
+	}
+	for i, tier := range tiers {
+		if tier.RateBps < 0 || tier.RateBps > 10000 {
+			return ErrInvalidFeeSchedule
+		}
+		if i > 0 && tier.MinPrice <= tiers[i-1].MinPrice {
+			return ErrInvalidFeeSchedule
+		}
+	}
+	return nil
+}
+
+// feeRulesFor loads every fee rule that may apply to the product.
+func feeRulesFor(ctx context.Context, q db.Querier, p db.Product) ([]db.FeeRule, error) {
+	return q.GetFeeRulesForCategory(ctx, p.Category)
+}
+
+// feeFor returns the fee of kind on price from the most specific schedule, zero when no schedule applies.
+// rules must be ordered by min_price, as returned by GetFeeRulesForCategory. Every tier charges its rate
+// on the part of the price inside it and the sum is rounded half up.
+func feeFor(rules []db.FeeRule, kind string, price int64) int64 {
+	for _, scope := range []string{FeeScopeCategory, FeeScopePlatform} {
+		var tiers []db.FeeRule
+		for _, rule := range rules {
+			if rule.Kind == kind && rule.Scope == scope {
+				tiers = append(tiers, rule)
+			}
+		}
+		if len(tiers) == 0 {
+			continue
+		}
+		var fee int64
+		for i, tier := range tiers {
+			if price <= tier.MinPrice {
+				break
+			}
+			upper := price
+			if i+1 < len(tiers) && tiers[i+1].MinPrice < price {
+				upper = tiers[i+1].MinPrice
+			}
+			fee += (upper - tier.MinPrice) * int64(tier.RateBps)
+		}
+		return (fee + 5000) / 10000
+	}
+	return 0
+}
+
+// recordInvoiceFees books the fees of a new invoice as platform revenue: the commission under the seller
+// and the buyer premium under the buyer, both referencing the invoice.
+func recordInvoiceFees(ctx context.Context, q db.Querier, invoice db.Invoice) error {
+	entries := []ledgerEntry{
+		{Kind: EntryCommission, UserID: invoice.SellerID, Amount: invoice.PlatformFee},
+		{Kind: EntryBuyerPremium, UserID: invoice.BuyerID, Amount: invoice.BuyerPremium},
+	}
+	return postInvoiceFees(ctx, q, invoice, entries)
+}
+
+// reverseInvoiceFees takes the fees of an expired invoice out of the platform revenue again.
+func reverseInvoiceFees(ctx context.Context, q db.Querier, invoice db.Invoice) error {
+	entries := []ledgerEntry{
+		{Kind: EntryFeeReversal, UserID: invoice.SellerID, Amount: invoice.PlatformFee},
+		{Kind: EntryFeeReversal, UserID: invoice.BuyerID, Amount: invoice.BuyerPremium},
+	}
+	return postInvoiceFees(ctx, q, invoice, entries)
+}
+
+// postInvoiceFees posts the fee entries of an invoice, locking the accounts of its parties in user order
+// like syncBidHolds does.
+func postInvoiceFees(ctx context.Context, q db.Querier, invoice db.Invoice, entries []ledgerEntry) error {
+	slices.SortFunc(entries, func(a, b ledgerEntry) int { return bytes.Compare(a.UserID[:], b.UserID[:]) })
+	for _, e := range entries {
+		if e.Amount == 0 {
+			continue
+		}
+		e.Key, e.ReferenceID, e.Currency = invoice.ID.String(), &invoice.ID, invoice.Currency
+		if _, err := postEntry(ctx, q, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+)
+
+// fixedPriceListing sells at its asking price (current_price) to the first buyer, who buys it through
+// buy now, or at a price the seller and a buyer agree on through offers. There is no bidding.
+type fixedPriceListing struct{ highestBidWins }
+
+// prepare makes the asking price the buy-now price and checks the optional offer thresholds.
+// A buy-now price given with the listing is ignored.
+func (fixedPriceListing) prepare(p db.Product, arg *db.AddProductParams, now time.Time) error {
+	if p.AutoAcceptPrice != nil && *p.AutoAcceptPrice > p.CurrentPrice {
+		return ErrInvalidOfferThresholds
+	}
+	if p.AutoDeclinePrice != nil {
+		if *p.AutoDeclinePrice > p.CurrentPrice || (p.AutoAcceptPrice != nil && *p.AutoDeclinePrice >= *p.AutoAcceptPrice) {
+			return ErrInvalidOfferThresholds
+		}
+	}
+	askingPrice := p.CurrentPrice
+	arg.BuyNowPrice = &askingPrice
+	arg.AutoAcceptPrice = p.AutoAcceptPrice
+	arg.AutoDeclinePrice = p.AutoDeclinePrice
+	return nil
+}
+
+func (fixedPriceListing) sealed() bool { return false }
+
+func (fixedPriceListing) bidLimits(ctx context.Context, q db.Querier, product db.Product) (BiddingState, error) {
+	return BiddingState{}, nil
+}
+
+func (fixedPriceListing) placeBid(ctx context.Context, q db.Querier, product db.Product, bidderId uuid.UUID, amount int64, quantity int32, now time.Time) error {
+	return ErrBiddingNotSupported
+}
+
+func (fixedPriceListing) clearingPrice(product db.Product, ranked []db.Bid) (int64, bool) {
+	return ranked[0].Price, true
+}
+
+func (fixedPriceListing) currentPrice(product db.Product, valid []db.Bid) int64 {
+	return product.CurrentPrice
+}
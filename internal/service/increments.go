@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	db "github.com/itsDrac/e-auc/internal/database"
+)
+
+// Bid increment ladder scopes, the most specific ladder that exists for a product wins.
+const (
+	IncrementScopePlatform = "platform"
+	IncrementScopeCategory = "category"
+	IncrementScopeProduct  = "product"
+)
+
+// IncrementStep is one rung of a bid increment ladder: from MinPrice upwards a bid must raise the price by at least Increment.
+type IncrementStep struct {
+	MinPrice  int64 `json:"min_price"`
+	Increment int64 `json:"increment"`
+}
+
+// BidBelowIncrementError is returned when a bid does not reach the next rung of the ladder.
+// It matches ErrBidBelowIncrement with errors.Is.
+type BidBelowIncrementError struct {
+	MinBid int64
+}
+
+func (e *BidBelowIncrementError) Error() string {
+	return fmt.Sprintf("bid must be at least %d", e.MinBid)
+}
+
+func (e *BidBelowIncrementError) Is(target error) bool {
+	return target == ErrBidBelowIncrement
+}
+
+// BidAboveIncrementError is returned when a bid on a reverse auction does not undercut the current price
+// by the increment. It matches ErrBidAboveIncrement with errors.Is.
+type BidAboveIncrementError struct {
+	MaxBid int64
+}
+
+func (e *BidAboveIncrementError) Error() string {
+	return fmt.Sprintf("bid must be at most %d", e.MaxBid)
+}
+
+func (e *BidAboveIncrementError) Is(target error) bool {
+	return target == ErrBidAboveIncrement
+}
+
+// validateLadder checks that a ladder starts at 0 and that its rungs are strictly increasing.
+func validateLadder(steps []IncrementStep) error {
+	if len(steps) == 0 || steps[0].MinPrice != 0 {
+		return ErrInvalidIncrementLadder
+	}
+	for i, step := range steps {
+		if step.Increment <= 0 {
+			return ErrInvalidIncrementLadder
+		}
+		if i > 0 && step.MinPrice <= steps[i-1].MinPrice {
+			return ErrInvalidIncrementLadder
+		}
+	}
+	return nil
+}
+
+// normalizeCategory trims and lower-cases a category so ladders match regardless of spelling.
+func normalizeCategory(category *string) *string {
+	if category == nil {
+		return nil
+	}
+	c := strings.ToLower(strings.TrimSpace(*category))
+	if c == "" {
+		return nil
+	}
+	return &c
+}
+
+// incrementRulesFor loads every rule that may apply to the product.
+func incrementRulesFor(ctx context.Context, q db.Querier, p db.Product) ([]db.BidIncrementRule, error) {
+	return q.GetBidIncrementRulesForProduct(ctx, db.GetBidIncrementRulesForProductParams{
+		Category:  p.Category,
+		ProductID: p.ID,
+	})
+}
+
+// nextMinBid returns the lowest acceptable bid on top of currentPrice.
+// rules must be ordered by min_price, as returned by GetBidIncrementRulesForProduct.
+func nextMinBid(rules []db.BidIncrementRule, currentPrice int64) int64 {
+	return currentPrice + incrementAt(rules, currentPrice)
+}
+
+// nextMaxBid returns the highest acceptable bid below currentPrice, for auctions where prices go down.
+func nextMaxBid(rules []db.BidIncrementRule, currentPrice int64) int64 {
+	return currentPrice - incrementAt(rules, currentPrice)
+}
+
+// incrementAt returns the increment of the most specific ladder at price, 1 when no ladder applies.
+func incrementAt(rules []db.BidIncrementRule, price int64) int64 {
+	for _, scope := range []string{IncrementScopeProduct, IncrementScopeCategory, IncrementScopePlatform} {
+		var increment int64
+		for _, rule := range rules {
+			if rule.Scope == scope && rule.MinPrice <= price {
+				increment = rule.Increment
+			}
+		}
+		if increment > 0 {
+			return increment
+		}
+	}
+	return 1
+}
@@ -0,0 +1,498 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/itsDrac/e-auc/internal/jobs"
+	"github.com/itsDrac/e-auc/internal/payments"
+	"github.com/jackc/pgx/v5"
+)
+
+// Statuses of an invoice, mirrored by the CHECK constraint on invoices.status.
+const (
+	InvoicePending    = "pending"
+	InvoiceProcessing = "processing"
+	InvoicePaid       = "paid"
+	InvoiceExpired    = "expired"
+)
+
+// Statuses of a payment attempt, mirrored by the CHECK constraint on payments.status.
+const (
+	PaymentProcessing = "processing"
+	PaymentSucceeded  = "succeeded"
+	PaymentFailed     = "failed"
+)
+
+// JobInvoiceExpiry settles stale payment attempts and expires invoices that were not paid by their deadline.
+const JobInvoiceExpiry = "invoices.expiry"
+
+const (
+	invoicePaymentWindow  = 72 * time.Hour
+	invoiceExpiryInterval = time.Minute
+	invoiceExpiryBatch    = 100
+	// Attempts processing for longer are assumed to be interrupted, their charge is looked up at the provider
+	paymentProcessingTimeout = 10 * time.Minute
+)
+
+// invoiceCharges is what the sale of one allocation costs, in the currency of the product. The buyer pays
+// the item, the buyer premium and shipping, the platform fee is charged to the seller.
+type invoiceCharges struct {
+	Currency     string
+	ItemPrice    int64
+	BuyerPremium int64
+	PlatformFee  int64
+	Shipping     int64
+}
+
+func (c invoiceCharges) total() int64 {
+	return c.ItemPrice + c.BuyerPremium + c.Shipping
+}
+
+// chargesFor prices the invoice of an allocation: the units sold, the fees of the product's fee schedules
+// on them and the shipping of the order.
+func chargesFor(product db.Product, a allocation, rules []db.FeeRule, shipping int64) invoiceCharges {
+	item := int64(a.Quantity) * a.UnitPrice
+	return invoiceCharges{
+		Currency:     product.Currency,
+		ItemPrice:    item,
+		BuyerPremium: feeFor(rules, FeeBuyerPremium, item),
+		PlatformFee:  feeFor(rules, FeeCommission, item),
+		Shipping:     shipping,
+	}
+}
+
+// createInvoice bills the buyer of the order, less the deposit captured from them, and books its fees.
+// An invoice the deposit covers entirely is paid right away, once the order knows how it ships.
+func createInvoice(ctx context.Context, q db.Querier, order db.Order, charges invoiceCharges, deposit int64) (db.Invoice, error) {
+	total := charges.total()
+	applied := min(deposit, total)
+	arg := db.CreateInvoiceParams{
+		OrderID:        order.ID,
+		ProductID:      order.ProductID,
+		SellerID:       order.SellerID,
+		BuyerID:        order.BuyerID,
+		ItemPrice:      charges.ItemPrice,
+		BuyerPremium:   charges.BuyerPremium,
+		PlatformFee:    charges.PlatformFee,
+		Shipping:       charges.Shipping,
+		Total:          total,
+		DepositApplied: applied,
+		AmountDue:      total - applied,
+		Status:         InvoicePending,
+		DueAt:          time.Now().UTC().Add(invoicePaymentWindow),
+		Currency:       charges.Currency,
+	}
+	// Without a shipping method the buyer still has to choose one, which may add to the amount due
+	if arg.AmountDue == 0 && order.ShippingMethod != nil {
+		now := time.Now().UTC()
+		arg.Status = InvoicePaid
+		arg.PaidAt = &now
+	}
+	invoice, err := q.CreateInvoice(ctx, arg)
+	if err != nil {
+		return db.Invoice{}, err
+	}
+	if err := recordInvoiceFees(ctx, q, invoice); err != nil {
+		return db.Invoice{}, err
+	}
+	if err := emitEvent(ctx, q, events.InvoiceCreated, invoice.ID, invoiceData(invoice)); err != nil {
+		return db.Invoice{}, err
+	}
+	return invoice, nil
+}
+
+func invoiceData(invoice db.Invoice) events.InvoiceData {
+	return events.InvoiceData{
+		InvoiceID: invoice.ID,
+		OrderID:   invoice.OrderID,
+		ProductID: invoice.ProductID,
+		SellerID:  invoice.SellerID,
+		BuyerID:   invoice.BuyerID,
+		Currency:  invoice.Currency,
+		Total:     invoice.Total,
+		AmountDue: invoice.AmountDue,
+		Status:    invoice.Status,
+		DueAt:     invoice.DueAt,
+	}
+}
+
+type InvoiceServicer interface {
+	GetInvoices(ctx context.Context, userID uuid.UUID, role string, limit uint, offset uint) ([]db.Invoice, error)
+	GetInvoice(ctx context.Context, userID uuid.UUID, invoiceId string) (db.Invoice, []db.Payment, error)
+	PayInvoice(ctx context.Context, buyerID uuid.UUID, invoiceId string, paymentToken string) (db.Invoice, error)
+	ReconcilePayments(ctx context.Context) (int, error)
+	ExpireInvoices(ctx context.Context) (int, error)
+	GetPayouts(ctx context.Context, sellerID uuid.UUID, limit uint, offset uint) ([]db.GetSellerPayoutsRow, error)
+}
+
+type InvoiceService struct {
+	db       db.Store
+	provider payments.PaymentProvider
+}
+
+func NewInvoiceService(db db.Store, provider payments.PaymentProvider) (*InvoiceService, error) {
+	return &InvoiceService{
+		db:       db,
+		provider: provider,
+	}, nil
+}
+
+func registerInvoiceJobs(is *InvoiceService, queue *jobs.Queue) {
+	queue.Register(JobInvoiceExpiry, func(ctx context.Context, job jobs.Job) error {
+		// Reconciled invoices that are reopened past their deadline expire in the same run
+		_, reconcileErr := is.ReconcilePayments(ctx)
+		_, err := is.ExpireInvoices(ctx)
+		return errors.Join(reconcileErr, err)
+	}, jobs.Options{MaxAttempts: 3})
+	queue.Every(JobInvoiceExpiry, invoiceExpiryInterval)
+}
+
+// GetInvoices returns the invoices the user owes (RoleBuyer) or is paid through (RoleSeller), newest first.
+func (is *InvoiceService) GetInvoices(ctx context.Context, userID uuid.UUID, role string, limit uint, offset uint) ([]db.Invoice, error) {
+	var invoices []db.Invoice
+	var err error
+	switch role {
+	case RoleBuyer:
+		invoices, err = is.db.GetInvoicesByBuyerID(ctx, db.GetInvoicesByBuyerIDParams{
+			BuyerID: userID,
+			Limit:   int32(limit),
+			Offset:  int32(offset),
+		})
+	case RoleSeller:
+		invoices, err = is.db.GetInvoicesBySellerID(ctx, db.GetInvoicesBySellerIDParams{
+			SellerID: userID,
+			Limit:    int32(limit),
+			Offset:   int32(offset),
+		})
+	default:
+		return nil, ErrInvalidOrderRole
+	}
+	if err != nil {
+		return nil, err
+	}
+	if invoices == nil {
+		invoices = []db.Invoice{}
+	}
+	return invoices, nil
+}
+
+// GetInvoice returns an invoice the user is a party of with its payment attempts, oldest first.
+// Invoices of other users are reported as not found.
+func (is *InvoiceService) GetInvoice(ctx context.Context, userID uuid.UUID, invoiceId string) (db.Invoice, []db.Payment, error) {
+	invoiceUUID, err := uuid.Parse(invoiceId)
+	if err != nil {
+		return db.Invoice{}, nil, ErrInvoiceNotFound
+	}
+	invoice, err := is.db.GetInvoiceByID(ctx, invoiceUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Invoice{}, nil, ErrInvoiceNotFound
+		}
+		return db.Invoice{}, nil, err
+	}
+	if invoice.BuyerID != userID && invoice.SellerID != userID {
+		return db.Invoice{}, nil, ErrInvoiceNotFound
+	}
+	attempts, err := is.db.GetPaymentsByInvoiceID(ctx, invoiceUUID)
+	if err != nil {
+		return db.Invoice{}, nil, err
+	}
+	if attempts == nil {
+		attempts = []db.Payment{}
+	}
+	return invoice, attempts, nil
+}
+
+// PayInvoice charges the amount due to the buyer's payment method through the payment provider, once they chose
+// how the order ships. The charge is made outside any transaction: a processing payment attempt is committed
+// first, which keeps the invoice from being paid twice, expiring or changing shipping meanwhile, and the result
+// is recorded afterwards. Declined payments are recorded and return ErrPaymentDeclined.
+func (is *InvoiceService) PayInvoice(ctx context.Context, buyerID uuid.UUID, invoiceId string, paymentToken string) (db.Invoice, error) {
+	invoiceUUID, err := uuid.Parse(invoiceId)
+	if err != nil {
+		return db.Invoice{}, ErrInvoiceNotFound
+	}
+
+	invoice, payment, err := is.startPayment(ctx, buyerID, invoiceUUID)
+	if err != nil {
+		return db.Invoice{}, err
+	}
+	// The attempt is the idempotency key, a resumed attempt is charged once by the provider
+	charge, err := is.provider.Charge(ctx, payments.ChargeRequest{
+		IdempotencyKey: payment.ID.String(),
+		Amount:         payment.Amount,
+		Currency:       invoice.Currency,
+		Token:          paymentToken,
+		Description:    fmt.Sprintf("Invoice %s", invoice.ID),
+	})
+	if err != nil && !errors.Is(err, payments.ErrPaymentDeclined) {
+		// The outcome is unknown, the attempt stays processing until the buyer pays again or it is reconciled
+		return db.Invoice{}, fmt.Errorf("failed to charge invoice %s: %w", invoice.ID, err)
+	}
+	return is.finishPayment(ctx, invoice.ID, payment.ID, charge, err)
+}
+
+// startPayment locks the invoice and commits a processing payment attempt for its amount due. An invoice that is
+// already processing resumes its attempt, whose charge may have gone through before its result was recorded.
+func (is *InvoiceService) startPayment(ctx context.Context, buyerID uuid.UUID, invoiceID uuid.UUID) (db.Invoice, db.Payment, error) {
+	var invoice db.Invoice
+	var payment db.Payment
+	err := is.db.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		invoice, err = q.GetInvoiceForUpdate(ctx, invoiceID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrInvoiceNotFound
+			}
+			return err
+		}
+		if invoice.BuyerID != buyerID {
+			return ErrInvoiceNotFound
+		}
+		switch {
+		case invoice.Status == InvoicePaid:
+			return ErrInvoiceAlreadyPaid
+		case invoice.Status == InvoiceProcessing:
+			payment, err = q.GetProcessingPayment(ctx, invoice.ID)
+			return err
+		case invoice.Status == InvoiceExpired || !time.Now().UTC().Before(invoice.DueAt):
+			return ErrInvoiceExpired
+		}
+		// Shipping is part of the amount due, it has to be settled first
+		order, err := q.GetOrderByID(ctx, invoice.OrderID)
+		if err != nil {
+			return err
+		}
+		if order.ShippingMethod == nil {
+			return ErrShippingNotSelected
+		}
+
+		payment, err = q.CreatePayment(ctx, db.CreatePaymentParams{
+			InvoiceID: invoice.ID,
+			Provider:  is.provider.Name(),
+			Amount:    invoice.AmountDue,
+			Status:    PaymentProcessing,
+		})
+		if err != nil {
+			return err
+		}
+		invoice, err = q.MarkInvoiceProcessing(ctx, invoice.ID)
+		return err
+	})
+	if err != nil {
+		return db.Invoice{}, db.Payment{}, err
+	}
+	return invoice, payment, nil
+}
+
+// finishPayment records the outcome of a charged attempt: the invoice is paid, or back to pending when the charge
+// was declined or never taken. An attempt already recorded by a concurrent resume returns its outcome.
+func (is *InvoiceService) finishPayment(ctx context.Context, invoiceID uuid.UUID, paymentID uuid.UUID, charge payments.Charge, chargeErr error) (db.Invoice, error) {
+	var invoice db.Invoice
+	declined := false
+	err := is.db.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		invoice, err = q.GetInvoiceForUpdate(ctx, invoiceID)
+		if err != nil {
+			return err
+		}
+		payment, err := q.GetPaymentByID(ctx, paymentID)
+		if err != nil {
+			return err
+		}
+		if payment.Status != PaymentProcessing {
+			declined = payment.Status == PaymentFailed
+			return nil
+		}
+
+		if chargeErr != nil {
+			declined = true
+			reason := chargeErr.Error()
+			_, err = q.CompletePayment(ctx, db.CompletePaymentParams{
+				ID:            payment.ID,
+				Status:        PaymentFailed,
+				FailureReason: &reason,
+			})
+			if err != nil {
+				return err
+			}
+			invoice, err = q.MarkInvoicePending(ctx, invoice.ID)
+			return err
+		}
+
+		_, err = q.CompletePayment(ctx, db.CompletePaymentParams{
+			ID:                payment.ID,
+			Status:            PaymentSucceeded,
+			ProviderReference: &charge.Reference,
+		})
+		if err != nil {
+			return err
+		}
+		invoice, err = q.MarkInvoicePaid(ctx, invoice.ID)
+		if err != nil {
+			return err
+		}
+		return emitEvent(ctx, q, events.InvoicePaid, invoice.ID, invoiceData(invoice))
+	})
+	if err != nil {
+		return db.Invoice{}, err
+	}
+	if declined {
+		return db.Invoice{}, ErrPaymentDeclined
+	}
+	return invoice, nil
+}
+
+// GetPayouts lists what the seller is paid per sold item, newest first: the item and shipping, the platform fee
+// and what is left. Items whose invoice expired unpaid are left out.
+func (is *InvoiceService) GetPayouts(ctx context.Context, sellerID uuid.UUID, limit uint, offset uint) ([]db.GetSellerPayoutsRow, error) {
+	payouts, err := is.db.GetSellerPayouts(ctx, db.GetSellerPayoutsParams{
+		SellerID: sellerID,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if payouts == nil {
+		payouts = []db.GetSellerPayoutsRow{}
+	}
+	return payouts, nil
+}
+
+// ReconcilePayments settles the payment attempts processing for longer than paymentProcessingTimeout, whose
+// buyer never paid again after the charge ended without a clear outcome. The provider is asked for the charge
+// taken under the attempt's key: the invoice is paid when there is one, otherwise the attempt fails and the
+// invoice is pending again, so it can be paid or expire. It returns how many attempts were settled.
+func (is *InvoiceService) ReconcilePayments(ctx context.Context) (int, error) {
+	startedBefore := time.Now().UTC().Add(-paymentProcessingTimeout)
+	return processDue(invoiceExpiryBatch, func(skip []uuid.UUID, pageLimit int32) ([]db.Payment, error) {
+		return is.db.GetStaleProcessingPayments(ctx, db.GetStaleProcessingPaymentsParams{
+			StartedBefore: startedBefore,
+			SkipIds:       skip,
+			PageLimit:     pageLimit,
+		})
+	}, func(payment db.Payment) uuid.UUID {
+		return payment.ID
+	}, func(payment db.Payment) error {
+		charge, chargeErr := is.provider.LookupCharge(ctx, payment.ID.String())
+		if chargeErr != nil && !errors.Is(chargeErr, payments.ErrChargeNotFound) {
+			return fmt.Errorf("failed to look up the charge of payment %s: %w", payment.ID, chargeErr)
+		}
+		_, err := is.finishPayment(ctx, payment.InvoiceID, payment.ID, charge, chargeErr)
+		if err != nil && !errors.Is(err, ErrPaymentDeclined) {
+			return fmt.Errorf("failed to reconcile payment %s: %w", payment.ID, err)
+		}
+		return nil
+	})
+}
+
+// ExpireInvoices expires every pending invoice past its deadline and returns how many were expired.
+func (is *InvoiceService) ExpireInvoices(ctx context.Context) (int, error) {
+	dueBefore := time.Now().UTC()
+	return processDue(invoiceExpiryBatch, func(skip []uuid.UUID, pageLimit int32) ([]db.Invoice, error) {
+		return is.db.GetOverdueInvoices(ctx, db.GetOverdueInvoicesParams{
+			DueBefore: dueBefore,
+			SkipIds:   skip,
+			PageLimit: pageLimit,
+		})
+	}, func(invoice db.Invoice) uuid.UUID {
+		return invoice.ID
+	}, func(invoice db.Invoice) error {
+		err := is.db.ExecTx(ctx, func(q db.Querier) error {
+			return expireInvoice(ctx, q, invoice.ProductID, invoice.ID)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to expire invoice %s: %w", invoice.ID, err)
+		}
+		return nil
+	})
+}
+
+// forfeitDeposit moves the deposit applied to an expired invoice out of escrow into the seller's available funds.
+func forfeitDeposit(ctx context.Context, q db.Querier, invoice db.Invoice) error {
+	if invoice.DepositApplied == 0 {
+		return nil
+	}
+	_, err := postEntry(ctx, q, ledgerEntry{
+		Kind:        EntryForfeit,
+		UserID:      invoice.SellerID,
+		Amount:      invoice.DepositApplied,
+		Key:         invoice.ID.String(),
+		ReferenceID: &invoice.ID,
+	})
+	return err
+}
+
+// expireInvoice gives the buyer of an unpaid invoice a non-payment strike, cancels the order, reverses its fees
+// and forfeits the deposit captured from the buyer to the seller. A single-unit product goes back to unsold
+// without the buyer's bids, so the seller can offer it to a runner-up, which happens right away when the seller
+// opted in. The product is locked before the invoice, like in the sale that created it.
+func expireInvoice(ctx context.Context, q db.Querier, productID uuid.UUID, invoiceID uuid.UUID) error {
+	product, err := q.GetProductForUpdate(ctx, productID)
+	if err != nil {
+		return err
+	}
+	invoice, err := q.GetInvoiceForUpdate(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+	if invoice.Status != InvoicePending || time.Now().UTC().Before(invoice.DueAt) {
+		return nil
+	}
+
+	invoice, err = q.MarkInvoiceExpired(ctx, invoice.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := q.CreateNonPaymentStrike(ctx, db.CreateNonPaymentStrikeParams{UserID: invoice.BuyerID, InvoiceID: invoice.ID}); err != nil {
+		return err
+	}
+	if err := q.CancelOrderFulfilment(ctx, invoice.OrderID); err != nil {
+		return err
+	}
+	if err := reverseInvoiceFees(ctx, q, invoice); err != nil {
+		return err
+	}
+	if err := forfeitDeposit(ctx, q, invoice); err != nil {
+		return err
+	}
+	if err := emitEvent(ctx, q, events.InvoiceExpired, invoice.ID, invoiceData(invoice)); err != nil {
+		return err
+	}
+
+	if product.Quantity != 1 || product.SoldTo == nil || *product.SoldTo != invoice.BuyerID {
+		return nil
+	}
+	err = q.InvalidateBidsByBidder(ctx, db.InvalidateBidsByBidderParams{ProductID: product.ID, UserID: invoice.BuyerID})
+	if err != nil {
+		return err
+	}
+	product, err = q.ReopenUnpaidProduct(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	if !product.SecondChanceOnNonPayment || !secondChanceAvailable(product) {
+		return nil
+	}
+	offers, err := q.GetSecondChanceOffersByProductID(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	bids, err := q.GetValidBidsByProductID(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	candidates := runnerUps(product, bids, offers)
+	if len(candidates) == 0 {
+		return nil
+	}
+	_, err = createSecondChanceOffer(ctx, q, product, candidates[0], defaultSecondChanceWindow, true)
+	return err
+}
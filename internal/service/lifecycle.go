@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/jackc/pgx/v5"
+)
+
+// Listing statuses, mirrored by the CHECK constraint on products.status.
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusLive      = "live"
+	StatusEnded     = "ended"
+	StatusRelisted  = "relisted"
+)
+
+// ProductStatuses lists every listing status in lifecycle order.
+var ProductStatuses = []string{StatusDraft, StatusScheduled, StatusLive, StatusEnded, StatusRelisted}
+
+// statusTransitions holds the statuses a listing may move to from each status.
+// A scheduled listing can be rescheduled, ended listings only move on when they are relisted.
+var statusTransitions = map[string][]string{
+	StatusDraft:     {StatusScheduled, StatusLive},
+	StatusScheduled: {StatusScheduled, StatusLive},
+	StatusLive:      {StatusEnded},
+	StatusEnded:     {StatusRelisted},
+}
+
+// JobStartScheduledListings puts scheduled listings live once their start time is reached.
+const JobStartScheduledListings = "auctions.start_scheduled"
+
+const (
+	scheduledStartInterval  = 5 * time.Second
+	scheduledStartBatchSize = 100
+)
+
+// ProductEdit holds the fields of a listing to change, nil fields are left alone.
+type ProductEdit struct {
+	Title        *string
+	Description  *string
+	Images       []string
+	MinPrice     *int64
+	CurrentPrice *int64
+	EndsAt       *time.Time
+	Category     *string
+	BuyNowPrice  *int64
+}
+
+// descriptionOnly reports whether the edit changes nothing but the description.
+func (e ProductEdit) descriptionOnly() bool {
+	return e.Title == nil && e.Images == nil && e.MinPrice == nil && e.CurrentPrice == nil &&
+		e.EndsAt == nil && e.Category == nil && e.BuyNowPrice == nil
+}
+
+func (e ProductEdit) apply(p db.Product) db.Product {
+	if e.Title != nil {
+		p.Title = *e.Title
+	}
+	if e.Description != nil {
+		p.Description = e.Description
+	}
+	if e.Images != nil {
+		p.Images = e.Images
+	}
+	if e.MinPrice != nil {
+		p.MinPrice = *e.MinPrice
+	}
+	if e.CurrentPrice != nil {
+		p.CurrentPrice = *e.CurrentPrice
+	}
+	if e.EndsAt != nil {
+		p.EndsAt = *e.EndsAt
+	}
+	if e.Category != nil {
+		p.Category = e.Category
+	}
+	if e.BuyNowPrice != nil {
+		p.BuyNowPrice = e.BuyNowPrice
+	}
+	return p
+}
+
+// checkTransition returns ErrInvalidStatusTransition unless the product may move to status.
+func checkTransition(product db.Product, status string) error {
+	if !slices.Contains(statusTransitions[product.Status], status) {
+		return ErrInvalidStatusTransition
+	}
+	return nil
+}
+
+// ownedProductForUpdate locks a product managed by ownerID.
+func ownedProductForUpdate(ctx context.Context, q db.Querier, ownerID uuid.UUID, productId string) (db.Product, error) {
+	productUUID, err := uuid.Parse(productId)
+	if err != nil {
+		return db.Product{}, ErrProductNotFound
+	}
+	product, err := q.GetProductForUpdate(ctx, productUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Product{}, ErrProductNotFound
+		}
+		return db.Product{}, err
+	}
+	if product.SellerID != ownerID {
+		return db.Product{}, ErrNotProductOwner
+	}
+	return product, nil
+}
+
+// EditProduct changes a listing. Drafts can be changed freely and are validated like a new listing,
+// scheduled and live listings only accept a new description.
+func (ps *ProductService) EditProduct(ctx context.Context, ownerID uuid.UUID, productId string, edit ProductEdit) (db.Product, error) {
+	var updated db.Product
+	err := ps.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := ownedProductForUpdate(ctx, q, ownerID, productId)
+		if err != nil {
+			return err
+		}
+		arg := db.UpdateProductListingParams{
+			ID:              product.ID,
+			Title:           product.Title,
+			Description:     product.Description,
+			Images:          product.Images,
+			MinPrice:        product.MinPrice,
+			CurrentPrice:    product.CurrentPrice,
+			StartPrice:      product.StartPrice,
+			EndsAt:          product.EndsAt,
+			Category:        product.Category,
+			BuyNowPrice:     product.BuyNowPrice,
+			NextPriceDropAt: product.NextPriceDropAt,
+		}
+		switch {
+		case product.Status == StatusDraft:
+			listing, err := listingParams(edit.apply(product), time.Now().UTC())
+			if err != nil {
+				return err
+			}
+			arg.Title = listing.Title
+			arg.Description = listing.Description
+			arg.Images = listing.Images
+			arg.MinPrice = listing.MinPrice
+			arg.CurrentPrice = listing.CurrentPrice
+			arg.StartPrice = listing.StartPrice
+			arg.EndsAt = listing.EndsAt
+			arg.Category = listing.Category
+			arg.BuyNowPrice = listing.BuyNowPrice
+			arg.NextPriceDropAt = listing.NextPriceDropAt
+		case (product.Status == StatusScheduled || product.Status == StatusLive) && edit.descriptionOnly():
+			arg.Description = edit.Description
+		default:
+			return ErrProductNotEditable
+		}
+		updated, err = q.UpdateProductListing(ctx, arg)
+		return err
+	})
+	if err != nil {
+		return db.Product{}, err
+	}
+	return updated, nil
+}
+
+// PublishProduct puts a draft or scheduled listing live right away. endsAt replaces the stored end when given.
+func (ps *ProductService) PublishProduct(ctx context.Context, ownerID uuid.UUID, productId string, endsAt *time.Time) (db.Product, error) {
+	var started db.Product
+	err := ps.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := ownedProductForUpdate(ctx, q, ownerID, productId)
+		if err != nil {
+			return err
+		}
+		if err := checkTransition(product, StatusLive); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if endsAt != nil {
+			product.EndsAt = endsAt.UTC()
+		}
+		if err := validateAuctionWindow(now, product.EndsAt); err != nil {
+			return err
+		}
+		started, err = startListing(ctx, q, product, now)
+		return err
+	})
+	if err != nil {
+		return db.Product{}, err
+	}
+	return started, nil
+}
+
+// ScheduleProduct schedules a draft, or reschedules a scheduled listing, to go live at startsAt.
+// endsAt replaces the stored end when given.
+func (ps *ProductService) ScheduleProduct(ctx context.Context, ownerID uuid.UUID, productId string, startsAt time.Time, endsAt *time.Time) (db.Product, error) {
+	var scheduled db.Product
+	err := ps.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := ownedProductForUpdate(ctx, q, ownerID, productId)
+		if err != nil {
+			return err
+		}
+		if err := checkTransition(product, StatusScheduled); err != nil {
+			return err
+		}
+		startsAt = startsAt.UTC()
+		if !startsAt.After(time.Now().UTC()) {
+			return ErrInvalidStartsAt
+		}
+		if endsAt != nil {
+			product.EndsAt = endsAt.UTC()
+		}
+		if err := validateAuctionWindow(startsAt, product.EndsAt); err != nil {
+			return err
+		}
+		scheduled, err = q.ScheduleProduct(ctx, db.ScheduleProductParams{
+			ID:       product.ID,
+			StartsAt: &startsAt,
+			EndsAt:   product.EndsAt,
+		})
+		return err
+	})
+	if err != nil {
+		return db.Product{}, err
+	}
+	return scheduled, nil
+}
+
+// RelistProduct starts a new live auction for an ended, unsold listing with its images and settings,
+// including its own increment ladder, its shipping options and the invitations of a private listing,
+// at its original starting price. The new auction runs as long as the old one did unless endsAt is given.
+// The old listing moves to relisted in the same transaction.
+func (ps *ProductService) RelistProduct(ctx context.Context, ownerID uuid.UUID, productId string, endsAt *time.Time) (db.Product, error) {
+	var relisted db.Product
+	err := ps.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := ownedProductForUpdate(ctx, q, ownerID, productId)
+		if err != nil {
+			return err
+		}
+		if err := checkTransition(product, StatusRelisted); err != nil {
+			return err
+		}
+		if product.SoldAt != nil {
+			return ErrRelistSold
+		}
+
+		now := time.Now().UTC()
+		p := product
+		p.CurrentPrice = product.StartPrice
+		p.RelistedFrom = &product.ID
+		p.EndsAt = now.Add(min(product.EndsAt.Sub(listingStart(product)), maxAuctionDuration))
+		if endsAt != nil {
+			p.EndsAt = endsAt.UTC()
+		}
+		arg, err := listingParams(p, now)
+		if err != nil {
+			return err
+		}
+		arg.Status = StatusLive
+		arg.StartsAt = &now
+
+		rules, err := incrementRulesFor(ctx, q, product)
+		if err != nil {
+			return err
+		}
+		var increments []IncrementStep
+		for _, rule := range rules {
+			if rule.Scope == IncrementScopeProduct {
+				increments = append(increments, IncrementStep{MinPrice: rule.MinPrice, Increment: rule.Increment})
+			}
+		}
+		shipping, err := shippingOptionsOf(ctx, q, product.ID)
+		if err != nil {
+			return err
+		}
+		relisted, err = insertListing(ctx, q, arg, increments, shipping)
+		if err != nil {
+			return err
+		}
+		if err := copyPrivateAccess(ctx, q, product, relisted); err != nil {
+			return err
+		}
+		if err := q.MarkProductRelisted(ctx, product.ID); err != nil {
+			return err
+		}
+		return emitEvent(ctx, q, events.AuctionStarted, relisted.ID, events.AuctionStartedData{
+			ProductID:    relisted.ID,
+			SellerID:     relisted.SellerID,
+			StartsAt:     now,
+			EndsAt:       relisted.EndsAt,
+			RelistedFrom: &product.ID,
+		})
+	})
+	if err != nil {
+		return db.Product{}, err
+	}
+	return relisted, nil
+}
+
+// listingStart is when the listing went live, listings from before the lifecycle only have created_at.
+func listingStart(product db.Product) time.Time {
+	if product.StartsAt != nil {
+		return *product.StartsAt
+	}
+	return product.CreatedAt
+}
+
+// startListing puts the locked product live at now. Dutch auctions restart their drop schedule from now.
+func startListing(ctx context.Context, q db.Querier, product db.Product, now time.Time) (db.Product, error) {
+	var nextDrop *time.Time
+	if product.AuctionType == AuctionTypeDutch && product.DutchIntervalSeconds != nil {
+		next := now.Add(time.Duration(*product.DutchIntervalSeconds) * time.Second)
+		nextDrop = &next
+	}
+	started, err := q.StartProduct(ctx, db.StartProductParams{
+		ID:              product.ID,
+		StartsAt:        &now,
+		EndsAt:          product.EndsAt,
+		NextPriceDropAt: nextDrop,
+	})
+	if err != nil {
+		return db.Product{}, err
+	}
+	err = emitEvent(ctx, q, events.AuctionStarted, product.ID, events.AuctionStartedData{
+		ProductID: product.ID,
+		SellerID:  product.SellerID,
+		StartsAt:  now,
+		EndsAt:    started.EndsAt,
+	})
+	if err != nil {
+		return db.Product{}, err
+	}
+	return started, nil
+}
+
+// StartScheduledProducts puts every scheduled listing whose start is due live and returns how many were started.
+func (ps *ProductService) StartScheduledProducts(ctx context.Context) (int, error) {
+	dueBefore := time.Now().UTC()
+	return processDue(scheduledStartBatchSize, func(skip []uuid.UUID, pageLimit int32) ([]db.Product, error) {
+		return ps.db.GetDueScheduledProducts(ctx, db.GetDueScheduledProductsParams{
+			DueBefore: dueBefore,
+			SkipIds:   skip,
+			PageLimit: pageLimit,
+		})
+	}, func(product db.Product) uuid.UUID {
+		return product.ID
+	}, func(product db.Product) error {
+		if err := ps.startScheduledProduct(ctx, product.ID); err != nil {
+			return fmt.Errorf("failed to start product %s: %w", product.ID, err)
+		}
+		return nil
+	})
+}
+
+// startScheduledProduct starts one listing under the product row lock, a listing that was published
+// or rescheduled in the meantime is left alone.
+func (ps *ProductService) startScheduledProduct(ctx context.Context, productID uuid.UUID) error {
+	return ps.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := q.GetProductForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if product.Status != StatusScheduled || product.StartsAt == nil || now.Before(*product.StartsAt) {
+			return nil
+		}
+		_, err = startListing(ctx, q, product, now)
+		return err
+	})
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/jobs"
+)
+
+// JobMaintenanceCleanup prunes delivered outbox rows and finished jobs.
+const JobMaintenanceCleanup = "maintenance.cleanup"
+
+const (
+	maintenanceInterval  = time.Hour
+	outboxRetention      = 7 * 24 * time.Hour
+	finishedJobRetention = 7 * 24 * time.Hour
+)
+
+func registerMaintenanceJobs(store db.Store, queue *jobs.Queue) {
+	queue.Register(JobMaintenanceCleanup, func(ctx context.Context, job jobs.Job) error {
+		outbox, err := store.DeleteSentOutboxEvents(ctx, time.Now().Add(-outboxRetention))
+		if err != nil {
+			return err
+		}
+		finished, err := store.DeleteFinishedJobs(ctx, time.Now().Add(-finishedJobRetention))
+		if err != nil {
+			return err
+		}
+		slog.Info("[Maintenance] cleanup done", "outbox_rows", outbox, "jobs", finished)
+		return nil
+	}, jobs.Options{MaxAttempts: 3})
+	queue.Every(JobMaintenanceCleanup, maintenanceInterval)
+}
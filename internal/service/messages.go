@@ -0,0 +1,508 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/itsDrac/e-auc/internal/storage"
+	"github.com/itsDrac/e-auc/pkg/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// Message statuses, mirrored by the CHECK constraint on messages.status.
+const (
+	MessageVisible = "visible"
+	MessageHeld    = "held"
+	MessageRemoved = "removed"
+)
+
+const (
+	messageAttachmentBucket = "message-attachments"
+	// Messages a user may send across all of their conversations within messageRateWindow
+	defaultMessageRateLimit = 20
+	messageRateWindow       = time.Minute
+)
+
+// Flags set on messages that look like an attempt to take the deal off the platform. Flagged messages are
+// held back from the recipient until an admin approves them.
+const (
+	FlagEmail              = "email"
+	FlagPhone              = "phone"
+	FlagLink               = "link"
+	FlagMessagingApp       = "messaging_app"
+	FlagOffPlatformPayment = "off_platform_payment"
+)
+
+var messageScreens = []struct {
+	flag    string
+	pattern *regexp.Regexp
+}{
+	{FlagEmail, regexp.MustCompile(`(?i)[a-z0-9._%+\-]+\s*(@|\(at\)|\[at\])\s*[a-z0-9\-]+\s*(\.|\(dot\)|\[dot\])\s*[a-z]{2,}`)},
+	{FlagPhone, regexp.MustCompile(`\+?\d(?:[\s\-.()]*\d){8,}`)},
+	{FlagLink, regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)},
+	{FlagMessagingApp, regexp.MustCompile(`(?i)\b(?:whats\s?app|telegram|wechat|viber|signal me)\b`)},
+	{FlagOffPlatformPayment, regexp.MustCompile(`(?i)\b(?:western union|moneygram|wire transfer|gift\s?cards?|bitcoin|btc|crypto|usdt|friends (?:and|&) family|zelle|cash\s?app|venmo|pay (?:me )?(?:outside|directly|off (?:the )?(?:site|platform)))\b`)},
+}
+
+// ConversationMessage is a message with a link to its attachment, if it has one.
+type ConversationMessage struct {
+	db.Message
+	AttachmentURL *string `json:"attachment_url"`
+}
+
+type MessageServicer interface {
+	StartProductConversation(ctx context.Context, userID uuid.UUID, productId string) (db.Conversation, bool, error)
+	StartOrderConversation(ctx context.Context, userID uuid.UUID, orderId string) (db.Conversation, bool, error)
+	GetConversations(ctx context.Context, userID uuid.UUID, limit uint, offset uint) ([]db.GetConversationsByUserIDRow, int64, error)
+	GetConversation(ctx context.Context, userID uuid.UUID, conversationId string) (db.Conversation, error)
+	GetMessages(ctx context.Context, userID uuid.UUID, conversationId string, limit uint, offset uint) ([]ConversationMessage, error)
+	SendMessage(ctx context.Context, senderID uuid.UUID, conversationId string, body string, attachmentKey *string) (ConversationMessage, error)
+	UploadAttachment(ctx context.Context, userID uuid.UUID, conversationId string, filename string, data []byte) (string, string, error)
+	MarkRead(ctx context.Context, userID uuid.UUID, conversationId string) (db.Conversation, error)
+	GetHeldMessages(ctx context.Context, limit uint, offset uint) ([]ConversationMessage, error)
+	ApproveMessage(ctx context.Context, adminID uuid.UUID, messageId string) (db.Message, error)
+	RemoveMessage(ctx context.Context, adminID uuid.UUID, messageId string) (db.Message, error)
+}
+
+type MessageService struct {
+	db        db.Store
+	storage   storage.Storager
+	rateLimit int64
+}
+
+func NewMessageService(db db.Store, s storage.Storager) (*MessageService, error) {
+	return &MessageService{
+		db:        db,
+		storage:   s,
+		rateLimit: int64(utils.GetIntEnv("MESSAGE_RATE_LIMIT_PER_MINUTE", defaultMessageRateLimit)),
+	}, nil
+}
+
+// StartProductConversation returns the thread between the user and the seller about a listing the user can see,
+// creating it on first contact. The bool reports whether it was created.
+func (ms *MessageService) StartProductConversation(ctx context.Context, userID uuid.UUID, productId string) (db.Conversation, bool, error) {
+	productUUID, err := uuid.Parse(productId)
+	if err != nil {
+		return db.Conversation{}, false, ErrProductNotFound
+	}
+
+	var conversation db.Conversation
+	var created bool
+	err = ms.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := q.GetProductByID(ctx, productUUID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		if product.SellerID == userID {
+			return ErrSelfMessaging
+		}
+		visible, err := canViewProduct(ctx, q, product, userID)
+		if err != nil {
+			return err
+		}
+		if !visible {
+			return ErrProductNotFound
+		}
+		if err := checkMessagingBlocked(ctx, q, product.SellerID, userID); err != nil {
+			return err
+		}
+
+		conversation, created, err = createConversation(ctx, q, db.CreateConversationParams{
+			ProductID: product.ID,
+			SellerID:  product.SellerID,
+			BuyerID:   userID,
+		}, func() (db.Conversation, error) {
+			return q.GetProductConversation(ctx, db.GetProductConversationParams{ProductID: product.ID, BuyerID: userID})
+		})
+		return err
+	})
+	if err != nil {
+		return db.Conversation{}, false, err
+	}
+	return conversation, created, nil
+}
+
+// StartOrderConversation returns the thread between the buyer and the seller of an order, creating it on first contact.
+// The bool reports whether it was created.
+func (ms *MessageService) StartOrderConversation(ctx context.Context, userID uuid.UUID, orderId string) (db.Conversation, bool, error) {
+	orderUUID, err := uuid.Parse(orderId)
+	if err != nil {
+		return db.Conversation{}, false, ErrOrderNotFound
+	}
+
+	var conversation db.Conversation
+	var created bool
+	err = ms.db.ExecTx(ctx, func(q db.Querier) error {
+		order, err := q.GetOrderByID(ctx, orderUUID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrOrderNotFound
+			}
+			return err
+		}
+		if order.BuyerID != userID && order.SellerID != userID {
+			return ErrOrderNotFound
+		}
+
+		conversation, created, err = createConversation(ctx, q, db.CreateConversationParams{
+			ProductID: order.ProductID,
+			OrderID:   &order.ID,
+			SellerID:  order.SellerID,
+			BuyerID:   order.BuyerID,
+		}, func() (db.Conversation, error) {
+			return q.GetOrderConversation(ctx, &order.ID)
+		})
+		return err
+	})
+	if err != nil {
+		return db.Conversation{}, false, err
+	}
+	return conversation, created, nil
+}
+
+// GetConversations returns the threads of the user, most recently active first, with their unread counts,
+// and the number of messages the user has not read across all of their threads.
+func (ms *MessageService) GetConversations(ctx context.Context, userID uuid.UUID, limit uint, offset uint) ([]db.GetConversationsByUserIDRow, int64, error) {
+	conversations, err := ms.db.GetConversationsByUserID(ctx, db.GetConversationsByUserIDParams{
+		UserID: userID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if conversations == nil {
+		conversations = []db.GetConversationsByUserIDRow{}
+	}
+	unread, err := ms.db.CountUnreadMessages(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return conversations, unread, nil
+}
+
+// GetConversation returns a thread the user takes part in, other threads are reported as not found.
+func (ms *MessageService) GetConversation(ctx context.Context, userID uuid.UUID, conversationId string) (db.Conversation, error) {
+	return conversationOf(ctx, ms.db, userID, conversationId)
+}
+
+// GetMessages returns the messages of a thread the user takes part in, newest first. Held messages are only
+// shown to their sender and removed ones to nobody.
+func (ms *MessageService) GetMessages(ctx context.Context, userID uuid.UUID, conversationId string, limit uint, offset uint) ([]ConversationMessage, error) {
+	conversation, err := conversationOf(ctx, ms.db, userID, conversationId)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := ms.db.GetMessagesByConversationID(ctx, db.GetMessagesByConversationIDParams{
+		ConversationID: conversation.ID,
+		ViewerID:       userID,
+		Limit:          int32(limit),
+		Offset:         int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ms.withAttachmentURLs(messages)
+}
+
+// SendMessage posts a message to a thread the user takes part in. attachmentKey must come from UploadAttachment
+// on the same thread. Senders are limited to MESSAGE_RATE_LIMIT_PER_MINUTE (default 20) messages a minute, and
+// messages that look like contact details or off-platform payment are held for review instead of delivered.
+// The sender's row is locked so concurrent sends count against the limit one after the other.
+func (ms *MessageService) SendMessage(ctx context.Context, senderID uuid.UUID, conversationId string, body string, attachmentKey *string) (ConversationMessage, error) {
+	body = strings.TrimSpace(body)
+	if body == "" && attachmentKey == nil {
+		return ConversationMessage{}, ErrEmptyMessage
+	}
+
+	var message db.Message
+	err := ms.db.ExecTx(ctx, func(q db.Querier) error {
+		conversation, err := conversationOf(ctx, q, senderID, conversationId)
+		if err != nil {
+			return err
+		}
+		if attachmentKey != nil && !strings.HasPrefix(*attachmentKey, attachmentPrefix(conversation.ID)) {
+			return ErrInvalidAttachment
+		}
+		// Order threads stay open so the parties can always sort out the sale
+		if conversation.OrderID == nil && conversation.BuyerID == senderID {
+			if err := checkMessagingBlocked(ctx, q, conversation.SellerID, senderID); err != nil {
+				return err
+			}
+		}
+
+		if _, err := q.GetUserForUpdate(ctx, senderID); err != nil {
+			return err
+		}
+		sent, err := q.CountRecentMessagesBySender(ctx, db.CountRecentMessagesBySenderParams{
+			SenderID: senderID,
+			Since:    time.Now().Add(-messageRateWindow),
+		})
+		if err != nil {
+			return err
+		}
+		if sent >= ms.rateLimit {
+			return ErrMessageRateLimited
+		}
+
+		flags := screenMessage(body)
+		status := MessageVisible
+		if len(flags) > 0 {
+			status = MessageHeld
+		}
+		message, err = q.CreateMessage(ctx, db.CreateMessageParams{
+			ConversationID: conversation.ID,
+			SenderID:       senderID,
+			Body:           body,
+			AttachmentKey:  attachmentKey,
+			Status:         status,
+			Flags:          flags,
+		})
+		if err != nil {
+			return err
+		}
+		if status != MessageVisible {
+			return nil
+		}
+		return deliverMessage(ctx, q, conversation, message)
+	})
+	if err != nil {
+		return ConversationMessage{}, err
+	}
+	if message.Status == MessageHeld {
+		slog.Info("[Messages] message held for review", "message_id", message.ID, "flags", message.Flags)
+	}
+	messages, err := ms.withAttachmentURLs([]db.Message{message})
+	if err != nil {
+		return ConversationMessage{}, err
+	}
+	return messages[0], nil
+}
+
+// UploadAttachment stores an image for a message on a thread the user takes part in and returns its key,
+// to send with the message, and its URL.
+func (ms *MessageService) UploadAttachment(ctx context.Context, userID uuid.UUID, conversationId string, filename string, data []byte) (string, string, error) {
+	conversation, err := conversationOf(ctx, ms.db, userID, conversationId)
+	if err != nil {
+		return "", "", err
+	}
+	info, err := ms.storage.SaveImage(messageAttachmentBucket, attachmentPrefix(conversation.ID)+filename, data)
+	if err != nil {
+		return "", "", err
+	}
+	url, err := ms.storage.GetFileUrl(messageAttachmentBucket, info.Key)
+	if err != nil {
+		return "", "", err
+	}
+	return info.Key, url, nil
+}
+
+// MarkRead marks every message of a thread as read by the user and tells the other party.
+func (ms *MessageService) MarkRead(ctx context.Context, userID uuid.UUID, conversationId string) (db.Conversation, error) {
+	var read db.Conversation
+	err := ms.db.ExecTx(ctx, func(q db.Querier) error {
+		conversation, err := conversationOf(ctx, q, userID, conversationId)
+		if err != nil {
+			return err
+		}
+		read, err = q.MarkConversationRead(ctx, db.MarkConversationReadParams{UserID: userID, ID: conversation.ID})
+		if err != nil {
+			return err
+		}
+		return emitEvent(ctx, q, events.ConversationRead, read.ID, events.ConversationReadData{
+			ConversationID: read.ID,
+			ReaderID:       userID,
+			ReadAt:         time.Now().UTC(),
+		})
+	})
+	if err != nil {
+		return db.Conversation{}, err
+	}
+	return read, nil
+}
+
+// GetHeldMessages returns the messages waiting for review, oldest first.
+func (ms *MessageService) GetHeldMessages(ctx context.Context, limit uint, offset uint) ([]ConversationMessage, error) {
+	messages, err := ms.db.GetHeldMessages(ctx, db.GetHeldMessagesParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ms.withAttachmentURLs(messages)
+}
+
+// ApproveMessage delivers a held message to its recipient.
+func (ms *MessageService) ApproveMessage(ctx context.Context, adminID uuid.UUID, messageId string) (db.Message, error) {
+	var approved db.Message
+	err := ms.db.ExecTx(ctx, func(q db.Querier) error {
+		message, err := messageForUpdate(ctx, q, messageId)
+		if err != nil {
+			return err
+		}
+		if message.Status != MessageHeld {
+			return ErrMessageNotHeld
+		}
+		approved, err = q.ModerateMessage(ctx, db.ModerateMessageParams{Status: MessageVisible, ModeratedBy: &adminID, ID: message.ID})
+		if err != nil {
+			return err
+		}
+		conversation, err := q.GetConversationByID(ctx, approved.ConversationID)
+		if err != nil {
+			return err
+		}
+		return deliverMessage(ctx, q, conversation, approved)
+	})
+	if err != nil {
+		return db.Message{}, err
+	}
+	return approved, nil
+}
+
+// RemoveMessage hides a message from both parties, it is kept for the record.
+func (ms *MessageService) RemoveMessage(ctx context.Context, adminID uuid.UUID, messageId string) (db.Message, error) {
+	var removed db.Message
+	err := ms.db.ExecTx(ctx, func(q db.Querier) error {
+		message, err := messageForUpdate(ctx, q, messageId)
+		if err != nil {
+			return err
+		}
+		if message.Status == MessageRemoved {
+			return ErrMessageAlreadyRemoved
+		}
+		removed, err = q.ModerateMessage(ctx, db.ModerateMessageParams{Status: MessageRemoved, ModeratedBy: &adminID, ID: message.ID})
+		return err
+	})
+	if err != nil {
+		return db.Message{}, err
+	}
+	return removed, nil
+}
+
+// withAttachmentURLs links the attachments of the messages.
+func (ms *MessageService) withAttachmentURLs(messages []db.Message) ([]ConversationMessage, error) {
+	result := make([]ConversationMessage, 0, len(messages))
+	for _, message := range messages {
+		withURL := ConversationMessage{Message: message}
+		if message.AttachmentKey != nil {
+			url, err := ms.storage.GetFileUrl(messageAttachmentBucket, *message.AttachmentKey)
+			if err != nil {
+				return nil, err
+			}
+			withURL.AttachmentURL = &url
+		}
+		result = append(result, withURL)
+	}
+	return result, nil
+}
+
+// createConversation creates a thread unless it exists already, in which case existing reads it.
+func createConversation(ctx context.Context, q db.Querier, arg db.CreateConversationParams, existing func() (db.Conversation, error)) (db.Conversation, bool, error) {
+	conversation, err := q.CreateConversation(ctx, arg)
+	if err == nil {
+		return conversation, true, nil
+	}
+	if err != pgx.ErrNoRows {
+		return db.Conversation{}, false, err
+	}
+	conversation, err = existing()
+	if err != nil {
+		return db.Conversation{}, false, err
+	}
+	return conversation, false, nil
+}
+
+// conversationOf returns a thread the user takes part in, other threads are reported as not found.
+func conversationOf(ctx context.Context, q db.Querier, userID uuid.UUID, conversationId string) (db.Conversation, error) {
+	conversationUUID, err := uuid.Parse(conversationId)
+	if err != nil {
+		return db.Conversation{}, ErrConversationNotFound
+	}
+	conversation, err := q.GetConversationByID(ctx, conversationUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Conversation{}, ErrConversationNotFound
+		}
+		return db.Conversation{}, err
+	}
+	if conversation.BuyerID != userID && conversation.SellerID != userID {
+		return db.Conversation{}, ErrConversationNotFound
+	}
+	return conversation, nil
+}
+
+func messageForUpdate(ctx context.Context, q db.Querier, messageId string) (db.Message, error) {
+	messageUUID, err := uuid.Parse(messageId)
+	if err != nil {
+		return db.Message{}, ErrMessageNotFound
+	}
+	message, err := q.GetMessageForUpdate(ctx, messageUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Message{}, ErrMessageNotFound
+		}
+		return db.Message{}, err
+	}
+	return message, nil
+}
+
+// checkMessagingBlocked returns ErrMessagingBlocked when the seller blocked the user, who then cannot ask
+// about their listings either.
+func checkMessagingBlocked(ctx context.Context, q db.Querier, sellerID uuid.UUID, userID uuid.UUID) error {
+	blocked, err := q.IsBidderBlocked(ctx, db.IsBidderBlockedParams{SellerID: sellerID, BidderID: userID})
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrMessagingBlocked
+	}
+	return nil
+}
+
+// deliverMessage bumps the thread and emits the message on it, which reaches the live feed of the conversation.
+func deliverMessage(ctx context.Context, q db.Querier, conversation db.Conversation, message db.Message) error {
+	if err := q.TouchConversation(ctx, conversation.ID); err != nil {
+		return err
+	}
+	recipientID := conversation.SellerID
+	if message.SenderID == conversation.SellerID {
+		recipientID = conversation.BuyerID
+	}
+	return emitEvent(ctx, q, events.MessageSent, conversation.ID, events.MessageSentData{
+		MessageID:      message.ID,
+		ConversationID: conversation.ID,
+		ProductID:      conversation.ProductID,
+		OrderID:        conversation.OrderID,
+		SenderID:       message.SenderID,
+		RecipientID:    recipientID,
+		Body:           message.Body,
+		AttachmentKey:  message.AttachmentKey,
+		SentAt:         message.CreatedAt.UTC(),
+	})
+}
+
+// screenMessage returns the flags of the patterns the body matches, none for a clean message.
+func screenMessage(body string) []string {
+	flags := []string{}
+	for _, screen := range messageScreens {
+		if screen.pattern.MatchString(body) {
+			flags = append(flags, screen.flag)
+		}
+	}
+	return flags
+}
+
+// attachmentPrefix is the key prefix of the attachments of a conversation.
+func attachmentPrefix(conversationID uuid.UUID) string {
+	return "messages/" + conversationID.String() + "/"
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/itsDrac/e-auc/internal/jobs"
+	"github.com/jackc/pgx/v5"
+)
+
+// Statuses of an offer, mirrored by the CHECK constraint on offers.status.
+// Only pending offers can be answered, every other status is final.
+const (
+	OfferPending   = "pending"
+	OfferAccepted  = "accepted"
+	OfferDeclined  = "declined"
+	OfferCountered = "countered"
+	OfferExpired   = "expired"
+)
+
+// CloseReasonOffer is sent with the auction.closed event of a sale through an accepted offer.
+const CloseReasonOffer = "offer_accepted"
+
+// JobOfferExpiry expires offers and counter-offers that were not answered in time.
+const JobOfferExpiry = "offers.expiry"
+
+const (
+	offerWindow         = 48 * time.Hour
+	offerExpiryInterval = 30 * time.Second
+	offerExpiryBatch    = 100
+)
+
+type OfferServicer interface {
+	MakeOffer(ctx context.Context, buyerID uuid.UUID, productId string, price int64) (db.Offer, error)
+	CounterOffer(ctx context.Context, userID uuid.UUID, offerId string, price int64) (db.Offer, error)
+	AcceptOffer(ctx context.Context, userID uuid.UUID, offerId string) (db.Product, error)
+	DeclineOffer(ctx context.Context, userID uuid.UUID, offerId string) (db.Offer, error)
+	GetProductOffers(ctx context.Context, userID uuid.UUID, productId string) ([]db.Offer, error)
+	GetOffers(ctx context.Context, userID uuid.UUID, role string, limit uint, offset uint) ([]db.Offer, error)
+	ExpireOffers(ctx context.Context) (int, error)
+}
+
+type OfferService struct {
+	db db.Store
+}
+
+func NewOfferService(db db.Store) (*OfferService, error) {
+	return &OfferService{
+		db: db,
+	}, nil
+}
+
+func registerOfferJobs(ofs *OfferService, queue *jobs.Queue) {
+	queue.Register(JobOfferExpiry, func(ctx context.Context, job jobs.Job) error {
+		_, err := ofs.ExpireOffers(ctx)
+		return err
+	}, jobs.Options{MaxAttempts: 3})
+	queue.Every(JobOfferExpiry, offerExpiryInterval)
+}
+
+// offerRecipient returns the party who answers the offer.
+func offerRecipient(offer db.Offer) uuid.UUID {
+	if offer.MadeBy == RoleBuyer {
+		return offer.SellerID
+	}
+	return offer.BuyerID
+}
+
+// checkOfferPrice checks the price of an offer made by role. Buyers offer below the asking price,
+// sellers may counter up to it.
+func checkOfferPrice(product db.Product, role string, price int64) error {
+	if price <= 0 || price > product.CurrentPrice || (role == RoleBuyer && price == product.CurrentPrice) {
+		return ErrInvalidOfferPrice
+	}
+	return nil
+}
+
+// MakeOffer opens a negotiation on a live fixed-price listing with an offer from the buyer.
+// The seller's thresholds may accept or decline the offer right away, the returned offer shows which.
+func (ofs *OfferService) MakeOffer(ctx context.Context, buyerID uuid.UUID, productId string, price int64) (db.Offer, error) {
+	productUUID, err := uuid.Parse(productId)
+	if err != nil {
+		return db.Offer{}, ErrProductNotFound
+	}
+
+	var offer db.Offer
+	err = ofs.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := q.GetProductForUpdate(ctx, productUUID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		if product.AuctionType != AuctionTypeFixedPrice {
+			return ErrOffersNotSupported
+		}
+		if product.SellerID == buyerID {
+			return ErrSelfBuying
+		}
+		if err := checkBuyerAccess(ctx, q, product, buyerID); err != nil {
+			return err
+		}
+		if err := checkProductOpen(product, time.Now().UTC()); err != nil {
+			return err
+		}
+		if err := checkOfferPrice(product, RoleBuyer, price); err != nil {
+			return err
+		}
+		_, err = q.GetPendingOfferForBuyer(ctx, db.GetPendingOfferForBuyerParams{ProductID: product.ID, BuyerID: buyerID})
+		if err == nil {
+			return ErrOfferAlreadyOpen
+		}
+		if err != pgx.ErrNoRows {
+			return err
+		}
+		offer, err = placeOffer(ctx, q, product, buyerID, nil, RoleBuyer, price)
+		return err
+	})
+	if err != nil {
+		return db.Offer{}, err
+	}
+	return offer, nil
+}
+
+// placeOffer stores a pending offer open for 48 hours and emits OfferMade on the offer.
+// Offers from the buyer then go through the seller's auto-accept and auto-decline thresholds.
+func placeOffer(ctx context.Context, q db.Querier, product db.Product, buyerID uuid.UUID, parentID *uuid.UUID, madeBy string, price int64) (db.Offer, error) {
+	offer, err := q.CreateOffer(ctx, db.CreateOfferParams{
+		ProductID: product.ID,
+		BuyerID:   buyerID,
+		SellerID:  product.SellerID,
+		ParentID:  parentID,
+		MadeBy:    madeBy,
+		Price:     price,
+		ExpiresAt: time.Now().UTC().Add(offerWindow),
+	})
+	if err != nil {
+		return db.Offer{}, err
+	}
+	err = emitEvent(ctx, q, events.OfferMade, offer.ID, events.OfferMadeData{
+		OfferID:   offer.ID,
+		ProductID: product.ID,
+		SellerID:  product.SellerID,
+		BuyerID:   buyerID,
+		ParentID:  parentID,
+		MadeBy:    madeBy,
+		Price:     price,
+		ExpiresAt: offer.ExpiresAt,
+	})
+	if err != nil {
+		return db.Offer{}, err
+	}
+	if madeBy != RoleBuyer {
+		return offer, nil
+	}
+
+	switch {
+	case product.AutoAcceptPrice != nil && price >= *product.AutoAcceptPrice:
+		accepted, _, err := acceptOffer(ctx, q, product, offer)
+		return accepted, err
+	case product.AutoDeclinePrice != nil && price < *product.AutoDeclinePrice:
+		return answerOffer(ctx, q, offer, OfferDeclined)
+	}
+	return offer, nil
+}
+
+// answerOffer closes a pending offer with status and emits OfferAnswered on the offer.
+func answerOffer(ctx context.Context, q db.Querier, offer db.Offer, status string) (db.Offer, error) {
+	answered, err := q.RespondToOffer(ctx, db.RespondToOfferParams{ID: offer.ID, Status: status})
+	if err != nil {
+		return db.Offer{}, err
+	}
+	err = emitEvent(ctx, q, events.OfferAnswered, offer.ID, events.OfferAnsweredData{
+		OfferID:   offer.ID,
+		ProductID: offer.ProductID,
+		SellerID:  offer.SellerID,
+		BuyerID:   offer.BuyerID,
+		Price:     offer.Price,
+		Status:    status,
+	})
+	if err != nil {
+		return db.Offer{}, err
+	}
+	return answered, nil
+}
+
+// acceptOffer sells the product to the buyer of the offer at its price through the same path as settlement.
+func acceptOffer(ctx context.Context, q db.Querier, product db.Product, offer db.Offer) (db.Offer, db.Product, error) {
+	accepted, err := answerOffer(ctx, q, offer, OfferAccepted)
+	if err != nil {
+		return db.Offer{}, db.Product{}, err
+	}
+	sold, err := sellProduct(ctx, q, product, []allocation{{WinnerID: offer.BuyerID, Quantity: 1, UnitPrice: offer.Price}}, CloseReasonOffer)
+	if err != nil {
+		return db.Offer{}, db.Product{}, err
+	}
+	return accepted, sold, nil
+}
+
+// CounterOffer answers an offer with a new price. The offer moves to countered and the counter-offer
+// waits for the other party, who can accept, decline or counter it in turn.
+func (ofs *OfferService) CounterOffer(ctx context.Context, userID uuid.UUID, offerId string, price int64) (db.Offer, error) {
+	var counter db.Offer
+	err := ofs.respond(ctx, userID, offerId, func(q db.Querier, product db.Product, offer db.Offer) error {
+		role := RoleSeller
+		if userID == offer.BuyerID {
+			role = RoleBuyer
+		}
+		if err := checkOfferPrice(product, role, price); err != nil {
+			return err
+		}
+		if _, err := answerOffer(ctx, q, offer, OfferCountered); err != nil {
+			return err
+		}
+		var err error
+		counter, err = placeOffer(ctx, q, product, offer.BuyerID, &offer.ID, role, price)
+		return err
+	})
+	if err != nil {
+		return db.Offer{}, err
+	}
+	return counter, nil
+}
+
+// AcceptOffer accepts an offer or counter-offer and sells the product at its price.
+func (ofs *OfferService) AcceptOffer(ctx context.Context, userID uuid.UUID, offerId string) (db.Product, error) {
+	var sold db.Product
+	err := ofs.respond(ctx, userID, offerId, func(q db.Querier, product db.Product, offer db.Offer) error {
+		var err error
+		_, sold, err = acceptOffer(ctx, q, product, offer)
+		return err
+	})
+	if err != nil {
+		return db.Product{}, err
+	}
+	return sold, nil
+}
+
+// DeclineOffer turns an offer or counter-offer down, which ends the negotiation.
+func (ofs *OfferService) DeclineOffer(ctx context.Context, userID uuid.UUID, offerId string) (db.Offer, error) {
+	var declined db.Offer
+	err := ofs.respond(ctx, userID, offerId, func(q db.Querier, product db.Product, offer db.Offer) error {
+		var err error
+		declined, err = answerOffer(ctx, q, offer, OfferDeclined)
+		return err
+	})
+	if err != nil {
+		return db.Offer{}, err
+	}
+	return declined, nil
+}
+
+// respond locks the product and then the offer, checks that the user is the party who answers it,
+// that it is still open and that the product can still be sold, and runs fn.
+// Offers the user is no party of are reported as not found.
+func (ofs *OfferService) respond(ctx context.Context, userID uuid.UUID, offerId string, fn func(q db.Querier, product db.Product, offer db.Offer) error) error {
+	offerUUID, err := uuid.Parse(offerId)
+	if err != nil {
+		return ErrOfferNotFound
+	}
+	existing, err := ofs.db.GetOfferByID(ctx, offerUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrOfferNotFound
+		}
+		return err
+	}
+	if existing.BuyerID != userID && existing.SellerID != userID {
+		return ErrOfferNotFound
+	}
+	if offerRecipient(existing) != userID {
+		return ErrOwnOffer
+	}
+	return ofs.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := q.GetProductForUpdate(ctx, existing.ProductID)
+		if err != nil {
+			return err
+		}
+		offer, err := q.GetOfferForUpdate(ctx, offerUUID)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if offer.Status != OfferPending || !now.Before(offer.ExpiresAt) {
+			return ErrOfferNotPending
+		}
+		if err := checkProductOpen(product, now); err != nil {
+			return err
+		}
+		return fn(q, product, offer)
+	})
+}
+
+// GetProductOffers returns the offers on a product, oldest first. The seller sees every negotiation,
+// anyone else only their own.
+func (ofs *OfferService) GetProductOffers(ctx context.Context, userID uuid.UUID, productId string) ([]db.Offer, error) {
+	productUUID, err := uuid.Parse(productId)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	product, err := ofs.db.GetProductByID(ctx, productUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	canView, err := canViewProduct(ctx, ofs.db, product, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrProductNotFound
+	}
+	offers, err := ofs.db.GetOffersByProductID(ctx, productUUID)
+	if err != nil {
+		return nil, err
+	}
+	visible := []db.Offer{}
+	for _, offer := range offers {
+		if product.SellerID == userID || offer.BuyerID == userID {
+			visible = append(visible, offer)
+		}
+	}
+	return visible, nil
+}
+
+// GetOffers returns the offers the user is negotiating as buyer (RoleBuyer) or seller (RoleSeller), newest first.
+func (ofs *OfferService) GetOffers(ctx context.Context, userID uuid.UUID, role string, limit uint, offset uint) ([]db.Offer, error) {
+	var offers []db.Offer
+	var err error
+	switch role {
+	case RoleBuyer:
+		offers, err = ofs.db.GetOffersByBuyerID(ctx, db.GetOffersByBuyerIDParams{
+			BuyerID: userID,
+			Limit:   int32(limit),
+			Offset:  int32(offset),
+		})
+	case RoleSeller:
+		offers, err = ofs.db.GetOffersBySellerID(ctx, db.GetOffersBySellerIDParams{
+			SellerID: userID,
+			Limit:    int32(limit),
+			Offset:   int32(offset),
+		})
+	default:
+		return nil, ErrInvalidOrderRole
+	}
+	if err != nil {
+		return nil, err
+	}
+	if offers == nil {
+		offers = []db.Offer{}
+	}
+	return offers, nil
+}
+
+// ExpireOffers expires every open offer past its deadline and returns how many were expired.
+func (ofs *OfferService) ExpireOffers(ctx context.Context) (int, error) {
+	expiredBefore := time.Now().UTC()
+	return processDue(offerExpiryBatch, func(skip []uuid.UUID, pageLimit int32) ([]db.Offer, error) {
+		return ofs.db.GetExpiredOffers(ctx, db.GetExpiredOffersParams{
+			ExpiredBefore: expiredBefore,
+			SkipIds:       skip,
+			PageLimit:     pageLimit,
+		})
+	}, func(offer db.Offer) uuid.UUID {
+		return offer.ID
+	}, func(offer db.Offer) error {
+		err := ofs.db.ExecTx(ctx, func(q db.Querier) error {
+			offer, err := q.GetOfferForUpdate(ctx, offer.ID)
+			if err != nil {
+				return err
+			}
+			if offer.Status != OfferPending || time.Now().UTC().Before(offer.ExpiresAt) {
+				return nil
+			}
+			_, err = answerOffer(ctx, q, offer, OfferExpired)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to expire offer %s: %w", offer.ID, err)
+		}
+		return nil
+	})
+}
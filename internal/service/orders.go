@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/jackc/pgx/v5"
+)
+
+type OrderServicer interface {
+	GetOrders(ctx context.Context, userID uuid.UUID, role string, limit uint, offset uint) ([]db.Order, error)
+	GetOrder(ctx context.Context, userID uuid.UUID, orderId string) (db.Order, error)
+	SetOrderShipping(ctx context.Context, buyerID uuid.UUID, orderId string, optionId string, addressId string) (db.Order, error)
+	ShipOrder(ctx context.Context, sellerID uuid.UUID, orderId string, carrier string, trackingNumber string) (db.Order, error)
+	ConfirmDelivery(ctx context.Context, userID uuid.UUID, orderId string) (db.Order, error)
+}
+
+type OrderService struct {
+	db db.Store
+}
+
+func NewOrderService(db db.Store) (*OrderService, error) {
+	return &OrderService{
+		db: db,
+	}, nil
+}
+
+// GetOrders returns the orders the user bought (RoleBuyer) or sold (RoleSeller), newest first.
+func (os *OrderService) GetOrders(ctx context.Context, userID uuid.UUID, role string, limit uint, offset uint) ([]db.Order, error) {
+	var orders []db.Order
+	var err error
+	switch role {
+	case RoleBuyer:
+		orders, err = os.db.GetOrdersByBuyerID(ctx, db.GetOrdersByBuyerIDParams{
+			BuyerID: userID,
+			Limit:   int32(limit),
+			Offset:  int32(offset),
+		})
+	case RoleSeller:
+		orders, err = os.db.GetOrdersBySellerID(ctx, db.GetOrdersBySellerIDParams{
+			SellerID: userID,
+			Limit:    int32(limit),
+			Offset:   int32(offset),
+		})
+	default:
+		return nil, ErrInvalidOrderRole
+	}
+	if err != nil {
+		return nil, err
+	}
+	if orders == nil {
+		orders = []db.Order{}
+	}
+	return orders, nil
+}
+
+// GetOrder returns an order the user is a party of, orders of other users are reported as not found.
+func (os *OrderService) GetOrder(ctx context.Context, userID uuid.UUID, orderId string) (db.Order, error) {
+	orderUUID, err := uuid.Parse(orderId)
+	if err != nil {
+		return db.Order{}, ErrOrderNotFound
+	}
+	order, err := os.db.GetOrderByID(ctx, orderUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Order{}, ErrOrderNotFound
+		}
+		return db.Order{}, err
+	}
+	if order.BuyerID != userID && order.SellerID != userID {
+		return db.Order{}, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// SetOrderShipping lets the buyer choose how an order awaiting shipment ships, and where to unless it is picked up,
+// while its invoice is unpaid. The invoice is repriced with the new shipping cost and paid right away when the
+// deposit applied to it covers everything. The product, invoice and order are locked in the order expireInvoice
+// locks them, so shipping never changes while the invoice expires or is paid.
+func (os *OrderService) SetOrderShipping(ctx context.Context, buyerID uuid.UUID, orderId string, optionId string, addressId string) (db.Order, error) {
+	orderUUID, err := uuid.Parse(orderId)
+	if err != nil {
+		return db.Order{}, ErrOrderNotFound
+	}
+	optionUUID, err := uuid.Parse(optionId)
+	if err != nil {
+		return db.Order{}, ErrShippingOptionNotFound
+	}
+
+	var updated db.Order
+	err = os.db.ExecTx(ctx, func(q db.Querier) error {
+		order, err := q.GetOrderByID(ctx, orderUUID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrOrderNotFound
+			}
+			return err
+		}
+		if order.BuyerID != buyerID {
+			if order.SellerID == buyerID {
+				return ErrNotOrderParty
+			}
+			return ErrOrderNotFound
+		}
+		if _, err := q.GetProductForUpdate(ctx, order.ProductID); err != nil {
+			return err
+		}
+		// Orders from before invoices have nothing left to pay for
+		invoice, err := q.GetInvoiceByOrderID(ctx, order.ID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrShippingLocked
+			}
+			return err
+		}
+		if invoice, err = q.GetInvoiceForUpdate(ctx, invoice.ID); err != nil {
+			return err
+		}
+		if order, err = q.GetOrderForUpdate(ctx, order.ID); err != nil {
+			return err
+		}
+		if order.FulfilmentStatus != FulfilmentAwaitingShipment || invoice.Status != InvoicePending {
+			return ErrShippingLocked
+		}
+
+		option, err := q.GetShippingOptionByID(ctx, optionUUID)
+		if err != nil || option.ProductID != order.ProductID {
+			if err == nil || err == pgx.ErrNoRows {
+				return ErrShippingOptionNotFound
+			}
+			return err
+		}
+		choice := shippingChoice{Method: &option.Method, Cost: option.Cost}
+		if option.Method != ShippingPickup {
+			address, err := buyerAddress(ctx, q, buyerID, addressId)
+			if err != nil {
+				return err
+			}
+			if !delivers(option, &address) {
+				return ErrShippingUnavailable
+			}
+			choice.Address = &address
+		}
+		// The deposit applied to the invoice cannot be handed back
+		if invoice.AmountDue-invoice.Shipping+choice.Cost < 0 {
+			return ErrShippingLocked
+		}
+
+		if updated, err = setOrderShipping(ctx, q, order.ID, choice); err != nil {
+			return err
+		}
+		invoice, err = q.UpdateInvoiceShipping(ctx, db.UpdateInvoiceShippingParams{ID: invoice.ID, Shipping: choice.Cost})
+		if err != nil {
+			return err
+		}
+		if invoice.AmountDue > 0 {
+			return nil
+		}
+		if invoice, err = q.MarkInvoicePaid(ctx, invoice.ID); err != nil {
+			return err
+		}
+		return emitEvent(ctx, q, events.InvoicePaid, invoice.ID, invoiceData(invoice))
+	})
+	if err != nil {
+		return db.Order{}, err
+	}
+	return updated, nil
+}
+
+// buyerAddress returns an address from the buyer's address book, or ErrAddressRequired when none is given.
+func buyerAddress(ctx context.Context, q db.Querier, buyerID uuid.UUID, addressId string) (db.Address, error) {
+	if addressId == "" {
+		return db.Address{}, ErrAddressRequired
+	}
+	addressUUID, err := uuid.Parse(addressId)
+	if err != nil {
+		return db.Address{}, ErrAddressNotFound
+	}
+	address, err := q.GetAddressByID(ctx, db.GetAddressByIDParams{ID: addressUUID, UserID: buyerID})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Address{}, ErrAddressNotFound
+		}
+		return db.Address{}, err
+	}
+	return address, nil
+}
+
+// ShipOrder marks a paid order shipped by the seller with the carrier and tracking number, and emits OrderShipped.
+// The buyer has to have given the address first. Pickup orders are not shipped, they are delivered when handed over.
+func (os *OrderService) ShipOrder(ctx context.Context, sellerID uuid.UUID, orderId string, carrier string, trackingNumber string) (db.Order, error) {
+	var shipped db.Order
+	err := os.db.ExecTx(ctx, func(q db.Querier) error {
+		order, err := orderForUpdate(ctx, q, sellerID, orderId)
+		if err != nil {
+			return err
+		}
+		if order.SellerID != sellerID {
+			return ErrNotOrderParty
+		}
+		if order.FulfilmentStatus != FulfilmentAwaitingShipment || order.ShippingMethod == nil || *order.ShippingMethod == ShippingPickup {
+			return ErrInvalidFulfilmentTransition
+		}
+		if err := checkOrderPaid(ctx, q, order); err != nil {
+			return err
+		}
+		if order.ShipToLine1 == nil {
+			return ErrAddressRequired
+		}
+		shipped, err = q.MarkOrderShipped(ctx, db.MarkOrderShippedParams{
+			ID:             order.ID,
+			Carrier:        &carrier,
+			TrackingNumber: &trackingNumber,
+		})
+		if err != nil {
+			return err
+		}
+		return emitEvent(ctx, q, events.OrderShipped, shipped.ID, fulfilmentData(shipped))
+	})
+	if err != nil {
+		return db.Order{}, err
+	}
+	return shipped, nil
+}
+
+// ConfirmDelivery marks an order delivered and emits OrderDelivered. The buyer confirms shipped orders arrived,
+// either party confirms a paid pickup order was handed over.
+func (os *OrderService) ConfirmDelivery(ctx context.Context, userID uuid.UUID, orderId string) (db.Order, error) {
+	var delivered db.Order
+	err := os.db.ExecTx(ctx, func(q db.Querier) error {
+		order, err := orderForUpdate(ctx, q, userID, orderId)
+		if err != nil {
+			return err
+		}
+		pickup := order.ShippingMethod != nil && *order.ShippingMethod == ShippingPickup
+		switch {
+		case order.FulfilmentStatus == FulfilmentShipped:
+			if order.BuyerID != userID {
+				return ErrNotOrderParty
+			}
+		case order.FulfilmentStatus == FulfilmentAwaitingShipment && pickup:
+			if err := checkOrderPaid(ctx, q, order); err != nil {
+				return err
+			}
+		default:
+			return ErrInvalidFulfilmentTransition
+		}
+		delivered, err = q.MarkOrderDelivered(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+		return emitEvent(ctx, q, events.OrderDelivered, delivered.ID, fulfilmentData(delivered))
+	})
+	if err != nil {
+		return db.Order{}, err
+	}
+	return delivered, nil
+}
+
+// orderForUpdate locks an order the user is a party of, orders of other users are reported as not found.
+func orderForUpdate(ctx context.Context, q db.Querier, userID uuid.UUID, orderId string) (db.Order, error) {
+	orderUUID, err := uuid.Parse(orderId)
+	if err != nil {
+		return db.Order{}, ErrOrderNotFound
+	}
+	order, err := q.GetOrderForUpdate(ctx, orderUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Order{}, ErrOrderNotFound
+		}
+		return db.Order{}, err
+	}
+	if order.BuyerID != userID && order.SellerID != userID {
+		return db.Order{}, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// checkOrderPaid returns ErrOrderNotPaid unless the invoice of the order is paid. Orders from before invoices
+// have none and count as paid.
+func checkOrderPaid(ctx context.Context, q db.Querier, order db.Order) error {
+	invoice, err := q.GetInvoiceByOrderID(ctx, order.ID)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if invoice.Status != InvoicePaid {
+		return ErrOrderNotPaid
+	}
+	return nil
+}
+
+func fulfilmentData(order db.Order) events.OrderFulfilmentData {
+	data := events.OrderFulfilmentData{
+		OrderID:          order.ID,
+		ProductID:        order.ProductID,
+		SellerID:         order.SellerID,
+		BuyerID:          order.BuyerID,
+		FulfilmentStatus: order.FulfilmentStatus,
+		Carrier:          order.Carrier,
+		TrackingNumber:   order.TrackingNumber,
+	}
+	if order.ShippingMethod != nil {
+		data.ShippingMethod = *order.ShippingMethod
+	}
+	return data
+}
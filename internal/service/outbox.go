@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/itsDrac/e-auc/internal/cache"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/itsDrac/e-auc/pkg/utils"
+)
+
+const (
+	outboxBatchSize     = 50
+	outboxRelayInterval = time.Second
+	outboxBaseBackoff   = 5 * time.Second
+	outboxMaxBackoff    = 10 * time.Minute
+)
+
+// emitEvent records a domain event in the outbox. q must be the transaction that performs
+// the business change, so the event is committed or rolled back together with it.
+func emitEvent(ctx context.Context, q db.Querier, eventType string, aggregateID uuid.UUID, data any) error {
+	e, err := events.New(eventType, aggregateID, data)
+	if err != nil {
+		return err
+	}
+	return q.InsertOutboxEvent(ctx, db.InsertOutboxEventParams{
+		EventID:     e.ID,
+		EventType:   e.Type,
+		AggregateID: e.AggregateID,
+		Payload:     e.Payload,
+	})
+}
+
+// OutboxRelay publishes committed outbox rows to the in-process event bus and to Redis.
+// Delivery is at-least-once: a row is only marked as sent after every publish succeeded,
+// so consumers dedupe on the event ID.
+type OutboxRelay struct {
+	store    db.Store
+	bus      *events.Bus
+	cache    cache.Cacher
+	interval time.Duration
+}
+
+func NewOutboxRelay(store db.Store, bus *events.Bus, c cache.Cacher) *OutboxRelay {
+	return &OutboxRelay{
+		store:    store,
+		bus:      bus,
+		cache:    c,
+		interval: outboxRelayInterval,
+	}
+}
+
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.RelayPending(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("[Outbox] relay failed", "error", err)
+			}
+		}
+	}
+}
+
+// RelayPending publishes one batch of due outbox rows and returns how many were sent.
+// Rows are locked with SKIP LOCKED for the duration of the batch, so several relays never publish the same row concurrently.
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	sent := 0
+	err := r.store.ExecTx(ctx, func(q db.Querier) error {
+		rows, err := q.ClaimPendingOutboxEvents(ctx, outboxBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			e := events.Event{
+				ID:          row.EventID,
+				Type:        row.EventType,
+				AggregateID: row.AggregateID,
+				Payload:     row.Payload,
+				OccurredAt:  row.CreatedAt.UTC(),
+			}
+			if err := r.publish(ctx, e); err != nil {
+				slog.Warn("[Outbox] publish failed, will retry", "event_id", e.ID, "type", e.Type, "error", err)
+				msg := err.Error()
+				err = q.MarkOutboxEventFailed(ctx, db.MarkOutboxEventFailedParams{
+					ID:            row.ID,
+					LastError:     &msg,
+					NextAttemptAt: time.Now().Add(utils.ExponentialBackoff(outboxBaseBackoff, outboxMaxBackoff, row.Attempts+1)),
+				})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if err := q.MarkOutboxEventSent(ctx, row.ID); err != nil {
+				return err
+			}
+			sent++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sent, nil
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, e events.Event) error {
+	if err := r.bus.Publish(ctx, e); err != nil {
+		return err
+	}
+	msg, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return r.cache.Publish(ctx, cache.EventChannel(e.AggregateID.String()), string(msg))
+}
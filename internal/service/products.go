@@ -2,51 +2,230 @@ package service
 
 import (
 	"context"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	db "github.com/itsDrac/e-auc/internal/database"
 	"github.com/itsDrac/e-auc/internal/storage"
+	"github.com/itsDrac/e-auc/pkg/money"
+	"github.com/itsDrac/e-auc/pkg/utils"
 	"github.com/jackc/pgx/v5"
 )
 
 const bucketName = "product-images"
 
+const (
+	defaultAuctionDuration = 7 * 24 * time.Hour
+	maxAuctionDuration     = 30 * 24 * time.Hour
+	// Buy-now is withdrawn once the leading bid reaches this share of the buy-now price
+	defaultBuyNowThresholdPercent = 75
+)
+
+// Reasons an auction closed, sent with the auction.closed event.
+const (
+	CloseReasonBuyNow = "buy_now"
+	CloseReasonEnded  = "ended"
+	CloseReasonAccept = "accepted"
+)
+
+// BiddingState is what a bidder needs to know about a product besides the product itself.
+// Reverse auctions set NextMaxBid, every other format sets NextMinBid.
+type BiddingState struct {
+	NextMinBid      *int64
+	NextMaxBid      *int64
+	BuyNowAvailable bool
+}
+
 type ProductServicer interface {
-	AddProduct(context.Context, db.Product) (uuid.UUID, error)
+	AddProduct(context.Context, db.Product, []IncrementStep, []ShippingOption) (uuid.UUID, error)
 	UploadProductImage(context.Context, string, []byte) (string, error)
-	GetProductUrls(context.Context, string) ([]string, error)
-	GetProductByID(context.Context, string) (*db.Product, error)
-	PlaceBid(context.Context, string, uuid.UUID, int32) error
-	GetProductsBySellerID(context.Context, string, uint, uint) ([]db.Product, error)
+	GetProductUrls(context.Context, string, uuid.UUID) ([]string, error)
+	GetProductByID(context.Context, string, uuid.UUID) (*db.Product, error)
+	GetShippingOptions(context.Context, uuid.UUID) ([]db.ShippingOption, error)
+	PlaceBid(context.Context, string, uuid.UUID, money.Money, int32) error
+	GetProductsBySellerID(context.Context, string, uuid.UUID, string, uint, uint) ([]db.Product, error)
+	GetBiddingState(context.Context, db.Product) (BiddingState, error)
+	BuyNow(context.Context, string, uuid.UUID) (db.Product, error)
+	GetBidsByProductID(context.Context, string) ([]db.Bid, error)
+	SettleEndedAuctions(context.Context) (int, error)
+	AcceptPrice(context.Context, string, uuid.UUID) (db.Product, error)
+	DropDutchPrices(context.Context) (int, error)
+	EditProduct(context.Context, uuid.UUID, string, ProductEdit) (db.Product, error)
+	PublishProduct(context.Context, uuid.UUID, string, *time.Time) (db.Product, error)
+	ScheduleProduct(context.Context, uuid.UUID, string, time.Time, *time.Time) (db.Product, error)
+	RelistProduct(context.Context, uuid.UUID, string, *time.Time) (db.Product, error)
+	StartScheduledProducts(context.Context) (int, error)
+	RetractBid(context.Context, uuid.UUID, string, string, string) (db.Product, error)
+	CancelBidderBids(context.Context, uuid.UUID, string, string, *string) (db.Product, error)
 	// Define methods related to product service here
 }
 
 type ProductService struct {
-	db      db.Querier
-	storage storage.Storager
+	db                     db.Store
+	storage                storage.Storager
+	buyNowThresholdPercent int32
+	bidRetractionWindow    time.Duration
 }
 
-func NewProductService(db db.Querier, s storage.Storager) (*ProductService, error) {
+func NewProductService(db db.Store, s storage.Storager) (*ProductService, error) {
 	return &ProductService{
-		db:      db,
-		storage: s,
+		db:                     db,
+		storage:                s,
+		buyNowThresholdPercent: int32(utils.GetIntEnv("BUY_NOW_THRESHOLD_PERCENT", defaultBuyNowThresholdPercent)),
+		bidRetractionWindow:    time.Duration(utils.GetIntEnv("BID_RETRACTION_WINDOW_MINUTES", defaultBidRetractionWindowMinutes)) * time.Minute,
 	}, nil
 }
 
-func (ps *ProductService) AddProduct(ctx context.Context, p db.Product) (uuid.UUID, error) {
-	arg := db.AddProductParams{
-		Title:        p.Title,
-		Description:  p.Description,
-		SellerID:     p.SellerID,
-		Images:       p.Images,
-		MinPrice:     p.MinPrice,
-		CurrentPrice: p.CurrentPrice,
+// AddProduct stores a new product together with its own bid increment ladder, if given.
+// The product goes live right away unless it is saved as a draft or scheduled to start later.
+func (ps *ProductService) AddProduct(ctx context.Context, p db.Product, increments []IncrementStep, shipping []ShippingOption) (uuid.UUID, error) {
+	now := time.Now().UTC()
+	status := p.Status
+	if status == "" {
+		status = StatusLive
+		if p.StartsAt != nil {
+			status = StatusScheduled
+		}
+	}
+	start := now
+	switch status {
+	case StatusDraft, StatusLive:
+	case StatusScheduled:
+		if p.StartsAt == nil || !p.StartsAt.After(now) {
+			return uuid.Nil, ErrInvalidStartsAt
+		}
+		start = p.StartsAt.UTC()
+	default:
+		return uuid.Nil, ErrInvalidStatus
+	}
+	if len(increments) > 0 {
+		if err := validateLadder(increments); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	shipping, err := validateShippingOptions(shipping)
+	if err != nil {
+		return uuid.Nil, err
 	}
-	product, err := ps.db.AddProduct(ctx, arg)
+
+	arg, err := listingParams(p, start)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	arg.Status = status
+	if status != StatusDraft {
+		arg.StartsAt = &start
+	}
+	var productID uuid.UUID
+	err = ps.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := insertListing(ctx, q, arg, increments, shipping)
+		productID = product.ID
+		return err
+	})
 	if err != nil {
 		return uuid.Nil, err
 	}
-	return product.ID, nil
+	return productID, nil
+}
+
+// listingParams validates a listing that starts at start and returns the columns to store.
+// A zero EndsAt runs the auction for the default duration.
+func listingParams(p db.Product, start time.Time) (db.AddProductParams, error) {
+	endsAt := p.EndsAt.UTC()
+	if p.EndsAt.IsZero() {
+		endsAt = start.Add(defaultAuctionDuration)
+	}
+	if err := validateAuctionWindow(start, endsAt); err != nil {
+		return db.AddProductParams{}, err
+	}
+	auctionType := p.AuctionType
+	if auctionType == "" {
+		auctionType = AuctionTypeEnglish
+	}
+	format, ok := auctionFormats[auctionType]
+	if !ok {
+		return db.AddProductParams{}, ErrInvalidAuctionType
+	}
+
+	arg := db.AddProductParams{
+		Title:                     p.Title,
+		Description:               p.Description,
+		SellerID:                  p.SellerID,
+		Images:                    p.Images,
+		MinPrice:                  p.MinPrice,
+		CurrentPrice:              p.CurrentPrice,
+		StartPrice:                p.CurrentPrice,
+		EndsAt:                    endsAt,
+		SoftCloseWindowMinutes:    p.SoftCloseWindowMinutes,
+		SoftCloseExtensionMinutes: p.SoftCloseExtensionMinutes,
+		MaxExtensions:             p.MaxExtensions,
+		Category:                  normalizeCategory(p.Category),
+		AuctionType:               auctionType,
+		RelistedFrom:              p.RelistedFrom,
+		SecondChanceOnNonPayment:  p.SecondChanceOnNonPayment,
+		MinBidderReputation:       p.MinBidderReputation,
+	}
+	if p.Currency == "" {
+		p.Currency = money.DefaultCurrency
+	}
+	currency, err := money.ParseCurrency(p.Currency)
+	if err != nil {
+		return db.AddProductParams{}, ErrInvalidCurrency
+	}
+	arg.Currency = currency
+	switch p.Visibility {
+	case "":
+		arg.Visibility = VisibilityPublic
+	case VisibilityPublic, VisibilityPrivate:
+		arg.Visibility = p.Visibility
+	default:
+		return db.AddProductParams{}, ErrInvalidVisibility
+	}
+	if auctionType != AuctionTypeFixedPrice && (p.AutoAcceptPrice != nil || p.AutoDeclinePrice != nil) {
+		return db.AddProductParams{}, ErrOffersNotSupported
+	}
+	if err := format.prepare(p, &arg, start); err != nil {
+		return db.AddProductParams{}, err
+	}
+	if err := prepareQuantity(p, auctionType, &arg); err != nil {
+		return db.AddProductParams{}, err
+	}
+	if err := prepareDeposit(p, auctionType, &arg); err != nil {
+		return db.AddProductParams{}, err
+	}
+	return arg, nil
+}
+
+// validateAuctionWindow checks that an auction starting at start ends after it, within the maximum duration.
+func validateAuctionWindow(start time.Time, endsAt time.Time) error {
+	if !endsAt.After(start) || endsAt.After(start.Add(maxAuctionDuration)) {
+		return ErrInvalidEndsAt
+	}
+	return nil
+}
+
+// insertListing stores a product with its own increment ladder and its shipping options.
+func insertListing(ctx context.Context, q db.Querier, arg db.AddProductParams, increments []IncrementStep, shipping []ShippingOption) (db.Product, error) {
+	product, err := q.AddProduct(ctx, arg)
+	if err != nil {
+		return db.Product{}, err
+	}
+	for _, step := range increments {
+		err := q.CreateBidIncrementRule(ctx, db.CreateBidIncrementRuleParams{
+			Scope:     IncrementScopeProduct,
+			ProductID: &product.ID,
+			MinPrice:  step.MinPrice,
+			Increment: step.Increment,
+		})
+		if err != nil {
+			return db.Product{}, err
+		}
+	}
+	if err := insertShippingOptions(ctx, q, product.ID, shipping); err != nil {
+		return db.Product{}, err
+	}
+	return product, nil
 }
 
 func (ps *ProductService) UploadProductImage(ctx context.Context, filename string, data []byte) (string, error) {
@@ -57,12 +236,14 @@ func (ps *ProductService) UploadProductImage(ctx context.Context, filename strin
 	return info.Key, nil
 }
 
-func (ps *ProductService) GetProductUrls(ctx context.Context, productId string) ([]string, error) {
-	productUUID, err := uuid.Parse(productId)
+// GetProductUrls returns the image URLs of the product as seen by the viewer, the images of a private listing
+// are not found like the listing itself.
+func (ps *ProductService) GetProductUrls(ctx context.Context, productId string, viewerID uuid.UUID) ([]string, error) {
+	product, err := ps.GetProductByID(ctx, productId, viewerID)
 	if err != nil {
 		return nil, err
 	}
-	imagekeys, err := ps.db.GetProductImages(ctx, productUUID)
+	imagekeys, err := ps.db.GetProductImages(ctx, product.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +261,9 @@ func (ps *ProductService) GetProductUrls(ctx context.Context, productId string)
 	return urls, nil
 }
 
-func (ps *ProductService) GetProductByID(ctx context.Context, productId string) (*db.Product, error) {
+// GetProductByID returns the product as seen by the viewer, uuid.Nil for anonymous visitors.
+// Private listings are not found unless the viewer is their seller or invited to them.
+func (ps *ProductService) GetProductByID(ctx context.Context, productId string, viewerID uuid.UUID) (*db.Product, error) {
 	productUUID, err := uuid.Parse(productId)
 	if err != nil {
 		return nil, err
@@ -92,73 +275,209 @@ func (ps *ProductService) GetProductByID(ctx context.Context, productId string)
 		}
 		return nil, err
 	}
+	visible, err := canViewProduct(ctx, ps.db, product, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, ErrProductNotFound
+	}
 	return &product, nil
 }
 
-func (ps *ProductService) PlaceBid(ctx context.Context, productId string, bidderId uuid.UUID, bidAmount int32) error {
+// PlaceBid bids the amount of bid per unit for quantity units of the product, 0 asks for a single unit.
+// A bid without a currency is in the currency of the listing, a bid in any other currency is rejected.
+func (ps *ProductService) PlaceBid(ctx context.Context, productId string, bidderId uuid.UUID, bid money.Money, quantity int32) error {
 	productUUID, err := uuid.Parse(productId)
 	if err != nil {
 		return err
 	}
-	// Should we check if the product.SellerId == bidderId to prevent self-bidding?
-	product, err := ps.db.GetProductByID(ctx, productUUID)
-	if err != nil {
-		return err
+
+	// The bid, the new current price and the bid event are committed together.
+	// Locking the product row serializes concurrent bids on the same product.
+	// What makes a bid acceptable depends on the auction format of the product.
+	// Products asking for a deposit hold the bidder's funds in the same transaction.
+	return ps.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := q.GetProductForUpdate(ctx, productUUID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		if product.SellerID == bidderId {
+			return ErrSelfBidding
+		}
+		if err := checkBuyerAccess(ctx, q, product, bidderId); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if err := checkProductOpen(product, now); err != nil {
+			return err
+		}
+		if bid.Currency != "" && bid.Currency != product.Currency {
+			return ErrCurrencyMismatch
+		}
+		if quantity == 0 {
+			quantity = 1
+		}
+		if quantity < 0 || quantity > product.Quantity {
+			return ErrInvalidBidQuantity
+		}
+
+		if err := formatFor(product).placeBid(ctx, q, product, bidderId, bid.Amount, quantity, now); err != nil {
+			return err
+		}
+		return syncBidHolds(ctx, q, product, bidderId)
+	})
+}
+
+// checkProductOpen returns an error unless the product is live and has neither closed nor reached its end.
+func checkProductOpen(product db.Product, now time.Time) error {
+	if product.ClosedAt != nil || !now.Before(product.EndsAt) {
+		return ErrAuctionEnded
 	}
-	if product.SellerID == bidderId {
-		return ErrSelfBidding
+	if product.Status != StatusLive {
+		return ErrProductNotLive
 	}
+	return nil
+}
 
-	// TODO: Add check for threshold bidding amount for the product
-	if bidAmount <= product.CurrentPrice {
-		return ErrInsufficientBid
+// GetShippingOptions returns how the product ships, cheapest first.
+func (ps *ProductService) GetShippingOptions(ctx context.Context, productID uuid.UUID) ([]db.ShippingOption, error) {
+	options, err := ps.db.GetShippingOptionsByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if options == nil {
+		options = []db.ShippingOption{}
 	}
+	return options, nil
+}
 
-	// Check if the last valid bidder is not the current bidder
-	lastBid, err := ps.db.GetLatestBidForProduct(ctx, productUUID)
-	if err != nil && err != pgx.ErrNoRows {
-		return err
+// GetBiddingState returns the lowest bid the product currently accepts according to its auction format
+// and whether it can still be bought at its buy-now price.
+func (ps *ProductService) GetBiddingState(ctx context.Context, product db.Product) (BiddingState, error) {
+	state, err := formatFor(product).bidLimits(ctx, ps.db, product)
+	if err != nil {
+		return BiddingState{}, err
 	}
-	if err == nil && lastBid.UserID == bidderId {
-		return ErrConsecutiveBid
+	state.BuyNowAvailable, err = ps.buyNowAvailable(ctx, ps.db, product)
+	if err != nil {
+		return BiddingState{}, err
 	}
+	return state, nil
+}
 
-	// Store the bid in bids table
-	err = ps.db.CreateBid(ctx, db.CreateBidParams{
-		ProductID: productUUID,
-		UserID:    bidderId,
-		Price:     bidAmount,
-		Comments:  nil,
-	})
+// BuyNow sells the product to the buyer at its buy-now price and closes the auction.
+// The product row is locked like in PlaceBid, so a purchase and a concurrent bid never both succeed.
+func (ps *ProductService) BuyNow(ctx context.Context, productId string, buyerId uuid.UUID) (db.Product, error) {
+	productUUID, err := uuid.Parse(productId)
 	if err != nil {
+		return db.Product{}, ErrProductNotFound
+	}
+
+	var sold db.Product
+	err = ps.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := q.GetProductForUpdate(ctx, productUUID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		if product.SellerID == buyerId {
+			return ErrSelfBuying
+		}
+		if err := checkBuyerAccess(ctx, q, product, buyerId); err != nil {
+			return err
+		}
+		if err := checkProductOpen(product, time.Now().UTC()); err != nil {
+			return err
+		}
+		available, err := ps.buyNowAvailable(ctx, q, product)
+		if err != nil {
+			return err
+		}
+		if !available {
+			return ErrBuyNowUnavailable
+		}
+
+		// Outstanding bids lose to the purchase
+		if err := q.InvalidateBidsForProduct(ctx, productUUID); err != nil {
+			return err
+		}
+		sold, err = sellProduct(ctx, q, product, []allocation{{WinnerID: buyerId, Quantity: 1, UnitPrice: *product.BuyNowPrice}}, CloseReasonBuyNow)
 		return err
+	})
+	if err != nil {
+		return db.Product{}, err
 	}
+	return sold, nil
+}
 
-	// TODO: Move this function to a separate worker or use a message queue for better scalability
-	// TODO: Add retry mechanism in case of transient failures
-	// Update product current price in a goroutine
-	go func() {
-		// Use background context to avoid cancellation when original context is done
-		bgCtx := context.Background()
-		_ = ps.db.UpdateProductCurrentPrice(bgCtx, db.UpdateProductCurrentPriceParams{
-			ID:           productUUID,
-			CurrentPrice: bidAmount,
-		})
-		// TODO: Add logging if update fails
-		// TODO: Add code to check for seller threshold on bidding of its products.
-		// TODO: If the bidding amount is higher than the threshold, notify the seller via email.
-	}()
+// buyNowAvailable reports whether the product has a buy-now price that bidding has not made obsolete yet.
+func (ps *ProductService) buyNowAvailable(ctx context.Context, q db.Querier, product db.Product) (bool, error) {
+	if product.BuyNowPrice == nil || checkProductOpen(product, time.Now().UTC()) != nil {
+		return false, nil
+	}
+	leading, err := q.GetLatestBidForProduct(ctx, product.ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return true, nil
+		}
+		return false, err
+	}
+	return leading.Price*100 < *product.BuyNowPrice*int64(ps.buyNowThresholdPercent), nil
+}
 
-	return nil
+// GetBidsByProductID returns every bid placed on the product, newest first.
+// Callers must hide the amounts while BidAmountsHidden reports true.
+func (ps *ProductService) GetBidsByProductID(ctx context.Context, productId string) ([]db.Bid, error) {
+	productUUID, err := uuid.Parse(productId)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	return ps.db.GetBidsByProductID(ctx, productUUID)
 }
 
-func (ps *ProductService) GetProductsBySellerID(ctx context.Context, sellerId string, limit uint, offset uint) ([]db.Product, error) {
+// softCloseExtension returns the new end of the auction when a bid placed at now falls within
+// the product's soft-close window and the product has extensions left.
+func softCloseExtension(p db.Product, now time.Time) (time.Time, bool) {
+	if p.SoftCloseWindowMinutes <= 0 || p.SoftCloseExtensionMinutes <= 0 {
+		return p.EndsAt, false
+	}
+	if p.MaxExtensions != nil && p.ExtensionCount >= *p.MaxExtensions {
+		return p.EndsAt, false
+	}
+	window := time.Duration(p.SoftCloseWindowMinutes) * time.Minute
+	if p.EndsAt.Sub(now) > window {
+		return p.EndsAt, false
+	}
+	return p.EndsAt.Add(time.Duration(p.SoftCloseExtensionMinutes) * time.Minute), true
+}
+
+// GetProductsBySellerID lists the seller's products, newest first, optionally only those with the given status.
+// Drafts are only listed when the viewer is the seller, private listings when the viewer is the seller or invited.
+func (ps *ProductService) GetProductsBySellerID(ctx context.Context, sellerId string, viewerID uuid.UUID, status string, limit uint, offset uint) ([]db.Product, error) {
 	sellerUUID, err := uuid.Parse(sellerId)
 	if err != nil {
 		return nil, err
 	}
+	statuses := ProductStatuses
+	if status != "" {
+		if !slices.Contains(ProductStatuses, status) {
+			return nil, ErrInvalidStatusFilter
+		}
+		statuses = []string{status}
+	}
+	if viewerID != sellerUUID {
+		statuses = slices.DeleteFunc(slices.Clone(statuses), func(s string) bool { return s == StatusDraft })
+	}
 	args := db.GetProductsBySellerIDParams{
 		SellerID: sellerUUID,
+		Statuses: statuses,
+		ViewerID: viewerID,
 		Limit:    int32(limit),
 		Offset:   int32(offset),
 	}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// Bidders may retract a bid for this long after placing it, BID_RETRACTION_WINDOW_MINUTES overrides it
+	defaultBidRetractionWindowMinutes = 10
+	// No bid can be retracted once the auction is this close to its end
+	bidRetractionCutoff = time.Hour
+)
+
+// RetractBid withdraws the bidder's own valid bid on a live auction. The bid must have been placed within
+// the retraction window, the auction must not be in its final hour and a reason is required.
+// The current price is recomputed from the bids left in the same transaction.
+func (ps *ProductService) RetractBid(ctx context.Context, bidderID uuid.UUID, productId string, bidId string, reason string) (db.Product, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return db.Product{}, ErrRetractionReasonRequired
+	}
+	productUUID, err := uuid.Parse(productId)
+	if err != nil {
+		return db.Product{}, ErrProductNotFound
+	}
+	bidUUID, err := uuid.Parse(bidId)
+	if err != nil {
+		return db.Product{}, ErrBidNotFound
+	}
+
+	var updated db.Product
+	err = ps.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := q.GetProductForUpdate(ctx, productUUID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		bid, err := q.GetBidByID(ctx, bidUUID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrBidNotFound
+			}
+			return err
+		}
+		if bid.ProductID != product.ID || bid.UserID != bidderID || !bid.IsValid {
+			return ErrBidNotFound
+		}
+		now := time.Now().UTC()
+		if err := checkProductOpen(product, now); err != nil {
+			return err
+		}
+		if !now.Before(product.EndsAt.Add(-bidRetractionCutoff)) {
+			return ErrRetractionFinalHour
+		}
+		if now.After(bid.BidAt.Add(ps.bidRetractionWindow)) {
+			return ErrRetractionWindowPassed
+		}
+
+		retracted, err := q.RetractBid(ctx, db.RetractBidParams{
+			ID:               bid.ID,
+			RetractedBy:      &bidderID,
+			RetractionReason: &reason,
+		})
+		if err != nil {
+			return err
+		}
+		updated, err = afterRetraction(ctx, q, product, bidderID, bidderID, []db.Bid{retracted}, &reason)
+		return err
+	})
+	if err != nil {
+		return db.Product{}, err
+	}
+	return updated, nil
+}
+
+// CancelBidderBids lets the seller cancel every valid bid of a bidder on their live auction, at any time
+// before the close. The current price is recomputed from the bids left in the same transaction.
+func (ps *ProductService) CancelBidderBids(ctx context.Context, sellerID uuid.UUID, productId string, bidderId string, reason *string) (db.Product, error) {
+	bidderUUID, err := uuid.Parse(bidderId)
+	if err != nil {
+		return db.Product{}, ErrBidNotFound
+	}
+	if reason != nil {
+		trimmed := strings.TrimSpace(*reason)
+		reason = &trimmed
+		if trimmed == "" {
+			reason = nil
+		}
+	}
+
+	var updated db.Product
+	err = ps.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := ownedProductForUpdate(ctx, q, sellerID, productId)
+		if err != nil {
+			return err
+		}
+		if err := checkProductOpen(product, time.Now().UTC()); err != nil {
+			return err
+		}
+		cancelled, err := q.RetractBidsByBidder(ctx, db.RetractBidsByBidderParams{
+			RetractedBy:      &sellerID,
+			RetractionReason: reason,
+			ProductID:        product.ID,
+			UserID:           bidderUUID,
+		})
+		if err != nil {
+			return err
+		}
+		if len(cancelled) == 0 {
+			return ErrBidNotFound
+		}
+		updated, err = afterRetraction(ctx, q, product, bidderUUID, sellerID, cancelled, reason)
+		return err
+	})
+	if err != nil {
+		return db.Product{}, err
+	}
+	return updated, nil
+}
+
+// afterRetraction recomputes the current price and the leading bid of the product, whose row is locked
+// by the caller, from the valid bids left and emits BidsRetracted.
+func afterRetraction(ctx context.Context, q db.Querier, product db.Product, bidderID uuid.UUID, retractedBy uuid.UUID, retracted []db.Bid, reason *string) (db.Product, error) {
+	valid, err := q.GetValidBidsByProductID(ctx, product.ID)
+	if err != nil {
+		return db.Product{}, err
+	}
+	previousPrice := product.CurrentPrice
+	price := formatFor(product).currentPrice(product, valid)
+	if price != previousPrice {
+		err := q.UpdateProductCurrentPrice(ctx, db.UpdateProductCurrentPriceParams{
+			ID:           product.ID,
+			CurrentPrice: price,
+		})
+		if err != nil {
+			return db.Product{}, err
+		}
+		product.CurrentPrice = price
+	}
+	if err := syncBidHolds(ctx, q, product, uuid.Nil); err != nil {
+		return db.Product{}, err
+	}
+
+	data := events.BidsRetractedData{
+		ProductID:    product.ID,
+		SellerID:     product.SellerID,
+		BidderID:     bidderID,
+		RetractedBy:  retractedBy,
+		Reason:       reason,
+		CurrentPrice: product.CurrentPrice,
+	}
+	for _, bid := range retracted {
+		data.BidIDs = append(data.BidIDs, bid.ID)
+	}
+	if leading, ok := LeadingBid(product, valid); ok {
+		data.LeadingBidderID = &leading.UserID
+	}
+	if err := emitEvent(ctx, q, events.BidsRetracted, product.ID, data); err != nil {
+		return db.Product{}, err
+	}
+	if price != previousPrice {
+		err := emitEvent(ctx, q, events.AuctionPriceUpdated, product.ID, events.AuctionPriceUpdatedData{
+			ProductID: product.ID,
+			SellerID:  product.SellerID,
+			Price:     price,
+		})
+		if err != nil {
+			return db.Product{}, err
+		}
+	}
+	return product, nil
+}
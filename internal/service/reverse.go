@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/jackc/pgx/v5"
+)
+
+// reverseAuction is a procurement auction: a buyer posts the listing with a starting price and sellers bid
+// it down. Every bid must undercut the current price by the increment ladder and the lowest bid wins at close
+// when it is within the buyer's budget, the min_price.
+// The buyer is the owner of the product, so the shared self-bidding check keeps them from bidding.
+type reverseAuction struct{}
+
+func (reverseAuction) prepare(p db.Product, arg *db.AddProductParams, now time.Time) error {
+	if p.BuyNowPrice != nil {
+		return ErrBuyNowNotSupported
+	}
+	return nil
+}
+
+func (reverseAuction) sealed() bool { return false }
+
+func (reverseAuction) bidLimits(ctx context.Context, q db.Querier, product db.Product) (BiddingState, error) {
+	rules, err := incrementRulesFor(ctx, q, product)
+	if err != nil {
+		return BiddingState{}, err
+	}
+	maxBid := nextMaxBid(rules, product.CurrentPrice)
+	return BiddingState{NextMaxBid: &maxBid}, nil
+}
+
+func (reverseAuction) placeBid(ctx context.Context, q db.Querier, product db.Product, bidderId uuid.UUID, bidAmount int64, quantity int32, now time.Time) error {
+	rules, err := incrementRulesFor(ctx, q, product)
+	if err != nil {
+		return err
+	}
+	if maxBid := nextMaxBid(rules, product.CurrentPrice); bidAmount > maxBid {
+		return &BidAboveIncrementError{MaxBid: maxBid}
+	}
+	if bidAmount <= 0 {
+		return ErrInsufficientBid
+	}
+
+	lastBid, err := q.GetLatestBidForProduct(ctx, product.ID)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+	if err == nil && lastBid.UserID == bidderId {
+		return ErrConsecutiveBid
+	}
+
+	err = q.CreateBid(ctx, db.CreateBidParams{
+		ProductID: product.ID,
+		UserID:    bidderId,
+		Price:     bidAmount,
+		Quantity:  quantity,
+	})
+	if err != nil {
+		return err
+	}
+	err = q.UpdateProductCurrentPrice(ctx, db.UpdateProductCurrentPriceParams{
+		ID:           product.ID,
+		CurrentPrice: bidAmount,
+	})
+	if err != nil {
+		return err
+	}
+	if err := extendOnLateBid(ctx, q, &product, now); err != nil {
+		return err
+	}
+
+	maxBid := nextMaxBid(rules, bidAmount)
+	return emitEvent(ctx, q, events.BidPlaced, product.ID, events.BidPlacedData{
+		ProductID:  product.ID,
+		SellerID:   product.SellerID,
+		BidderID:   bidderId,
+		BidAmount:  bidAmount,
+		Quantity:   quantity,
+		NextMaxBid: &maxBid,
+		EndsAt:     product.EndsAt,
+	})
+}
+
+func (reverseAuction) rank(bids []db.Bid) []db.Bid {
+	return rankBids(bids, func(a, b int64) bool { return a < b })
+}
+
+// clearingPrice is the lowest bid. min_price is the buyer's budget, so a lowest bid above it leaves the request unsold.
+func (reverseAuction) clearingPrice(product db.Product, ranked []db.Bid) (int64, bool) {
+	return ranked[0].Price, ranked[0].Price <= product.MinPrice
+}
+
+// currentPrice is the lowest bid, or the starting price once no bids are left.
+func (reverseAuction) currentPrice(product db.Product, valid []db.Bid) int64 {
+	ranked := reverseAuction{}.rank(valid)
+	if len(ranked) == 0 {
+		return product.StartPrice
+	}
+	return ranked[0].Price
+}
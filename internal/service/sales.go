@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+)
+
+// Pricing rules of multi-unit auctions, mirrored by the CHECK constraint on products.pricing_rule.
+// Pay-as-bid charges every winner their own bid, uniform charges every winner the lowest winning bid.
+const (
+	PricingPayAsBid = "pay_as_bid"
+	PricingUniform  = "uniform"
+)
+
+// multiUnitFormats are the auction formats that can sell more than one unit of a product.
+var multiUnitFormats = map[string]bool{
+	AuctionTypeEnglish:          true,
+	AuctionTypeSealedFirstPrice: true,
+}
+
+// allocation is the share of a sold product that goes to one winner.
+// BidID is nil when the sale did not come from a stored bid, like a buy-now purchase.
+type allocation struct {
+	WinnerID  uuid.UUID
+	BidID     *uuid.UUID
+	Quantity  int32
+	UnitPrice int64
+}
+
+// prepareQuantity checks the number of units and the pricing rule of a new product.
+func prepareQuantity(p db.Product, auctionType string, arg *db.AddProductParams) error {
+	arg.Quantity = p.Quantity
+	if arg.Quantity == 0 {
+		arg.Quantity = 1
+	}
+	if arg.Quantity < 0 {
+		return ErrInvalidQuantity
+	}
+	if arg.Quantity > 1 && (!multiUnitFormats[auctionType] || p.BuyNowPrice != nil) {
+		return ErrQuantityNotSupported
+	}
+
+	arg.PricingRule = p.PricingRule
+	if arg.PricingRule == "" {
+		arg.PricingRule = PricingPayAsBid
+	}
+	if arg.PricingRule != PricingPayAsBid && arg.PricingRule != PricingUniform {
+		return ErrInvalidPricingRule
+	}
+	return nil
+}
+
+// allocateUnits hands out units to the ranked bids in order until none are left.
+// The last winner may get fewer units than they asked for. Every winner is priced at their own bid.
+func allocateUnits(units int32, ranked []db.Bid) []allocation {
+	var allocs []allocation
+	for _, bid := range ranked {
+		if units == 0 {
+			break
+		}
+		bidID := bid.ID
+		take := min(bid.Quantity, units)
+		allocs = append(allocs, allocation{
+			WinnerID:  bid.UserID,
+			BidID:     &bidID,
+			Quantity:  take,
+			UnitPrice: bid.Price,
+		})
+		units -= take
+	}
+	return allocs
+}
+
+// winningAllocations returns who wins the product and at what price, or nothing when it goes unsold.
+// Single-unit products pay the clearing price of their format. Multi-unit products allocate their units
+// to the bids that meet the reserve and price them by the product's pricing rule.
+func winningAllocations(product db.Product, format auctionFormat, ranked []db.Bid) []allocation {
+	if len(ranked) == 0 {
+		return nil
+	}
+	if product.Quantity <= 1 {
+		price, ok := format.clearingPrice(product, ranked)
+		if !ok {
+			return nil
+		}
+		bidID := ranked[0].ID
+		return []allocation{{WinnerID: ranked[0].UserID, BidID: &bidID, Quantity: 1, UnitPrice: price}}
+	}
+
+	eligible := make([]db.Bid, 0, len(ranked))
+	for _, bid := range ranked {
+		if bid.Price >= product.MinPrice {
+			eligible = append(eligible, bid)
+		}
+	}
+	allocs := allocateUnits(product.Quantity, eligible)
+	if product.PricingRule == PricingUniform && len(allocs) > 0 {
+		lowest := allocs[len(allocs)-1].UnitPrice
+		for i := range allocs {
+			allocs[i].UnitPrice = lowest
+		}
+	}
+	return allocs
+}
+
+// sellProduct closes the product, whose row is locked by the caller, with a sale to every allocation.
+// It marks the product sold, creates an order and its invoice per winner, shipping the way defaultShipping picks
+// for the buyer, and emits AuctionClosed and one ItemSold per order. Offers still open on the product are declined.
+// The deposits of the winners are captured towards their invoice and the others released.
+// A single winner is recorded in sold_to, several winners only in their orders.
+// The product closes at the lowest unit price sold.
+func sellProduct(ctx context.Context, q db.Querier, product db.Product, allocs []allocation, reason string) (db.Product, error) {
+	price := allocs[len(allocs)-1].UnitPrice
+	var sold db.Product
+	var err error
+	if len(allocs) == 1 {
+		sold, err = q.MarkProductAsSold(ctx, db.MarkProductAsSoldParams{
+			ID:           product.ID,
+			SoldTo:       &allocs[0].WinnerID,
+			CurrentPrice: price,
+		})
+	} else {
+		sold, err = q.MarkProductAsSoldToWinners(ctx, db.MarkProductAsSoldToWinnersParams{
+			ID:           product.ID,
+			CurrentPrice: price,
+		})
+	}
+	if err != nil {
+		return db.Product{}, err
+	}
+	if err := q.DeclineOpenOffersForProduct(ctx, product.ID); err != nil {
+		return db.Product{}, err
+	}
+	rules, err := feeRulesFor(ctx, q, product)
+	if err != nil {
+		return db.Product{}, err
+	}
+	options, err := q.GetShippingOptionsByProductID(ctx, product.ID)
+	if err != nil {
+		return db.Product{}, err
+	}
+	shipping := make([]shippingChoice, len(allocs))
+	charges := make([]invoiceCharges, len(allocs))
+	owed := map[uuid.UUID]int64{}
+	for i, a := range allocs {
+		_, buyer := tradeParties(product, a.WinnerID)
+		shipping[i], err = defaultShipping(ctx, q, options, buyer)
+		if err != nil {
+			return db.Product{}, err
+		}
+		charges[i] = chargesFor(product, a, rules, shipping[i].Cost)
+		owed[a.WinnerID] += charges[i].total()
+	}
+	captured, err := settleBidHolds(ctx, q, product, owed)
+	if err != nil {
+		return db.Product{}, err
+	}
+
+	closed := events.AuctionClosedData{
+		ProductID: product.ID,
+		SellerID:  product.SellerID,
+		Price:     price,
+		Reason:    reason,
+	}
+	if len(allocs) == 1 {
+		closed.WinnerID = &allocs[0].WinnerID
+	}
+	for _, a := range allocs {
+		closed.Winners = append(closed.Winners, events.AuctionWinner{
+			BidderID:  a.WinnerID,
+			Quantity:  a.Quantity,
+			UnitPrice: a.UnitPrice,
+		})
+	}
+	if err := emitEvent(ctx, q, events.AuctionClosed, product.ID, closed); err != nil {
+		return db.Product{}, err
+	}
+
+	for i, a := range allocs {
+		seller, buyer := tradeParties(product, a.WinnerID)
+		order, err := q.CreateOrder(ctx, db.CreateOrderParams{
+			ProductID:  product.ID,
+			BidID:      a.BidID,
+			SellerID:   seller,
+			BuyerID:    buyer,
+			Quantity:   a.Quantity,
+			UnitPrice:  a.UnitPrice,
+			TotalPrice: int64(a.Quantity) * a.UnitPrice,
+		})
+		if err != nil {
+			return db.Product{}, err
+		}
+		if shipping[i].Method != nil {
+			if order, err = setOrderShipping(ctx, q, order.ID, shipping[i]); err != nil {
+				return db.Product{}, err
+			}
+		}
+		if _, err := createInvoice(ctx, q, order, charges[i], captured[a.WinnerID]); err != nil {
+			return db.Product{}, err
+		}
+		err = emitEvent(ctx, q, events.ItemSold, product.ID, events.ItemSoldData{
+			ProductID: product.ID,
+			OrderID:   order.ID,
+			SellerID:  seller,
+			BuyerID:   buyer,
+			Quantity:  order.Quantity,
+			Price:     order.TotalPrice,
+		})
+		if err != nil {
+			return db.Product{}, err
+		}
+	}
+	return sold, nil
+}
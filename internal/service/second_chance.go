@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/itsDrac/e-auc/internal/jobs"
+	"github.com/jackc/pgx/v5"
+)
+
+// Statuses of a second-chance offer, mirrored by the CHECK constraint on second_chance_offers.status.
+const (
+	SecondChancePending   = "pending"
+	SecondChanceAccepted  = "accepted"
+	SecondChanceDeclined  = "declined"
+	SecondChanceExpired   = "expired"
+	SecondChanceCancelled = "cancelled"
+)
+
+// CloseReasonSecondChance is sent with the auction.closed event of a sale to a runner-up.
+const CloseReasonSecondChance = "second_chance"
+
+// JobSecondChanceExpiry expires second-chance offers that were not answered in time.
+const JobSecondChanceExpiry = "offers.second_chance_expiry"
+
+const (
+	defaultSecondChanceWindow  = 24 * time.Hour
+	maxSecondChanceWindow      = 72 * time.Hour
+	secondChanceExpiryInterval = 30 * time.Second
+	secondChanceExpiryBatch    = 100
+)
+
+type SecondChanceServicer interface {
+	OfferToRunnerUp(ctx context.Context, sellerID uuid.UUID, productId string, bidId string, window time.Duration, autoAdvance bool) (db.SecondChanceOffer, error)
+	GetProductOffers(ctx context.Context, sellerID uuid.UUID, productId string) ([]db.SecondChanceOffer, error)
+	GetPendingOffers(ctx context.Context, bidderID uuid.UUID) ([]db.SecondChanceOffer, error)
+	AcceptOffer(ctx context.Context, bidderID uuid.UUID, offerId string) (db.Product, error)
+	DeclineOffer(ctx context.Context, bidderID uuid.UUID, offerId string) (db.SecondChanceOffer, error)
+	ExpireOffers(ctx context.Context) (int, error)
+}
+
+type SecondChanceService struct {
+	db db.Store
+}
+
+func NewSecondChanceService(db db.Store) (*SecondChanceService, error) {
+	return &SecondChanceService{
+		db: db,
+	}, nil
+}
+
+func registerSecondChanceJobs(scs *SecondChanceService, queue *jobs.Queue) {
+	queue.Register(JobSecondChanceExpiry, func(ctx context.Context, job jobs.Job) error {
+		_, err := scs.ExpireOffers(ctx)
+		return err
+	}, jobs.Options{MaxAttempts: 3})
+	queue.Every(JobSecondChanceExpiry, secondChanceExpiryInterval)
+}
+
+// secondChanceAvailable reports whether the product can still be sold to a runner-up:
+// it ended unsold, was not relisted and sells a single unit.
+func secondChanceAvailable(product db.Product) bool {
+	return product.Status == StatusEnded && product.SoldAt == nil && product.Quantity == 1
+}
+
+// OfferToRunnerUp offers the unsold product to the bidder of bidId at their bid, open for window
+// (24 hours when zero). With autoAdvance a declined or expired offer moves on to the next best bidder.
+func (scs *SecondChanceService) OfferToRunnerUp(ctx context.Context, sellerID uuid.UUID, productId string, bidId string, window time.Duration, autoAdvance bool) (db.SecondChanceOffer, error) {
+	if window == 0 {
+		window = defaultSecondChanceWindow
+	}
+	if window < 0 || window > maxSecondChanceWindow {
+		return db.SecondChanceOffer{}, ErrInvalidOfferWindow
+	}
+	bidUUID, err := uuid.Parse(bidId)
+	if err != nil {
+		return db.SecondChanceOffer{}, ErrBidNotEligible
+	}
+
+	var offer db.SecondChanceOffer
+	err = scs.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := ownedProductForUpdate(ctx, q, sellerID, productId)
+		if err != nil {
+			return err
+		}
+		if !secondChanceAvailable(product) {
+			return ErrSecondChanceUnavailable
+		}
+		offers, err := q.GetSecondChanceOffersByProductID(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		for _, o := range offers {
+			if o.Status == SecondChancePending {
+				return ErrSecondChancePending
+			}
+		}
+		bids, err := q.GetValidBidsByProductID(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		for _, bid := range runnerUps(product, bids, offers) {
+			if bid.ID == bidUUID {
+				offer, err = createSecondChanceOffer(ctx, q, product, bid, window, autoAdvance)
+				return err
+			}
+		}
+		return ErrBidNotEligible
+	})
+	if err != nil {
+		return db.SecondChanceOffer{}, err
+	}
+	return offer, nil
+}
+
+// runnerUps returns the best valid bid of every bidder who was not offered the product yet, best first.
+func runnerUps(product db.Product, bids []db.Bid, offers []db.SecondChanceOffer) []db.Bid {
+	offered := map[uuid.UUID]bool{}
+	for _, o := range offers {
+		offered[o.BidderID] = true
+	}
+	var candidates []db.Bid
+	for _, bid := range formatFor(product).rank(bids) {
+		if offered[bid.UserID] {
+			continue
+		}
+		offered[bid.UserID] = true
+		candidates = append(candidates, bid)
+	}
+	return candidates
+}
+
+func createSecondChanceOffer(ctx context.Context, q db.Querier, product db.Product, bid db.Bid, window time.Duration, autoAdvance bool) (db.SecondChanceOffer, error) {
+	offer, err := q.CreateSecondChanceOffer(ctx, db.CreateSecondChanceOfferParams{
+		ProductID:   product.ID,
+		BidID:       bid.ID,
+		SellerID:    product.SellerID,
+		BidderID:    bid.UserID,
+		Price:       bid.Price,
+		AutoAdvance: autoAdvance,
+		ExpiresAt:   time.Now().UTC().Add(window),
+	})
+	if err != nil {
+		return db.SecondChanceOffer{}, err
+	}
+	err = emitEvent(ctx, q, events.SecondChanceOffered, offer.ID, events.SecondChanceOfferedData{
+		OfferID:   offer.ID,
+		ProductID: product.ID,
+		SellerID:  product.SellerID,
+		BidderID:  offer.BidderID,
+		Price:     offer.Price,
+		ExpiresAt: offer.ExpiresAt,
+	})
+	if err != nil {
+		return db.SecondChanceOffer{}, err
+	}
+	return offer, nil
+}
+
+// GetProductOffers returns every second-chance offer made for the seller's product, oldest first.
+func (scs *SecondChanceService) GetProductOffers(ctx context.Context, sellerID uuid.UUID, productId string) ([]db.SecondChanceOffer, error) {
+	productUUID, err := uuid.Parse(productId)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	product, err := scs.db.GetProductByID(ctx, productUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	if product.SellerID != sellerID {
+		return nil, ErrNotProductOwner
+	}
+	return scs.db.GetSecondChanceOffersByProductID(ctx, productUUID)
+}
+
+// GetPendingOffers returns the offers waiting for the bidder's answer, the soonest to expire first.
+func (scs *SecondChanceService) GetPendingOffers(ctx context.Context, bidderID uuid.UUID) ([]db.SecondChanceOffer, error) {
+	return scs.db.GetPendingSecondChanceOffersByBidder(ctx, bidderID)
+}
+
+// AcceptOffer sells the product to the bidder at their bid through the same path as settlement.
+func (scs *SecondChanceService) AcceptOffer(ctx context.Context, bidderID uuid.UUID, offerId string) (db.Product, error) {
+	var sold db.Product
+	err := scs.respond(ctx, bidderID, offerId, func(q db.Querier, product db.Product, offer db.SecondChanceOffer) error {
+		if !secondChanceAvailable(product) {
+			return ErrSecondChanceUnavailable
+		}
+		if _, err := q.RespondToSecondChanceOffer(ctx, db.RespondToSecondChanceOfferParams{ID: offer.ID, Status: SecondChanceAccepted}); err != nil {
+			return err
+		}
+		bidID := offer.BidID
+		var err error
+		sold, err = sellProduct(ctx, q, product, []allocation{{WinnerID: bidderID, BidID: &bidID, Quantity: 1, UnitPrice: offer.Price}}, CloseReasonSecondChance)
+		return err
+	})
+	if err != nil {
+		return db.Product{}, err
+	}
+	return sold, nil
+}
+
+// DeclineOffer turns the offer down, the next runner-up gets an offer when the seller opted in.
+func (scs *SecondChanceService) DeclineOffer(ctx context.Context, bidderID uuid.UUID, offerId string) (db.SecondChanceOffer, error) {
+	var declined db.SecondChanceOffer
+	err := scs.respond(ctx, bidderID, offerId, func(q db.Querier, product db.Product, offer db.SecondChanceOffer) error {
+		var err error
+		declined, err = closeSecondChanceOffer(ctx, q, product, offer, SecondChanceDeclined)
+		return err
+	})
+	if err != nil {
+		return db.SecondChanceOffer{}, err
+	}
+	return declined, nil
+}
+
+// respond locks the product and then the offer, in the same order as settlement and expiry,
+// checks that the offer is the bidder's and still open and runs fn.
+func (scs *SecondChanceService) respond(ctx context.Context, bidderID uuid.UUID, offerId string, fn func(q db.Querier, product db.Product, offer db.SecondChanceOffer) error) error {
+	offerUUID, err := uuid.Parse(offerId)
+	if err != nil {
+		return ErrOfferNotFound
+	}
+	existing, err := scs.db.GetSecondChanceOfferByID(ctx, offerUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrOfferNotFound
+		}
+		return err
+	}
+	if existing.BidderID != bidderID {
+		return ErrOfferNotFound
+	}
+	return scs.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := q.GetProductForUpdate(ctx, existing.ProductID)
+		if err != nil {
+			return err
+		}
+		offer, err := q.GetSecondChanceOfferForUpdate(ctx, offerUUID)
+		if err != nil {
+			return err
+		}
+		if offer.Status != SecondChancePending || !time.Now().UTC().Before(offer.ExpiresAt) {
+			return ErrOfferNotPending
+		}
+		return fn(q, product, offer)
+	})
+}
+
+// closeSecondChanceOffer ends an open offer with status and, when the seller opted in,
+// offers the product to the next runner-up for the same length of time.
+func closeSecondChanceOffer(ctx context.Context, q db.Querier, product db.Product, offer db.SecondChanceOffer, status string) (db.SecondChanceOffer, error) {
+	closed, err := q.RespondToSecondChanceOffer(ctx, db.RespondToSecondChanceOfferParams{ID: offer.ID, Status: status})
+	if err != nil {
+		return db.SecondChanceOffer{}, err
+	}
+	if !offer.AutoAdvance || !secondChanceAvailable(product) {
+		return closed, nil
+	}
+	offers, err := q.GetSecondChanceOffersByProductID(ctx, product.ID)
+	if err != nil {
+		return db.SecondChanceOffer{}, err
+	}
+	bids, err := q.GetValidBidsByProductID(ctx, product.ID)
+	if err != nil {
+		return db.SecondChanceOffer{}, err
+	}
+	candidates := runnerUps(product, bids, offers)
+	if len(candidates) == 0 {
+		return closed, nil
+	}
+	window := offer.ExpiresAt.Sub(offer.CreatedAt)
+	if _, err := createSecondChanceOffer(ctx, q, product, candidates[0], window, true); err != nil {
+		return db.SecondChanceOffer{}, err
+	}
+	return closed, nil
+}
+
+// ExpireOffers expires every open offer past its deadline and returns how many were expired.
+func (scs *SecondChanceService) ExpireOffers(ctx context.Context) (int, error) {
+	expiredBefore := time.Now().UTC()
+	return processDue(secondChanceExpiryBatch, func(skip []uuid.UUID, pageLimit int32) ([]db.SecondChanceOffer, error) {
+		return scs.db.GetExpiredSecondChanceOffers(ctx, db.GetExpiredSecondChanceOffersParams{
+			ExpiredBefore: expiredBefore,
+			SkipIds:       skip,
+			PageLimit:     pageLimit,
+		})
+	}, func(offer db.SecondChanceOffer) uuid.UUID {
+		return offer.ID
+	}, func(offer db.SecondChanceOffer) error {
+		err := scs.db.ExecTx(ctx, func(q db.Querier) error {
+			product, err := q.GetProductForUpdate(ctx, offer.ProductID)
+			if err != nil {
+				return err
+			}
+			offer, err := q.GetSecondChanceOfferForUpdate(ctx, offer.ID)
+			if err != nil {
+				return err
+			}
+			if offer.Status != SecondChancePending || time.Now().UTC().Before(offer.ExpiresAt) {
+				return nil
+			}
+			_, err = closeSecondChanceOffer(ctx, q, product, offer, SecondChanceExpired)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to expire offer %s: %w", offer.ID, err)
+		}
+		return nil
+	})
+}
@@ -1,33 +1,143 @@
 package service
 
 import (
+	"context"
+
+	"github.com/itsDrac/e-auc/internal/cache"
 	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/itsDrac/e-auc/internal/jobs"
+	"github.com/itsDrac/e-auc/internal/mailer"
+	"github.com/itsDrac/e-auc/internal/payments"
 	"github.com/itsDrac/e-auc/internal/storage"
 )
 
+// Worker is a background process whose lifecycle is managed by the server.
+// Run must block until the given context is cancelled.
+type Worker interface {
+	Run(ctx context.Context)
+}
+
 type Services struct {
 	UserService    UserServicer
 	AuthService    AuthServicer
 	ProductService ProductServicer
+	WebhookService WebhookServicer
+	AdminService   AdminServicer
+	OrderService   OrderServicer
+	// Second-chance offers of unsold items to runner-up bidders
+	SecondChanceService SecondChanceServicer
+	// Offers and counter-offers on fixed-price listings
+	OfferService OfferServicer
+	// Seller blocklists and access to private listings
+	AccessService AccessServicer
+	// User wallets on the double-entry ledger
+	WalletService WalletServicer
+	// Invoices of orders, paid through the payment provider
+	InvoiceService InvoiceServicer
+	// Address books of buyers, which orders ship to
+	AddressService AddressServicer
+	// Feedback between the parties of orders and the reputation built from it
+	FeedbackService FeedbackServicer
+	// Buyer-seller conversations about listings and orders
+	MessageService MessageServicer
 }
 
-func NewServices(db db.Querier, s storage.Storager) (*Services, error) {
-	authService, err := NewAuthService(db)
+func NewServices(store db.Store, s storage.Storager, provider payments.PaymentProvider, bus *events.Bus, queue *jobs.Queue, c cache.Cacher, m mailer.Mailer) (*Services, error) {
+	authService, err := NewAuthService(store)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookService, err := NewWebhookService(store)
+	if err != nil {
+		return nil, err
+	}
+	productService, err := NewProductService(store, s)
+	if err != nil {
+		return nil, err
+	}
+
+	adminService, err := NewAdminService(store)
+	if err != nil {
+		return nil, err
+	}
+
+	orderService, err := NewOrderService(store)
+	if err != nil {
+		return nil, err
+	}
+
+	secondChanceService, err := NewSecondChanceService(store)
+	if err != nil {
+		return nil, err
+	}
+
+	offerService, err := NewOfferService(store)
 	if err != nil {
 		return nil, err
 	}
 
-	userService, err := NewUserService(db)
+	accessService, err := NewAccessService(store)
 	if err != nil {
 		return nil, err
 	}
-	productService, err := NewProductService(db, s)
+
+	walletService, err := NewWalletService(store, provider)
 	if err != nil {
 		return nil, err
 	}
+
+	invoiceService, err := NewInvoiceService(store, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	addressService, err := NewAddressService(store)
+	if err != nil {
+		return nil, err
+	}
+
+	feedbackService, err := NewFeedbackService(store, c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Public profiles show the reputation of their user
+	userService, err := NewUserService(store, s, m, feedbackService)
+	if err != nil {
+		return nil, err
+	}
+
+	messageService, err := NewMessageService(store, s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Event consumers, fed by the outbox relay
+	bus.Subscribe(webhookService.HandleEvent, WebhookEventTypes...)
+
+	// Background job handlers
+	registerMaintenanceJobs(store, queue)
+	registerAuctionJobs(productService, queue)
+	registerSecondChanceJobs(secondChanceService, queue)
+	registerOfferJobs(offerService, queue)
+	registerInvoiceJobs(invoiceService, queue)
+
 	return &Services{
-		UserService:    userService,
-		AuthService:    authService,
-		ProductService: productService,
+		UserService:         userService,
+		AuthService:         authService,
+		ProductService:      productService,
+		WebhookService:      webhookService,
+		AdminService:        adminService,
+		OrderService:        orderService,
+		SecondChanceService: secondChanceService,
+		OfferService:        offerService,
+		AccessService:       accessService,
+		WalletService:       walletService,
+		InvoiceService:      invoiceService,
+		AddressService:      addressService,
+		FeedbackService:     feedbackService,
+		MessageService:      messageService,
 	}, err
 }
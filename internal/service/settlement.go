@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/itsDrac/e-auc/internal/jobs"
+)
+
+// JobAuctionSettlement closes auctions that are past their end time.
+const JobAuctionSettlement = "auctions.settle"
+
+const (
+	settlementInterval  = 30 * time.Second
+	settlementBatchSize = 100
+)
+
+func registerAuctionJobs(ps *ProductService, queue *jobs.Queue) {
+	queue.Register(JobAuctionSettlement, func(ctx context.Context, job jobs.Job) error {
+		_, err := ps.SettleEndedAuctions(ctx)
+		return err
+	}, jobs.Options{MaxAttempts: 3})
+	queue.Every(JobAuctionSettlement, settlementInterval)
+
+	queue.Register(JobDutchPriceDrops, func(ctx context.Context, job jobs.Job) error {
+		_, err := ps.DropDutchPrices(ctx)
+		return err
+	}, jobs.Options{MaxAttempts: 3})
+	queue.Every(JobDutchPriceDrops, dutchDropInterval)
+
+	queue.Register(JobStartScheduledListings, func(ctx context.Context, job jobs.Job) error {
+		_, err := ps.StartScheduledProducts(ctx)
+		return err
+	}, jobs.Options{MaxAttempts: 3})
+	queue.Every(JobStartScheduledListings, scheduledStartInterval)
+}
+
+// SettleEndedAuctions closes every auction past its end time and returns how many were settled.
+func (ps *ProductService) SettleEndedAuctions(ctx context.Context) (int, error) {
+	endedBefore := time.Now().UTC()
+	return processDue(settlementBatchSize, func(skip []uuid.UUID, pageLimit int32) ([]db.Product, error) {
+		return ps.db.GetAuctionsToSettle(ctx, db.GetAuctionsToSettleParams{
+			EndedBefore: endedBefore,
+			SkipIds:     skip,
+			PageLimit:   pageLimit,
+		})
+	}, func(product db.Product) uuid.UUID {
+		return product.ID
+	}, func(product db.Product) error {
+		if err := ps.settleAuction(ctx, product.ID); err != nil {
+			return fmt.Errorf("failed to settle product %s: %w", product.ID, err)
+		}
+		return nil
+	})
+}
+
+// settleAuction awards the product to the best valid bid at the clearing price of its format,
+// or its units to the best bids for multi-unit products, and closes it unsold when there are
+// no bids or the reserve was not met, releasing every deposit held for it.
+// A product that was closed or extended in the meantime is left alone.
+func (ps *ProductService) settleAuction(ctx context.Context, productID uuid.UUID) error {
+	return ps.db.ExecTx(ctx, func(q db.Querier) error {
+		product, err := q.GetProductForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if product.ClosedAt != nil || time.Now().UTC().Before(product.EndsAt) {
+			return nil
+		}
+
+		bids, err := q.GetValidBidsByProductID(ctx, productID)
+		if err != nil {
+			return err
+		}
+		format := formatFor(product)
+		allocs := winningAllocations(product, format, format.rank(bids))
+		if len(allocs) == 0 {
+			if err := q.CloseProduct(ctx, productID); err != nil {
+				return err
+			}
+			if _, err := settleBidHolds(ctx, q, product, nil); err != nil {
+				return err
+			}
+			return emitEvent(ctx, q, events.AuctionClosed, productID, events.AuctionClosedData{
+				ProductID: productID,
+				SellerID:  product.SellerID,
+				Reason:    CloseReasonEnded,
+			})
+		}
+		_, err = sellProduct(ctx, q, product, allocs, CloseReasonEnded)
+		return err
+	})
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// Shipping methods of a listing, mirrored by the CHECK constraints on shipping_options.method and orders.shipping_method.
+// Regional rates apply to buyers in one country, flat rates anywhere.
+const (
+	ShippingPickup   = "pickup"
+	ShippingFlatRate = "flat_rate"
+	ShippingRegional = "regional"
+)
+
+// Fulfilment statuses of an order, mirrored by the CHECK constraint on orders.fulfilment_status.
+// Orders await shipment until the seller ships them, pickup orders go straight to delivered when handed over.
+// Orders whose invoice expires are cancelled.
+const (
+	FulfilmentAwaitingShipment = "awaiting_shipment"
+	FulfilmentShipped          = "shipped"
+	FulfilmentDelivered        = "delivered"
+	FulfilmentCancelled        = "cancelled"
+)
+
+// ShippingOption is one way a listing ships. Region is the ISO 3166-1 alpha-2 country of a regional rate.
+type ShippingOption struct {
+	Method string
+	Region string
+	Cost   int64
+}
+
+// validateShippingOptions checks the shipping options of a new listing, which needs at least one, and
+// normalizes their regions. A listing has at most one option per method and region, and pickup is free.
+func validateShippingOptions(options []ShippingOption) ([]ShippingOption, error) {
+	if len(options) == 0 {
+		return nil, ErrInvalidShippingOptions
+	}
+	seen := map[string]bool{}
+	normalized := make([]ShippingOption, 0, len(options))
+	for _, o := range options {
+		o.Region = strings.ToUpper(strings.TrimSpace(o.Region))
+		switch o.Method {
+		case ShippingPickup:
+			if o.Cost != 0 || o.Region != "" {
+				return nil, ErrInvalidShippingOptions
+			}
+		case ShippingFlatRate:
+			if o.Region != "" {
+				return nil, ErrInvalidShippingOptions
+			}
+		case ShippingRegional:
+			if !isCountryCode(o.Region) {
+				return nil, ErrInvalidShippingOptions
+			}
+		default:
+			return nil, ErrInvalidShippingOptions
+		}
+		if o.Cost < 0 || seen[o.Method+":"+o.Region] {
+			return nil, ErrInvalidShippingOptions
+		}
+		seen[o.Method+":"+o.Region] = true
+		normalized = append(normalized, o)
+	}
+	return normalized, nil
+}
+
+// isCountryCode reports whether code looks like an upper-case ISO 3166-1 alpha-2 code.
+func isCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// insertShippingOptions stores the shipping options of a new listing.
+func insertShippingOptions(ctx context.Context, q db.Querier, productID uuid.UUID, options []ShippingOption) error {
+	for _, o := range options {
+		arg := db.CreateShippingOptionParams{
+			ProductID: productID,
+			Method:    o.Method,
+			Cost:      o.Cost,
+		}
+		if o.Region != "" {
+			region := o.Region
+			arg.Region = &region
+		}
+		if _, err := q.CreateShippingOption(ctx, arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shippingOptionsOf returns the stored shipping options of a listing, to carry them over to its relist.
+func shippingOptionsOf(ctx context.Context, q db.Querier, productID uuid.UUID) ([]ShippingOption, error) {
+	rows, err := q.GetShippingOptionsByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	options := make([]ShippingOption, 0, len(rows))
+	for _, row := range rows {
+		o := ShippingOption{Method: row.Method, Cost: row.Cost}
+		if row.Region != nil {
+			o.Region = *row.Region
+		}
+		options = append(options, o)
+	}
+	return options, nil
+}
+
+// delivers reports whether the option ships to address. Pickup needs no address.
+func delivers(option db.ShippingOption, address *db.Address) bool {
+	switch option.Method {
+	case ShippingPickup:
+		return true
+	case ShippingFlatRate:
+		return address != nil
+	case ShippingRegional:
+		return address != nil && option.Region != nil && *option.Region == address.Country
+	}
+	return false
+}
+
+// shippingChoice is how an order ships and where to. Method is nil while the buyer has not chosen,
+// Address is nil for pickup and until the buyer gives one.
+type shippingChoice struct {
+	Method  *string
+	Cost    int64
+	Address *db.Address
+}
+
+// defaultShipping picks how an order ships at settlement: the cheapest option that delivers to the buyer's
+// default address, otherwise local pickup. Buyers without an address get the flat rate, which does not
+// depend on where it ships, and add their address before the order ships. When nothing applies the buyer
+// chooses before paying. options are ordered by cost.
+func defaultShipping(ctx context.Context, q db.Querier, options []db.ShippingOption, buyerID uuid.UUID) (shippingChoice, error) {
+	address, err := q.GetDefaultAddress(ctx, buyerID)
+	if err != nil && err != pgx.ErrNoRows {
+		return shippingChoice{}, err
+	}
+	hasAddress := err == nil
+	for _, o := range options {
+		switch {
+		case o.Method == ShippingPickup:
+		case hasAddress && delivers(o, &address):
+			return shippingChoice{Method: &o.Method, Cost: o.Cost, Address: &address}, nil
+		case !hasAddress && o.Method == ShippingFlatRate:
+			return shippingChoice{Method: &o.Method, Cost: o.Cost}, nil
+		}
+	}
+	for _, o := range options {
+		if o.Method == ShippingPickup {
+			return shippingChoice{Method: &o.Method}, nil
+		}
+	}
+	return shippingChoice{}, nil
+}
+
+// setOrderShipping records how the order ships, copying the address so later edits of the address book
+// leave the order alone.
+func setOrderShipping(ctx context.Context, q db.Querier, orderID uuid.UUID, choice shippingChoice) (db.Order, error) {
+	arg := db.UpdateOrderShippingParams{
+		ID:             orderID,
+		ShippingMethod: choice.Method,
+		ShippingCost:   choice.Cost,
+	}
+	if a := choice.Address; a != nil {
+		arg.ShipToName = &a.Name
+		arg.ShipToLine1 = &a.Line1
+		arg.ShipToLine2 = a.Line2
+		arg.ShipToCity = &a.City
+		arg.ShipToState = a.State
+		arg.ShipToPostalCode = &a.PostalCode
+		arg.ShipToCountry = &a.Country
+	}
+	return q.UpdateOrderShipping(ctx, arg)
+}
@@ -2,24 +2,70 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/mailer"
+	"github.com/itsDrac/e-auc/internal/storage"
+	"github.com/itsDrac/e-auc/pkg/utils"
+	"github.com/jackc/pgx/v5"
 )
 
+const (
+	profileImageBucket  = "profile-images"
+	maxFeaturedListings = 6
+	// A new email has to be confirmed within this many hours
+	defaultEmailVerificationHours = 24
+)
+
+// PublicProfile is what anyone can see of a user: no email or other account details. The listings are the
+// seller's public ones, Featured in the order the seller chose and Active and Sold newest first.
+type PublicProfile struct {
+	ID         uuid.UUID    `json:"id"`
+	Username   string       `json:"username"`
+	JoinedAt   time.Time    `json:"joined_at"`
+	Bio        *string      `json:"bio"`
+	AvatarURL  *string      `json:"avatar_url"`
+	BannerURL  *string      `json:"banner_url"`
+	Reputation Reputation   `json:"reputation"`
+	Featured   []db.Product `json:"featured_listings"`
+	Active     []db.Product `json:"active_listings"`
+	Sold       []db.Product `json:"sold_listings"`
+}
+
 type UserServicer interface {
 	GetUserByID(ctx context.Context, id string) (db.User, error)
+	GetPublicProfile(ctx context.Context, username string, limit uint, offset uint) (PublicProfile, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, username *string, bio *string) (db.User, error)
+	RequestEmailChange(ctx context.Context, userID uuid.UUID, email string) error
+	ConfirmEmailChange(ctx context.Context, userID uuid.UUID, token string) (db.User, error)
+	UploadAvatar(ctx context.Context, userID uuid.UUID, filename string, data []byte) (string, error)
+	UploadBanner(ctx context.Context, userID uuid.UUID, filename string, data []byte) (string, error)
+	SetFeaturedListings(ctx context.Context, sellerID uuid.UUID, productIds []string) ([]db.Product, error)
 }
 
 type UserService struct {
-	db db.Querier // We'll be using code genrated by sqlc here
+	db                db.Store // We'll be using code genrated by sqlc here
+	storage           storage.Storager
+	mailer            mailer.Mailer
+	feedback          FeedbackServicer
+	verificationValid time.Duration
 }
 
-func NewUserService(db db.Querier) (*UserService, error) {
+func NewUserService(db db.Store, s storage.Storager, m mailer.Mailer, feedback FeedbackServicer) (*UserService, error) {
 	return &UserService{
-		db: db,
+		db:                db,
+		storage:           s,
+		mailer:            m,
+		feedback:          feedback,
+		verificationValid: time.Duration(utils.GetIntEnv("EMAIL_VERIFICATION_HOURS", defaultEmailVerificationHours)) * time.Hour,
 	}, nil
 }
 
@@ -39,3 +85,321 @@ func (us *UserService) GetUserByID(ctx context.Context, id string) (db.User, err
 
 	return user, nil
 }
+
+// GetPublicProfile returns the profile and storefront of the user with the given username.
+// limit and offset page through the active and sold listings.
+func (us *UserService) GetPublicProfile(ctx context.Context, username string, limit uint, offset uint) (PublicProfile, error) {
+	user, err := us.db.GetUserByUsername(ctx, username)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return PublicProfile{}, ErrUserNotFound
+		}
+		return PublicProfile{}, err
+	}
+
+	profile := PublicProfile{
+		ID:       user.ID,
+		Username: user.Username,
+		JoinedAt: user.CreatedAt,
+		Bio:      user.Bio,
+	}
+	if profile.AvatarURL, err = us.imageURL(user.AvatarKey); err != nil {
+		return PublicProfile{}, err
+	}
+	if profile.BannerURL, err = us.imageURL(user.BannerKey); err != nil {
+		return PublicProfile{}, err
+	}
+	if profile.Reputation, err = us.feedback.GetReputation(ctx, user.ID); err != nil {
+		return PublicProfile{}, err
+	}
+	if profile.Featured, err = us.db.GetFeaturedListings(ctx, user.ID); err != nil {
+		return PublicProfile{}, err
+	}
+	// Nobody is invited as uuid.Nil, so only public listings are returned
+	if profile.Active, err = us.db.GetProductsBySellerID(ctx, db.GetProductsBySellerIDParams{
+		SellerID: user.ID,
+		Statuses: []string{StatusLive},
+		ViewerID: uuid.Nil,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	}); err != nil {
+		return PublicProfile{}, err
+	}
+	if profile.Sold, err = us.db.GetSoldProductsBySellerID(ctx, db.GetSoldProductsBySellerIDParams{
+		SellerID: user.ID,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	}); err != nil {
+		return PublicProfile{}, err
+	}
+	return profile, nil
+}
+
+// UpdateProfile changes the username and bio of a user, omitted fields are left alone. An empty bio clears it.
+func (us *UserService) UpdateProfile(ctx context.Context, userID uuid.UUID, username *string, bio *string) (db.User, error) {
+	var updated db.User
+	err := us.db.ExecTx(ctx, func(q db.Querier) error {
+		user, err := q.GetUserForUpdate(ctx, userID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		arg := db.UpdateUserProfileParams{
+			ID:       user.ID,
+			Username: user.Username,
+			Bio:      user.Bio,
+		}
+		if username != nil && *username != user.Username {
+			if err := checkUsernameFree(ctx, q, *username); err != nil {
+				return err
+			}
+			arg.Username = *username
+		}
+		if bio != nil {
+			arg.Bio = bio
+			if *bio == "" {
+				arg.Bio = nil
+			}
+		}
+		updated, err = q.UpdateUserProfile(ctx, arg)
+		return err
+	})
+	if err != nil {
+		return db.User{}, err
+	}
+	return updated, nil
+}
+
+// RequestEmailChange keeps email as the pending email of the user and mails it a verification token.
+// The email of the account only changes once the token is confirmed, a new request replaces the last one.
+func (us *UserService) RequestEmailChange(ctx context.Context, userID uuid.UUID, email string) error {
+	user, err := us.db.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if email == user.Email {
+		return ErrEmailUnchanged
+	}
+	if err := checkEmailFree(ctx, us.db, email); err != nil {
+		return err
+	}
+
+	token, err := newVerificationToken()
+	if err != nil {
+		return err
+	}
+	hash := hashVerificationToken(token)
+	expiresAt := time.Now().Add(us.verificationValid)
+	if _, err := us.db.SetPendingEmail(ctx, db.SetPendingEmailParams{
+		ID:                         user.ID,
+		PendingEmail:               &email,
+		EmailVerificationTokenHash: &hash,
+		EmailVerificationExpiresAt: &expiresAt,
+	}); err != nil {
+		return err
+	}
+
+	return us.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Hi %s,\n\nconfirm this address for your account with the code below. It expires at %s.\n\n%s",
+			user.Username, expiresAt.UTC().Format(time.RFC1123), token),
+	})
+}
+
+// ConfirmEmailChange makes the pending email the email of the user when the token matches and has not expired.
+func (us *UserService) ConfirmEmailChange(ctx context.Context, userID uuid.UUID, token string) (db.User, error) {
+	var confirmed db.User
+	err := us.db.ExecTx(ctx, func(q db.Querier) error {
+		user, err := q.GetUserForUpdate(ctx, userID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		if user.PendingEmail == nil || user.EmailVerificationTokenHash == nil || user.EmailVerificationExpiresAt == nil {
+			return ErrInvalidVerificationToken
+		}
+		hash := hashVerificationToken(token)
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(*user.EmailVerificationTokenHash)) != 1 {
+			return ErrInvalidVerificationToken
+		}
+		if time.Now().After(*user.EmailVerificationExpiresAt) {
+			return ErrInvalidVerificationToken
+		}
+		// Another account may have taken the address since the change was requested
+		if err := checkEmailFree(ctx, q, *user.PendingEmail); err != nil {
+			return err
+		}
+		confirmed, err = q.ConfirmPendingEmail(ctx, user.ID)
+		return err
+	})
+	if err != nil {
+		return db.User{}, err
+	}
+	return confirmed, nil
+}
+
+// UploadAvatar stores the image as the avatar of the user and returns its URL.
+func (us *UserService) UploadAvatar(ctx context.Context, userID uuid.UUID, filename string, data []byte) (string, error) {
+	user, err := us.db.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", ErrUserNotFound
+		}
+		return "", err
+	}
+	key, err := us.saveProfileImage("avatars/"+filename, data)
+	if err != nil {
+		return "", err
+	}
+	if _, err := us.db.SetUserAvatar(ctx, db.SetUserAvatarParams{ID: user.ID, AvatarKey: &key}); err != nil {
+		return "", err
+	}
+	us.deleteProfileImage(user.ID, user.AvatarKey)
+	return us.storage.GetFileUrl(profileImageBucket, key)
+}
+
+// UploadBanner stores the image as the storefront banner of the user and returns its URL.
+func (us *UserService) UploadBanner(ctx context.Context, userID uuid.UUID, filename string, data []byte) (string, error) {
+	user, err := us.db.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", ErrUserNotFound
+		}
+		return "", err
+	}
+	key, err := us.saveProfileImage("banners/"+filename, data)
+	if err != nil {
+		return "", err
+	}
+	if _, err := us.db.SetUserBanner(ctx, db.SetUserBannerParams{ID: user.ID, BannerKey: &key}); err != nil {
+		return "", err
+	}
+	us.deleteProfileImage(user.ID, user.BannerKey)
+	return us.storage.GetFileUrl(profileImageBucket, key)
+}
+
+// SetFeaturedListings replaces the featured listings of a seller's storefront with the given products, in order.
+// Only the seller's own public listings that are not drafts can be featured.
+func (us *UserService) SetFeaturedListings(ctx context.Context, sellerID uuid.UUID, productIds []string) ([]db.Product, error) {
+	if len(productIds) > maxFeaturedListings {
+		return nil, ErrTooManyFeaturedListings
+	}
+
+	var featured []db.Product
+	err := us.db.ExecTx(ctx, func(q db.Querier) error {
+		if err := q.DeleteFeaturedListings(ctx, sellerID); err != nil {
+			return err
+		}
+		seen := make(map[uuid.UUID]bool, len(productIds))
+		for _, productId := range productIds {
+			productUUID, err := uuid.Parse(productId)
+			if err != nil {
+				return ErrProductNotFound
+			}
+			if seen[productUUID] {
+				continue
+			}
+			seen[productUUID] = true
+
+			product, err := q.GetProductByID(ctx, productUUID)
+			if err != nil {
+				if err == pgx.ErrNoRows {
+					return ErrProductNotFound
+				}
+				return err
+			}
+			if product.SellerID != sellerID || product.Visibility != VisibilityPublic || product.Status == StatusDraft {
+				return ErrListingNotFeaturable
+			}
+			if err := q.AddFeaturedListing(ctx, db.AddFeaturedListingParams{
+				SellerID:  sellerID,
+				ProductID: product.ID,
+				Position:  int32(len(seen) - 1),
+			}); err != nil {
+				return err
+			}
+		}
+		var err error
+		featured, err = q.GetFeaturedListings(ctx, sellerID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return featured, nil
+}
+
+func (us *UserService) saveProfileImage(key string, data []byte) (string, error) {
+	info, err := us.storage.SaveImage(profileImageBucket, key, data)
+	if err != nil {
+		return "", err
+	}
+	return info.Key, nil
+}
+
+// deleteProfileImage removes a replaced avatar or banner from storage, a failure only leaves it behind.
+func (us *UserService) deleteProfileImage(userID uuid.UUID, key *string) {
+	if key == nil {
+		return
+	}
+	if err := us.storage.DeleteFile(profileImageBucket, *key); err != nil {
+		slog.Warn("[Storage] failed to delete replaced profile image", "user_id", userID, "key", *key, "error", err)
+	}
+}
+
+// imageURL returns the URL of a stored profile image, nil when there is none.
+func (us *UserService) imageURL(key *string) (*string, error) {
+	if key == nil {
+		return nil, nil
+	}
+	url, err := us.storage.GetFileUrl(profileImageBucket, *key)
+	if err != nil {
+		return nil, err
+	}
+	return &url, nil
+}
+
+// checkUsernameFree returns ErrUsernameTaken when another user has the username.
+func checkUsernameFree(ctx context.Context, q db.Querier, username string) error {
+	if _, err := q.GetUserByUsername(ctx, username); err != pgx.ErrNoRows {
+		if err == nil {
+			return ErrUsernameTaken
+		}
+		return err
+	}
+	return nil
+}
+
+// checkEmailFree returns ErrEmailTaken when another user has the email.
+func checkEmailFree(ctx context.Context, q db.Querier, email string) error {
+	if _, err := q.GetUserByEmail(ctx, email); err != pgx.ErrNoRows {
+		if err == nil {
+			return ErrEmailTaken
+		}
+		return err
+	}
+	return nil
+}
+
+func newVerificationToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate verification token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashVerificationToken returns the hex encoded SHA-256 of the token, which is what gets stored.
+func hashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
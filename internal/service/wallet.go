@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/payments"
+	"github.com/itsDrac/e-auc/pkg/money"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ledger account kinds, mirrored by the CHECK constraint on accounts.kind.
+// Users own an available and a held account, the external, escrow, receivable and revenue accounts
+// belong to the platform.
+const (
+	AccountAvailable  = "available"
+	AccountHeld       = "held"
+	AccountExternal   = "external"
+	AccountEscrow     = "escrow"
+	AccountReceivable = "receivable"
+	AccountRevenue    = "revenue"
+)
+
+// systemAccounts are the accounts of the platform, one of each kind per currency.
+var systemAccounts = []string{AccountExternal, AccountEscrow, AccountReceivable, AccountRevenue}
+
+// Journal entry kinds, mirrored by the CHECK constraint on journal_entries.kind.
+const (
+	EntryDeposit      = "deposit"
+	EntryWithdrawal   = "withdrawal"
+	EntryHold         = "hold"
+	EntryRelease      = "release"
+	EntryCapture      = "capture"
+	EntryCommission   = "commission"
+	EntryBuyerPremium = "buyer_premium"
+	EntryFeeReversal  = "fee_reversal"
+	EntryForfeit      = "forfeit"
+)
+
+// Posting directions. User accounts are liabilities of the platform, credits raise their balance.
+const (
+	DirectionDebit  = "debit"
+	DirectionCredit = "credit"
+)
+
+// entryAccounts holds the account debited and the account credited by each kind of journal entry.
+var entryAccounts = map[string][2]string{
+	EntryDeposit:    {AccountExternal, AccountAvailable},
+	EntryWithdrawal: {AccountAvailable, AccountExternal},
+	EntryHold:       {AccountAvailable, AccountHeld},
+	EntryRelease:    {AccountHeld, AccountAvailable},
+	EntryCapture:    {AccountHeld, AccountEscrow},
+	// Fees are earned at settlement, before the buyer pays
+	EntryCommission:   {AccountReceivable, AccountRevenue},
+	EntryBuyerPremium: {AccountReceivable, AccountRevenue},
+	EntryFeeReversal:  {AccountRevenue, AccountReceivable},
+	// Deposits of winners who never pay go to the seller
+	EntryForfeit: {AccountEscrow, AccountAvailable},
+}
+
+// Wallet is a user's balance, derived from the postings on their accounts.
+// Held funds are reserved and cannot be spent or withdrawn until they are released.
+type Wallet struct {
+	Available int64
+	Held      int64
+}
+
+// ledgerEntry is a money movement of one user, Key makes posting it idempotent.
+// Entries without a Currency are in the wallet currency, money.DefaultCurrency. Only entries between
+// platform accounts, like fees, may be in another currency.
+type ledgerEntry struct {
+	Kind        string
+	UserID      uuid.UUID
+	Amount      int64
+	Currency    string
+	Key         string
+	ReferenceID *uuid.UUID
+	Description *string
+}
+
+type WalletServicer interface {
+	GetWallet(context.Context, uuid.UUID) (Wallet, error)
+	GetTransactions(context.Context, uuid.UUID, uint, uint) ([]db.JournalEntry, error)
+	Deposit(context.Context, uuid.UUID, int64, string, string) (db.JournalEntry, error)
+	Withdraw(context.Context, uuid.UUID, int64, string) (db.JournalEntry, error)
+	Hold(context.Context, uuid.UUID, int64, string, *uuid.UUID) (db.JournalEntry, error)
+	Release(context.Context, uuid.UUID, int64, string, *uuid.UUID) (db.JournalEntry, error)
+}
+
+type WalletService struct {
+	db       db.Store
+	provider payments.PaymentProvider
+}
+
+func NewWalletService(db db.Store, provider payments.PaymentProvider) (*WalletService, error) {
+	return &WalletService{
+		db:       db,
+		provider: provider,
+	}, nil
+}
+
+// GetWallet returns the available and held balance of the user, zero for users without ledger accounts yet.
+func (ws *WalletService) GetWallet(ctx context.Context, userID uuid.UUID) (Wallet, error) {
+	balances, err := ws.db.GetUserAccountBalances(ctx, &userID)
+	if err != nil {
+		return Wallet{}, err
+	}
+	var wallet Wallet
+	for _, balance := range balances {
+		switch balance.Kind {
+		case AccountAvailable:
+			wallet.Available = balance.Balance
+		case AccountHeld:
+			wallet.Held = balance.Balance
+		}
+	}
+	return wallet, nil
+}
+
+// GetTransactions lists the journal entries of the user, newest first.
+func (ws *WalletService) GetTransactions(ctx context.Context, userID uuid.UUID, limit uint, offset uint) ([]db.JournalEntry, error) {
+	entries, err := ws.db.GetJournalEntriesByUserID(ctx, db.GetJournalEntriesByUserIDParams{
+		UserID: userID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []db.JournalEntry{}
+	}
+	return entries, nil
+}
+
+// Deposit charges amount to the user's payment method through the payment provider and adds it to their
+// available funds once the charge went through. The provider is charged under the deposit's key, so a retry
+// after a failed posting is charged once; a key already posted returns its entry without charging again.
+// Declined payments return ErrPaymentDeclined.
+func (ws *WalletService) Deposit(ctx context.Context, userID uuid.UUID, amount int64, key string, paymentToken string) (db.JournalEntry, error) {
+	e := ledgerEntry{Kind: EntryDeposit, UserID: userID, Amount: amount, Currency: money.DefaultCurrency, Key: strings.TrimSpace(key)}
+	if e.Amount <= 0 {
+		return db.JournalEntry{}, ErrInvalidAmount
+	}
+	if e.Key == "" {
+		return db.JournalEntry{}, ErrIdempotencyKeyRequired
+	}
+	existing, err := ws.db.GetJournalEntryByKey(ctx, entryKey(e))
+	if err == nil {
+		return replayedEntry(existing, e)
+	}
+	if err != pgx.ErrNoRows {
+		return db.JournalEntry{}, err
+	}
+
+	charge, err := ws.provider.Charge(ctx, payments.ChargeRequest{
+		IdempotencyKey: entryKey(e),
+		Amount:         e.Amount,
+		Currency:       e.Currency,
+		Token:          paymentToken,
+		Description:    "Wallet deposit",
+	})
+	if err != nil {
+		if errors.Is(err, payments.ErrPaymentDeclined) {
+			return db.JournalEntry{}, ErrPaymentDeclined
+		}
+		return db.JournalEntry{}, fmt.Errorf("failed to charge deposit: %w", err)
+	}
+	description := "Payment " + charge.Reference
+	e.Description = &description
+	return ws.post(ctx, e)
+}
+
+// Withdraw takes amount out of the user's available funds.
+func (ws *WalletService) Withdraw(ctx context.Context, userID uuid.UUID, amount int64, key string) (db.JournalEntry, error) {
+	return ws.post(ctx, ledgerEntry{Kind: EntryWithdrawal, UserID: userID, Amount: amount, Key: key})
+}
+
+// Hold reserves amount of the user's available funds for what referenceID points at.
+func (ws *WalletService) Hold(ctx context.Context, userID uuid.UUID, amount int64, key string, referenceID *uuid.UUID) (db.JournalEntry, error) {
+	return ws.post(ctx, ledgerEntry{Kind: EntryHold, UserID: userID, Amount: amount, Key: key, ReferenceID: referenceID})
+}
+
+// Release returns amount of the user's held funds to their available funds.
+func (ws *WalletService) Release(ctx context.Context, userID uuid.UUID, amount int64, key string, referenceID *uuid.UUID) (db.JournalEntry, error) {
+	return ws.post(ctx, ledgerEntry{Kind: EntryRelease, UserID: userID, Amount: amount, Key: key, ReferenceID: referenceID})
+}
+
+func (ws *WalletService) post(ctx context.Context, e ledgerEntry) (db.JournalEntry, error) {
+	var entry db.JournalEntry
+	err := ws.db.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		entry, err = postEntry(ctx, q, e)
+		return err
+	})
+	if err != nil {
+		return db.JournalEntry{}, err
+	}
+	return entry, nil
+}
+
+// postEntry records the entry as a debit and an equal credit. The debited user account must cover the amount.
+// Posting a key again returns the entry recorded the first time, or ErrIdempotencyKeyReused when it differs.
+// The platform accounts posted to are those of the entry's currency, so amounts in different currencies
+// never share a balance.
+//
+// Taking the user's accounts locks them, always available before held, so the entries of a user are
+// serialized and the balance checked here cannot change before the commit.
+func postEntry(ctx context.Context, q db.Querier, e ledgerEntry) (db.JournalEntry, error) {
+	if e.Amount <= 0 {
+		return db.JournalEntry{}, ErrInvalidAmount
+	}
+	e.Key = strings.TrimSpace(e.Key)
+	if e.Key == "" {
+		return db.JournalEntry{}, ErrIdempotencyKeyRequired
+	}
+	if e.Currency == "" {
+		e.Currency = money.DefaultCurrency
+	}
+	if e.Currency != money.DefaultCurrency {
+		for _, kind := range entryAccounts[e.Kind] {
+			if !slices.Contains(systemAccounts, kind) {
+				return db.JournalEntry{}, ErrWalletCurrency
+			}
+		}
+	}
+	key := entryKey(e)
+
+	accounts := map[string]db.Account{}
+	for _, kind := range []string{AccountAvailable, AccountHeld} {
+		account, err := q.EnsureUserAccount(ctx, db.EnsureUserAccountParams{UserID: &e.UserID, Kind: kind})
+		if err != nil {
+			return db.JournalEntry{}, err
+		}
+		accounts[kind] = account
+	}
+	for _, kind := range entryAccounts[e.Kind] {
+		if !slices.Contains(systemAccounts, kind) {
+			continue
+		}
+		account, err := systemAccount(ctx, q, kind, e.Currency)
+		if err != nil {
+			return db.JournalEntry{}, err
+		}
+		accounts[kind] = account
+	}
+
+	existing, err := q.GetJournalEntryByKey(ctx, key)
+	if err == nil {
+		return replayedEntry(existing, e)
+	}
+	if err != pgx.ErrNoRows {
+		return db.JournalEntry{}, err
+	}
+
+	from, to := accounts[entryAccounts[e.Kind][0]], accounts[entryAccounts[e.Kind][1]]
+	if from.UserID != nil {
+		balance, err := q.GetAccountBalance(ctx, from.ID)
+		if err != nil {
+			return db.JournalEntry{}, err
+		}
+		if balance < e.Amount {
+			return db.JournalEntry{}, ErrInsufficientFunds
+		}
+	}
+
+	entry, err := q.CreateJournalEntry(ctx, db.CreateJournalEntryParams{
+		IdempotencyKey: key,
+		Kind:           e.Kind,
+		UserID:         e.UserID,
+		Amount:         e.Amount,
+		ReferenceID:    e.ReferenceID,
+		Description:    e.Description,
+		Currency:       e.Currency,
+	})
+	if err != nil {
+		return db.JournalEntry{}, err
+	}
+	postings := []db.CreatePostingParams{
+		{EntryID: entry.ID, AccountID: from.ID, Direction: DirectionDebit, Amount: e.Amount},
+		{EntryID: entry.ID, AccountID: to.ID, Direction: DirectionCredit, Amount: e.Amount},
+	}
+	for _, posting := range postings {
+		if err := q.CreatePosting(ctx, posting); err != nil {
+			return db.JournalEntry{}, err
+		}
+	}
+	return entry, nil
+}
+
+// systemAccount returns the platform account of kind in currency, creating it for a currency seen the first time.
+func systemAccount(ctx context.Context, q db.Querier, kind string, currency string) (db.Account, error) {
+	arg := db.GetSystemAccountParams{Kind: kind, Currency: currency}
+	account, err := q.GetSystemAccount(ctx, arg)
+	if err != pgx.ErrNoRows {
+		return account, err
+	}
+	if err := q.CreateSystemAccount(ctx, db.CreateSystemAccountParams{Kind: kind, Currency: currency}); err != nil {
+		return db.Account{}, err
+	}
+	return q.GetSystemAccount(ctx, arg)
+}
+
+// replayedEntry returns the entry already recorded under the key of e, unless e asks for a different movement.
+// entryKey scopes the key of the entry to its kind and user.
+func entryKey(e ledgerEntry) string {
+	return e.Kind + ":" + e.UserID.String() + ":" + e.Key
+}
+
+func replayedEntry(existing db.JournalEntry, e ledgerEntry) (db.JournalEntry, error) {
+	if existing.Amount != e.Amount || existing.Currency != e.Currency || !sameReference(existing.ReferenceID, e.ReferenceID) {
+		return db.JournalEntry{}, ErrIdempotencyKeyReused
+	}
+	return existing, nil
+}
+
+func sameReference(a *uuid.UUID, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,453 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/itsDrac/e-auc/internal/database"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/itsDrac/e-auc/pkg/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// WebhookEventTypes lists every event type a seller can subscribe an endpoint to.
+var WebhookEventTypes = []string{events.BidPlaced, events.AuctionClosed, events.ItemSold, events.InvoicePaid, events.InvoiceExpired, events.OrderShipped, events.OrderDelivered}
+
+// Headers sent along with every webhook delivery.
+const (
+	WebhookSignatureHeader = "X-Eauc-Signature"
+	WebhookTimestampHeader = "X-Eauc-Timestamp"
+	WebhookEventHeader     = "X-Eauc-Event"
+	WebhookDeliveryHeader  = "X-Eauc-Delivery"
+)
+
+const (
+	webhookStatusPending    = "pending"
+	webhookStatusSucceeded  = "succeeded"
+	webhookStatusFailed     = "failed"
+	webhookBatchSize        = 20
+	webhookRequestTimeout   = 10 * time.Second
+	webhookBaseBackoff      = 30 * time.Second
+	webhookMaxBackoff       = 6 * time.Hour
+	webhookDispatchInterval = 2 * time.Second
+)
+
+// WebhookPayload is the envelope posted to seller endpoints.
+// ID is the event ID, it stays the same across retries and redeliveries.
+type WebhookPayload struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+type WebhookServicer interface {
+	RegisterEndpoint(ctx context.Context, sellerID uuid.UUID, url string, eventTypes []string) (db.WebhookEndpoint, error)
+	GetEndpoints(ctx context.Context, sellerID uuid.UUID) ([]db.WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, sellerID uuid.UUID, endpointId string) error
+	GetDeliveries(ctx context.Context, sellerID uuid.UUID, endpointId string, limit uint, offset uint) ([]db.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, sellerID uuid.UUID, deliveryId string) (db.WebhookDelivery, []db.WebhookDeliveryAttempt, error)
+	Redeliver(ctx context.Context, sellerID uuid.UUID, deliveryId string) (db.WebhookDelivery, error)
+	Enqueue(ctx context.Context, sellerID uuid.UUID, e events.Event) error
+	HandleEvent(ctx context.Context, e events.Event) error
+	DispatchDue(ctx context.Context) (int, error)
+}
+
+type WebhookService struct {
+	db     db.Querier
+	client *http.Client
+	// Loopback endpoints are only allowed for local development and tests
+	allowLoopback bool
+}
+
+func NewWebhookService(db db.Querier) (*WebhookService, error) {
+	ws := &WebhookService{
+		db:            db,
+		allowLoopback: utils.GetEnv("WEBHOOK_ALLOW_LOOPBACK", "false") == "true",
+	}
+	// Endpoints are checked again when connecting, a host can resolve to another address than at registration
+	dialer := &net.Dialer{
+		Timeout: webhookRequestTimeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !ws.addressAllowed(ip) {
+				return ErrWebhookURLNotAllowed
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the endpoint and bypass the check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	ws.client = &http.Client{Timeout: webhookRequestTimeout, Transport: transport}
+	return ws, nil
+}
+
+// RegisterEndpoint stores a new endpoint together with a freshly generated signing secret.
+// The secret is only ever returned here, it is hidden from every other response.
+// URLs whose host resolves to a loopback, private or link-local address are refused with ErrWebhookURLNotAllowed.
+func (ws *WebhookService) RegisterEndpoint(ctx context.Context, sellerID uuid.UUID, url string, eventTypes []string) (db.WebhookEndpoint, error) {
+	if err := ws.checkEndpointURL(ctx, url); err != nil {
+		return db.WebhookEndpoint{}, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return db.WebhookEndpoint{}, err
+	}
+	return ws.db.CreateWebhookEndpoint(ctx, db.CreateWebhookEndpointParams{
+		SellerID:   sellerID,
+		Url:        url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+	})
+}
+
+func (ws *WebhookService) GetEndpoints(ctx context.Context, sellerID uuid.UUID) ([]db.WebhookEndpoint, error) {
+	return ws.db.GetWebhookEndpointsBySellerID(ctx, sellerID)
+}
+
+func (ws *WebhookService) DeleteEndpoint(ctx context.Context, sellerID uuid.UUID, endpointId string) error {
+	endpointUUID, err := uuid.Parse(endpointId)
+	if err != nil {
+		return ErrWebhookNotFound
+	}
+	deleted, err := ws.db.DeleteWebhookEndpoint(ctx, db.DeleteWebhookEndpointParams{
+		ID:       endpointUUID,
+		SellerID: sellerID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (ws *WebhookService) GetDeliveries(ctx context.Context, sellerID uuid.UUID, endpointId string, limit uint, offset uint) ([]db.WebhookDelivery, error) {
+	endpoint, err := ws.ownedEndpoint(ctx, sellerID, endpointId)
+	if err != nil {
+		return nil, err
+	}
+	return ws.db.GetWebhookDeliveriesByEndpointID(ctx, db.GetWebhookDeliveriesByEndpointIDParams{
+		EndpointID: endpoint.ID,
+		Limit:      int32(limit),
+		Offset:     int32(offset),
+	})
+}
+
+func (ws *WebhookService) GetDelivery(ctx context.Context, sellerID uuid.UUID, deliveryId string) (db.WebhookDelivery, []db.WebhookDeliveryAttempt, error) {
+	delivery, err := ws.ownedDelivery(ctx, sellerID, deliveryId)
+	if err != nil {
+		return db.WebhookDelivery{}, nil, err
+	}
+	attempts, err := ws.db.GetWebhookDeliveryAttempts(ctx, delivery.ID)
+	if err != nil {
+		return db.WebhookDelivery{}, nil, err
+	}
+	return delivery, attempts, nil
+}
+
+// Redeliver puts a delivery back on the queue so the dispatcher picks it up on its next tick.
+func (ws *WebhookService) Redeliver(ctx context.Context, sellerID uuid.UUID, deliveryId string) (db.WebhookDelivery, error) {
+	delivery, err := ws.ownedDelivery(ctx, sellerID, deliveryId)
+	if err != nil {
+		return db.WebhookDelivery{}, err
+	}
+	delivery, err = ws.db.ResetWebhookDelivery(ctx, delivery.ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.WebhookDelivery{}, ErrWebhookDeliveryInFlight
+		}
+		return db.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+// HandleEvent is subscribed to the event bus and routes product events to the seller's endpoints.
+func (ws *WebhookService) HandleEvent(ctx context.Context, e events.Event) error {
+	var data struct {
+		SellerID uuid.UUID `json:"seller_id"`
+	}
+	if err := json.Unmarshal(e.Payload, &data); err != nil {
+		return fmt.Errorf("failed to decode %s event: %w", e.Type, err)
+	}
+	if data.SellerID == uuid.Nil {
+		return nil
+	}
+	return ws.Enqueue(ctx, data.SellerID, e)
+}
+
+// Enqueue stores one delivery per active endpoint of the seller subscribed to the event type.
+// The event ID is the idempotency key, enqueuing the same event twice is a no-op.
+func (ws *WebhookService) Enqueue(ctx context.Context, sellerID uuid.UUID, e events.Event) error {
+	endpoints, err := ws.db.GetActiveWebhookEndpointsForEvent(ctx, db.GetActiveWebhookEndpointsForEventParams{
+		SellerID:  sellerID,
+		EventType: e.Type,
+	})
+	if err != nil {
+		return err
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(WebhookPayload{
+		ID:        e.ID,
+		Type:      e.Type,
+		CreatedAt: e.OccurredAt,
+		Data:      e.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	for _, endpoint := range endpoints {
+		err := ws.db.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+			EndpointID: endpoint.ID,
+			EventID:    e.ID,
+			EventType:  e.Type,
+			Payload:    body,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DispatchDue claims the deliveries whose next attempt is due and posts them to their endpoints.
+// It returns the number of deliveries that were attempted.
+func (ws *WebhookService) DispatchDue(ctx context.Context) (int, error) {
+	if _, err := ws.db.RequeueStaleWebhookDeliveries(ctx); err != nil {
+		return 0, err
+	}
+	deliveries, err := ws.db.ClaimDueWebhookDeliveries(ctx, webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, delivery := range deliveries {
+		if err := ws.deliver(ctx, delivery); err != nil {
+			slog.Error("[Webhook] failed to record delivery result", "delivery_id", delivery.ID, "error", err)
+		}
+	}
+	return len(deliveries), nil
+}
+
+func (ws *WebhookService) deliver(ctx context.Context, delivery db.WebhookDelivery) error {
+	endpoint, err := ws.db.GetWebhookEndpointByID(ctx, delivery.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	attempt := delivery.Attempts + 1
+	started := time.Now()
+	statusCode, sendErr := ws.send(ctx, endpoint, delivery)
+	duration := time.Since(started)
+
+	var responseCode *int32
+	if statusCode != 0 {
+		code := int32(statusCode)
+		responseCode = &code
+	}
+	var lastError *string
+	if sendErr != nil {
+		msg := sendErr.Error()
+		lastError = &msg
+	}
+
+	err = ws.db.CreateWebhookDeliveryAttempt(ctx, db.CreateWebhookDeliveryAttemptParams{
+		DeliveryID:   delivery.ID,
+		Attempt:      attempt,
+		ResponseCode: responseCode,
+		Error:        lastError,
+		DurationMs:   int32(duration.Milliseconds()),
+	})
+	if err != nil {
+		return err
+	}
+
+	result := db.RecordWebhookDeliveryResultParams{
+		ID:               delivery.ID,
+		LastResponseCode: responseCode,
+		LastError:        lastError,
+		NextAttemptAt:    delivery.NextAttemptAt,
+	}
+	switch {
+	case sendErr == nil:
+		now := time.Now()
+		result.Status = webhookStatusSucceeded
+		result.DeliveredAt = &now
+	case attempt >= delivery.MaxAttempts:
+		result.Status = webhookStatusFailed
+		slog.Warn("[Webhook] delivery exhausted retries", "delivery_id", delivery.ID, "endpoint_id", endpoint.ID)
+	default:
+		result.Status = webhookStatusPending
+		result.NextAttemptAt = time.Now().Add(utils.ExponentialBackoff(webhookBaseBackoff, webhookMaxBackoff, attempt))
+	}
+	return ws.db.RecordWebhookDeliveryResult(ctx, result)
+}
+
+// send posts the signed payload and treats any non 2xx response as a failure.
+func (ws *WebhookService) send(ctx context.Context, endpoint db.WebhookEndpoint, delivery db.WebhookDelivery) (int, error) {
+	if !endpoint.IsActive {
+		return 0, fmt.Errorf("endpoint is disabled")
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := ws.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (ws *WebhookService) ownedEndpoint(ctx context.Context, sellerID uuid.UUID, endpointId string) (db.WebhookEndpoint, error) {
+	endpointUUID, err := uuid.Parse(endpointId)
+	if err != nil {
+		return db.WebhookEndpoint{}, ErrWebhookNotFound
+	}
+	endpoint, err := ws.db.GetWebhookEndpointByID(ctx, endpointUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.WebhookEndpoint{}, ErrWebhookNotFound
+		}
+		return db.WebhookEndpoint{}, err
+	}
+	if endpoint.SellerID != sellerID {
+		return db.WebhookEndpoint{}, ErrWebhookNotFound
+	}
+	return endpoint, nil
+}
+
+func (ws *WebhookService) ownedDelivery(ctx context.Context, sellerID uuid.UUID, deliveryId string) (db.WebhookDelivery, error) {
+	deliveryUUID, err := uuid.Parse(deliveryId)
+	if err != nil {
+		return db.WebhookDelivery{}, ErrWebhookDeliveryNotFound
+	}
+	delivery, err := ws.db.GetWebhookDeliveryByID(ctx, deliveryUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.WebhookDelivery{}, ErrWebhookDeliveryNotFound
+		}
+		return db.WebhookDelivery{}, err
+	}
+	if _, err := ws.ownedEndpoint(ctx, sellerID, delivery.EndpointID.String()); err != nil {
+		return db.WebhookDelivery{}, ErrWebhookDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+// checkEndpointURL resolves the host of the URL and refuses it when any of its addresses is not allowed.
+func (ws *WebhookService) checkEndpointURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return ErrWebhookURLNotAllowed
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		if !ws.addressAllowed(ip) {
+			return ErrWebhookURLNotAllowed
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return ErrWebhookURLNotAllowed
+	}
+	for _, addr := range addrs {
+		if !ws.addressAllowed(addr.IP) {
+			return ErrWebhookURLNotAllowed
+		}
+	}
+	return nil
+}
+
+// addressAllowed reports whether deliveries may connect to ip. Addresses inside the network the server runs in,
+// such as private ranges or the link-local cloud metadata service, are never allowed.
+func (ws *WebhookService) addressAllowed(ip net.IP) bool {
+	if ip.IsLoopback() {
+		return ws.allowLoopback
+	}
+	return !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsMulticast() && !ip.IsUnspecified()
+}
+
+// SignWebhookPayload computes the hex encoded HMAC-SHA256 of "<timestamp>.<body>" with the endpoint secret.
+// Receivers recompute it to verify that a delivery really came from us.
+func SignWebhookPayload(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// WebhookDispatcher periodically delivers due webhooks until its context is cancelled.
+type WebhookDispatcher struct {
+	svc      WebhookServicer
+	interval time.Duration
+}
+
+func NewWebhookDispatcher(svc WebhookServicer) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		svc:      svc,
+		interval: webhookDispatchInterval,
+	}
+}
+
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.svc.DispatchDue(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("[Webhook] dispatch failed", "error", err)
+			}
+		}
+	}
+}
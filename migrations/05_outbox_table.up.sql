@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    sent_at TIMESTAMP,
+    CONSTRAINT uq_outbox_event_id UNIQUE (event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unsent ON outbox(next_attempt_at, id) WHERE sent_at IS NULL;
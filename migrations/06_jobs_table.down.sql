@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS dead_letter_jobs;
+DROP TABLE IF EXISTS jobs;
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
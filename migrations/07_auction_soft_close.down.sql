@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_products_ends_at;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS extension_count,
+    DROP COLUMN IF EXISTS max_extensions,
+    DROP COLUMN IF EXISTS soft_close_extension_minutes,
+    DROP COLUMN IF EXISTS soft_close_window_minutes,
+    DROP COLUMN IF EXISTS ends_at;
@@ -0,0 +1,8 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS ends_at TIMESTAMP NOT NULL DEFAULT (NOW() + INTERVAL '7 days'),
+    ADD COLUMN IF NOT EXISTS soft_close_window_minutes INTEGER NOT NULL DEFAULT 0 CHECK (soft_close_window_minutes >= 0),
+    ADD COLUMN IF NOT EXISTS soft_close_extension_minutes INTEGER NOT NULL DEFAULT 0 CHECK (soft_close_extension_minutes >= 0),
+    ADD COLUMN IF NOT EXISTS max_extensions INTEGER CHECK (max_extensions >= 0),
+    ADD COLUMN IF NOT EXISTS extension_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_products_ends_at ON products(ends_at);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS bid_increment_rules;
+
+DROP INDEX IF EXISTS idx_products_category;
+ALTER TABLE products DROP COLUMN IF EXISTS category;
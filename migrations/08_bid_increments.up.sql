@@ -0,0 +1,30 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_products_category ON products(category);
+
+-- A ladder is the set of rules of one scope. The rule with the highest min_price
+-- not above the current price gives the increment.
+CREATE TABLE IF NOT EXISTS bid_increment_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    scope TEXT NOT NULL CHECK (scope IN ('platform', 'category', 'product')),
+    category TEXT,
+    product_id UUID REFERENCES products(id) ON DELETE CASCADE,
+    min_price INTEGER NOT NULL CHECK (min_price >= 0),
+    increment INTEGER NOT NULL CHECK (increment > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_bid_increment_scope CHECK (
+        (scope = 'platform' AND category IS NULL AND product_id IS NULL) OR
+        (scope = 'category' AND category IS NOT NULL AND product_id IS NULL) OR
+        (scope = 'product' AND product_id IS NOT NULL AND category IS NULL)
+    )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_bid_increment_platform ON bid_increment_rules(min_price) WHERE scope = 'platform';
+CREATE UNIQUE INDEX IF NOT EXISTS uq_bid_increment_category ON bid_increment_rules(category, min_price) WHERE scope = 'category';
+CREATE UNIQUE INDEX IF NOT EXISTS uq_bid_increment_product ON bid_increment_rules(product_id, min_price) WHERE scope = 'product';
+
+-- Default platform ladder
+INSERT INTO bid_increment_rules (scope, min_price, increment) VALUES
+    ('platform', 0, 1),
+    ('platform', 100, 5),
+    ('platform', 1000, 25);
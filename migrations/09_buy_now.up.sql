@@ -0,0 +1 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS buy_now_price INTEGER CHECK (buy_now_price > 0);
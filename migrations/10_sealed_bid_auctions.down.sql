@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_bids_product_user;
+DROP INDEX IF EXISTS idx_products_unsettled;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS closed_at,
+    DROP COLUMN IF EXISTS auction_type;
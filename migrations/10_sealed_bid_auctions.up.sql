@@ -0,0 +1,9 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS auction_type TEXT NOT NULL DEFAULT 'english' CHECK (auction_type IN ('english', 'sealed_first_price', 'vickrey')),
+    ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP;
+
+-- Products sold before settlement existed are already closed
+UPDATE products SET closed_at = sold_at WHERE sold_at IS NOT NULL AND closed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_unsettled ON products(ends_at) WHERE closed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_bids_product_user ON bids(product_id, user_id) WHERE is_valid = true;
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_products_next_price_drop;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS next_price_drop_at,
+    DROP COLUMN IF EXISTS dutch_interval_seconds,
+    DROP COLUMN IF EXISTS dutch_price_step;
+
+DELETE FROM products WHERE auction_type = 'dutch';
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_auction_type_check;
+ALTER TABLE products ADD CONSTRAINT products_auction_type_check
+    CHECK (auction_type IN ('english', 'sealed_first_price', 'vickrey'));
@@ -0,0 +1,10 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_auction_type_check;
+ALTER TABLE products ADD CONSTRAINT products_auction_type_check
+    CHECK (auction_type IN ('english', 'sealed_first_price', 'vickrey', 'dutch'));
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS dutch_price_step INTEGER CHECK (dutch_price_step > 0),
+    ADD COLUMN IF NOT EXISTS dutch_interval_seconds INTEGER CHECK (dutch_interval_seconds > 0),
+    ADD COLUMN IF NOT EXISTS next_price_drop_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_products_next_price_drop ON products(next_price_drop_at) WHERE next_price_drop_at IS NOT NULL;
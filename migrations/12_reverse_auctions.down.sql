@@ -0,0 +1,4 @@
+DELETE FROM products WHERE auction_type = 'reverse';
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_auction_type_check;
+ALTER TABLE products ADD CONSTRAINT products_auction_type_check
+    CHECK (auction_type IN ('english', 'sealed_first_price', 'vickrey', 'dutch'));
@@ -0,0 +1,3 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_auction_type_check;
+ALTER TABLE products ADD CONSTRAINT products_auction_type_check
+    CHECK (auction_type IN ('english', 'sealed_first_price', 'vickrey', 'dutch', 'reverse'));
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS orders;
+
+ALTER TABLE bids DROP COLUMN IF EXISTS quantity;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS pricing_rule,
+    DROP COLUMN IF EXISTS quantity;
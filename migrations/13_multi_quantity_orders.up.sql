@@ -0,0 +1,34 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    ADD COLUMN IF NOT EXISTS pricing_rule TEXT NOT NULL DEFAULT 'pay_as_bid' CHECK (pricing_rule IN ('pay_as_bid', 'uniform'));
+
+ALTER TABLE bids
+    ADD COLUMN IF NOT EXISTS quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0);
+
+-- One order per winner of a product, created when the product is sold
+CREATE TABLE IF NOT EXISTS orders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    bid_id UUID,
+    seller_id UUID NOT NULL,
+    buyer_id UUID NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price INTEGER NOT NULL CHECK (unit_price >= 0),
+    total_price INTEGER NOT NULL CHECK (total_price >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_order_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_order_bid FOREIGN KEY (bid_id) REFERENCES bids(id) ON DELETE SET NULL,
+    CONSTRAINT fk_order_seller FOREIGN KEY (seller_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_order_buyer FOREIGN KEY (buyer_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_product_id ON orders(product_id);
+CREATE INDEX IF NOT EXISTS idx_orders_seller_id ON orders(seller_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_orders_buyer_id ON orders(buyer_id, created_at);
+
+-- Products sold before orders existed
+INSERT INTO orders (product_id, seller_id, buyer_id, quantity, unit_price, total_price, created_at, updated_at)
+SELECT id, seller_id, sold_to, 1, current_price, current_price, sold_at, sold_at
+FROM products
+WHERE sold_at IS NOT NULL AND sold_to IS NOT NULL;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_products_scheduled;
+DROP INDEX IF EXISTS idx_products_seller_status;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS relisted_from,
+    DROP COLUMN IF EXISTS start_price,
+    DROP COLUMN IF EXISTS starts_at,
+    DROP COLUMN IF EXISTS status;
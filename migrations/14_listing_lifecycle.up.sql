@@ -0,0 +1,14 @@
+-- Listing lifecycle: draft -> scheduled -> live -> ended -> relisted
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'live' CHECK (status IN ('draft', 'scheduled', 'live', 'ended', 'relisted')),
+    ADD COLUMN IF NOT EXISTS starts_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS start_price INTEGER,
+    ADD COLUMN IF NOT EXISTS relisted_from UUID REFERENCES products(id) ON DELETE SET NULL;
+
+UPDATE products SET starts_at = created_at, start_price = current_price;
+UPDATE products SET status = 'ended' WHERE closed_at IS NOT NULL;
+
+ALTER TABLE products ALTER COLUMN start_price SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_seller_status ON products(seller_id, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_products_scheduled ON products(starts_at) WHERE status = 'scheduled';
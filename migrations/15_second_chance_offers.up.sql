@@ -0,0 +1,26 @@
+-- Offers of an unsold item to a runner-up bidder at that bidder's own price
+CREATE TABLE IF NOT EXISTS second_chance_offers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    bid_id UUID NOT NULL,
+    seller_id UUID NOT NULL,
+    bidder_id UUID NOT NULL,
+    price INTEGER NOT NULL CHECK (price >= 0),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'expired', 'cancelled')),
+    -- Move on to the next runner-up when the offer is declined or expires
+    auto_advance BOOLEAN NOT NULL DEFAULT false,
+    expires_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_second_chance_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_second_chance_bid FOREIGN KEY (bid_id) REFERENCES bids(id) ON DELETE CASCADE,
+    CONSTRAINT fk_second_chance_seller FOREIGN KEY (seller_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_second_chance_bidder FOREIGN KEY (bidder_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- A product has at most one open offer at a time and every bidder is offered it at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_second_chance_pending ON second_chance_offers(product_id) WHERE status = 'pending';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_second_chance_product_bidder ON second_chance_offers(product_id, bidder_id);
+CREATE INDEX IF NOT EXISTS idx_second_chance_bidder ON second_chance_offers(bidder_id, status);
+CREATE INDEX IF NOT EXISTS idx_second_chance_expiry ON second_chance_offers(expires_at) WHERE status = 'pending';
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS offers;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS auto_decline_price,
+    DROP COLUMN IF EXISTS auto_accept_price;
+
+DELETE FROM products WHERE auction_type = 'fixed_price';
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_auction_type_check;
+ALTER TABLE products ADD CONSTRAINT products_auction_type_check
+    CHECK (auction_type IN ('english', 'sealed_first_price', 'vickrey', 'dutch', 'reverse'));
@@ -0,0 +1,37 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_auction_type_check;
+ALTER TABLE products ADD CONSTRAINT products_auction_type_check
+    CHECK (auction_type IN ('english', 'sealed_first_price', 'vickrey', 'dutch', 'reverse', 'fixed_price'));
+
+-- Offers on fixed-price listings at or above auto_accept_price are accepted right away,
+-- offers below auto_decline_price are declined right away
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS auto_accept_price INTEGER CHECK (auto_accept_price > 0),
+    ADD COLUMN IF NOT EXISTS auto_decline_price INTEGER CHECK (auto_decline_price > 0);
+
+-- Offers and counter-offers on fixed-price listings. A counter-offer is a new row pointing at the
+-- offer it answers, which moves to countered. Every row is answered by the party that did not make it.
+CREATE TABLE IF NOT EXISTS offers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    buyer_id UUID NOT NULL,
+    seller_id UUID NOT NULL,
+    parent_id UUID,
+    made_by TEXT NOT NULL CHECK (made_by IN ('buyer', 'seller')),
+    price INTEGER NOT NULL CHECK (price > 0),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'countered', 'expired')),
+    expires_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_offers_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_offers_buyer FOREIGN KEY (buyer_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_offers_seller FOREIGN KEY (seller_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_offers_parent FOREIGN KEY (parent_id) REFERENCES offers(id) ON DELETE CASCADE
+);
+
+-- A buyer negotiates at most once at a time on the same product
+CREATE UNIQUE INDEX IF NOT EXISTS idx_offers_open_negotiation ON offers(product_id, buyer_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_offers_product ON offers(product_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_offers_buyer ON offers(buyer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_offers_seller ON offers(seller_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_offers_expiry ON offers(expires_at) WHERE status = 'pending';
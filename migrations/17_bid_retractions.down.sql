@@ -0,0 +1,4 @@
+ALTER TABLE bids
+    DROP COLUMN IF EXISTS retraction_reason,
+    DROP COLUMN IF EXISTS retracted_by,
+    DROP COLUMN IF EXISTS retracted_at;
@@ -0,0 +1,5 @@
+-- Bids withdrawn by their bidder or cancelled by the seller stay on record as invalid bids
+ALTER TABLE bids
+    ADD COLUMN IF NOT EXISTS retracted_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS retracted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS retraction_reason TEXT;
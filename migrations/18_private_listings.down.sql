@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS seller_blocked_bidders;
+DROP TABLE IF EXISTS product_access_codes;
+DROP TABLE IF EXISTS product_invitations;
+
+ALTER TABLE products DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,36 @@
+-- Private listings are only visible and biddable to the seller, invited users and holders of the access code
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS visibility TEXT NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'private'));
+
+-- Users invited by the seller, or who redeemed the access code, to a private listing
+CREATE TABLE IF NOT EXISTS product_invitations (
+    product_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    source TEXT NOT NULL DEFAULT 'seller' CHECK (source IN ('seller', 'access_code')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_id, user_id),
+    CONSTRAINT fk_product_invitations_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_product_invitations_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_invitations_user ON product_invitations(user_id);
+
+-- At most one access code per private listing, kept apart from products so it never ends up in a product response
+CREATE TABLE IF NOT EXISTS product_access_codes (
+    product_id UUID PRIMARY KEY,
+    code TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_product_access_codes_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Bidders a seller refuses on all of their listings
+CREATE TABLE IF NOT EXISTS seller_blocked_bidders (
+    seller_id UUID NOT NULL,
+    bidder_id UUID NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (seller_id, bidder_id),
+    CONSTRAINT fk_seller_blocked_bidders_seller FOREIGN KEY (seller_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_seller_blocked_bidders_bidder FOREIGN KEY (bidder_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_seller_blocked_bidders_self CHECK (seller_id <> bidder_id)
+);
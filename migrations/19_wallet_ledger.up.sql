@@ -0,0 +1,63 @@
+-- Ledger accounts. Every user has an available and a held wallet account, created on first use.
+-- The external account stands for money outside the platform: deposits come from it, withdrawals go to it.
+CREATE TABLE IF NOT EXISTS accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID,
+    kind TEXT NOT NULL CHECK (kind IN ('available', 'held', 'external')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_accounts_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CONSTRAINT chk_accounts_owner CHECK ((user_id IS NULL) = (kind = 'external'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_user_kind ON accounts(user_id, kind);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_system_kind ON accounts(kind) WHERE user_id IS NULL;
+
+INSERT INTO accounts (kind) VALUES ('external') ON CONFLICT DO NOTHING;
+
+-- One money movement of a user. Posting the same idempotency key twice records it once.
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    idempotency_key TEXT NOT NULL UNIQUE,
+    kind TEXT NOT NULL CHECK (kind IN ('deposit', 'withdrawal', 'hold', 'release')),
+    user_id UUID NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    reference_id UUID,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_journal_entries_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_user ON journal_entries(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_reference ON journal_entries(reference_id) WHERE reference_id IS NOT NULL;
+
+-- The debits and credits of a journal entry. Balances are derived from postings, which are never changed.
+CREATE TABLE IF NOT EXISTS postings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entry_id UUID NOT NULL,
+    account_id UUID NOT NULL,
+    direction TEXT NOT NULL CHECK (direction IN ('debit', 'credit')),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_postings_entry FOREIGN KEY (entry_id) REFERENCES journal_entries(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_postings_account FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_postings_account ON postings(account_id);
+CREATE INDEX IF NOT EXISTS idx_postings_entry ON postings(entry_id);
+
+-- Debits must equal credits in every journal entry. The check runs at commit, once all postings of the entry are in.
+CREATE OR REPLACE FUNCTION check_journal_entry_balanced() RETURNS TRIGGER AS $$
+BEGIN
+    IF (SELECT SUM(CASE WHEN direction = 'debit' THEN amount ELSE -amount END)
+        FROM postings WHERE entry_id = NEW.entry_id) <> 0 THEN
+        RAISE EXCEPTION 'journal entry % is unbalanced', NEW.entry_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_postings_balanced ON postings;
+CREATE CONSTRAINT TRIGGER trg_postings_balanced
+    AFTER INSERT ON postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_journal_entry_balanced();
@@ -0,0 +1,15 @@
+ALTER TABLE journal_entries DROP CONSTRAINT IF EXISTS journal_entries_kind_check;
+ALTER TABLE journal_entries
+    ADD CONSTRAINT journal_entries_kind_check CHECK (kind IN ('deposit', 'withdrawal', 'hold', 'release'));
+
+DELETE FROM accounts WHERE kind = 'escrow' AND user_id IS NULL AND id NOT IN (SELECT account_id FROM postings);
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS chk_accounts_owner;
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_kind_check;
+ALTER TABLE accounts
+    ADD CONSTRAINT accounts_kind_check CHECK (kind IN ('available', 'held', 'external')),
+    ADD CONSTRAINT chk_accounts_owner CHECK ((user_id IS NULL) = (kind = 'external'));
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS chk_products_deposit_amount;
+ALTER TABLE products
+    DROP COLUMN IF EXISTS deposit_amount,
+    DROP COLUMN IF EXISTS deposit_type;
@@ -0,0 +1,20 @@
+-- Bidders on a product with a deposit requirement must hold funds in their wallet: their bid amount or a fixed deposit
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS deposit_type TEXT NOT NULL DEFAULT 'none' CHECK (deposit_type IN ('none', 'bid_amount', 'fixed')),
+    ADD COLUMN IF NOT EXISTS deposit_amount INTEGER CHECK (deposit_amount > 0);
+
+ALTER TABLE products
+    ADD CONSTRAINT chk_products_deposit_amount CHECK ((deposit_type = 'fixed') = (deposit_amount IS NOT NULL));
+
+-- Held funds of winners are captured into the platform's escrow account
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_kind_check;
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS chk_accounts_owner;
+ALTER TABLE accounts
+    ADD CONSTRAINT accounts_kind_check CHECK (kind IN ('available', 'held', 'external', 'escrow')),
+    ADD CONSTRAINT chk_accounts_owner CHECK ((user_id IS NULL) = (kind IN ('external', 'escrow')));
+
+INSERT INTO accounts (kind) VALUES ('escrow') ON CONFLICT DO NOTHING;
+
+ALTER TABLE journal_entries DROP CONSTRAINT IF EXISTS journal_entries_kind_check;
+ALTER TABLE journal_entries
+    ADD CONSTRAINT journal_entries_kind_check CHECK (kind IN ('deposit', 'withdrawal', 'hold', 'release', 'capture'));
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS non_payment_strikes;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS invoices;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS second_chance_on_non_payment,
+    DROP COLUMN IF EXISTS shipping_cost;
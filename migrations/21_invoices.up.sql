@@ -0,0 +1,62 @@
+-- Flat shipping charged on every order of a product, and whether an unpaid sale goes to the runner-up
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS shipping_cost INTEGER NOT NULL DEFAULT 0 CHECK (shipping_cost >= 0),
+    ADD COLUMN IF NOT EXISTS second_chance_on_non_payment BOOLEAN NOT NULL DEFAULT false;
+
+-- What the buyer of an order owes, created with the order. The platform fee is charged to the seller,
+-- the buyer pays the item, the buyer premium and shipping, less the deposit captured when they won.
+CREATE TABLE IF NOT EXISTS invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL UNIQUE,
+    product_id UUID NOT NULL,
+    seller_id UUID NOT NULL,
+    buyer_id UUID NOT NULL,
+    item_price INTEGER NOT NULL CHECK (item_price >= 0),
+    buyer_premium INTEGER NOT NULL DEFAULT 0 CHECK (buyer_premium >= 0),
+    platform_fee INTEGER NOT NULL DEFAULT 0 CHECK (platform_fee >= 0),
+    shipping INTEGER NOT NULL DEFAULT 0 CHECK (shipping >= 0),
+    total INTEGER NOT NULL CHECK (total >= 0),
+    deposit_applied INTEGER NOT NULL DEFAULT 0 CHECK (deposit_applied >= 0),
+    amount_due INTEGER NOT NULL CHECK (amount_due >= 0),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid', 'expired')),
+    due_at TIMESTAMP NOT NULL,
+    paid_at TIMESTAMP,
+    expired_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_invoices_order FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    CONSTRAINT fk_invoices_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_invoices_seller FOREIGN KEY (seller_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_invoices_buyer FOREIGN KEY (buyer_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_buyer ON invoices(buyer_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_invoices_seller ON invoices(seller_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_invoices_pending_due ON invoices(due_at) WHERE status = 'pending';
+
+-- Every attempt to pay an invoice through the payment provider
+CREATE TABLE IF NOT EXISTS payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invoice_id UUID NOT NULL,
+    provider TEXT NOT NULL,
+    provider_reference TEXT,
+    amount INTEGER NOT NULL CHECK (amount >= 0),
+    status TEXT NOT NULL CHECK (status IN ('succeeded', 'failed')),
+    failure_reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_payments_invoice FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_invoice ON payments(invoice_id, created_at);
+
+-- A buyer who let an invoice expire, at most one per invoice
+CREATE TABLE IF NOT EXISTS non_payment_strikes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    invoice_id UUID NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_non_payment_strikes_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_non_payment_strikes_invoice FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_non_payment_strikes_user ON non_payment_strikes(user_id);
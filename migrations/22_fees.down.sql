@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_journal_entries_fees;
+
+ALTER TABLE journal_entries DROP CONSTRAINT IF EXISTS journal_entries_kind_check;
+ALTER TABLE journal_entries
+    ADD CONSTRAINT journal_entries_kind_check CHECK (kind IN ('deposit', 'withdrawal', 'hold', 'release', 'capture'));
+
+DELETE FROM accounts WHERE kind IN ('receivable', 'revenue') AND user_id IS NULL AND id NOT IN (SELECT account_id FROM postings);
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS chk_accounts_owner;
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_kind_check;
+ALTER TABLE accounts
+    ADD CONSTRAINT accounts_kind_check CHECK (kind IN ('available', 'held', 'external', 'escrow')),
+    ADD CONSTRAINT chk_accounts_owner CHECK ((user_id IS NULL) = (kind IN ('external', 'escrow')));
+
+DROP TABLE IF EXISTS fee_rules;
@@ -0,0 +1,36 @@
+-- Fee schedules charged at settlement: a commission on the final value, paid by the seller, and a buyer premium
+-- on top of it. A schedule is the set of tiers of one kind and scope; each tier charges rate_bps (basis points)
+-- on the part of the price from its min_price up to the next tier, and a category schedule beats the platform one.
+CREATE TABLE IF NOT EXISTS fee_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind TEXT NOT NULL CHECK (kind IN ('commission', 'buyer_premium')),
+    scope TEXT NOT NULL CHECK (scope IN ('platform', 'category')),
+    category TEXT,
+    min_price INTEGER NOT NULL CHECK (min_price >= 0),
+    rate_bps INTEGER NOT NULL CHECK (rate_bps >= 0 AND rate_bps <= 10000),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_fee_rules_scope CHECK ((scope = 'category') = (category IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_fee_rules_platform ON fee_rules(kind, min_price) WHERE scope = 'platform';
+CREATE UNIQUE INDEX IF NOT EXISTS uq_fee_rules_category ON fee_rules(kind, category, min_price) WHERE scope = 'category';
+
+-- Default platform commission of 10%, no buyer premium
+INSERT INTO fee_rules (kind, scope, min_price, rate_bps) VALUES
+    ('commission', 'platform', 0, 1000);
+
+-- Fees are earned at settlement: the platform's receivable account is debited and its revenue account credited.
+-- Expired invoices reverse their fees.
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS chk_accounts_owner;
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_kind_check;
+ALTER TABLE accounts
+    ADD CONSTRAINT accounts_kind_check CHECK (kind IN ('available', 'held', 'external', 'escrow', 'receivable', 'revenue')),
+    ADD CONSTRAINT chk_accounts_owner CHECK ((user_id IS NULL) = (kind IN ('external', 'escrow', 'receivable', 'revenue')));
+
+INSERT INTO accounts (kind) VALUES ('receivable'), ('revenue') ON CONFLICT DO NOTHING;
+
+ALTER TABLE journal_entries DROP CONSTRAINT IF EXISTS journal_entries_kind_check;
+ALTER TABLE journal_entries
+    ADD CONSTRAINT journal_entries_kind_check CHECK (kind IN ('deposit', 'withdrawal', 'hold', 'release', 'capture', 'commission', 'buyer_premium', 'fee_reversal'));
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_fees ON journal_entries(created_at) WHERE kind IN ('commission', 'buyer_premium', 'fee_reversal');
@@ -0,0 +1,40 @@
+ALTER TABLE journal_entries DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE payments ALTER COLUMN amount TYPE INTEGER;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN item_price TYPE INTEGER,
+    ALTER COLUMN buyer_premium TYPE INTEGER,
+    ALTER COLUMN platform_fee TYPE INTEGER,
+    ALTER COLUMN shipping TYPE INTEGER,
+    ALTER COLUMN total TYPE INTEGER,
+    ALTER COLUMN deposit_applied TYPE INTEGER,
+    ALTER COLUMN amount_due TYPE INTEGER;
+
+ALTER TABLE fee_rules ALTER COLUMN min_price TYPE INTEGER;
+
+ALTER TABLE bid_increment_rules
+    ALTER COLUMN min_price TYPE INTEGER,
+    ALTER COLUMN increment TYPE INTEGER;
+
+ALTER TABLE second_chance_offers ALTER COLUMN price TYPE INTEGER;
+ALTER TABLE offers ALTER COLUMN price TYPE INTEGER;
+
+ALTER TABLE orders
+    ALTER COLUMN unit_price TYPE INTEGER,
+    ALTER COLUMN total_price TYPE INTEGER;
+
+ALTER TABLE bids ALTER COLUMN price TYPE INTEGER;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN min_price TYPE INTEGER,
+    ALTER COLUMN current_price TYPE INTEGER,
+    ALTER COLUMN start_price TYPE INTEGER,
+    ALTER COLUMN buy_now_price TYPE INTEGER,
+    ALTER COLUMN dutch_price_step TYPE INTEGER,
+    ALTER COLUMN auto_accept_price TYPE INTEGER,
+    ALTER COLUMN auto_decline_price TYPE INTEGER,
+    ALTER COLUMN deposit_amount TYPE INTEGER,
+    ALTER COLUMN shipping_cost TYPE INTEGER;
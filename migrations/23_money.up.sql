@@ -0,0 +1,44 @@
+-- Amounts are minor units of the listing's currency (ISO 4217), widened to BIGINT. Existing listings are in USD.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'USD' CHECK (currency ~ '^[A-Z]{3}$'),
+    ALTER COLUMN min_price TYPE BIGINT,
+    ALTER COLUMN current_price TYPE BIGINT,
+    ALTER COLUMN start_price TYPE BIGINT,
+    ALTER COLUMN buy_now_price TYPE BIGINT,
+    ALTER COLUMN dutch_price_step TYPE BIGINT,
+    ALTER COLUMN auto_accept_price TYPE BIGINT,
+    ALTER COLUMN auto_decline_price TYPE BIGINT,
+    ALTER COLUMN deposit_amount TYPE BIGINT,
+    ALTER COLUMN shipping_cost TYPE BIGINT;
+
+ALTER TABLE bids ALTER COLUMN price TYPE BIGINT;
+
+ALTER TABLE orders
+    ALTER COLUMN unit_price TYPE BIGINT,
+    ALTER COLUMN total_price TYPE BIGINT;
+
+ALTER TABLE offers ALTER COLUMN price TYPE BIGINT;
+ALTER TABLE second_chance_offers ALTER COLUMN price TYPE BIGINT;
+
+ALTER TABLE bid_increment_rules
+    ALTER COLUMN min_price TYPE BIGINT,
+    ALTER COLUMN increment TYPE BIGINT;
+
+ALTER TABLE fee_rules ALTER COLUMN min_price TYPE BIGINT;
+
+-- Invoices are charged in the currency of their product
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'USD' CHECK (currency ~ '^[A-Z]{3}$'),
+    ALTER COLUMN item_price TYPE BIGINT,
+    ALTER COLUMN buyer_premium TYPE BIGINT,
+    ALTER COLUMN platform_fee TYPE BIGINT,
+    ALTER COLUMN shipping TYPE BIGINT,
+    ALTER COLUMN total TYPE BIGINT,
+    ALTER COLUMN deposit_applied TYPE BIGINT,
+    ALTER COLUMN amount_due TYPE BIGINT;
+
+ALTER TABLE payments ALTER COLUMN amount TYPE BIGINT;
+
+-- Wallets hold the platform currency; fee entries are booked in the currency of their invoice
+ALTER TABLE journal_entries
+    ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'USD' CHECK (currency ~ '^[A-Z]{3}$');
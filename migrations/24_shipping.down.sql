@@ -0,0 +1,28 @@
+DROP INDEX IF EXISTS idx_orders_seller_fulfilment;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS delivered_at,
+    DROP COLUMN IF EXISTS shipped_at,
+    DROP COLUMN IF EXISTS tracking_number,
+    DROP COLUMN IF EXISTS carrier,
+    DROP COLUMN IF EXISTS ship_to_country,
+    DROP COLUMN IF EXISTS ship_to_postal_code,
+    DROP COLUMN IF EXISTS ship_to_state,
+    DROP COLUMN IF EXISTS ship_to_city,
+    DROP COLUMN IF EXISTS ship_to_line2,
+    DROP COLUMN IF EXISTS ship_to_line1,
+    DROP COLUMN IF EXISTS ship_to_name,
+    DROP COLUMN IF EXISTS shipping_cost,
+    DROP COLUMN IF EXISTS shipping_method,
+    DROP COLUMN IF EXISTS fulfilment_status;
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS shipping_cost BIGINT NOT NULL DEFAULT 0 CHECK (shipping_cost >= 0);
+
+UPDATE products p
+SET shipping_cost = s.cost
+FROM shipping_options s
+WHERE s.product_id = p.id AND s.method = 'flat_rate';
+
+DROP TABLE IF EXISTS shipping_options;
+DROP TABLE IF EXISTS addresses;
@@ -0,0 +1,71 @@
+-- Addresses a user ships to, at most one of them the default
+CREATE TABLE IF NOT EXISTS addresses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    line1 TEXT NOT NULL,
+    line2 TEXT,
+    city TEXT NOT NULL,
+    state TEXT,
+    postal_code TEXT NOT NULL,
+    country TEXT NOT NULL CHECK (country ~ '^[A-Z]{2}$'),
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_addresses_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_addresses_user ON addresses(user_id, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_addresses_user_default ON addresses(user_id) WHERE is_default;
+
+-- How the items of a listing get to the buyer: local pickup, a flat rate anywhere, or a regional rate
+-- for buyers in one country (ISO 3166-1 alpha-2)
+CREATE TABLE IF NOT EXISTS shipping_options (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    method TEXT NOT NULL CHECK (method IN ('pickup', 'flat_rate', 'regional')),
+    region TEXT CHECK (region ~ '^[A-Z]{2}$'),
+    cost BIGINT NOT NULL DEFAULT 0 CHECK (cost >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_shipping_options_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT chk_shipping_options_region CHECK ((method = 'regional') = (region IS NOT NULL)),
+    CONSTRAINT chk_shipping_options_pickup CHECK (method <> 'pickup' OR cost = 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shipping_options_product ON shipping_options(product_id, method, COALESCE(region, ''));
+
+-- The flat shipping of existing listings becomes their flat rate
+INSERT INTO shipping_options (product_id, method, cost)
+SELECT id, 'flat_rate', shipping_cost FROM products;
+
+ALTER TABLE products DROP COLUMN IF EXISTS shipping_cost;
+
+-- Delivery of an order: how and where it ships, for what, and how far it got. The address is copied
+-- from the buyer's address book so later edits do not move orders in flight
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS fulfilment_status TEXT NOT NULL DEFAULT 'awaiting_shipment'
+        CHECK (fulfilment_status IN ('awaiting_shipment', 'shipped', 'delivered', 'cancelled')),
+    ADD COLUMN IF NOT EXISTS shipping_method TEXT CHECK (shipping_method IN ('pickup', 'flat_rate', 'regional')),
+    ADD COLUMN IF NOT EXISTS shipping_cost BIGINT NOT NULL DEFAULT 0 CHECK (shipping_cost >= 0),
+    ADD COLUMN IF NOT EXISTS ship_to_name TEXT,
+    ADD COLUMN IF NOT EXISTS ship_to_line1 TEXT,
+    ADD COLUMN IF NOT EXISTS ship_to_line2 TEXT,
+    ADD COLUMN IF NOT EXISTS ship_to_city TEXT,
+    ADD COLUMN IF NOT EXISTS ship_to_state TEXT,
+    ADD COLUMN IF NOT EXISTS ship_to_postal_code TEXT,
+    ADD COLUMN IF NOT EXISTS ship_to_country TEXT,
+    ADD COLUMN IF NOT EXISTS carrier TEXT,
+    ADD COLUMN IF NOT EXISTS tracking_number TEXT,
+    ADD COLUMN IF NOT EXISTS shipped_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP;
+
+-- Existing orders shipped at the flat rate of their invoice, orders of expired invoices never ship
+UPDATE orders o
+SET shipping_method = 'flat_rate',
+    shipping_cost = COALESCE((SELECT i.shipping FROM invoices i WHERE i.order_id = o.id), 0),
+    fulfilment_status = CASE
+        WHEN EXISTS (SELECT 1 FROM invoices i WHERE i.order_id = o.id AND i.status = 'expired') THEN 'cancelled'
+        ELSE 'awaiting_shipment'
+    END;
+
+CREATE INDEX IF NOT EXISTS idx_orders_seller_fulfilment ON orders(seller_id, fulfilment_status);
@@ -0,0 +1,3 @@
+ALTER TABLE products DROP COLUMN IF EXISTS min_bidder_reputation;
+
+DROP TABLE IF EXISTS feedback;
@@ -0,0 +1,24 @@
+-- Each party of a paid order rates the other once, within the feedback window. The recipient may reply once.
+-- recipient_role is the role the recipient had in the order, so reputations can be split into seller and buyer feedback.
+CREATE TABLE IF NOT EXISTS feedback (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL,
+    author_id UUID NOT NULL,
+    recipient_id UUID NOT NULL,
+    recipient_role TEXT NOT NULL CHECK (recipient_role IN ('buyer', 'seller')),
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT,
+    reply TEXT,
+    replied_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_feedback_order_author UNIQUE (order_id, author_id),
+    CONSTRAINT fk_feedback_order FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    CONSTRAINT fk_feedback_author FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_feedback_recipient FOREIGN KEY (recipient_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_feedback_recipient ON feedback(recipient_id, created_at);
+
+-- Bidders and buyers of a listing with a minimum reputation need at least that score
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS min_bidder_reputation INTEGER CHECK (min_bidder_reputation > 0);
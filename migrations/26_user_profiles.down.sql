@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS featured_listings;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS email_verification_expires_at,
+    DROP COLUMN IF EXISTS email_verification_token_hash,
+    DROP COLUMN IF EXISTS pending_email,
+    DROP COLUMN IF EXISTS banner_key,
+    DROP COLUMN IF EXISTS avatar_key,
+    DROP COLUMN IF EXISTS bio;
+
+DROP INDEX IF EXISTS uq_users_username;
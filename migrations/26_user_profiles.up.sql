@@ -0,0 +1,23 @@
+-- Usernames address public profiles, so they are unique among users who have not been deleted
+CREATE UNIQUE INDEX IF NOT EXISTS uq_users_username ON users(username) WHERE deleted_at IS NULL;
+
+-- Public profile and storefront of a user. Avatar and banner are object keys in storage.
+-- A new email stays pending until the user confirms it with the token mailed to it, only its hash is stored.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS bio TEXT,
+    ADD COLUMN IF NOT EXISTS avatar_key TEXT,
+    ADD COLUMN IF NOT EXISTS banner_key TEXT,
+    ADD COLUMN IF NOT EXISTS pending_email TEXT,
+    ADD COLUMN IF NOT EXISTS email_verification_token_hash TEXT,
+    ADD COLUMN IF NOT EXISTS email_verification_expires_at TIMESTAMP;
+
+-- Listings a seller puts first on their storefront, in position order
+CREATE TABLE IF NOT EXISTS featured_listings (
+    seller_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    position INTEGER NOT NULL CHECK (position >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (seller_id, product_id),
+    CONSTRAINT fk_featured_listing_seller FOREIGN KEY (seller_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_featured_listing_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
@@ -0,0 +1,48 @@
+-- Threads between a listing's seller and one other user, about the product or about one of its orders.
+-- There is a single product thread per user and listing and a single thread per order. For reverse auctions
+-- seller_id is the owner of the listing, like products.seller_id. Each party's last_read_at drives unread counts.
+CREATE TABLE IF NOT EXISTS conversations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    order_id UUID,
+    seller_id UUID NOT NULL,
+    buyer_id UUID NOT NULL,
+    seller_last_read_at TIMESTAMP,
+    buyer_last_read_at TIMESTAMP,
+    last_message_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_conversation_parties CHECK (seller_id <> buyer_id),
+    CONSTRAINT uq_conversation_order UNIQUE (order_id),
+    CONSTRAINT fk_conversation_product FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_conversation_order FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    CONSTRAINT fk_conversation_seller FOREIGN KEY (seller_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_conversation_buyer FOREIGN KEY (buyer_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_conversation_product_buyer ON conversations(product_id, buyer_id) WHERE order_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_conversations_seller ON conversations(seller_id, last_message_at);
+CREATE INDEX IF NOT EXISTS idx_conversations_buyer ON conversations(buyer_id, last_message_at);
+
+-- Messages are screened when sent: flags lists what was found (contact details, scam phrases) and flagged
+-- messages are held back from the recipient until an admin approves them. Removed messages stay for the record.
+-- visible_at is when the recipient could first see the message, unread counts compare it to their last_read_at.
+CREATE TABLE IF NOT EXISTS messages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    conversation_id UUID NOT NULL,
+    sender_id UUID NOT NULL,
+    body TEXT NOT NULL,
+    attachment_key TEXT,
+    status TEXT NOT NULL DEFAULT 'visible' CHECK (status IN ('visible', 'held', 'removed')),
+    flags TEXT[] NOT NULL DEFAULT '{}',
+    moderated_by UUID,
+    moderated_at TIMESTAMP,
+    visible_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_message_conversation FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_message_sender FOREIGN KEY (sender_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_message_moderator FOREIGN KEY (moderated_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_messages_conversation ON messages(conversation_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_messages_sender ON messages(sender_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_messages_held ON messages(created_at) WHERE status = 'held';
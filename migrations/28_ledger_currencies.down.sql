@@ -0,0 +1,16 @@
+UPDATE postings p
+SET account_id = target.id
+FROM accounts a, accounts target
+WHERE a.id = p.account_id
+  AND a.user_id IS NULL
+  AND a.currency <> 'USD'
+  AND target.user_id IS NULL
+  AND target.kind = a.kind
+  AND target.currency = 'USD';
+
+DELETE FROM accounts WHERE user_id IS NULL AND currency <> 'USD';
+
+DROP INDEX IF EXISTS idx_accounts_system_kind_currency;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_system_kind ON accounts(kind) WHERE user_id IS NULL;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,27 @@
+-- Platform accounts are kept per currency, so fees in one currency never add up with another.
+-- User accounts stay in the wallet currency, USD.
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'USD' CHECK (currency ~ '^[A-Z]{3}$');
+
+DROP INDEX IF EXISTS idx_accounts_system_kind;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_system_kind_currency ON accounts(kind, currency) WHERE user_id IS NULL;
+
+-- Move the postings of entries booked in another currency onto the platform accounts of that currency
+INSERT INTO accounts (kind, currency)
+SELECT DISTINCT a.kind, je.currency
+FROM postings p
+JOIN accounts a ON a.id = p.account_id
+JOIN journal_entries je ON je.id = p.entry_id
+WHERE a.user_id IS NULL AND je.currency <> a.currency
+ON CONFLICT (kind, currency) WHERE user_id IS NULL DO NOTHING;
+
+UPDATE postings p
+SET account_id = target.id
+FROM accounts a, journal_entries je, accounts target
+WHERE a.id = p.account_id
+  AND je.id = p.entry_id
+  AND a.user_id IS NULL
+  AND je.currency <> a.currency
+  AND target.user_id IS NULL
+  AND target.kind = a.kind
+  AND target.currency = je.currency;
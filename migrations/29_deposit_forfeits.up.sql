@@ -0,0 +1,4 @@
+-- A deposit captured from a winner who never pays is forfeited from escrow to the seller when the invoice expires
+ALTER TABLE journal_entries DROP CONSTRAINT IF EXISTS journal_entries_kind_check;
+ALTER TABLE journal_entries
+    ADD CONSTRAINT journal_entries_kind_check CHECK (kind IN ('deposit', 'withdrawal', 'hold', 'release', 'capture', 'commission', 'buyer_premium', 'fee_reversal', 'forfeit'));
@@ -0,0 +1,12 @@
+UPDATE payments SET status = 'failed', failure_reason = 'interrupted' WHERE status = 'processing';
+UPDATE invoices SET status = 'pending' WHERE status = 'processing';
+
+DROP INDEX IF EXISTS uq_payments_processing;
+
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_status_check;
+ALTER TABLE payments
+    ADD CONSTRAINT payments_status_check CHECK (status IN ('succeeded', 'failed'));
+
+ALTER TABLE invoices DROP CONSTRAINT IF EXISTS invoices_status_check;
+ALTER TABLE invoices
+    ADD CONSTRAINT invoices_status_check CHECK (status IN ('pending', 'paid', 'expired'));
@@ -0,0 +1,12 @@
+-- Invoices are charged in two steps: the invoice and its payment attempt are marked processing and committed,
+-- the provider is charged outside the transaction, then the result is recorded. At most one attempt of an
+-- invoice is processing at a time.
+ALTER TABLE invoices DROP CONSTRAINT IF EXISTS invoices_status_check;
+ALTER TABLE invoices
+    ADD CONSTRAINT invoices_status_check CHECK (status IN ('pending', 'processing', 'paid', 'expired'));
+
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_status_check;
+ALTER TABLE payments
+    ADD CONSTRAINT payments_status_check CHECK (status IN ('processing', 'succeeded', 'failed'));
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_payments_processing ON payments(invoice_id) WHERE status = 'processing';
@@ -0,0 +1,98 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultCurrency is the currency of listings created without one, and of wallets.
+const DefaultCurrency = "USD"
+
+// ErrUnknownCurrency is returned for codes that are not a supported ISO 4217 currency.
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// ErrCurrencyMismatch is returned when an amount is not in the currency it is expected in.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// minorUnits holds the number of decimals of the minor unit of every supported ISO 4217 currency.
+var minorUnits = map[string]int{
+	"AUD": 2,
+	"BRL": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CNY": 2,
+	"DKK": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"HKD": 2,
+	"INR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"MXN": 2,
+	"NOK": 2,
+	"NZD": 2,
+	"SEK": 2,
+	"SGD": 2,
+	"USD": 2,
+	"ZAR": 2,
+}
+
+// Money is an amount in the minor units of its currency, cents for USD.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// New returns amount minor units of currency, which is normalized to upper case.
+func New(amount int64, currency string) (Money, error) {
+	code, err := ParseCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: code}, nil
+}
+
+// ParseCurrency normalizes an ISO 4217 code and checks that it is supported.
+func ParseCurrency(currency string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(currency))
+	if _, ok := minorUnits[code]; !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownCurrency, currency)
+	}
+	return code, nil
+}
+
+// Decimal formats the amount in major units with the decimals of the currency, "1234.50" for 123450 USD.
+func (m Money) Decimal() string {
+	decimals := minorUnits[m.Currency]
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	if decimals == 0 {
+		return fmt.Sprintf("%s%d", sign, amount)
+	}
+	scale := int64(1)
+	for range decimals {
+		scale *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/scale, decimals, amount%scale)
+}
+
+// String formats the amount followed by its currency code, "1234.50 USD".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// MarshalJSON writes the amount in minor units with its currency and its formatted value,
+// the shape every amount of the API is returned in.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount    int64  `json:"amount"`
+		Currency  string `json:"currency"`
+		Formatted string `json:"formatted"`
+	}{m.Amount, m.Currency, m.String()})
+}
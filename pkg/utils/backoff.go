@@ -0,0 +1,15 @@
+package utils
+
+import "time"
+
+// ExponentialBackoff doubles base after every failed attempt, capped at max.
+func ExponentialBackoff(base, max time.Duration, attempt int32) time.Duration {
+	backoff := base
+	for i := int32(1); i < attempt; i++ {
+		backoff *= 2
+		if backoff >= max {
+			return max
+		}
+	}
+	return backoff
+}
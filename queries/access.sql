@@ -0,0 +1,77 @@
+-- name: CreateProductInvitation :one
+INSERT INTO product_invitations (
+    product_id,
+    user_id,
+    source
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (product_id, user_id) DO UPDATE SET product_id = EXCLUDED.product_id
+RETURNING *;
+
+-- name: DeleteProductInvitation :execrows
+DELETE FROM product_invitations
+WHERE product_id = $1 AND user_id = $2;
+
+-- name: GetProductInvitations :many
+SELECT * FROM product_invitations
+WHERE product_id = $1
+ORDER BY created_at;
+
+-- name: IsUserInvited :one
+SELECT EXISTS (
+    SELECT 1 FROM product_invitations
+    WHERE product_id = $1 AND user_id = $2
+);
+
+-- name: CopyProductInvitations :exec
+INSERT INTO product_invitations (product_id, user_id, source)
+SELECT sqlc.arg(to_product_id)::uuid, user_id, source FROM product_invitations
+WHERE product_id = sqlc.arg(from_product_id)
+ON CONFLICT DO NOTHING;
+
+-- name: SetProductAccessCode :one
+INSERT INTO product_access_codes (
+    product_id,
+    code
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (product_id) DO UPDATE SET code = EXCLUDED.code, created_at = NOW()
+RETURNING *;
+
+-- name: GetProductAccessCode :one
+SELECT * FROM product_access_codes
+WHERE product_id = $1
+LIMIT 1;
+
+-- name: DeleteProductAccessCode :execrows
+DELETE FROM product_access_codes
+WHERE product_id = $1;
+
+-- name: BlockBidder :one
+INSERT INTO seller_blocked_bidders (
+    seller_id,
+    bidder_id,
+    reason
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (seller_id, bidder_id) DO UPDATE SET reason = EXCLUDED.reason
+RETURNING *;
+
+-- name: UnblockBidder :execrows
+DELETE FROM seller_blocked_bidders
+WHERE seller_id = $1 AND bidder_id = $2;
+
+-- name: GetBlockedBidders :many
+SELECT * FROM seller_blocked_bidders
+WHERE seller_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: IsBidderBlocked :one
+SELECT EXISTS (
+    SELECT 1 FROM seller_blocked_bidders
+    WHERE seller_id = $1 AND bidder_id = $2
+);
@@ -0,0 +1,64 @@
+-- name: CreateAddress :one
+INSERT INTO addresses (
+    user_id,
+    name,
+    line1,
+    line2,
+    city,
+    state,
+    postal_code,
+    country,
+    is_default
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING *;
+
+-- name: GetAddressesByUserID :many
+SELECT * FROM addresses
+WHERE user_id = $1
+ORDER BY is_default DESC, created_at;
+
+-- name: GetAddressByID :one
+SELECT * FROM addresses
+WHERE id = $1 AND user_id = $2
+LIMIT 1;
+
+-- name: GetDefaultAddress :one
+SELECT * FROM addresses
+WHERE user_id = $1 AND is_default
+LIMIT 1;
+
+-- name: CountAddressesByUserID :one
+SELECT COUNT(*) FROM addresses
+WHERE user_id = $1;
+
+-- name: UpdateAddress :one
+UPDATE addresses
+SET name = $3, line1 = $4, line2 = $5, city = $6, state = $7, postal_code = $8, country = $9,
+    is_default = $10, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: ClearDefaultAddress :exec
+UPDATE addresses
+SET is_default = false, updated_at = NOW()
+WHERE user_id = $1 AND is_default;
+
+-- name: DeleteAddress :one
+DELETE FROM addresses
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: PromoteOldestAddress :exec
+-- Makes the oldest address of a user without a default address their default.
+UPDATE addresses
+SET is_default = true, updated_at = NOW()
+WHERE id = (
+    SELECT a.id FROM addresses a
+    WHERE a.user_id = $1
+    ORDER BY a.created_at
+    LIMIT 1
+) AND NOT EXISTS (
+    SELECT 1 FROM addresses d
+    WHERE d.user_id = $1 AND d.is_default
+);
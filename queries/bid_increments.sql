@@ -0,0 +1,31 @@
+-- name: GetBidIncrementRulesForProduct :many
+-- Returns the product, category and platform rules that can apply to a product.
+SELECT * FROM bid_increment_rules
+WHERE scope = 'platform'
+   OR (scope = 'category' AND category = sqlc.narg(category)::text)
+   OR (scope = 'product' AND product_id = sqlc.arg(product_id)::uuid)
+ORDER BY min_price;
+
+-- name: GetBidIncrementRules :many
+SELECT * FROM bid_increment_rules
+WHERE scope IN ('platform', 'category')
+ORDER BY scope, category, min_price;
+
+-- name: CreateBidIncrementRule :exec
+INSERT INTO bid_increment_rules (
+    scope,
+    category,
+    product_id,
+    min_price,
+    increment
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: DeletePlatformBidIncrementRules :exec
+DELETE FROM bid_increment_rules
+WHERE scope = 'platform';
+
+-- name: DeleteCategoryBidIncrementRules :exec
+DELETE FROM bid_increment_rules
+WHERE scope = 'category' AND category = $1;
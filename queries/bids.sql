@@ -3,9 +3,10 @@ INSERT INTO bids (
     product_id,
     user_id,
     price,
-    comments
+    comments,
+    quantity
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5
 );
 
 -- name: GetBidsByProductID :many
@@ -28,12 +29,44 @@ UPDATE bids
 SET is_valid = false
 WHERE id = $1;
 
+-- name: GetBidByID :one
+SELECT * FROM bids
+WHERE id = $1
+LIMIT 1;
+
+-- name: RetractBid :one
+UPDATE bids
+SET is_valid = false, retracted_at = NOW(), retracted_by = $2, retraction_reason = $3
+WHERE id = $1 AND is_valid = true
+RETURNING *;
+
+-- name: RetractBidsByBidder :many
+UPDATE bids
+SET is_valid = false, retracted_at = NOW(), retracted_by = sqlc.arg(retracted_by), retraction_reason = sqlc.arg(retraction_reason)
+WHERE product_id = sqlc.arg(product_id) AND user_id = sqlc.arg(user_id) AND is_valid = true
+RETURNING *;
+
+-- name: InvalidateBidsForProduct :exec
+UPDATE bids
+SET is_valid = false
+WHERE product_id = $1 AND is_valid = true;
+
 -- name: GetLatestBidForProduct :one
 SELECT * FROM bids
 WHERE product_id = $1 AND is_valid = true
 ORDER BY bid_at DESC
 LIMIT 1;
 
+-- name: GetBidByProductAndUser :one
+SELECT * FROM bids
+WHERE product_id = $1 AND user_id = $2 AND is_valid = true
+LIMIT 1;
+
+-- name: ReviseBid :exec
+UPDATE bids
+SET price = $2, quantity = $3, bid_at = NOW()
+WHERE id = $1;
+
 -- name: CountBidsByProduct :one
 SELECT COUNT(*) FROM bids
 WHERE product_id = $1 AND is_valid = true;
@@ -41,3 +74,8 @@ WHERE product_id = $1 AND is_valid = true;
 -- name: DeleteBid :exec
 DELETE FROM bids
 WHERE id = $1;
+
+-- name: InvalidateBidsByBidder :exec
+UPDATE bids
+SET is_valid = false
+WHERE product_id = $1 AND user_id = $2 AND is_valid = true;
@@ -0,0 +1,52 @@
+-- name: CreateFeedback :one
+INSERT INTO feedback (
+    order_id,
+    author_id,
+    recipient_id,
+    recipient_role,
+    rating,
+    comment
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetFeedbackForUpdate :one
+SELECT * FROM feedback
+WHERE id = $1
+FOR UPDATE;
+
+-- name: FeedbackExists :one
+SELECT EXISTS (
+    SELECT 1 FROM feedback
+    WHERE order_id = $1 AND author_id = $2
+) AS left_feedback;
+
+-- name: ReplyToFeedback :one
+UPDATE feedback
+SET reply = $2, replied_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetFeedbackByRecipientID :many
+SELECT * FROM feedback
+WHERE recipient_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetFeedbackByRecipientAndRole :many
+SELECT * FROM feedback
+WHERE recipient_id = $1 AND recipient_role = $2
+ORDER BY created_at DESC
+LIMIT $3 OFFSET $4;
+
+-- name: GetReputationByUserID :one
+-- Counts the ratings a user received: 4 and 5 are positive, 3 neutral, 1 and 2 negative.
+SELECT
+    COUNT(*)::bigint AS feedback_count,
+    COUNT(*) FILTER (WHERE rating >= 4)::bigint AS positive,
+    COUNT(*) FILTER (WHERE rating = 3)::bigint AS neutral,
+    COUNT(*) FILTER (WHERE rating <= 2)::bigint AS negative,
+    COUNT(*) FILTER (WHERE recipient_role = 'seller')::bigint AS as_seller,
+    COALESCE(SUM(rating), 0)::bigint AS rating_sum
+FROM feedback
+WHERE recipient_id = $1;
@@ -0,0 +1,45 @@
+-- name: GetFeeRulesForCategory :many
+-- Returns the category and platform rules that can apply to a product of the category.
+SELECT * FROM fee_rules
+WHERE scope = 'platform'
+   OR (scope = 'category' AND category = sqlc.narg(category)::text)
+ORDER BY kind, min_price;
+
+-- name: GetFeeRules :many
+SELECT * FROM fee_rules
+ORDER BY kind, scope, category, min_price;
+
+-- name: CreateFeeRule :exec
+INSERT INTO fee_rules (
+    kind,
+    scope,
+    category,
+    min_price,
+    rate_bps
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: DeletePlatformFeeRules :exec
+DELETE FROM fee_rules
+WHERE kind = $1 AND scope = 'platform';
+
+-- name: DeleteCategoryFeeRules :exec
+DELETE FROM fee_rules
+WHERE kind = $1 AND scope = 'category' AND category = $2;
+
+-- name: GetFeeRevenue :many
+-- Sums the fees earned per period and currency, net of the fees of expired invoices.
+SELECT
+    date_trunc(sqlc.arg(period)::text, created_at)::timestamp AS period_start,
+    currency,
+    COALESCE(SUM(amount) FILTER (WHERE kind = 'commission'), 0)::bigint AS commission,
+    COALESCE(SUM(amount) FILTER (WHERE kind = 'buyer_premium'), 0)::bigint AS buyer_premium,
+    COALESCE(SUM(amount) FILTER (WHERE kind = 'fee_reversal'), 0)::bigint AS reversed,
+    COUNT(*) FILTER (WHERE kind = 'commission')::bigint AS sales
+FROM journal_entries
+WHERE kind IN ('commission', 'buyer_premium', 'fee_reversal')
+  AND created_at >= sqlc.arg(from_time)::timestamp
+  AND created_at < sqlc.arg(to_time)::timestamp
+GROUP BY period_start, currency
+ORDER BY period_start, currency;
@@ -0,0 +1,160 @@
+-- name: CreateInvoice :one
+INSERT INTO invoices (
+    order_id,
+    product_id,
+    seller_id,
+    buyer_id,
+    item_price,
+    buyer_premium,
+    platform_fee,
+    shipping,
+    total,
+    deposit_applied,
+    amount_due,
+    status,
+    due_at,
+    paid_at,
+    currency
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+) RETURNING *;
+
+-- name: GetInvoiceByID :one
+SELECT * FROM invoices
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetInvoiceForUpdate :one
+SELECT * FROM invoices
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetInvoicesByBuyerID :many
+SELECT * FROM invoices
+WHERE buyer_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetInvoicesBySellerID :many
+SELECT * FROM invoices
+WHERE seller_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: UpdateInvoiceShipping :one
+UPDATE invoices
+SET shipping = sqlc.arg(shipping)::bigint, total = total - shipping + sqlc.arg(shipping)::bigint,
+    amount_due = amount_due - shipping + sqlc.arg(shipping)::bigint, updated_at = NOW()
+WHERE id = sqlc.arg(id)::uuid
+RETURNING *;
+
+-- name: GetInvoiceByOrderID :one
+SELECT * FROM invoices
+WHERE order_id = $1
+LIMIT 1;
+
+-- name: MarkInvoiceProcessing :one
+UPDATE invoices
+SET status = 'processing', updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkInvoicePending :one
+UPDATE invoices
+SET status = 'pending', updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkInvoicePaid :one
+UPDATE invoices
+SET status = 'paid', paid_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkInvoiceExpired :one
+UPDATE invoices
+SET status = 'expired', expired_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetOverdueInvoices :many
+SELECT * FROM invoices
+WHERE status = 'pending' AND due_at <= sqlc.arg(due_before)::timestamp
+    AND NOT (id = ANY(sqlc.arg(skip_ids)::uuid[]))
+ORDER BY due_at
+LIMIT sqlc.arg(page_limit);
+
+-- name: CreatePayment :one
+INSERT INTO payments (
+    invoice_id,
+    provider,
+    provider_reference,
+    amount,
+    status,
+    failure_reason
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetPaymentsByInvoiceID :many
+SELECT * FROM payments
+WHERE invoice_id = $1
+ORDER BY created_at;
+
+-- name: GetPaymentByID :one
+SELECT * FROM payments
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetProcessingPayment :one
+SELECT * FROM payments
+WHERE invoice_id = $1 AND status = 'processing'
+LIMIT 1;
+
+-- name: GetStaleProcessingPayments :many
+SELECT * FROM payments
+WHERE status = 'processing' AND created_at <= sqlc.arg(started_before)::timestamp
+    AND NOT (id = ANY(sqlc.arg(skip_ids)::uuid[]))
+ORDER BY created_at
+LIMIT sqlc.arg(page_limit);
+
+-- name: CompletePayment :one
+UPDATE payments
+SET status = sqlc.arg(status), provider_reference = sqlc.arg(provider_reference), failure_reason = sqlc.arg(failure_reason)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateNonPaymentStrike :one
+INSERT INTO non_payment_strikes (
+    user_id,
+    invoice_id
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (invoice_id) DO UPDATE SET invoice_id = EXCLUDED.invoice_id
+RETURNING *;
+
+-- name: CountNonPaymentStrikes :one
+SELECT COUNT(*) FROM non_payment_strikes
+WHERE user_id = $1;
+
+-- name: GetSellerPayouts :many
+-- Lists what the seller is paid per sold item: the item and shipping, less the platform fee.
+-- Expired invoices pay nothing and are left out.
+SELECT
+    i.id AS invoice_id,
+    i.order_id,
+    i.product_id,
+    p.title,
+    i.status,
+    i.currency,
+    (i.item_price + i.shipping)::bigint AS gross,
+    i.platform_fee AS fees,
+    (i.item_price + i.shipping - i.platform_fee)::bigint AS net,
+    i.paid_at,
+    i.created_at
+FROM invoices i
+JOIN products p ON p.id = i.product_id
+WHERE i.seller_id = $1 AND i.status <> 'expired'
+ORDER BY i.created_at DESC
+LIMIT $2 OFFSET $3;
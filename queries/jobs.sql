@@ -0,0 +1,88 @@
+-- name: EnqueueJob :one
+INSERT INTO jobs (
+    kind,
+    payload,
+    max_attempts,
+    run_at,
+    unique_key
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+ON CONFLICT (unique_key) DO NOTHING
+RETURNING *;
+
+-- name: ClaimDueJobs :many
+UPDATE jobs
+SET status = 'running', attempts = attempts + 1, locked_at = NOW(), updated_at = NOW()
+WHERE id IN (
+    SELECT id FROM jobs
+    WHERE status = 'pending' AND kind = $1 AND run_at <= NOW()
+    ORDER BY run_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteJob :exec
+UPDATE jobs
+SET status = 'succeeded', locked_at = NULL, last_error = NULL, finished_at = NOW(), updated_at = NOW()
+WHERE id = $1;
+
+-- name: RetryJobLater :exec
+UPDATE jobs
+SET status = 'pending', locked_at = NULL, last_error = $2, run_at = $3, updated_at = NOW()
+WHERE id = $1;
+
+-- name: MarkJobDead :exec
+UPDATE jobs
+SET status = 'dead', locked_at = NULL, last_error = $2, finished_at = NOW(), updated_at = NOW()
+WHERE id = $1;
+
+-- name: CreateDeadLetterJob :exec
+INSERT INTO dead_letter_jobs (
+    job_id,
+    kind,
+    payload,
+    attempts,
+    last_error
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: RequeueStaleJobs :execrows
+UPDATE jobs
+SET status = 'pending', locked_at = NULL, updated_at = NOW()
+WHERE status = 'running' AND locked_at < sqlc.arg(locked_before)::timestamp;
+
+-- name: GetJobByID :one
+SELECT * FROM jobs
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListJobs :many
+SELECT * FROM jobs
+WHERE (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(kind)::text IS NULL OR kind = sqlc.narg(kind)::text)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: RequeueJob :one
+UPDATE jobs
+SET status = 'pending', attempts = 0, run_at = NOW(), locked_at = NULL, last_error = NULL, finished_at = NULL, updated_at = NOW()
+WHERE id = $1 AND status IN ('pending', 'dead', 'cancelled')
+RETURNING *;
+
+-- name: CancelJob :one
+UPDATE jobs
+SET status = 'cancelled', finished_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: GetDeadLetterJobs :many
+SELECT * FROM dead_letter_jobs
+ORDER BY failed_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: DeleteFinishedJobs :execrows
+DELETE FROM jobs
+WHERE status IN ('succeeded', 'cancelled') AND finished_at < sqlc.arg(finished_before)::timestamp;
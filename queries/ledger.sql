@@ -0,0 +1,83 @@
+-- name: GetSystemAccount :one
+SELECT * FROM accounts
+WHERE user_id IS NULL AND kind = $1 AND currency = $2
+LIMIT 1;
+
+-- name: CreateSystemAccount :exec
+-- Platform accounts of a currency are created the first time an entry in that currency is posted.
+INSERT INTO accounts (
+    kind,
+    currency
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (kind, currency) WHERE user_id IS NULL DO NOTHING;
+
+-- name: EnsureUserAccount :one
+INSERT INTO accounts (
+    user_id,
+    kind
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (user_id, kind) DO UPDATE SET kind = EXCLUDED.kind
+RETURNING *;
+
+-- name: GetAccountForUpdate :one
+SELECT * FROM accounts
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetAccountBalance :one
+SELECT COALESCE(SUM(CASE WHEN direction = 'credit' THEN amount ELSE -amount END), 0)::bigint AS balance
+FROM postings
+WHERE account_id = $1;
+
+-- name: GetUserAccountBalances :many
+SELECT a.kind, COALESCE(SUM(CASE WHEN p.direction = 'credit' THEN p.amount ELSE -p.amount END), 0)::bigint AS balance
+FROM accounts a
+LEFT JOIN postings p ON p.account_id = a.id
+WHERE a.user_id = $1
+GROUP BY a.kind;
+
+-- name: CreateJournalEntry :one
+INSERT INTO journal_entries (
+    idempotency_key,
+    kind,
+    user_id,
+    amount,
+    reference_id,
+    description,
+    currency
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetJournalEntryByKey :one
+SELECT * FROM journal_entries
+WHERE idempotency_key = $1
+LIMIT 1;
+
+-- name: GetJournalEntriesByUserID :many
+SELECT * FROM journal_entries
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CreatePosting :exec
+INSERT INTO postings (
+    entry_id,
+    account_id,
+    direction,
+    amount
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: GetHeldAmountsByReference :many
+SELECT user_id, SUM(CASE WHEN kind = 'hold' THEN amount ELSE -amount END)::bigint AS held
+FROM journal_entries
+WHERE reference_id = $1 AND kind IN ('hold', 'release', 'capture')
+GROUP BY user_id
+HAVING SUM(CASE WHEN kind = 'hold' THEN amount ELSE -amount END) > 0
+ORDER BY user_id;
@@ -0,0 +1,100 @@
+-- name: CreateConversation :one
+-- Does nothing when the thread already exists, callers then read it with GetProductConversation or GetOrderConversation.
+INSERT INTO conversations (
+    product_id,
+    order_id,
+    seller_id,
+    buyer_id
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: GetProductConversation :one
+SELECT * FROM conversations
+WHERE product_id = $1 AND buyer_id = $2 AND order_id IS NULL;
+
+-- name: GetOrderConversation :one
+SELECT * FROM conversations
+WHERE order_id = $1;
+
+-- name: GetConversationByID :one
+SELECT * FROM conversations
+WHERE id = $1;
+
+-- name: GetConversationsByUserID :many
+-- Threads of the user, most recently active first, with the messages they have not read yet.
+SELECT c.*, (
+    SELECT COUNT(*) FROM messages m
+    WHERE m.conversation_id = c.id AND m.sender_id <> sqlc.arg(user_id) AND m.status = 'visible'
+        AND m.visible_at > COALESCE(CASE WHEN c.seller_id = sqlc.arg(user_id) THEN c.seller_last_read_at ELSE c.buyer_last_read_at END, '-infinity')
+)::bigint AS unread_count
+FROM conversations c
+WHERE c.seller_id = sqlc.arg(user_id) OR c.buyer_id = sqlc.arg(user_id)
+ORDER BY COALESCE(c.last_message_at, c.created_at) DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountUnreadMessages :one
+SELECT COUNT(*)::bigint AS unread_count
+FROM messages m
+JOIN conversations c ON c.id = m.conversation_id
+WHERE (c.seller_id = sqlc.arg(user_id) OR c.buyer_id = sqlc.arg(user_id))
+    AND m.sender_id <> sqlc.arg(user_id) AND m.status = 'visible'
+    AND m.visible_at > COALESCE(CASE WHEN c.seller_id = sqlc.arg(user_id) THEN c.seller_last_read_at ELSE c.buyer_last_read_at END, '-infinity');
+
+-- name: MarkConversationRead :one
+UPDATE conversations
+SET seller_last_read_at = CASE WHEN seller_id = sqlc.arg(user_id) THEN NOW() ELSE seller_last_read_at END,
+    buyer_last_read_at = CASE WHEN buyer_id = sqlc.arg(user_id) THEN NOW() ELSE buyer_last_read_at END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: TouchConversation :exec
+UPDATE conversations
+SET last_message_at = NOW()
+WHERE id = $1;
+
+-- name: CreateMessage :one
+INSERT INTO messages (
+    conversation_id,
+    sender_id,
+    body,
+    attachment_key,
+    status,
+    flags,
+    visible_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, CASE WHEN $5::text = 'visible' THEN NOW() END
+) RETURNING *;
+
+-- name: GetMessagesByConversationID :many
+-- Messages the viewer can see, newest first: visible ones and their own held ones.
+SELECT * FROM messages
+WHERE conversation_id = sqlc.arg(conversation_id)
+    AND (status = 'visible' OR (status = 'held' AND sender_id = sqlc.arg(viewer_id)))
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountRecentMessagesBySender :one
+SELECT COUNT(*)::bigint AS sent
+FROM messages
+WHERE sender_id = sqlc.arg(sender_id) AND created_at > sqlc.arg(since)::timestamp;
+
+-- name: GetHeldMessages :many
+SELECT * FROM messages
+WHERE status = 'held'
+ORDER BY created_at
+LIMIT $1 OFFSET $2;
+
+-- name: GetMessageForUpdate :one
+SELECT * FROM messages
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ModerateMessage :one
+UPDATE messages
+SET status = sqlc.arg(status), moderated_by = sqlc.arg(moderated_by), moderated_at = NOW(),
+    visible_at = CASE WHEN sqlc.arg(status)::text = 'visible' THEN NOW() ELSE visible_at END
+WHERE id = sqlc.arg(id)
+RETURNING *;
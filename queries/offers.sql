@@ -0,0 +1,62 @@
+-- name: CreateOffer :one
+INSERT INTO offers (
+    product_id,
+    buyer_id,
+    seller_id,
+    parent_id,
+    made_by,
+    price,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetOfferByID :one
+SELECT * FROM offers
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetOfferForUpdate :one
+SELECT * FROM offers
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetPendingOfferForBuyer :one
+SELECT * FROM offers
+WHERE product_id = $1 AND buyer_id = $2 AND status = 'pending'
+LIMIT 1;
+
+-- name: GetOffersByProductID :many
+SELECT * FROM offers
+WHERE product_id = $1
+ORDER BY created_at;
+
+-- name: GetOffersByBuyerID :many
+SELECT * FROM offers
+WHERE buyer_id = sqlc.arg(buyer_id)
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetOffersBySellerID :many
+SELECT * FROM offers
+WHERE seller_id = sqlc.arg(seller_id)
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: RespondToOffer :one
+UPDATE offers
+SET status = $2, responded_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeclineOpenOffersForProduct :exec
+UPDATE offers
+SET status = 'declined', responded_at = NOW(), updated_at = NOW()
+WHERE product_id = $1 AND status = 'pending';
+
+-- name: GetExpiredOffers :many
+SELECT * FROM offers
+WHERE status = 'pending' AND expires_at <= sqlc.arg(expired_before)::timestamp
+    AND NOT (id = ANY(sqlc.arg(skip_ids)::uuid[]))
+ORDER BY expires_at
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,63 @@
+-- name: CreateOrder :one
+INSERT INTO orders (
+    product_id,
+    bid_id,
+    seller_id,
+    buyer_id,
+    quantity,
+    unit_price,
+    total_price
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetOrderByID :one
+SELECT * FROM orders
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetOrderForUpdate :one
+SELECT * FROM orders
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateOrderShipping :one
+UPDATE orders
+SET shipping_method = $2, shipping_cost = $3, ship_to_name = $4, ship_to_line1 = $5, ship_to_line2 = $6,
+    ship_to_city = $7, ship_to_state = $8, ship_to_postal_code = $9, ship_to_country = $10, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkOrderShipped :one
+UPDATE orders
+SET fulfilment_status = 'shipped', carrier = $2, tracking_number = $3, shipped_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkOrderDelivered :one
+UPDATE orders
+SET fulfilment_status = 'delivered', delivered_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: CancelOrderFulfilment :exec
+UPDATE orders
+SET fulfilment_status = 'cancelled', updated_at = NOW()
+WHERE id = $1 AND fulfilment_status = 'awaiting_shipment';
+
+-- name: GetOrdersByProductID :many
+SELECT * FROM orders
+WHERE product_id = $1
+ORDER BY unit_price DESC, created_at;
+
+-- name: GetOrdersByBuyerID :many
+SELECT * FROM orders
+WHERE buyer_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetOrdersBySellerID :many
+SELECT * FROM orders
+WHERE seller_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
@@ -0,0 +1,30 @@
+-- name: InsertOutboxEvent :exec
+INSERT INTO outbox (
+    event_id,
+    event_type,
+    aggregate_id,
+    payload
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: ClaimPendingOutboxEvents :many
+SELECT * FROM outbox
+WHERE sent_at IS NULL AND next_attempt_at <= NOW()
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventSent :exec
+UPDATE outbox
+SET sent_at = NOW(), attempts = attempts + 1, last_error = NULL
+WHERE id = $1;
+
+-- name: MarkOutboxEventFailed :exec
+UPDATE outbox
+SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+WHERE id = $1;
+
+-- name: DeleteSentOutboxEvents :execrows
+DELETE FROM outbox
+WHERE sent_at IS NOT NULL AND sent_at < sqlc.arg(sent_before)::timestamp;
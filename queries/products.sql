@@ -5,9 +5,33 @@ INSERT INTO products (
     seller_id,
     images,
     min_price,
-    current_price
+    current_price,
+    ends_at,
+    soft_close_window_minutes,
+    soft_close_extension_minutes,
+    max_extensions,
+    category,
+    buy_now_price,
+    auction_type,
+    dutch_price_step,
+    dutch_interval_seconds,
+    next_price_drop_at,
+    quantity,
+    pricing_rule,
+    status,
+    starts_at,
+    start_price,
+    relisted_from,
+    auto_accept_price,
+    auto_decline_price,
+    visibility,
+    deposit_type,
+    deposit_amount,
+    second_chance_on_non_payment,
+    currency,
+    min_bidder_reputation
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30
 ) RETURNING *;
 
 -- name: GetProductImages :one
@@ -28,17 +52,98 @@ LIMIT 1;
 
 -- name: GetProductsBySellerID :many
 SELECT * FROM products
-WHERE seller_id = $1
+WHERE seller_id = sqlc.arg(seller_id) AND status = ANY(sqlc.arg(statuses)::text[])
+    AND (visibility = 'public' OR seller_id = sqlc.arg(viewer_id) OR EXISTS (
+        SELECT 1 FROM product_invitations i
+        WHERE i.product_id = products.id AND i.user_id = sqlc.arg(viewer_id)
+    ))
 ORDER BY created_at DESC
-LIMIT $2 OFFSET $3;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: MarkProductAsSold :one
 UPDATE products
-SET sold_at = NOW(), sold_to = $2, current_price = $3, updated_at = NOW()
+SET sold_at = NOW(), sold_to = $2, current_price = $3, closed_at = NOW(), status = 'ended', updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkProductAsSoldToWinners :one
+UPDATE products
+SET sold_at = NOW(), sold_to = NULL, current_price = $2, closed_at = NOW(), status = 'ended', updated_at = NOW()
 WHERE id = $1
 RETURNING *;
 
 -- name: UpdateProductCurrentPrice :exec
 UPDATE products
 SET current_price = $2, updated_at = NOW()
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetProductForUpdate :one
+SELECT * FROM products
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ExtendProductEndsAt :exec
+UPDATE products
+SET ends_at = $2, extension_count = extension_count + 1, updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetAuctionsToSettle :many
+SELECT * FROM products
+WHERE status = 'live' AND closed_at IS NULL AND ends_at <= sqlc.arg(ended_before)
+    AND NOT (id = ANY(sqlc.arg(skip_ids)::uuid[]))
+ORDER BY ends_at
+LIMIT sqlc.arg(page_limit);
+
+-- name: ReopenUnpaidProduct :one
+UPDATE products
+SET sold_at = NULL, sold_to = NULL, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: CloseProduct :exec
+UPDATE products
+SET closed_at = NOW(), status = 'ended', updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetDueDutchPriceDrops :many
+SELECT * FROM products
+WHERE auction_type = 'dutch' AND status = 'live' AND closed_at IS NULL AND next_price_drop_at <= sqlc.arg(due_before)::timestamp
+    AND NOT (id = ANY(sqlc.arg(skip_ids)::uuid[]))
+ORDER BY next_price_drop_at
+LIMIT sqlc.arg(page_limit);
+
+-- name: DropProductPrice :exec
+UPDATE products
+SET current_price = $2, next_price_drop_at = $3, updated_at = NOW()
+WHERE id = $1;
+
+-- name: UpdateProductListing :one
+UPDATE products
+SET title = $2, description = $3, images = $4, min_price = $5, current_price = $6, start_price = $7,
+    ends_at = $8, category = $9, buy_now_price = $10, next_price_drop_at = $11, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: StartProduct :one
+UPDATE products
+SET status = 'live', starts_at = $2, ends_at = $3, next_price_drop_at = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ScheduleProduct :one
+UPDATE products
+SET status = 'scheduled', starts_at = $2, ends_at = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkProductRelisted :exec
+UPDATE products
+SET status = 'relisted', updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetDueScheduledProducts :many
+SELECT * FROM products
+WHERE status = 'scheduled' AND starts_at <= sqlc.arg(due_before)::timestamp
+    AND NOT (id = ANY(sqlc.arg(skip_ids)::uuid[]))
+ORDER BY starts_at
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,45 @@
+-- name: CreateSecondChanceOffer :one
+INSERT INTO second_chance_offers (
+    product_id,
+    bid_id,
+    seller_id,
+    bidder_id,
+    price,
+    auto_advance,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetSecondChanceOfferByID :one
+SELECT * FROM second_chance_offers
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetSecondChanceOfferForUpdate :one
+SELECT * FROM second_chance_offers
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetSecondChanceOffersByProductID :many
+SELECT * FROM second_chance_offers
+WHERE product_id = $1
+ORDER BY created_at;
+
+-- name: GetPendingSecondChanceOffersByBidder :many
+SELECT * FROM second_chance_offers
+WHERE bidder_id = $1 AND status = 'pending'
+ORDER BY expires_at;
+
+-- name: RespondToSecondChanceOffer :one
+UPDATE second_chance_offers
+SET status = $2, responded_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetExpiredSecondChanceOffers :many
+SELECT * FROM second_chance_offers
+WHERE status = 'pending' AND expires_at <= sqlc.arg(expired_before)::timestamp
+    AND NOT (id = ANY(sqlc.arg(skip_ids)::uuid[]))
+ORDER BY expires_at
+LIMIT sqlc.arg(page_limit);
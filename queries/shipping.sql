@@ -0,0 +1,19 @@
+-- name: CreateShippingOption :one
+INSERT INTO shipping_options (
+    product_id,
+    method,
+    region,
+    cost
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetShippingOptionsByProductID :many
+SELECT * FROM shipping_options
+WHERE product_id = $1
+ORDER BY cost, method, region;
+
+-- name: GetShippingOptionByID :one
+SELECT * FROM shipping_options
+WHERE id = $1
+LIMIT 1;
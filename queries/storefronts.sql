@@ -0,0 +1,25 @@
+-- name: GetSoldProductsBySellerID :many
+SELECT * FROM products
+WHERE seller_id = $1 AND visibility = 'public'
+    AND EXISTS (SELECT 1 FROM orders o WHERE o.product_id = products.id)
+ORDER BY closed_at DESC NULLS LAST, created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetFeaturedListings :many
+SELECT p.* FROM featured_listings f
+JOIN products p ON p.id = f.product_id
+WHERE f.seller_id = $1 AND p.visibility = 'public' AND p.status <> 'draft'
+ORDER BY f.position;
+
+-- name: DeleteFeaturedListings :exec
+DELETE FROM featured_listings
+WHERE seller_id = $1;
+
+-- name: AddFeaturedListing :exec
+INSERT INTO featured_listings (
+    seller_id,
+    product_id,
+    position
+) VALUES (
+    $1, $2, $3
+);
@@ -20,4 +20,40 @@ LIMIT 1;
 -- name: GetUserByUsername :one
 SELECT * FROM users
 WHERE username = $1 AND deleted_at IS NULL
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
+
+-- name: UpdateUserProfile :one
+UPDATE users
+SET username = $2, bio = $3, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SetUserAvatar :one
+UPDATE users
+SET avatar_key = $2, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SetUserBanner :one
+UPDATE users
+SET banner_key = $2, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SetPendingEmail :one
+UPDATE users
+SET pending_email = $2, email_verification_token_hash = $3, email_verification_expires_at = $4, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ConfirmPendingEmail :one
+UPDATE users
+SET email = pending_email, pending_email = NULL, email_verification_token_hash = NULL,
+    email_verification_expires_at = NULL, updated_at = NOW()
+WHERE id = $1 AND pending_email IS NOT NULL
+RETURNING *;
+
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE;
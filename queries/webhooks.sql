@@ -0,0 +1,101 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (
+    seller_id,
+    url,
+    secret,
+    event_types
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhookEndpointByID :one
+SELECT * FROM webhook_endpoints
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetWebhookEndpointsBySellerID :many
+SELECT * FROM webhook_endpoints
+WHERE seller_id = $1
+ORDER BY created_at DESC;
+
+-- name: GetActiveWebhookEndpointsForEvent :many
+SELECT * FROM webhook_endpoints
+WHERE seller_id = $1 AND is_active = true AND sqlc.arg(event_type)::text = ANY(event_types);
+
+-- name: DeleteWebhookEndpoint :execrows
+DELETE FROM webhook_endpoints
+WHERE id = $1 AND seller_id = $2;
+
+-- name: CreateWebhookDelivery :exec
+INSERT INTO webhook_deliveries (
+    endpoint_id,
+    event_id,
+    event_type,
+    payload
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (endpoint_id, event_id) DO NOTHING;
+
+-- name: GetWebhookDeliveryByID :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetWebhookDeliveriesByEndpointID :many
+SELECT * FROM webhook_deliveries
+WHERE endpoint_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ClaimDueWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET status = 'delivering', updated_at = NOW()
+WHERE id IN (
+    SELECT id FROM webhook_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RequeueStaleWebhookDeliveries :execrows
+UPDATE webhook_deliveries
+SET status = 'pending', updated_at = NOW()
+WHERE status = 'delivering' AND updated_at < NOW() - INTERVAL '5 minutes';
+
+-- name: RecordWebhookDeliveryResult :exec
+UPDATE webhook_deliveries
+SET status = $2,
+    attempts = attempts + 1,
+    last_response_code = $3,
+    last_error = $4,
+    next_attempt_at = $5,
+    delivered_at = $6,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: ResetWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = 'pending',
+    next_attempt_at = NOW(),
+    max_attempts = GREATEST(max_attempts, attempts + 1),
+    updated_at = NOW()
+WHERE id = $1 AND status <> 'delivering'
+RETURNING *;
+
+-- name: CreateWebhookDeliveryAttempt :exec
+INSERT INTO webhook_delivery_attempts (
+    delivery_id,
+    attempt,
+    response_code,
+    error,
+    duration_ms
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: GetWebhookDeliveryAttempts :many
+SELECT * FROM webhook_delivery_attempts
+WHERE delivery_id = $1
+ORDER BY attempted_at ASC;
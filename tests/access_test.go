@@ -0,0 +1,221 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callAccessEndpoint calls a blocklist or private listing handler as user with the given URL parameters,
+// body is only sent when given
+func callAccessEndpoint(t *testing.T, user *TestUser, params map[string]string, body map[string]interface{}, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		require.NoError(t, err, "Should marshal payload")
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/access", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	rctx := chi.NewRouteContext()
+	for key, value := range params {
+		rctx.URLParams.Add(key, value)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+// viewTestProduct fetches a product as viewer, anonymously when viewer is nil
+func viewTestProduct(t *testing.T, env *TestEnv, viewer *TestUser, productID string) int {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/products/%s", productID), nil)
+	req = addProductIDToContext(req, productID)
+	if viewer != nil {
+		req = addProductAuthContext(req, viewer)
+	}
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.GetProductByID(w, req)
+	return w.Code
+}
+
+// viewTestProductImages fetches the image URLs of a product as viewer, anonymously when viewer is nil
+func viewTestProductImages(t *testing.T, env *TestEnv, viewer *TestUser, productID string) int {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/products/images?productId=%s", productID), nil)
+	if viewer != nil {
+		req = addProductAuthContext(req, viewer)
+	}
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.GetProductImageUrls(w, req)
+	return w.Code
+}
+
+// sellerTestProductIDs lists the IDs of the seller's products visible to viewer
+func sellerTestProductIDs(t *testing.T, env *TestEnv, viewer *TestUser, seller *TestUser) []string {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/products/seller/%s?limit=100", seller.UserID), nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("sellerId", seller.UserID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = addProductAuthContext(req, viewer)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.ProductsBySellerID(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	var ids []string
+	for _, p := range response["data"].(map[string]interface{})["products"].([]interface{}) {
+		ids = append(ids, p.(map[string]interface{})["id"].(string))
+	}
+	return ids
+}
+
+// TestBlockedBidderCannotBid tests that sellers can block and unblock bidders on all of their listings
+func TestBlockedBidderCannotBid(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(4)
+	blocked := GetTestUser(7)
+	other := GetTestUser(2)
+	require.NotNil(t, seller)
+	require.NotNil(t, blocked)
+	require.NotNil(t, other)
+	handler := env.Dependencies.AccessHandler
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Guarded Guitar",
+		"min_price":     100,
+		"current_price": 100,
+	})
+
+	w := callAccessEndpoint(t, seller, nil, map[string]interface{}{"bidder_id": seller.UserID.String()}, handler.BlockBidder)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "SELF_BLOCKING_NOT_ALLOWED")
+
+	w = callAccessEndpoint(t, seller, nil, map[string]interface{}{"bidder_id": blocked.UserID.String(), "reason": "never pays"}, handler.BlockBidder)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	t.Cleanup(func() {
+		_ = env.Dependencies.Services.AccessService.UnblockBidder(context.Background(), seller.UserID, blocked.UserID.String())
+	})
+	assert.Contains(t, w.Body.String(), "never pays")
+
+	w = placeTestBid(t, env, blocked, productID, 110)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "BIDDER_BLOCKED")
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, other, productID, 110).Code, "Other bidders are not affected")
+
+	w = callAccessEndpoint(t, seller, nil, nil, handler.ListBlockedBidders)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), blocked.UserID.String())
+
+	w = callAccessEndpoint(t, seller, map[string]string{"bidderId": blocked.UserID.String()}, nil, handler.UnblockBidder)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusOK, placeTestBid(t, env, blocked, productID, 120).Code, "Unblocked bidders can bid again")
+
+	w = callAccessEndpoint(t, seller, map[string]string{"bidderId": blocked.UserID.String()}, nil, handler.UnblockBidder)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "BLOCK_NOT_FOUND")
+}
+
+// TestPrivateListingAccess tests that private listings are only visible and open to invited users and code holders
+func TestPrivateListingAccess(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(4)
+	invitee := GetTestUser(2)
+	codeHolder := GetTestUser(7)
+	require.NotNil(t, seller)
+	require.NotNil(t, invitee)
+	require.NotNil(t, codeHolder)
+	handler := env.Dependencies.AccessHandler
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Private Collection Watch",
+		"min_price":     500,
+		"current_price": 500,
+		"visibility":    "private",
+	})
+	product := map[string]string{"productId": productID}
+
+	assert.Equal(t, http.StatusNotFound, viewTestProduct(t, env, nil, productID), "Anonymous visitors do not find private listings")
+	assert.Equal(t, http.StatusNotFound, viewTestProduct(t, env, invitee, productID))
+	assert.Equal(t, http.StatusOK, viewTestProduct(t, env, seller, productID))
+	assert.Equal(t, http.StatusNotFound, viewTestProductImages(t, env, nil, productID), "The images of private listings are hidden as well")
+	assert.Equal(t, http.StatusNotFound, viewTestProductImages(t, env, invitee, productID))
+	assert.Equal(t, http.StatusOK, viewTestProductImages(t, env, seller, productID))
+	assert.Equal(t, http.StatusNotFound, placeTestBid(t, env, invitee, productID, 550).Code)
+	assert.NotContains(t, sellerTestProductIDs(t, env, invitee, seller), productID, "Private listings are left out of public listings")
+	assert.Contains(t, sellerTestProductIDs(t, env, seller, seller), productID)
+
+	// Invitations by the seller
+	w := callAccessEndpoint(t, invitee, product, map[string]interface{}{"user_id": invitee.UserID.String()}, handler.InviteUser)
+	assert.Equal(t, http.StatusForbidden, w.Code, "Only the seller manages invitations")
+	w = callAccessEndpoint(t, seller, product, map[string]interface{}{"user_id": invitee.UserID.String()}, handler.InviteUser)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	assert.Equal(t, http.StatusOK, viewTestProduct(t, env, invitee, productID))
+	assert.Equal(t, http.StatusOK, viewTestProductImages(t, env, invitee, productID))
+	assert.Contains(t, sellerTestProductIDs(t, env, invitee, seller), productID)
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, invitee, productID, 550).Code)
+
+	// Access codes handed out by the seller
+	w = callAccessEndpoint(t, seller, product, nil, handler.RotateAccessCode)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	code := response["data"].(map[string]interface{})["access_code"].(map[string]interface{})["code"].(string)
+	require.Len(t, code, 8)
+
+	w = callAccessEndpoint(t, codeHolder, product, map[string]interface{}{"access_code": "WRONGONE"}, handler.RedeemAccessCode)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_ACCESS_CODE")
+	assert.Equal(t, http.StatusNotFound, viewTestProduct(t, env, codeHolder, productID))
+
+	w = callAccessEndpoint(t, codeHolder, product, map[string]interface{}{"access_code": strings.ToLower(code)}, handler.RedeemAccessCode)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusOK, viewTestProduct(t, env, codeHolder, productID))
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, codeHolder, productID, 600).Code)
+
+	w = callAccessEndpoint(t, seller, product, nil, handler.ListInvitations)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"source":"seller"`)
+	assert.Contains(t, w.Body.String(), `"source":"access_code"`)
+
+	// Removed invitees lose access, their bids stay
+	w = callAccessEndpoint(t, seller, map[string]string{"productId": productID, "userId": invitee.UserID.String()}, nil, handler.RemoveInvitation)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusNotFound, viewTestProduct(t, env, invitee, productID))
+	var validBids int
+	err := env.Dependencies.Conn.QueryRow(env.Context, "SELECT COUNT(*) FROM bids WHERE product_id = $1 AND is_valid", productID).Scan(&validBids)
+	require.NoError(t, err)
+	assert.Equal(t, 2, validBids)
+
+	publicID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Public Watch",
+		"min_price":     500,
+		"current_price": 500,
+	})
+	w = callAccessEndpoint(t, seller, map[string]string{"productId": publicID}, map[string]interface{}{"user_id": invitee.UserID.String()}, handler.InviteUser)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "PRODUCT_NOT_PRIVATE")
+
+	// Redeeming tells public and missing listings apart from private ones no more than a wrong code does
+	for _, id := range []string{publicID, uuid.NewString()} {
+		w = callAccessEndpoint(t, codeHolder, map[string]string{"productId": id}, map[string]interface{}{"access_code": code}, handler.RedeemAccessCode)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_ACCESS_CODE")
+	}
+}
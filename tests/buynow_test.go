@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buyTestProduct buys a product through the handler and returns the recorded response
+func buyTestProduct(env *TestEnv, buyer *TestUser, productID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/products/%s/buy", productID), nil)
+	req.Header.Set("Authorization", "Bearer "+buyer.AccessToken)
+	req = addProductIDToContext(req, productID)
+	req = addProductAuthContext(req, buyer)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.BuyNow(w, req)
+	return w
+}
+
+// TestBuyNowClosesAuction tests that buying a product sells it and closes the auction
+func TestBuyNowClosesAuction(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(5)
+	buyer := GetTestUser(6)
+	bidder := GetTestUser(8)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyer)
+	require.NotNil(t, bidder)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Buy Now Product",
+		"min_price":     50,
+		"current_price": 50,
+		"buy_now_price": 200,
+	})
+
+	var mu sync.Mutex
+	var closed []events.Event
+	env.Dependencies.Bus.Subscribe(func(ctx context.Context, e events.Event) error {
+		if e.AggregateID.String() != productID {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		closed = append(closed, e)
+		return nil
+	}, events.AuctionClosed)
+
+	assert.Equal(t, true, getTestProduct(t, env, productID)["buy_now_available"])
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, productID, 60).Code, "Bid below the threshold should succeed")
+	assert.Equal(t, true, getTestProduct(t, env, productID)["buy_now_available"], "Low bids should keep buy now available")
+
+	assert.Equal(t, http.StatusForbidden, buyTestProduct(env, seller, productID).Code, "Seller should not buy their own product")
+	require.Equal(t, http.StatusOK, buyTestProduct(env, buyer, productID).Code, "Buy now should succeed")
+
+	product := getTestProduct(t, env, productID)
+	assert.Equal(t, buyer.UserID.String(), product["sold_to"])
+	assert.EqualValues(t, 200, product["current_price"])
+	assert.Equal(t, false, product["buy_now_available"])
+
+	w := placeTestBid(t, env, bidder, productID, 300)
+	assert.Equal(t, http.StatusConflict, w.Code, "Bids after the purchase should be rejected")
+	w = buyTestProduct(env, bidder, productID)
+	assert.Equal(t, http.StatusConflict, w.Code, "Product should not be sold twice")
+	assert.Contains(t, w.Body.String(), "AUCTION_ENDED")
+
+	_, err := env.Dependencies.OutboxRelay.RelayPending(env.Context)
+	require.NoError(t, err)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, closed, 1, "Auction closed event should be published once")
+	assert.Contains(t, string(closed[0].Payload), `"reason":"buy_now"`)
+}
+
+// TestBuyNowWithdrawnByBidding tests that buy now disappears once bids get close to the buy-now price
+func TestBuyNowWithdrawnByBidding(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(5)
+	buyer := GetTestUser(6)
+	bidder := GetTestUser(8)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyer)
+	require.NotNil(t, bidder)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Popular Buy Now Product",
+		"min_price":     50,
+		"current_price": 50,
+		"buy_now_price": 200,
+	})
+
+	// 150 is 75% of the buy-now price
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, productID, 150).Code)
+	assert.Equal(t, false, getTestProduct(t, env, productID)["buy_now_available"])
+
+	w := buyTestProduct(env, buyer, productID)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "BUY_NOW_UNAVAILABLE")
+	assert.Nil(t, getTestProduct(t, env, productID)["sold_to"], "Product should stay on auction")
+}
+
+// TestBuyNowRacesFinalBid tests that a purchase and a bid crossing the threshold never both succeed
+func TestBuyNowRacesFinalBid(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(5)
+	buyer := GetTestUser(6)
+	bidder := GetTestUser(8)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyer)
+	require.NotNil(t, bidder)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Contested Buy Now Product",
+		"min_price":     50,
+		"current_price": 50,
+		"buy_now_price": 200,
+	})
+
+	var wg sync.WaitGroup
+	var bidCode, buyCode int
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		bidCode = placeTestBid(t, env, bidder, productID, 180).Code
+	}()
+	go func() {
+		defer wg.Done()
+		buyCode = buyTestProduct(env, buyer, productID).Code
+	}()
+	wg.Wait()
+
+	product := getTestProduct(t, env, productID)
+	if buyCode == http.StatusOK {
+		assert.Equal(t, http.StatusConflict, bidCode, "Bid should lose to the purchase")
+		assert.Equal(t, buyer.UserID.String(), product["sold_to"])
+	} else {
+		assert.Equal(t, http.StatusOK, bidCode, "Either the bid or the purchase should win")
+		assert.Equal(t, http.StatusConflict, buyCode, "Purchase should lose to the bid")
+		assert.Nil(t, product["sold_to"])
+		assert.EqualValues(t, 180, product["current_price"])
+	}
+}
@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/itsDrac/e-auc/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fundTestWallet empties the available funds of user and deposits amount, returning the wallet
+func fundTestWallet(t *testing.T, env *TestEnv, user *TestUser, amount int64) service.Wallet {
+	svc := env.Dependencies.Services.WalletService
+	wallet, err := svc.GetWallet(env.Context, user.UserID)
+	require.NoError(t, err)
+	if wallet.Available > 0 {
+		_, err = svc.Withdraw(env.Context, user.UserID, wallet.Available, uuid.NewString())
+		require.NoError(t, err)
+	}
+	_, err = svc.Deposit(env.Context, user.UserID, amount, uuid.NewString(), "tok_visa")
+	require.NoError(t, err)
+	wallet, err = svc.GetWallet(env.Context, user.UserID)
+	require.NoError(t, err)
+	return wallet
+}
+
+// TestBidDepositHolds tests that bid deposits are held while winning, released when outbid and captured on win
+func TestBidDepositHolds(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(1)
+	outbid := GetTestUser(5)
+	winner := GetTestUser(9)
+	require.NotNil(t, seller)
+	require.NotNil(t, outbid)
+	require.NotNil(t, winner)
+	svc := env.Dependencies.Services.WalletService
+
+	outbidBefore := fundTestWallet(t, env, outbid, 200)
+	winnerBefore := fundTestWallet(t, env, winner, 200)
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Deposit Drum Kit",
+		"min_price":     100,
+		"current_price": 100,
+		"deposit_type":  "bid_amount",
+	})
+
+	w := placeTestBid(t, env, outbid, productID, 250)
+	assert.Equal(t, http.StatusPaymentRequired, w.Code)
+	assert.Contains(t, w.Body.String(), "INSUFFICIENT_FUNDS")
+
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, outbid, productID, 150).Code)
+	wallet, err := svc.GetWallet(env.Context, outbid.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), wallet.Available)
+	assert.Equal(t, outbidBefore.Held+150, wallet.Held)
+
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, winner, productID, 160).Code)
+	wallet, err = svc.GetWallet(env.Context, outbid.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, outbidBefore, wallet, "Outbid bidders get their deposit back")
+	wallet, err = svc.GetWallet(env.Context, winner.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), wallet.Available)
+
+	endTestAuction(t, env, productID)
+	wallet, err = svc.GetWallet(env.Context, winner.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), wallet.Available)
+	assert.Equal(t, winnerBefore.Held, wallet.Held, "The winner's deposit is captured")
+	wallet, err = svc.GetWallet(env.Context, outbid.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, outbidBefore, wallet)
+}
+
+// TestConcurrentBidDepositHolds tests that concurrent bids cannot hold the same funds twice
+func TestConcurrentBidDepositHolds(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(1)
+	bidder := GetTestUser(5)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+	fundTestWallet(t, env, bidder, 100)
+
+	var productIDs []string
+	for _, title := range []string{"Fixed Deposit Lamp", "Fixed Deposit Chair", "Fixed Deposit Desk"} {
+		productIDs = append(productIDs, createTestProduct(t, env, seller, map[string]interface{}{
+			"title":          title,
+			"min_price":      100,
+			"current_price":  100,
+			"deposit_type":   "fixed",
+			"deposit_amount": 100,
+		}))
+	}
+
+	codes := make([]int, len(productIDs))
+	var wg sync.WaitGroup
+	for i, productID := range productIDs {
+		wg.Add(1)
+		go func(i int, productID string) {
+			defer wg.Done()
+			codes[i] = placeTestBid(t, env, bidder, productID, 150).Code
+		}(i, productID)
+	}
+	wg.Wait()
+
+	var placed, refused int
+	for _, code := range codes {
+		switch code {
+		case http.StatusOK:
+			placed++
+		case http.StatusPaymentRequired:
+			refused++
+		}
+	}
+	assert.Equal(t, 1, placed, "Only one bid can hold the funds")
+	assert.Equal(t, 2, refused)
+
+	wallet, err := env.Dependencies.Services.WalletService.GetWallet(env.Context, bidder.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), wallet.Available)
+}
+
+// TestDepositForfeitedOnNonPayment tests that the deposit captured from a winner who never pays leaves escrow for the seller
+func TestDepositForfeitedOnNonPayment(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(1)
+	winner := GetTestUser(9)
+	require.NotNil(t, seller)
+	require.NotNil(t, winner)
+	svc := env.Dependencies.Services.WalletService
+
+	fundTestWallet(t, env, winner, 500)
+	sellerBefore, err := svc.GetWallet(env.Context, seller.UserID)
+	require.NoError(t, err)
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":          "Forfeit Bass Guitar",
+		"min_price":      100,
+		"current_price":  100,
+		"deposit_type":   "fixed",
+		"deposit_amount": 50,
+	})
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, winner, productID, 300).Code)
+	endTestAuction(t, env, productID)
+
+	invoice := findTestInvoice(t, env, winner, "buyer", productID)
+	require.NotNil(t, invoice)
+	assert.Equal(t, float64(50), invoice["deposit_applied"])
+	escrowBefore := systemTestBalance(t, env, "escrow", "USD")
+
+	_, err = env.Dependencies.Conn.Exec(env.Context, "UPDATE invoices SET due_at = NOW() - INTERVAL '1 second' WHERE id = $1", invoice["id"])
+	require.NoError(t, err)
+	_, err = env.Dependencies.Services.InvoiceService.ExpireInvoices(env.Context)
+	require.NoError(t, err)
+	assert.Equal(t, "expired", findTestInvoice(t, env, winner, "buyer", productID)["status"])
+
+	assert.Equal(t, escrowBefore-50, systemTestBalance(t, env, "escrow", "USD"), "Nothing is left in escrow")
+	sellerAfter, err := svc.GetWallet(env.Context, seller.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, sellerBefore.Available+50, sellerAfter.Available, "The seller keeps the forfeited deposit")
+	winnerAfter, err := svc.GetWallet(env.Context, winner.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(450), winnerAfter.Available, "The winner does not get the deposit back")
+}
@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// acceptTestPrice accepts the current price of a dutch auction through the handler
+func acceptTestPrice(env *TestEnv, buyer *TestUser, productID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/products/%s/accept", productID), nil)
+	req = addProductIDToContext(req, productID)
+	req = addProductAuthContext(req, buyer)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.AcceptPrice(w, req)
+	return w
+}
+
+// dueTestPriceDrop moves the next price drop of a dutch auction into the past
+func dueTestPriceDrop(t *testing.T, env *TestEnv, productID string, ago string) {
+	_, err := env.Dependencies.Conn.Exec(env.Context, "UPDATE products SET next_price_drop_at = NOW() - $2::interval WHERE id = $1", productID, ago)
+	require.NoError(t, err)
+}
+
+// TestDutchAuctionPriceDrops tests that the scheduler lowers the price step by step down to the floor
+func TestDutchAuctionPriceDrops(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(2)
+	bidder := GetTestUser(3)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":                  "Dutch Clearance",
+		"min_price":              60,
+		"current_price":          100,
+		"auction_type":           "dutch",
+		"dutch_price_step":       10,
+		"dutch_interval_seconds": 60,
+	})
+
+	var mu sync.Mutex
+	var drops []events.Event
+	env.Dependencies.Bus.Subscribe(func(ctx context.Context, e events.Event) error {
+		if e.AggregateID.String() != productID {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		drops = append(drops, e)
+		return nil
+	}, events.AuctionPriceDropped)
+
+	w := placeTestBid(t, env, bidder, productID, 100)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "BIDDING_NOT_SUPPORTED")
+
+	// Not due yet
+	_, err := env.Dependencies.Services.ProductService.DropDutchPrices(env.Context)
+	require.NoError(t, err)
+	assert.EqualValues(t, 100, getTestProduct(t, env, productID)["current_price"])
+
+	// Three intervals have passed since the drop was due
+	dueTestPriceDrop(t, env, productID, "130 seconds")
+	_, err = env.Dependencies.Services.ProductService.DropDutchPrices(env.Context)
+	require.NoError(t, err)
+	product := getTestProduct(t, env, productID)
+	assert.EqualValues(t, 70, product["current_price"])
+	assert.NotNil(t, product["next_price_drop_at"])
+
+	// The price never goes below the floor
+	dueTestPriceDrop(t, env, productID, "1 hour")
+	_, err = env.Dependencies.Services.ProductService.DropDutchPrices(env.Context)
+	require.NoError(t, err)
+	product = getTestProduct(t, env, productID)
+	assert.EqualValues(t, 60, product["current_price"])
+	assert.Nil(t, product["next_price_drop_at"], "Drops should stop at the floor")
+
+	_, err = env.Dependencies.OutboxRelay.RelayPending(env.Context)
+	require.NoError(t, err)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, drops, 2, "Each drop should be broadcast")
+	assert.Contains(t, string(drops[1].Payload), `"price":60`)
+}
+
+// TestDutchAuctionAccept tests that the first accept wins at the current price
+func TestDutchAuctionAccept(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(2)
+	buyerA := GetTestUser(3)
+	buyerB := GetTestUser(4)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyerA)
+	require.NotNil(t, buyerB)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":                  "Dutch Race",
+		"min_price":              50,
+		"current_price":          90,
+		"auction_type":           "dutch",
+		"dutch_price_step":       5,
+		"dutch_interval_seconds": 60,
+	})
+	assert.Equal(t, http.StatusForbidden, acceptTestPrice(env, seller, productID).Code)
+
+	// A due drop the scheduler has not applied yet is honoured
+	dueTestPriceDrop(t, env, productID, "1 second")
+
+	var wg sync.WaitGroup
+	codes := make([]int, 2)
+	for i, buyer := range []*TestUser{buyerA, buyerB} {
+		wg.Add(1)
+		go func(i int, buyer *TestUser) {
+			defer wg.Done()
+			codes[i] = acceptTestPrice(env, buyer, productID).Code
+		}(i, buyer)
+	}
+	wg.Wait()
+	assert.ElementsMatch(t, []int{http.StatusOK, http.StatusConflict}, codes, "Exactly one accept should win")
+
+	winner := buyerA
+	if codes[1] == http.StatusOK {
+		winner = buyerB
+	}
+	product := getTestProduct(t, env, productID)
+	assert.Equal(t, winner.UserID.String(), product["sold_to"])
+	assert.EqualValues(t, 85, product["current_price"])
+
+	// English auctions cannot be accepted
+	englishID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Not Dutch",
+		"min_price":     10,
+		"current_price": 10,
+	})
+	w := acceptTestPrice(env, buyerA, englishID)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "NOT_DUTCH_AUCTION")
+}
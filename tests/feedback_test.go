@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withURLParam adds the chi URL param key to the request
+func withURLParam(req *http.Request, key string, value string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+// getTestReputation returns the reputation of user through the public endpoint
+func getTestReputation(t *testing.T, env *TestEnv, user *TestUser) map[string]interface{} {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/users/%s/reputation", user.UserID), nil)
+	w := httptest.NewRecorder()
+	env.Dependencies.FeedbackHandler.GetReputation(w, withURLParam(req, "userId", user.UserID.String()))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	return decodeTestData(t, w)["reputation"].(map[string]interface{})
+}
+
+// TestFeedbackAndReputation tests that both parties of a paid order rate each other once, and that the seller's
+// reputation shown on their listings follows
+func TestFeedbackAndReputation(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(6)
+	buyer := GetTestUser(7)
+	stranger := GetTestUser(5)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyer)
+	require.NotNil(t, stranger)
+	handler := env.Dependencies.FeedbackHandler
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Rated Phonograph",
+		"min_price":     100,
+		"current_price": 100,
+	})
+	// Caches the seller's reputation before they get feedback
+	before := getTestProductData(t, env, productID)["seller_reputation"].(map[string]interface{})
+
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, buyer, productID, 200).Code)
+	endTestAuction(t, env, productID)
+	invoice := findTestInvoice(t, env, buyer, "buyer", productID)
+	require.NotNil(t, invoice)
+	orderID := invoice["order_id"].(string)
+
+	rating := map[string]interface{}{"rating": 5, "comment": "Fast and friendly"}
+	w := callShippingEndpoint(t, buyer, "orderId", orderID, rating, handler.LeaveFeedback)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "FEEDBACK_NOT_ALLOWED", "Unpaid orders cannot be rated")
+
+	w = callInvoiceEndpoint(t, buyer, invoice["id"].(string), map[string]interface{}{"payment_token": "tok_visa"}, env.Dependencies.InvoiceHandler.PayInvoice)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = callShippingEndpoint(t, stranger, "orderId", orderID, rating, handler.LeaveFeedback)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = callShippingEndpoint(t, buyer, "orderId", orderID, map[string]interface{}{"rating": 6}, handler.LeaveFeedback)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = callShippingEndpoint(t, buyer, "orderId", orderID, rating, handler.LeaveFeedback)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	feedback := decodeTestData(t, w)["feedback"].(map[string]interface{})
+	assert.Equal(t, seller.UserID.String(), feedback["recipient_id"])
+	assert.Equal(t, "seller", feedback["recipient_role"])
+	w = callShippingEndpoint(t, buyer, "orderId", orderID, rating, handler.LeaveFeedback)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "FEEDBACK_ALREADY_LEFT")
+
+	w = callShippingEndpoint(t, seller, "orderId", orderID, map[string]interface{}{"rating": 4}, handler.LeaveFeedback)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// Only the recipient replies, once
+	feedbackID := feedback["id"].(string)
+	reply := map[string]interface{}{"reply": "Thank you!"}
+	w = callShippingEndpoint(t, buyer, "feedbackId", feedbackID, reply, handler.ReplyToFeedback)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = callShippingEndpoint(t, seller, "feedbackId", feedbackID, reply, handler.ReplyToFeedback)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = callShippingEndpoint(t, seller, "feedbackId", feedbackID, reply, handler.ReplyToFeedback)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/users/%s/feedback?role=seller", seller.UserID), nil)
+	w = httptest.NewRecorder()
+	handler.ListFeedback(w, withURLParam(req, "userId", seller.UserID.String()))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	received := decodeTestData(t, w)["feedback"].([]interface{})
+	require.NotEmpty(t, received)
+	assert.Equal(t, "Thank you!", received[0].(map[string]interface{})["reply"])
+
+	// The cached reputation was dropped when the feedback came in
+	after := getTestProductData(t, env, productID)["seller_reputation"].(map[string]interface{})
+	assert.Equal(t, before["score"].(float64)+1, after["score"])
+	assert.Equal(t, before["as_seller_count"].(float64)+1, after["as_seller_count"])
+	assert.Equal(t, after, getTestReputation(t, env, seller))
+	assert.GreaterOrEqual(t, getTestReputation(t, env, buyer)["score"], float64(1))
+}
+
+// TestMinimumBidderReputation tests that listings asking for a reputation refuse bidders without it
+func TestMinimumBidderReputation(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(1)
+	newcomer := GetTestUser(9)
+	require.NotNil(t, seller)
+	require.NotNil(t, newcomer)
+	require.Equal(t, float64(0), getTestReputation(t, env, newcomer)["score"])
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":                 "Guarded Chronometer",
+		"min_price":             1000,
+		"current_price":         1000,
+		"min_bidder_reputation": 3,
+	})
+	w := placeTestBid(t, env, newcomer, productID, 1100)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "REPUTATION_TOO_LOW")
+
+	open := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Open Chronometer",
+		"min_price":     1000,
+		"current_price": 1000,
+	})
+	assert.Equal(t, http.StatusOK, placeTestBid(t, env, newcomer, open, 1100).Code)
+}
@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/itsDrac/e-auc/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callAdminFeeEndpoint runs an admin fee handler behind the admin middleware with the given route params
+func callAdminFeeEndpoint(t *testing.T, env *TestEnv, user *TestUser, method, path string, params map[string]string, body map[string]interface{}, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body != nil {
+		payloadBytes, err := json.Marshal(body)
+		require.NoError(t, err, "Should marshal payload")
+		req = httptest.NewRequest(method, path, bytes.NewReader(payloadBytes))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req = httptest.NewRequest(method, path, nil)
+	}
+	rctx := chi.NewRouteContext()
+	for key, value := range params {
+		rctx.URLParams.Add(key, value)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	middleware.AdminMiddleware(env.Dependencies.Services.AdminService)(handler).ServeHTTP(w, req)
+	return w
+}
+
+// todayTestFeeRevenue sums the commission and buyer premium booked today
+func todayTestFeeRevenue(t *testing.T, env *TestEnv, admin *TestUser) (float64, float64) {
+	w := callAdminFeeEndpoint(t, env, admin, http.MethodGet, "/api/v1/admin/fees/revenue?period=day", nil, nil, env.Dependencies.AdminHandler.ExportFeeRevenue)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	var commission, premium float64
+	for _, r := range response["data"].(map[string]interface{})["revenue"].([]interface{}) {
+		row := r.(map[string]interface{})
+		commission += row["commission"].(float64)
+		premium += row["buyer_premium"].(float64)
+	}
+	return commission, premium
+}
+
+// TestTieredFeesAtSettlement tests that category fee schedules are invoiced, booked as revenue and reported to the seller
+func TestTieredFeesAtSettlement(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	admin := GetTestUser(0)
+	seller := GetTestUser(3)
+	buyer := GetTestUser(4)
+	require.NotNil(t, admin)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyer)
+	makeTestAdmin(t, env, admin)
+	handler := env.Dependencies.AdminHandler
+	category := "fee-test-cameras"
+
+	w := callAdminFeeEndpoint(t, env, admin, http.MethodPut, "/api/v1/admin/fees/bogus/platform", map[string]string{"kind": "bogus"},
+		map[string]interface{}{"tiers": []map[string]interface{}{{"min_price": 0, "rate_bps": 100}}}, handler.SetPlatformFees)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_FEE_KIND")
+
+	w = callAdminFeeEndpoint(t, env, admin, http.MethodPut, "/api/v1/admin/fees/commission/categories/"+category, map[string]string{"kind": "commission", "category": category},
+		map[string]interface{}{"tiers": []map[string]interface{}{{"min_price": 100, "rate_bps": 100}}}, handler.SetCategoryFees)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_FEE_SCHEDULE")
+
+	// 10% up to 1000 and 5% above, plus a 15% buyer premium
+	w = callAdminFeeEndpoint(t, env, admin, http.MethodPut, "/api/v1/admin/fees/commission/categories/"+category, map[string]string{"kind": "commission", "category": category},
+		map[string]interface{}{"tiers": []map[string]interface{}{{"min_price": 0, "rate_bps": 1000}, {"min_price": 1000, "rate_bps": 500}}}, handler.SetCategoryFees)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = callAdminFeeEndpoint(t, env, admin, http.MethodPut, "/api/v1/admin/fees/buyer_premium/categories/"+category, map[string]string{"kind": "buyer_premium", "category": category},
+		map[string]interface{}{"tiers": []map[string]interface{}{{"min_price": 0, "rate_bps": 1500}}}, handler.SetCategoryFees)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	commissionBefore, premiumBefore := todayTestFeeRevenue(t, env, admin)
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Fee Schedule Camera",
+		"min_price":     100,
+		"current_price": 100,
+		"category":      category,
+		"shipping_cost": 30,
+	})
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, buyer, productID, 1200).Code)
+	endTestAuction(t, env, productID)
+
+	invoice := findTestInvoice(t, env, buyer, "buyer", productID)
+	require.NotNil(t, invoice)
+	assert.Equal(t, float64(1200), invoice["item_price"])
+	assert.Equal(t, float64(180), invoice["buyer_premium"])
+	assert.Equal(t, float64(110), invoice["platform_fee"], "100 on the first 1000 and 10 on the 200 above")
+	assert.Equal(t, float64(1410), invoice["total"])
+
+	commission, premium := todayTestFeeRevenue(t, env, admin)
+	assert.Equal(t, commissionBefore+110, commission)
+	assert.Equal(t, premiumBefore+180, premium)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me/payouts?limit=100", nil)
+	req = addProductAuthContext(req, seller)
+	w = httptest.NewRecorder()
+	env.Dependencies.InvoiceHandler.ListPayouts(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	var payout map[string]interface{}
+	for _, p := range response["data"].(map[string]interface{})["payouts"].([]interface{}) {
+		if p.(map[string]interface{})["product_id"] == productID {
+			payout = p.(map[string]interface{})
+		}
+	}
+	require.NotNil(t, payout)
+	assert.Equal(t, float64(1230), payout["gross"])
+	assert.Equal(t, float64(110), payout["fees"])
+	assert.Equal(t, float64(1120), payout["net"])
+
+	w = callAdminFeeEndpoint(t, env, admin, http.MethodGet, "/api/v1/admin/fees/revenue?period=month&format=csv", nil, nil, handler.ExportFeeRevenue)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "period_start,commission,buyer_premium,reversed,net,sales")
+
+	w = callAdminFeeEndpoint(t, env, admin, http.MethodGet, "/api/v1/admin/fees/revenue?period=year", nil, nil, handler.ExportFeeRevenue)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_FEE_PERIOD")
+}
+
+// systemTestBalance returns the balance of the platform account of kind in currency, zero when it does not exist yet
+func systemTestBalance(t *testing.T, env *TestEnv, kind string, currency string) int64 {
+	var balance int64
+	err := env.Dependencies.Conn.QueryRow(env.Context, `
+		SELECT COALESCE(SUM(CASE WHEN p.direction = 'credit' THEN p.amount ELSE -p.amount END), 0)::bigint
+		FROM accounts a JOIN postings p ON p.account_id = a.id
+		WHERE a.user_id IS NULL AND a.kind = $1 AND a.currency = $2`, kind, currency).Scan(&balance)
+	require.NoError(t, err)
+	return balance
+}
+
+// TestFeesBookedPerCurrency tests that fees of a listing in another currency go to the platform accounts of that
+// currency and never into the USD revenue
+func TestFeesBookedPerCurrency(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(3)
+	buyer := GetTestUser(4)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyer)
+
+	usdRevenue := systemTestBalance(t, env, "revenue", "USD")
+	eurRevenue := systemTestBalance(t, env, "revenue", "EUR")
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Euro Fee Camera",
+		"min_price":     100,
+		"current_price": 100,
+		"currency":      "EUR",
+	})
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, buyer, productID, 2000).Code)
+	endTestAuction(t, env, productID)
+
+	invoice := findTestInvoice(t, env, buyer, "buyer", productID)
+	require.NotNil(t, invoice)
+	assert.Equal(t, "EUR", invoice["currency"])
+	fees := int64(invoice["platform_fee"].(float64) + invoice["buyer_premium"].(float64))
+	require.Positive(t, fees, "The platform commission applies")
+
+	assert.Equal(t, eurRevenue+fees, systemTestBalance(t, env, "revenue", "EUR"))
+	assert.Equal(t, usdRevenue, systemTestBalance(t, env, "revenue", "USD"), "EUR fees are not added to USD revenue")
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/itsDrac/e-auc/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setTestCategoryLadder replaces a category ladder through the admin endpoint
+func setTestCategoryLadder(t *testing.T, env *TestEnv, user *TestUser, category string, steps []map[string]interface{}) *httptest.ResponseRecorder {
+	payloadBytes, err := json.Marshal(map[string]interface{}{"steps": steps})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/bid-increments/categories/"+category, bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("category", category)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	middleware.AdminMiddleware(env.Dependencies.Services.AdminService)(http.HandlerFunc(env.Dependencies.AdminHandler.SetCategoryBidIncrements)).ServeHTTP(w, req)
+	return w
+}
+
+// TestPlatformBidIncrements tests that bids are validated against the default platform ladder
+func TestPlatformBidIncrements(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(2)
+	bidder := GetTestUser(3)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Ladder Product",
+		"min_price":     150,
+		"current_price": 150,
+	})
+	assert.EqualValues(t, 155, getTestProduct(t, env, productID)["next_min_bid"], "Prices from 100 go up by 5")
+
+	w := placeTestBid(t, env, bidder, productID, 152)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	apiErr := response["error"].(map[string]interface{})
+	assert.Equal(t, "BID_BELOW_INCREMENT", apiErr["code"])
+	assert.Contains(t, apiErr["message"], "155", "Error should include the required amount")
+
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, productID, 155).Code)
+	assert.EqualValues(t, 160, getTestProduct(t, env, productID)["next_min_bid"])
+}
+
+// TestProductAndCategoryBidIncrements tests that product ladders beat category ladders which beat the platform ladder
+func TestProductAndCategoryBidIncrements(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	admin := GetTestUser(7)
+	seller := GetTestUser(2)
+	bidder := GetTestUser(4)
+	require.NotNil(t, admin)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+	makeTestAdmin(t, env, admin)
+
+	// Only admins manage ladders, and ladders must start at 0
+	w := setTestCategoryLadder(t, env, seller, "watches", []map[string]interface{}{{"min_price": 0, "increment": 50}})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = setTestCategoryLadder(t, env, admin, "watches", []map[string]interface{}{{"min_price": 10, "increment": 50}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_INCREMENT_LADDER")
+	w = setTestCategoryLadder(t, env, admin, "watches", []map[string]interface{}{
+		{"min_price": 0, "increment": 50},
+		{"min_price": 1000, "increment": 100},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	categoryProduct := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Category Ladder Product",
+		"min_price":     10,
+		"current_price": 10,
+		"category":      "Watches",
+	})
+	assert.EqualValues(t, 60, getTestProduct(t, env, categoryProduct)["next_min_bid"])
+
+	ownProduct := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":          "Product Ladder Product",
+		"min_price":      10,
+		"current_price":  10,
+		"category":       "watches",
+		"bid_increments": []map[string]interface{}{{"min_price": 0, "increment": 7}},
+	})
+	assert.EqualValues(t, 17, getTestProduct(t, env, ownProduct)["next_min_bid"])
+
+	assert.Equal(t, http.StatusBadRequest, placeTestBid(t, env, bidder, ownProduct, 16).Code)
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, ownProduct, 17).Code)
+	assert.EqualValues(t, 24, getTestProduct(t, env, ownProduct)["next_min_bid"])
+}
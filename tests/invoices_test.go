@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/itsDrac/e-auc/internal/payments"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findTestInvoice returns the invoice of productID listed for user in role, or nil
+func findTestInvoice(t *testing.T, env *TestEnv, user *TestUser, role string, productID string) map[string]interface{} {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/invoices?role=%s&limit=100", role), nil)
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	env.Dependencies.InvoiceHandler.ListInvoices(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	for _, i := range response["data"].(map[string]interface{})["invoices"].([]interface{}) {
+		invoice := i.(map[string]interface{})
+		if invoice["product_id"] == productID {
+			return invoice
+		}
+	}
+	return nil
+}
+
+// callInvoiceEndpoint calls an invoice handler for invoiceID as user, the body is only sent when given
+func callInvoiceEndpoint(t *testing.T, user *TestUser, invoiceID string, body map[string]interface{}, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body != nil {
+		payloadBytes, err := json.Marshal(body)
+		require.NoError(t, err, "Should marshal payload")
+		req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/invoices/%s/pay", invoiceID), bytes.NewReader(payloadBytes))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/invoices/%s", invoiceID), nil)
+	}
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("invoiceId", invoiceID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+// TestInvoicePayment tests that settlement invoices the winner, who can retry a declined payment once
+func TestInvoicePayment(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(3)
+	buyer := GetTestUser(2)
+	stranger := GetTestUser(4)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyer)
+	require.NotNil(t, stranger)
+	handler := env.Dependencies.InvoiceHandler
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Invoiced Turntable",
+		"min_price":     100,
+		"current_price": 100,
+		"shipping_cost": 25,
+	})
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, buyer, productID, 150).Code)
+	endTestAuction(t, env, productID)
+
+	invoice := findTestInvoice(t, env, buyer, "buyer", productID)
+	require.NotNil(t, invoice, "The winner is invoiced")
+	assert.Equal(t, "pending", invoice["status"])
+	assert.Equal(t, float64(150), invoice["item_price"])
+	assert.Equal(t, float64(25), invoice["shipping"])
+	assert.Equal(t, float64(175), invoice["total"])
+	assert.Equal(t, float64(175), invoice["amount_due"])
+	assert.NotNil(t, findTestInvoice(t, env, seller, "seller", productID))
+	invoiceID := invoice["id"].(string)
+
+	w := callInvoiceEndpoint(t, stranger, invoiceID, nil, handler.GetInvoice)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = callInvoiceEndpoint(t, buyer, invoiceID, map[string]interface{}{"payment_token": payments.FakeDeclineToken}, handler.PayInvoice)
+	assert.Equal(t, http.StatusPaymentRequired, w.Code)
+	assert.Contains(t, w.Body.String(), "PAYMENT_DECLINED")
+
+	w = callInvoiceEndpoint(t, buyer, invoiceID, map[string]interface{}{"payment_token": payments.FakeUnavailableToken}, handler.PayInvoice)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var status string
+	err := env.Dependencies.Conn.QueryRow(env.Context, "SELECT status FROM invoices WHERE id = $1", invoiceID).Scan(&status)
+	require.NoError(t, err)
+	assert.Equal(t, "processing", status, "A charge with an unknown outcome leaves the invoice processing")
+
+	// Paying again resumes the processing attempt instead of starting another one
+	w = callInvoiceEndpoint(t, buyer, invoiceID, map[string]interface{}{"payment_token": "tok_visa"}, handler.PayInvoice)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = callInvoiceEndpoint(t, buyer, invoiceID, map[string]interface{}{"payment_token": "tok_visa"}, handler.PayInvoice)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "INVOICE_ALREADY_PAID")
+
+	w = callInvoiceEndpoint(t, seller, invoiceID, nil, handler.GetInvoice)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "paid", data["invoice"].(map[string]interface{})["status"])
+	attempts := data["payments"].([]interface{})
+	require.Len(t, attempts, 2)
+	assert.Equal(t, "failed", attempts[0].(map[string]interface{})["status"])
+	assert.Equal(t, "succeeded", attempts[1].(map[string]interface{})["status"])
+}
+
+// TestInvoiceExpiryNonPayment tests that an unpaid invoice expires with a strike and a second chance for the runner-up
+func TestInvoiceExpiryNonPayment(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(6)
+	winner := GetTestUser(5)
+	runnerUp := GetTestUser(7)
+	require.NotNil(t, seller)
+	require.NotNil(t, winner)
+	require.NotNil(t, runnerUp)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":                        "Unpaid Synthesizer",
+		"min_price":                    100,
+		"current_price":                100,
+		"second_chance_on_non_payment": true,
+	})
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, runnerUp, productID, 150).Code)
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, winner, productID, 200).Code)
+	endTestAuction(t, env, productID)
+
+	invoice := findTestInvoice(t, env, winner, "buyer", productID)
+	require.NotNil(t, invoice)
+	invoiceID := invoice["id"].(string)
+	var strikesBefore int
+	err := env.Dependencies.Conn.QueryRow(env.Context, "SELECT COUNT(*) FROM non_payment_strikes WHERE user_id = $1", winner.UserID).Scan(&strikesBefore)
+	require.NoError(t, err)
+
+	_, err = env.Dependencies.Conn.Exec(env.Context, "UPDATE invoices SET due_at = NOW() - INTERVAL '1 second' WHERE id = $1", invoiceID)
+	require.NoError(t, err)
+	w := callInvoiceEndpoint(t, winner, invoiceID, map[string]interface{}{"payment_token": "tok_visa"}, env.Dependencies.InvoiceHandler.PayInvoice)
+	assert.Equal(t, http.StatusConflict, w.Code, "Overdue invoices cannot be paid")
+	assert.Contains(t, w.Body.String(), "INVOICE_EXPIRED")
+
+	_, err = env.Dependencies.Services.InvoiceService.ExpireInvoices(env.Context)
+	require.NoError(t, err)
+	invoice = findTestInvoice(t, env, winner, "buyer", productID)
+	assert.Equal(t, "expired", invoice["status"])
+	var strikes int
+	err = env.Dependencies.Conn.QueryRow(env.Context, "SELECT COUNT(*) FROM non_payment_strikes WHERE user_id = $1", winner.UserID).Scan(&strikes)
+	require.NoError(t, err)
+	assert.Equal(t, strikesBefore+1, strikes)
+
+	product := getTestProduct(t, env, productID)
+	assert.Nil(t, product["sold_to"], "The product is no longer sold to the winner")
+	offer := pendingTestSecondChance(t, env, runnerUp, productID)
+	require.NotNil(t, offer, "The runner-up gets a second chance")
+	assert.Equal(t, float64(150), offer["price"])
+}
+
+// TestStaleProcessingInvoiceExpires tests that an attempt without a known outcome is reconciled, so its invoice can expire
+func TestStaleProcessingInvoiceExpires(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(3)
+	buyer := GetTestUser(4)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyer)
+	svc := env.Dependencies.Services.InvoiceService
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Interrupted Payment Radio",
+		"min_price":     100,
+		"current_price": 100,
+	})
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, buyer, productID, 120).Code)
+	endTestAuction(t, env, productID)
+	invoice := findTestInvoice(t, env, buyer, "buyer", productID)
+	require.NotNil(t, invoice)
+	invoiceID := invoice["id"].(string)
+
+	w := callInvoiceEndpoint(t, buyer, invoiceID, map[string]interface{}{"payment_token": payments.FakeUnavailableToken}, env.Dependencies.InvoiceHandler.PayInvoice)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	_, err := env.Dependencies.Conn.Exec(env.Context, "UPDATE invoices SET due_at = NOW() - INTERVAL '1 second' WHERE id = $1", invoiceID)
+	require.NoError(t, err)
+
+	_, err = svc.ExpireInvoices(env.Context)
+	require.NoError(t, err)
+	assert.Equal(t, "processing", findTestInvoice(t, env, buyer, "buyer", productID)["status"], "A processing invoice does not expire")
+	_, err = svc.ReconcilePayments(env.Context)
+	require.NoError(t, err)
+	assert.Equal(t, "processing", findTestInvoice(t, env, buyer, "buyer", productID)["status"], "Recent attempts may still be charged")
+
+	_, err = env.Dependencies.Conn.Exec(env.Context, "UPDATE payments SET created_at = NOW() - INTERVAL '1 hour' WHERE invoice_id = $1", invoiceID)
+	require.NoError(t, err)
+	reconciled, err := svc.ReconcilePayments(env.Context)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, reconciled, 1)
+	_, err = svc.ExpireInvoices(env.Context)
+	require.NoError(t, err)
+	assert.Equal(t, "expired", findTestInvoice(t, env, buyer, "buyer", productID)["status"], "Without a charge the invoice reopens and expires")
+
+	var status string
+	err = env.Dependencies.Conn.QueryRow(env.Context, "SELECT status FROM payments WHERE invoice_id = $1", invoiceID).Scan(&status)
+	require.NoError(t, err)
+	assert.Equal(t, "failed", status)
+}
@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/itsDrac/e-auc/internal/jobs"
+	"github.com/itsDrac/e-auc/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addJobIDToContext adds jobId URL param to chi context
+func addJobIDToContext(req *http.Request, jobID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("jobId", jobID)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(ctx)
+}
+
+// makeTestAdmin grants the admin role to a test user
+func makeTestAdmin(t *testing.T, env *TestEnv, user *TestUser) {
+	_, err := env.Dependencies.Conn.Exec(env.Context, "UPDATE users SET is_admin = TRUE WHERE id = $1", user.UserID)
+	require.NoError(t, err)
+}
+
+// callAdminJobEndpoint runs an admin job handler behind the admin middleware
+func callAdminJobEndpoint(env *TestEnv, user *TestUser, method, jobID string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	path := "/api/v1/admin/jobs"
+	if jobID != "" {
+		path = fmt.Sprintf("%s/%s", path, jobID)
+	}
+	req := httptest.NewRequest(method, path, nil)
+	req = addJobIDToContext(req, jobID)
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	middleware.AdminMiddleware(env.Dependencies.Services.AdminService)(handler).ServeHTTP(w, req)
+	return w
+}
+
+func jobStatus(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	return response["data"].(map[string]interface{})["job"].(map[string]interface{})
+}
+
+type testJobPayload struct {
+	Name string `json:"name"`
+}
+
+// TestJobQueueRetriesAndDeadLetter tests retries with backoff, dead-lettering and admin retry of a job
+func TestJobQueueRetriesAndDeadLetter(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	admin := GetTestUser(7)
+	require.NotNil(t, admin)
+	makeTestAdmin(t, env, admin)
+
+	var healthy atomic.Bool
+	var seen atomic.Value
+	queue := env.Dependencies.Jobs
+	queue.Register("test.flaky", jobs.Typed(func(ctx context.Context, payload testJobPayload) error {
+		seen.Store(payload.Name)
+		if !healthy.Load() {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	}), jobs.Options{MaxAttempts: 2})
+
+	// First failure is retried later
+	retried, err := queue.Enqueue(env.Context, "test.flaky", testJobPayload{Name: "retry"})
+	require.NoError(t, err)
+	_, err = queue.RunDue(env.Context)
+	require.NoError(t, err)
+	assert.Equal(t, "retry", seen.Load())
+
+	w := callAdminJobEndpoint(env, admin, http.MethodGet, retried.ID.String(), env.Dependencies.AdminHandler.GetJob)
+	require.Equal(t, http.StatusOK, w.Code)
+	job := jobStatus(t, w)
+	assert.Equal(t, jobs.StatusPending, job["status"])
+	assert.EqualValues(t, 1, job["attempts"])
+	assert.Equal(t, "downstream unavailable", job["last_error"])
+	runAt, err := time.Parse(time.RFC3339Nano, job["run_at"].(string))
+	require.NoError(t, err)
+	assert.True(t, runAt.After(time.Now()), "Retry should be delayed by the backoff")
+
+	// Running out of attempts moves the job to the dead-letter table
+	dead, err := queue.Enqueue(env.Context, "test.flaky", testJobPayload{Name: "dead"}, jobs.MaxAttempts(1))
+	require.NoError(t, err)
+	_, err = queue.RunDue(env.Context)
+	require.NoError(t, err)
+
+	w = callAdminJobEndpoint(env, admin, http.MethodGet, dead.ID.String(), env.Dependencies.AdminHandler.GetJob)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, jobs.StatusDead, jobStatus(t, w)["status"])
+
+	w = callAdminJobEndpoint(env, admin, http.MethodGet, "", env.Dependencies.AdminHandler.ListDeadLetterJobs)
+	require.Equal(t, http.StatusOK, w.Code)
+	var listResp map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&listResp))
+	found := false
+	for _, entry := range listResp["data"].(map[string]interface{})["jobs"].([]interface{}) {
+		if entry.(map[string]interface{})["job_id"] == dead.ID.String() {
+			found = true
+		}
+	}
+	assert.True(t, found, "Dead job should be listed in the dead-letter table")
+
+	// An admin retry runs the dead job again straight away
+	healthy.Store(true)
+	w = callAdminJobEndpoint(env, admin, http.MethodPost, dead.ID.String(), env.Dependencies.AdminHandler.RetryJob)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	_, err = queue.RunDue(env.Context)
+	require.NoError(t, err)
+
+	w = callAdminJobEndpoint(env, admin, http.MethodGet, dead.ID.String(), env.Dependencies.AdminHandler.GetJob)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, jobs.StatusSucceeded, jobStatus(t, w)["status"])
+
+	// Succeeded jobs cannot be retried
+	w = callAdminJobEndpoint(env, admin, http.MethodPost, dead.ID.String(), env.Dependencies.AdminHandler.RetryJob)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+// TestJobQueueSchedulingAndCancel tests delayed jobs, unique keys, cancellation and admin-only access
+func TestJobQueueSchedulingAndCancel(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	admin := GetTestUser(7)
+	user := GetTestUser(8)
+	require.NotNil(t, admin)
+	require.NotNil(t, user)
+	makeTestAdmin(t, env, admin)
+
+	var runs atomic.Int32
+	queue := env.Dependencies.Jobs
+	queue.Register("test.delayed", func(ctx context.Context, job jobs.Job) error {
+		runs.Add(1)
+		return nil
+	}, jobs.Options{})
+
+	delayed, err := queue.Enqueue(env.Context, "test.delayed", nil, jobs.After(time.Hour), jobs.UniqueKey("test.delayed:1"))
+	require.NoError(t, err)
+	_, err = queue.Enqueue(env.Context, "test.delayed", nil, jobs.UniqueKey("test.delayed:1"))
+	assert.ErrorIs(t, err, jobs.ErrDuplicate, "Unique key should prevent a second job")
+
+	_, err = queue.RunDue(env.Context)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, runs.Load(), "Delayed job should not run before its time")
+
+	// Regular users cannot reach the admin endpoints
+	w := callAdminJobEndpoint(env, user, http.MethodPost, delayed.ID.String(), env.Dependencies.AdminHandler.CancelJob)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = callAdminJobEndpoint(env, admin, http.MethodPost, delayed.ID.String(), env.Dependencies.AdminHandler.CancelJob)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, jobs.StatusCancelled, jobStatus(t, w)["status"])
+
+	w = callAdminJobEndpoint(env, admin, http.MethodPost, delayed.ID.String(), env.Dependencies.AdminHandler.CancelJob)
+	assert.Equal(t, http.StatusConflict, w.Code, "Cancelled job cannot be cancelled again")
+
+	w = callAdminJobEndpoint(env, admin, http.MethodGet, "00000000-0000-0000-0000-000000000000", env.Dependencies.AdminHandler.GetJob)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/itsDrac/e-auc/internal/leader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRunner counts how many times it was started and whether it is running
+type countingRunner struct {
+	starts  atomic.Int32
+	running atomic.Bool
+}
+
+func (c *countingRunner) Run(ctx context.Context) {
+	c.starts.Add(1)
+	c.running.Store(true)
+	<-ctx.Done()
+	c.running.Store(false)
+}
+
+// TestLeaderElection tests that only one elector leads at a time and that leadership moves on resign
+func TestLeaderElection(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	first := leader.NewElector(env.Dependencies.Cache, "test-election", 5*time.Second)
+	second := leader.NewElector(env.Dependencies.Cache, "test-election", 5*time.Second)
+
+	ok, err := first.Elect(env.Context)
+	require.NoError(t, err)
+	assert.True(t, ok, "First elector should acquire the lease")
+
+	ok, err = second.Elect(env.Context)
+	require.NoError(t, err)
+	assert.False(t, ok, "Second elector should not acquire a held lease")
+
+	// Renewal keeps the leader in place
+	ok, err = first.Elect(env.Context)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, first.Status().IsLeader)
+	assert.False(t, second.Status().IsLeader)
+
+	require.NoError(t, first.Resign(env.Context))
+	assert.False(t, first.IsLeader())
+
+	ok, err = second.Elect(env.Context)
+	require.NoError(t, err)
+	assert.True(t, ok, "Second elector should take over after resign")
+	require.NoError(t, second.Resign(env.Context))
+}
+
+// TestLeaderFailover tests that a lease that is no longer renewed expires and another elector takes over
+func TestLeaderFailover(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	ttl := time.Second
+	dead := leader.NewElector(env.Dependencies.Cache, "test-failover", ttl)
+	standby := leader.NewElector(env.Dependencies.Cache, "test-failover", ttl)
+
+	ok, err := dead.Elect(env.Context)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The standby runs a singleton worker which must stay idle while it is not the leader
+	runner := &countingRunner{}
+	ctx, cancel := context.WithCancel(env.Context)
+	defer cancel()
+	go standby.Run(ctx)
+	go leader.NewSingleton("test-worker", standby, runner).Run(ctx)
+
+	time.Sleep(ttl / 2)
+	assert.EqualValues(t, 0, runner.starts.Load(), "Singleton should not run on a follower")
+
+	// The leader stops renewing, the standby takes over once the lease expires
+	assert.Eventually(t, standby.IsLeader, 5*time.Second, 100*time.Millisecond, "Standby should take over")
+	assert.Eventually(t, runner.running.Load, 2*time.Second, 100*time.Millisecond, "Singleton should start on the new leader")
+	assert.False(t, dead.IsLeader(), "Expired lease should not count as leadership")
+
+	ok, err = dead.Elect(env.Context)
+	require.NoError(t, err)
+	assert.False(t, ok, "Old leader should not win the lease back")
+
+	cancel()
+	assert.Eventually(t, func() bool { return !runner.running.Load() }, 2*time.Second, 100*time.Millisecond)
+	assert.EqualValues(t, 1, runner.starts.Load())
+}
@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callListingEndpoint sends body to a product lifecycle handler as user
+func callListingEndpoint(t *testing.T, user *TestUser, productID string, body map[string]interface{}, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	payloadBytes, err := json.Marshal(body)
+	require.NoError(t, err, "Should marshal payload")
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/products/%s", productID), bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req = addProductIDToContext(req, productID)
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+// listTestSellerProducts lists the products of seller as viewer, optionally filtered by status
+func listTestSellerProducts(t *testing.T, env *TestEnv, viewer *TestUser, seller *TestUser, status string) []string {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/products/seller/%s?limit=100&status=%s", seller.UserID, status), nil)
+	req = addSellerIDToContext(req, seller.UserID.String())
+	req = addProductAuthContext(req, viewer)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.ProductsBySellerID(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	var ids []string
+	for _, p := range response["data"].(map[string]interface{})["products"].([]interface{}) {
+		ids = append(ids, p.(map[string]interface{})["id"].(string))
+	}
+	return ids
+}
+
+// TestDraftPublishLifecycle tests that drafts are private and editable and only live products take bids
+func TestDraftPublishLifecycle(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(6)
+	bidder := GetTestUser(5)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+	handler := env.Dependencies.ProductHandler
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Draft Lamp",
+		"min_price":     10,
+		"current_price": 10,
+		"status":        "draft",
+	})
+	assert.Equal(t, "draft", getTestProduct(t, env, productID)["status"])
+
+	w := placeTestBid(t, env, bidder, productID, 20)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "PRODUCT_NOT_LIVE")
+
+	assert.Contains(t, listTestSellerProducts(t, env, seller, seller, "draft"), productID)
+	assert.NotContains(t, listTestSellerProducts(t, env, bidder, seller, ""), productID, "Drafts are only listed for their seller")
+	assert.NotContains(t, listTestSellerProducts(t, env, seller, seller, "live"), productID)
+
+	// Drafts can be changed freely
+	w = callListingEndpoint(t, seller, productID, map[string]interface{}{"title": "Desk Lamp", "current_price": 15}, handler.UpdateProduct)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	product := getTestProduct(t, env, productID)
+	assert.Equal(t, "Desk Lamp", product["title"])
+	assert.EqualValues(t, 15, product["current_price"])
+
+	w = callListingEndpoint(t, bidder, productID, nil, handler.PublishProduct)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "NOT_PRODUCT_OWNER")
+
+	require.Equal(t, http.StatusOK, callListingEndpoint(t, seller, productID, nil, handler.PublishProduct).Code)
+	product = getTestProduct(t, env, productID)
+	assert.Equal(t, "live", product["status"])
+	assert.NotNil(t, product["starts_at"])
+	assert.Contains(t, listTestSellerProducts(t, env, bidder, seller, "live"), productID)
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, productID, 20).Code)
+
+	w = callListingEndpoint(t, seller, productID, nil, handler.PublishProduct)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_STATUS_TRANSITION")
+
+	// Live listings only take a new description
+	w = callListingEndpoint(t, seller, productID, map[string]interface{}{"current_price": 1}, handler.UpdateProduct)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "PRODUCT_NOT_EDITABLE")
+	w = callListingEndpoint(t, seller, productID, map[string]interface{}{"description": "Barely used"}, handler.UpdateProduct)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Barely used", getTestProduct(t, env, productID)["description"])
+}
+
+// TestScheduledListingStarts tests that the scheduler puts a scheduled listing live at its start time
+func TestScheduledListingStarts(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(6)
+	bidder := GetTestUser(5)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+	handler := env.Dependencies.ProductHandler
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Scheduled Clock",
+		"min_price":     10,
+		"current_price": 10,
+		"status":        "draft",
+	})
+
+	w := callListingEndpoint(t, seller, productID, map[string]interface{}{"starts_at": time.Now().Add(-time.Minute)}, handler.ScheduleProduct)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_START_TIME")
+
+	startsAt := time.Now().Add(time.Hour).UTC()
+	w = callListingEndpoint(t, seller, productID, map[string]interface{}{"starts_at": startsAt}, handler.ScheduleProduct)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "scheduled", getTestProduct(t, env, productID)["status"])
+	assert.Equal(t, http.StatusConflict, placeTestBid(t, env, bidder, productID, 20).Code)
+
+	// Not due yet
+	_, err := env.Dependencies.Services.ProductService.StartScheduledProducts(env.Context)
+	require.NoError(t, err)
+	assert.Equal(t, "scheduled", getTestProduct(t, env, productID)["status"])
+
+	_, err = env.Dependencies.Conn.Exec(env.Context, "UPDATE products SET starts_at = NOW() - INTERVAL '1 second' WHERE id = $1", productID)
+	require.NoError(t, err)
+	_, err = env.Dependencies.Services.ProductService.StartScheduledProducts(env.Context)
+	require.NoError(t, err)
+	assert.Equal(t, "live", getTestProduct(t, env, productID)["status"])
+	assert.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, productID, 20).Code)
+}
+
+// TestRelistUnsoldProduct tests that relisting copies an unsold listing into a new live auction
+func TestRelistUnsoldProduct(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(6)
+	bidder := GetTestUser(5)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+	handler := env.Dependencies.ProductHandler
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Unloved Vase",
+		"min_price":     100,
+		"current_price": 20,
+		"category":      "Decor",
+	})
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, productID, 30).Code)
+
+	w := callListingEndpoint(t, seller, productID, nil, handler.RelistProduct)
+	assert.Equal(t, http.StatusConflict, w.Code, "Live auctions cannot be relisted")
+
+	// The only bid misses the reserve
+	endTestAuction(t, env, productID)
+	old := getTestProduct(t, env, productID)
+	require.Equal(t, "ended", old["status"])
+	require.Nil(t, old["sold_at"])
+
+	w = callListingEndpoint(t, seller, productID, nil, handler.RelistProduct)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	relistedID := response["data"].(map[string]interface{})["product_id"].(string)
+
+	relisted := getTestProduct(t, env, relistedID)
+	assert.Equal(t, "live", relisted["status"])
+	assert.Equal(t, productID, relisted["relisted_from"])
+	assert.Equal(t, old["images"], relisted["images"])
+	assert.Equal(t, old["category"], relisted["category"])
+	assert.EqualValues(t, 20, relisted["current_price"], "The new auction starts at the original starting price")
+	assert.EqualValues(t, 100, relisted["min_price"])
+	assert.Equal(t, "relisted", getTestProduct(t, env, productID)["status"])
+
+	w = callListingEndpoint(t, seller, productID, nil, handler.RelistProduct)
+	assert.Equal(t, http.StatusConflict, w.Code, "A listing is only relisted once")
+}
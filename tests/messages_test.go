@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/itsDrac/e-auc/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callMessageEndpoint calls a messaging handler as user with the given URL parameters and JSON body
+func callMessageEndpoint(t *testing.T, user *TestUser, method string, params map[string]string, body map[string]interface{}, handler http.Handler) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body != nil {
+		payloadBytes, err := json.Marshal(body)
+		require.NoError(t, err, "Should marshal payload")
+		req = httptest.NewRequest(method, "/api/v1/conversations", bytes.NewReader(payloadBytes))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req = httptest.NewRequest(method, "/api/v1/conversations", nil)
+	}
+	rctx := chi.NewRouteContext()
+	for key, value := range params {
+		rctx.URLParams.Add(key, value)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = addAuthContext(req, user)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+// unreadTestMessages returns the total of unread messages of user
+func unreadTestMessages(t *testing.T, env *TestEnv, user *TestUser) float64 {
+	w := callMessageEndpoint(t, user, http.MethodGet, nil, nil, http.HandlerFunc(env.Dependencies.MessageHandler.ListConversations))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	return decodeTestData(t, w)["unread_count"].(float64)
+}
+
+// testMessageBodies returns the bodies of the messages of a conversation that user sees
+func testMessageBodies(t *testing.T, env *TestEnv, user *TestUser, conversationID string) []string {
+	w := callMessageEndpoint(t, user, http.MethodGet, map[string]string{"conversationId": conversationID}, nil, http.HandlerFunc(env.Dependencies.MessageHandler.ListMessages))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var bodies []string
+	for _, message := range decodeTestData(t, w)["messages"].([]interface{}) {
+		bodies = append(bodies, message.(map[string]interface{})["body"].(string))
+	}
+	return bodies
+}
+
+// TestConversationAboutListing tests that a buyer and the seller can message each other and nobody else can read along
+func TestConversationAboutListing(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(6)
+	buyer := GetTestUser(5)
+	stranger := GetTestUser(8)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyer)
+	require.NotNil(t, stranger)
+	handler := env.Dependencies.MessageHandler
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Messaging Test Turntable",
+		"min_price":     100,
+		"current_price": 100,
+	})
+	product := map[string]string{"productId": productID}
+
+	w := callMessageEndpoint(t, seller, http.MethodPost, product, nil, http.HandlerFunc(handler.StartProductConversation))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "SELF_MESSAGING_NOT_ALLOWED")
+
+	w = callMessageEndpoint(t, buyer, http.MethodPost, product, nil, http.HandlerFunc(handler.StartProductConversation))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	conversationID := decodeTestData(t, w)["conversation"].(map[string]interface{})["id"].(string)
+	w = callMessageEndpoint(t, buyer, http.MethodPost, product, nil, http.HandlerFunc(handler.StartProductConversation))
+	require.Equal(t, http.StatusOK, w.Code, "Asking again returns the same conversation")
+	assert.Equal(t, conversationID, decodeTestData(t, w)["conversation"].(map[string]interface{})["id"])
+	conversation := map[string]string{"conversationId": conversationID}
+
+	sellerUnread := unreadTestMessages(t, env, seller)
+	w = callMessageEndpoint(t, buyer, http.MethodPost, conversation, map[string]interface{}{"body": "   "}, http.HandlerFunc(handler.SendMessage))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "EMPTY_MESSAGE")
+	w = callMessageEndpoint(t, buyer, http.MethodPost, conversation, map[string]interface{}{"body": "Does it come with the dust cover?"}, http.HandlerFunc(handler.SendMessage))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "visible", decodeTestData(t, w)["message"].(map[string]interface{})["status"])
+	assert.Equal(t, sellerUnread+1, unreadTestMessages(t, env, seller))
+
+	var events int
+	err := env.Dependencies.Conn.QueryRow(env.Context,
+		"SELECT COUNT(*) FROM outbox WHERE event_type = 'message.sent' AND aggregate_id = $1", conversationID).Scan(&events)
+	require.NoError(t, err)
+	assert.Equal(t, 1, events, "Delivered messages are published on the conversation's live feed")
+
+	assert.Contains(t, testMessageBodies(t, env, seller, conversationID), "Does it come with the dust cover?")
+	w = callMessageEndpoint(t, seller, http.MethodPost, conversation, nil, http.HandlerFunc(handler.MarkRead))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, sellerUnread, unreadTestMessages(t, env, seller))
+
+	w = callMessageEndpoint(t, stranger, http.MethodGet, conversation, nil, http.HandlerFunc(handler.ListMessages))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = callMessageEndpoint(t, stranger, http.MethodPost, conversation, map[string]interface{}{"body": "Hello"}, http.HandlerFunc(handler.SendMessage))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "CONVERSATION_NOT_FOUND")
+
+	w = callMessageEndpoint(t, buyer, http.MethodPost, conversation, map[string]interface{}{"body": "Hi", "attachment_key": "messages/elsewhere/photo.png"}, http.HandlerFunc(handler.SendMessage))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Attachments must be uploaded to the conversation")
+}
+
+// TestHeldMessageModeration tests that messages with contact details are held until an admin approves them
+func TestHeldMessageModeration(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(6)
+	buyer := GetTestUser(5)
+	admin := GetTestUser(7)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyer)
+	require.NotNil(t, admin)
+	makeTestAdmin(t, env, admin)
+	handler := env.Dependencies.MessageHandler
+	adminOnly := middleware.AdminMiddleware(env.Dependencies.Services.AdminService)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Moderation Test Amplifier",
+		"min_price":     100,
+		"current_price": 100,
+	})
+	w := callMessageEndpoint(t, buyer, http.MethodPost, map[string]string{"productId": productID}, nil, http.HandlerFunc(handler.StartProductConversation))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	conversationID := decodeTestData(t, w)["conversation"].(map[string]interface{})["id"].(string)
+	conversation := map[string]string{"conversationId": conversationID}
+
+	heldBodies := []string{
+		"Email me at buyer@example.com instead",
+		"I can pay by western union if you drop the fees",
+	}
+	var heldIDs []string
+	for _, body := range heldBodies {
+		w = callMessageEndpoint(t, buyer, http.MethodPost, conversation, map[string]interface{}{"body": body}, http.HandlerFunc(handler.SendMessage))
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		message := decodeTestData(t, w)["message"].(map[string]interface{})
+		assert.Equal(t, "held", message["status"])
+		assert.NotEmpty(t, message["flags"])
+		heldIDs = append(heldIDs, message["id"].(string))
+	}
+
+	sellerSees := testMessageBodies(t, env, seller, conversationID)
+	for _, body := range heldBodies {
+		assert.NotContains(t, sellerSees, body, "Held messages are not delivered")
+		assert.Contains(t, testMessageBodies(t, env, buyer, conversationID), body, "Senders see their held messages")
+	}
+
+	w = callMessageEndpoint(t, buyer, http.MethodGet, nil, nil, adminOnly(http.HandlerFunc(handler.ListHeldMessages)))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = callMessageEndpoint(t, admin, http.MethodGet, nil, nil, adminOnly(http.HandlerFunc(handler.ListHeldMessages)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), heldIDs[0])
+
+	sellerUnread := unreadTestMessages(t, env, seller)
+	w = callMessageEndpoint(t, admin, http.MethodPost, map[string]string{"messageId": heldIDs[0]}, nil, adminOnly(http.HandlerFunc(handler.ApproveMessage)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, testMessageBodies(t, env, seller, conversationID), heldBodies[0])
+	assert.Equal(t, sellerUnread+1, unreadTestMessages(t, env, seller), "Approved messages arrive unread")
+
+	w = callMessageEndpoint(t, admin, http.MethodPost, map[string]string{"messageId": heldIDs[0]}, nil, adminOnly(http.HandlerFunc(handler.ApproveMessage)))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "MESSAGE_NOT_HELD")
+
+	w = callMessageEndpoint(t, admin, http.MethodPost, map[string]string{"messageId": heldIDs[1]}, nil, adminOnly(http.HandlerFunc(handler.RemoveMessage)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(t, testMessageBodies(t, env, buyer, conversationID), heldBodies[1], "Removed messages are hidden from both parties")
+	assert.NotContains(t, testMessageBodies(t, env, seller, conversationID), heldBodies[1])
+}
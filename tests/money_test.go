@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/itsDrac/e-auc/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// placeTestBidInCurrency places a bid of amount in currency through the handler
+func placeTestBidInCurrency(t *testing.T, env *TestEnv, bidder *TestUser, productID string, amount int, currency string) *httptest.ResponseRecorder {
+	payloadBytes, err := json.Marshal(map[string]interface{}{"bid_amount": amount, "currency": currency})
+	require.NoError(t, err, "Should marshal payload")
+
+	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/products/%s/bid", productID), bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req = addProductIDToContext(req, productID)
+	req = addProductAuthContext(req, bidder)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.PlaceBid(w, req)
+	return w
+}
+
+// TestListingCurrency tests that listings default to USD, format their price and refuse bids in another currency
+func TestListingCurrency(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(6)
+	bidder := GetTestUser(5)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+
+	usdID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Dollar Typewriter",
+		"min_price":     1000,
+		"current_price": 1050,
+	})
+	product := getTestProduct(t, env, usdID)
+	assert.Equal(t, money.DefaultCurrency, product["currency"])
+	price := product["price"].(map[string]interface{})
+	assert.Equal(t, float64(1050), price["amount"])
+	assert.Equal(t, "10.50 USD", price["formatted"])
+
+	eurID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Euro Typewriter",
+		"min_price":     1000,
+		"current_price": 1000,
+		"currency":      "eur",
+	})
+	assert.Equal(t, "EUR", getTestProduct(t, env, eurID)["currency"])
+
+	w := placeTestBidInCurrency(t, env, bidder, eurID, 1200, "USD")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "CURRENCY_MISMATCH")
+	w = placeTestBidInCurrency(t, env, bidder, eurID, 1200, "XYZ")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_CURRENCY")
+
+	require.Equal(t, http.StatusOK, placeTestBidInCurrency(t, env, bidder, eurID, 1200, "EUR").Code)
+	// Bids without a currency are in the product's
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, eurID, 1300).Code)
+	assert.Equal(t, "13.00 EUR", getTestProduct(t, env, eurID)["price"].(map[string]interface{})["formatted"])
+
+	// Amounts are formatted with the decimals of their currency
+	assert.Equal(t, "1300 JPY", money.Money{Amount: 1300, Currency: "JPY"}.String())
+	assert.Equal(t, "1.300 KWD", money.Money{Amount: 1300, Currency: "KWD"}.String())
+	assert.Equal(t, "-0.05 USD", money.Money{Amount: -5, Currency: "USD"}.String())
+}
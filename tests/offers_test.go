@@ -0,0 +1,236 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getTestProductAs fetches a product through the handler as viewer and returns its JSON representation
+func getTestProductAs(t *testing.T, env *TestEnv, viewer *TestUser, productID string) map[string]interface{} {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/products/%s", productID), nil)
+	req = addProductIDToContext(req, productID)
+	req = addProductAuthContext(req, viewer)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.GetProductByID(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	return decodeTestData(t, w)["product"].(map[string]interface{})
+}
+
+// makeTestOffer offers price on a fixed-price listing as buyer through the handler
+func makeTestOffer(t *testing.T, env *TestEnv, buyer *TestUser, productID string, price int) *httptest.ResponseRecorder {
+	payloadBytes, err := json.Marshal(map[string]interface{}{"price": price})
+	require.NoError(t, err, "Should marshal payload")
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/products/%s/offers", productID), bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req = addProductIDToContext(req, productID)
+	req = addProductAuthContext(req, buyer)
+	w := httptest.NewRecorder()
+	env.Dependencies.OfferHandler.MakeOffer(w, req)
+	return w
+}
+
+// answerTestOffer calls an offer endpoint as user, body is only sent when given
+func answerTestOffer(t *testing.T, user *TestUser, offerID string, body map[string]interface{}, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		require.NoError(t, err, "Should marshal payload")
+	}
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/offers/%s", offerID), bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("offerId", offerID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+// decodeTestOffer returns the offer of an offer endpoint response
+func decodeTestOffer(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	return response["data"].(map[string]interface{})["offer"].(map[string]interface{})
+}
+
+// getTestProductOffers lists the offers on a product visible to user, keyed by offer ID
+func getTestProductOffers(t *testing.T, env *TestEnv, user *TestUser, productID string) map[string]map[string]interface{} {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/products/%s/offers", productID), nil)
+	req = addProductIDToContext(req, productID)
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	env.Dependencies.OfferHandler.ListProductOffers(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	offers := map[string]map[string]interface{}{}
+	for _, o := range response["data"].(map[string]interface{})["offers"].([]interface{}) {
+		offer := o.(map[string]interface{})
+		offers[offer["id"].(string)] = offer
+	}
+	return offers
+}
+
+// TestFixedPriceOfferNegotiation tests offers, thresholds and counter-offers ending in a sale
+func TestFixedPriceOfferNegotiation(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(3)
+	buyer := GetTestUser(2)
+	other := GetTestUser(4)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyer)
+	require.NotNil(t, other)
+	handler := env.Dependencies.OfferHandler
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":              "Vintage Camera",
+		"min_price":          1,
+		"current_price":      1000,
+		"auction_type":       "fixed_price",
+		"auto_accept_price":  950,
+		"auto_decline_price": 300,
+	})
+	product := getTestProduct(t, env, productID)
+	assert.EqualValues(t, 1000, product["buy_now_price"], "The asking price can be paid right away")
+	assert.NotContains(t, product, "auto_accept_price", "Offer thresholds are hidden from buyers")
+	assert.NotContains(t, product, "auto_decline_price")
+	product = getTestProductAs(t, env, buyer, productID)
+	assert.NotContains(t, product, "auto_accept_price")
+	assert.NotContains(t, product, "auto_decline_price")
+	product = getTestProductAs(t, env, seller, productID)
+	assert.EqualValues(t, 950, product["auto_accept_price"], "The seller sees their offer thresholds")
+	assert.EqualValues(t, 300, product["auto_decline_price"])
+
+	assert.NotEqual(t, http.StatusOK, placeTestBid(t, env, buyer, productID, 500).Code, "Fixed-price listings take no bids")
+
+	w := makeTestOffer(t, env, buyer, productID, 1000)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_OFFER_PRICE")
+
+	w = makeTestOffer(t, env, seller, productID, 500)
+	assert.Equal(t, http.StatusForbidden, w.Code, "Sellers cannot make offers on their own listing")
+
+	w = makeTestOffer(t, env, buyer, productID, 200)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "declined", decodeTestOffer(t, w)["status"], "Offers below the auto-decline price are declined right away")
+
+	w = makeTestOffer(t, env, buyer, productID, 600)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	offer := decodeTestOffer(t, w)
+	assert.Equal(t, "pending", offer["status"])
+	assert.Equal(t, "buyer", offer["made_by"])
+	offerID := offer["id"].(string)
+
+	w = makeTestOffer(t, env, buyer, productID, 650)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "OFFER_ALREADY_OPEN")
+
+	w = answerTestOffer(t, buyer, offerID, nil, handler.AcceptOffer)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "CANNOT_ANSWER_OWN_OFFER")
+	w = answerTestOffer(t, other, offerID, nil, handler.AcceptOffer)
+	assert.Equal(t, http.StatusNotFound, w.Code, "Offers of other buyers are not visible")
+
+	// The seller counters and the buyer accepts the counter-offer
+	w = answerTestOffer(t, seller, offerID, map[string]interface{}{"price": 800}, handler.CounterOffer)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	counter := decodeTestOffer(t, w)
+	assert.Equal(t, "seller", counter["made_by"])
+	assert.Equal(t, offerID, counter["parent_id"])
+	counterID := counter["id"].(string)
+
+	w = makeTestOffer(t, env, other, productID, 500)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	otherOfferID := decodeTestOffer(t, w)["id"].(string)
+	assert.Len(t, getTestProductOffers(t, env, other, productID), 1, "Buyers only see their own offers")
+
+	w = answerTestOffer(t, seller, offerID, nil, handler.AcceptOffer)
+	assert.Equal(t, http.StatusConflict, w.Code, "A countered offer is no longer open")
+
+	w = answerTestOffer(t, buyer, counterID, nil, handler.AcceptOffer)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	product = getTestProduct(t, env, productID)
+	assert.Equal(t, buyer.UserID.String(), product["sold_to"])
+	assert.EqualValues(t, 800, product["current_price"])
+	order, ok := getTestProductOrders(t, env, seller, productID)[buyer.UserID.String()]
+	require.True(t, ok, "Accepting should create an order")
+	assert.EqualValues(t, 800, order["total_price"])
+
+	offers := getTestProductOffers(t, env, seller, productID)
+	assert.Len(t, offers, 4)
+	assert.Equal(t, "countered", offers[offerID]["status"])
+	assert.Equal(t, "accepted", offers[counterID]["status"])
+	assert.Equal(t, "declined", offers[otherOfferID]["status"], "Open offers are declined once the product sells")
+}
+
+// TestFixedPriceOfferExpiryAndAutoAccept tests that unanswered offers expire and generous offers sell right away
+func TestFixedPriceOfferExpiryAndAutoAccept(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(3)
+	buyer := GetTestUser(4)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyer)
+
+	// Thresholds only apply to fixed-price listings
+	payloadBytes, err := json.Marshal(map[string]interface{}{
+		"title":             "Auction With Threshold",
+		"images":            uploadTestImages(t, env, seller, "test_image_1.png"),
+		"min_price":         1,
+		"current_price":     100,
+		"auto_accept_price": 90,
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader(payloadBytes))
+	req = addProductAuthContext(req, seller)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.CreateProduct(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "OFFERS_NOT_SUPPORTED")
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":             "Record Player",
+		"min_price":         1,
+		"current_price":     500,
+		"auction_type":      "fixed_price",
+		"auto_accept_price": 450,
+	})
+
+	w = makeTestOffer(t, env, buyer, productID, 300)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	offerID := decodeTestOffer(t, w)["id"].(string)
+
+	_, err = env.Dependencies.Conn.Exec(env.Context, "UPDATE offers SET expires_at = NOW() - INTERVAL '1 second' WHERE id = $1", offerID)
+	require.NoError(t, err)
+	_, err = env.Dependencies.Services.OfferService.ExpireOffers(env.Context)
+	require.NoError(t, err)
+	assert.Equal(t, "expired", getTestProductOffers(t, env, seller, productID)[offerID]["status"])
+
+	w = answerTestOffer(t, seller, offerID, nil, env.Dependencies.OfferHandler.AcceptOffer)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "OFFER_NOT_PENDING")
+
+	w = makeTestOffer(t, env, buyer, productID, 460)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "accepted", decodeTestOffer(t, w)["status"], "Offers at the auto-accept price are accepted right away")
+	product := getTestProduct(t, env, productID)
+	assert.Equal(t, buyer.UserID.String(), product["sold_to"])
+	assert.EqualValues(t, 460, product["current_price"])
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOutboxRelay tests that bid events are only published once the outbox is relayed, and only once
+func TestOutboxRelay(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(5)
+	bidder := GetTestUser(6)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Outbox Product",
+		"min_price":     10,
+		"current_price": 10,
+	})
+
+	var mu sync.Mutex
+	var received []events.Event
+	env.Dependencies.Bus.Subscribe(func(ctx context.Context, e events.Event) error {
+		if e.AggregateID.String() != productID {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, e)
+		return nil
+	}, events.BidPlaced)
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received)
+	}
+
+	// A rejected bid rolls back and leaves nothing in the outbox
+	require.Equal(t, http.StatusForbidden, placeTestBid(t, env, seller, productID, 20).Code, "Self bid should fail")
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, productID, 20).Code, "Bid should succeed")
+	assert.Equal(t, 0, count(), "Events should not be published before the relay runs")
+
+	_, err := env.Dependencies.OutboxRelay.RelayPending(env.Context)
+	require.NoError(t, err)
+	require.Equal(t, 1, count(), "Bid event should be published exactly once")
+
+	// Rows already sent are not published again
+	_, err = env.Dependencies.OutboxRelay.RelayPending(env.Context)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count())
+
+	mu.Lock()
+	event := received[0]
+	mu.Unlock()
+	assert.Equal(t, events.BidPlaced, event.Type)
+	var data events.BidPlacedData
+	require.NoError(t, json.Unmarshal(event.Payload, &data))
+	assert.Equal(t, seller.UserID, data.SellerID)
+	assert.Equal(t, bidder.UserID, data.BidderID)
+	assert.EqualValues(t, 20, data.BidAmount)
+}
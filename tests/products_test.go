@@ -98,6 +98,56 @@ func addSellerIDToContext(req *http.Request, sellerID string) *http.Request {
 	return req.WithContext(ctx)
 }
 
+// createTestProduct creates a product listed by the given seller and returns its ID
+func createTestProduct(t *testing.T, env *TestEnv, seller *TestUser, payload map[string]interface{}) string {
+	if _, ok := payload["images"]; !ok {
+		payload["images"] = uploadTestImages(t, env, seller, "test_image_1.png")
+	}
+	payloadBytes, err := json.Marshal(payload)
+	require.NoError(t, err, "Should marshal payload")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+seller.AccessToken)
+	req = addProductAuthContext(req, seller)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.CreateProduct(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, "Product creation should succeed")
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	data := response["data"].(map[string]interface{})
+	return data["product_id"].(string)
+}
+
+// placeTestBid places a bid through the handler and returns the recorded response
+func placeTestBid(t *testing.T, env *TestEnv, bidder *TestUser, productID string, amount int) *httptest.ResponseRecorder {
+	payloadBytes, err := json.Marshal(map[string]interface{}{"bid_amount": amount})
+	require.NoError(t, err, "Should marshal payload")
+
+	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/products/%s/bid", productID), bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+bidder.AccessToken)
+	req = addProductIDToContext(req, productID)
+	req = addProductAuthContext(req, bidder)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.PlaceBid(w, req)
+	return w
+}
+
+// getTestProduct fetches a product through the handler and returns its JSON representation
+func getTestProduct(t *testing.T, env *TestEnv, productID string) map[string]interface{} {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/products/%s", productID), nil)
+	req = addProductIDToContext(req, productID)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.GetProductByID(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "Product should be readable")
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	return response["data"].(map[string]interface{})["product"].(map[string]interface{})
+}
+
 // TestProductCreation tests product creation endpoint
 func TestProductCreation(t *testing.T) {
 	env := GetTestEnv()
@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsDrac/e-auc/internal/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callProfileEndpoint sends body as JSON to a handler of the signed in user
+func callProfileEndpoint(t *testing.T, user *TestUser, method string, body map[string]interface{}, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	payloadBytes, err := json.Marshal(body)
+	require.NoError(t, err, "Should marshal payload")
+	req := httptest.NewRequest(method, "/api/v1/users/me", bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req = addAuthContext(req, user)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+// getTestProfile returns the public profile of the user with the given username
+func getTestProfile(t *testing.T, env *TestEnv, username string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+username, nil)
+	w := httptest.NewRecorder()
+	env.Dependencies.UserHandler.PublicProfile(w, withURLParam(req, "username", username))
+	return w
+}
+
+// uploadTestProfileImage uploads a test asset as the given multipart field through handler
+func uploadTestProfileImage(t *testing.T, user *TestUser, field string, imageFile string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	file, err := os.Open(filepath.Join("assets", imageFile))
+	require.NoError(t, err, "Should open test image file")
+	defer file.Close()
+	part, err := writer.CreateFormFile(field, imageFile)
+	require.NoError(t, err, "Should create form file")
+	_, err = io.Copy(part, file)
+	require.NoError(t, err, "Should copy file content")
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/me/"+field, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req = addAuthContext(req, user)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+// TestPublicProfileAndStorefront tests that anyone can see a seller's storefront without their private details
+func TestPublicProfileAndStorefront(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(2)
+	other := GetTestUser(3)
+	require.NotNil(t, seller)
+	require.NotNil(t, other)
+	handler := env.Dependencies.UserHandler
+
+	w := callProfileEndpoint(t, seller, http.MethodPatch, map[string]interface{}{"bio": "Vintage clocks and radios"}, handler.UpdateProfile)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = uploadTestProfileImage(t, seller, "banner", "test_image_2.png", handler.UploadBanner)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotEmpty(t, decodeTestData(t, w)["banner_url"])
+	w = uploadTestProfileImage(t, seller, "avatar", "test_image_3.png", handler.UploadAvatar)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	featuredID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Featured Wireless Set",
+		"min_price":     100,
+		"current_price": 100,
+	})
+	draftID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Unfinished Wireless Set",
+		"min_price":     100,
+		"current_price": 100,
+		"status":        "draft",
+	})
+	otherID := createTestProduct(t, env, other, map[string]interface{}{
+		"title":         "Someone Else's Set",
+		"min_price":     100,
+		"current_price": 100,
+	})
+
+	for _, productID := range []string{draftID, otherID} {
+		w = callProfileEndpoint(t, seller, http.MethodPut, map[string]interface{}{"product_ids": []string{productID}}, handler.SetFeaturedListings)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "LISTING_NOT_FEATURABLE")
+	}
+	w = callProfileEndpoint(t, seller, http.MethodPut, map[string]interface{}{"product_ids": []string{featuredID}}, handler.SetFeaturedListings)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = getTestProfile(t, env, seller.Username)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), seller.Email, "Public profiles never show the email")
+	profile := decodeTestData(t, w)["profile"].(map[string]interface{})
+	assert.Equal(t, seller.Username, profile["username"])
+	assert.NotEmpty(t, profile["joined_at"])
+	assert.Equal(t, "Vintage clocks and radios", profile["bio"])
+	assert.NotEmpty(t, profile["banner_url"])
+	assert.NotEmpty(t, profile["avatar_url"])
+	assert.Contains(t, profile, "reputation")
+	assert.Contains(t, profile, "sold_listings")
+
+	featured := profile["featured_listings"].([]interface{})
+	require.Len(t, featured, 1)
+	assert.Equal(t, featuredID, featured[0].(map[string]interface{})["id"])
+	var active []string
+	for _, product := range profile["active_listings"].([]interface{}) {
+		active = append(active, product.(map[string]interface{})["id"].(string))
+	}
+	assert.Contains(t, active, featuredID)
+	assert.NotContains(t, active, draftID, "Drafts are not shown")
+
+	w = getTestProfile(t, env, "nobody-by-this-name")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// TestProfileUpdates tests username changes and that a new email only takes effect once verified
+func TestProfileUpdates(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	user := GetTestUser(0)
+	other := GetTestUser(1)
+	require.NotNil(t, user)
+	require.NotNil(t, other)
+	handler := env.Dependencies.UserHandler
+
+	w := callProfileEndpoint(t, user, http.MethodPatch, map[string]interface{}{"username": other.Username}, handler.UpdateProfile)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "USERNAME_TAKEN")
+
+	renamed := user.Username + "-renamed"
+	w = callProfileEndpoint(t, user, http.MethodPatch, map[string]interface{}{"username": renamed}, handler.UpdateProfile)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	previous := user.Username
+	user.Username = renamed
+	assert.Equal(t, http.StatusOK, getTestProfile(t, env, renamed).Code)
+	assert.Equal(t, http.StatusNotFound, getTestProfile(t, env, previous).Code)
+
+	w = callProfileEndpoint(t, user, http.MethodPost, map[string]interface{}{"email": other.Email}, handler.ChangeEmail)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "EMAIL_TAKEN")
+
+	newEmail := "renamed-" + user.Email
+	w = callProfileEndpoint(t, user, http.MethodPost, map[string]interface{}{"email": newEmail}, handler.ChangeEmail)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+
+	// The email stays the same until it is verified
+	w = callProfileEndpoint(t, user, http.MethodGet, nil, handler.Profile)
+	require.Equal(t, http.StatusOK, w.Code)
+	me := decodeTestData(t, w)
+	assert.Equal(t, user.Email, me["email"])
+	assert.Equal(t, newEmail, me["pending_email"])
+	assert.NotContains(t, me, "email_verification_token_hash")
+
+	fakeMailer, ok := env.Dependencies.Mailer.(*mailer.FakeMailer)
+	require.True(t, ok, "Tests run with the fake mailer")
+	message, ok := fakeMailer.LastTo(newEmail)
+	require.True(t, ok, "A verification code should be mailed to the new email")
+	lines := strings.Split(strings.TrimSpace(message.Body), "\n")
+	token := lines[len(lines)-1]
+
+	w = callProfileEndpoint(t, user, http.MethodPost, map[string]interface{}{"token": "not-the-token"}, handler.VerifyEmail)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_VERIFICATION_TOKEN")
+
+	w = callProfileEndpoint(t, user, http.MethodPost, map[string]interface{}{"token": token}, handler.VerifyEmail)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	user.Email = newEmail
+	me = decodeTestData(t, w)
+	assert.Equal(t, newEmail, me["email"])
+	assert.Nil(t, me["pending_email"])
+
+	// Tokens are used once
+	w = callProfileEndpoint(t, user, http.MethodPost, map[string]interface{}{"token": token}, handler.VerifyEmail)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// placeTestLotBid bids amount per unit for quantity units through the handler
+func placeTestLotBid(t *testing.T, env *TestEnv, bidder *TestUser, productID string, amount int, quantity int) *httptest.ResponseRecorder {
+	payloadBytes, err := json.Marshal(map[string]interface{}{"bid_amount": amount, "quantity": quantity})
+	require.NoError(t, err, "Should marshal payload")
+
+	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/products/%s/bid", productID), bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req = addProductIDToContext(req, productID)
+	req = addProductAuthContext(req, bidder)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.PlaceBid(w, req)
+	return w
+}
+
+// getTestProductOrders lists the orders of a product sold by seller, keyed by buyer
+func getTestProductOrders(t *testing.T, env *TestEnv, seller *TestUser, productID string) map[string]map[string]interface{} {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders?role=seller&limit=100", nil)
+	req = addProductAuthContext(req, seller)
+	w := httptest.NewRecorder()
+	env.Dependencies.OrderHandler.ListOrders(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	orders := map[string]map[string]interface{}{}
+	for _, o := range response["data"].(map[string]interface{})["orders"].([]interface{}) {
+		order := o.(map[string]interface{})
+		if order["product_id"] == productID {
+			orders[order["buyer_id"].(string)] = order
+		}
+	}
+	return orders
+}
+
+// getTestOrder fetches a single order through the handler as user
+func getTestOrder(env *TestEnv, user *TestUser, orderID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/orders/%s", orderID), nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("orderId", orderID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	env.Dependencies.OrderHandler.GetOrder(w, req)
+	return w
+}
+
+// TestMultiUnitPayAsBid tests that units go to the highest bids and every winner pays their own bid
+func TestMultiUnitPayAsBid(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(7)
+	bidderA := GetTestUser(8)
+	bidderB := GetTestUser(9)
+	bidderC := GetTestUser(0)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidderA)
+	require.NotNil(t, bidderB)
+	require.NotNil(t, bidderC)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Box of Three",
+		"min_price":     10,
+		"current_price": 10,
+		"quantity":      3,
+	})
+	assert.EqualValues(t, 3, getTestProduct(t, env, productID)["quantity"])
+
+	w := placeTestLotBid(t, env, bidderA, productID, 20, 4)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_BID_QUANTITY")
+
+	// The price to beat only moves once every unit is taken
+	require.Equal(t, http.StatusOK, placeTestLotBid(t, env, bidderA, productID, 20, 2).Code)
+	assert.EqualValues(t, 10, getTestProduct(t, env, productID)["current_price"])
+	require.Equal(t, http.StatusOK, placeTestLotBid(t, env, bidderB, productID, 15, 1).Code)
+	assert.EqualValues(t, 15, getTestProduct(t, env, productID)["current_price"])
+
+	w = placeTestLotBid(t, env, bidderC, productID, 12, 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "BID_BELOW_INCREMENT")
+
+	// C outbids B and takes the last unit of their two
+	require.Equal(t, http.StatusOK, placeTestLotBid(t, env, bidderC, productID, 18, 2).Code)
+	assert.EqualValues(t, 18, getTestProduct(t, env, productID)["current_price"])
+
+	endTestAuction(t, env, productID)
+	product := getTestProduct(t, env, productID)
+	assert.Nil(t, product["sold_to"], "Several winners are only recorded in their orders")
+	assert.NotNil(t, product["sold_at"])
+
+	orders := getTestProductOrders(t, env, seller, productID)
+	require.Len(t, orders, 2)
+	orderA := orders[bidderA.UserID.String()]
+	require.NotNil(t, orderA)
+	assert.EqualValues(t, 2, orderA["quantity"])
+	assert.EqualValues(t, 20, orderA["unit_price"])
+	assert.EqualValues(t, 40, orderA["total_price"])
+	orderC := orders[bidderC.UserID.String()]
+	require.NotNil(t, orderC)
+	assert.EqualValues(t, 1, orderC["quantity"], "The last winner gets what is left")
+	assert.EqualValues(t, 18, orderC["unit_price"])
+	assert.Nil(t, orders[bidderB.UserID.String()])
+}
+
+// TestMultiUnitUniformPrice tests that every winner of a uniform-price auction pays the lowest winning bid
+func TestMultiUnitUniformPrice(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(7)
+	bidderA := GetTestUser(8)
+	bidderB := GetTestUser(9)
+	bidderC := GetTestUser(0)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidderA)
+	require.NotNil(t, bidderB)
+	require.NotNil(t, bidderC)
+
+	// Buy-now sells a single unit
+	payload := map[string]interface{}{
+		"title":         "Two Seats",
+		"images":        uploadTestImages(t, env, seller, "test_image_1.png"),
+		"min_price":     10,
+		"current_price": 10,
+		"buy_now_price": 50,
+		"quantity":      2,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader(payloadBytes))
+	req = addProductAuthContext(req, seller)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.CreateProduct(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_QUANTITY")
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Two Seats",
+		"min_price":     10,
+		"current_price": 10,
+		"quantity":      2,
+		"auction_type":  "sealed_first_price",
+		"pricing_rule":  "uniform",
+	})
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderA, productID, 30).Code)
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderB, productID, 25).Code)
+	require.Equal(t, http.StatusOK, placeTestLotBid(t, env, bidderC, productID, 20, 2).Code)
+
+	endTestAuction(t, env, productID)
+	assert.EqualValues(t, 25, getTestProduct(t, env, productID)["current_price"])
+
+	orders := getTestProductOrders(t, env, seller, productID)
+	require.Len(t, orders, 2)
+	for _, winner := range []*TestUser{bidderA, bidderB} {
+		order := orders[winner.UserID.String()]
+		require.NotNil(t, order)
+		assert.EqualValues(t, 1, order["quantity"])
+		assert.EqualValues(t, 25, order["unit_price"], "Every winner pays the lowest winning bid")
+	}
+
+	// Orders are only visible to their parties
+	orderID := orders[bidderA.UserID.String()]["id"].(string)
+	assert.Equal(t, http.StatusOK, getTestOrder(env, bidderA, orderID).Code)
+	assert.Equal(t, http.StatusOK, getTestOrder(env, seller, orderID).Code)
+	w = getTestOrder(env, bidderC, orderID)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "ORDER_NOT_FOUND")
+}
@@ -0,0 +1,173 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callBidsEndpoint sends body to a bid retraction handler as user with the given extra URL parameter
+func callBidsEndpoint(t *testing.T, user *TestUser, productID string, param string, value string, body map[string]interface{}, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	payloadBytes, err := json.Marshal(body)
+	require.NoError(t, err, "Should marshal payload")
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/products/%s", productID), bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("productId", productID)
+	rctx.URLParams.Add(param, value)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+// findTestBid returns the bid of bidder at price on the product
+func findTestBid(t *testing.T, env *TestEnv, productID string, bidder *TestUser, price int) map[string]interface{} {
+	for _, bid := range getTestProductBids(t, env, productID) {
+		if bid["user_id"] == bidder.UserID.String() && bid["price"] == float64(price) {
+			return bid
+		}
+	}
+	require.FailNow(t, "bid not found", "%s bid %d", bidder.UserID, price)
+	return nil
+}
+
+// TestBidRetractionRecomputesPrice tests that retracted and cancelled bids stop counting and the price falls back
+func TestBidRetractionRecomputesPrice(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(0)
+	bidderA := GetTestUser(1)
+	bidderB := GetTestUser(9)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidderA)
+	require.NotNil(t, bidderB)
+	handler := env.Dependencies.ProductHandler
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Retractable Lamp",
+		"min_price":     100,
+		"current_price": 100,
+	})
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderA, productID, 110).Code)
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderB, productID, 120).Code)
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderA, productID, 130).Code)
+	topBid := findTestBid(t, env, productID, bidderA, 130)["id"].(string)
+
+	w := callBidsEndpoint(t, bidderB, productID, "bidId", topBid, map[string]interface{}{"reason": "not mine"}, handler.RetractBid)
+	assert.Equal(t, http.StatusNotFound, w.Code, "Only the bidder can retract a bid")
+
+	w = callBidsEndpoint(t, bidderA, productID, "bidId", topBid, map[string]interface{}{}, handler.RetractBid)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "RETRACTION_REASON_REQUIRED")
+
+	w = callBidsEndpoint(t, bidderA, productID, "bidId", topBid, map[string]interface{}{"reason": "typed one zero too many"}, handler.RetractBid)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.EqualValues(t, 120, getTestProduct(t, env, productID)["current_price"], "The price falls back to the best bid left")
+
+	retracted := findTestBid(t, env, productID, bidderA, 130)
+	assert.Equal(t, false, retracted["is_valid"])
+	assert.Equal(t, "typed one zero too many", retracted["retraction_reason"])
+	assert.Equal(t, true, findTestBid(t, env, productID, bidderB, 120)["leading"])
+
+	// Bids can only be retracted shortly after they were placed
+	bidB := findTestBid(t, env, productID, bidderB, 120)["id"].(string)
+	_, err := env.Dependencies.Conn.Exec(env.Context, "UPDATE bids SET bid_at = NOW() - INTERVAL '1 hour' WHERE id = $1", bidB)
+	require.NoError(t, err)
+	w = callBidsEndpoint(t, bidderB, productID, "bidId", bidB, map[string]interface{}{"reason": "changed my mind"}, handler.RetractBid)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "RETRACTION_WINDOW_PASSED")
+
+	// The seller can cancel the bids of a bidder at any time
+	w = callBidsEndpoint(t, bidderA, productID, "bidderId", bidderB.UserID.String(), nil, handler.CancelBidderBids)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = callBidsEndpoint(t, seller, productID, "bidderId", bidderB.UserID.String(), map[string]interface{}{"reason": "unpaid items elsewhere"}, handler.CancelBidderBids)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.EqualValues(t, 110, getTestProduct(t, env, productID)["current_price"])
+
+	w = callBidsEndpoint(t, seller, productID, "bidderId", bidderB.UserID.String(), nil, handler.CancelBidderBids)
+	assert.Equal(t, http.StatusNotFound, w.Code, "No valid bids are left to cancel")
+
+	w = callBidsEndpoint(t, seller, productID, "bidderId", bidderA.UserID.String(), nil, handler.CancelBidderBids)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.EqualValues(t, 100, getTestProduct(t, env, productID)["current_price"], "Without bids the price is back at the start")
+}
+
+// TestBidRetractionFinalHour tests that bidders cannot retract in the final hour of an auction
+func TestBidRetractionFinalHour(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(0)
+	bidder := GetTestUser(1)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Last Minute Clock",
+		"min_price":     50,
+		"current_price": 50,
+		"ends_at":       time.Now().Add(30 * time.Minute).UTC().Format(time.RFC3339),
+	})
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, productID, 60).Code)
+	bidID := findTestBid(t, env, productID, bidder, 60)["id"].(string)
+
+	w := callBidsEndpoint(t, bidder, productID, "bidId", bidID, map[string]interface{}{"reason": "too late to back out"}, env.Dependencies.ProductHandler.RetractBid)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "RETRACTION_FINAL_HOUR")
+	assert.EqualValues(t, 60, getTestProduct(t, env, productID)["current_price"])
+}
+
+// TestLiveFeedSeesRetractedPrice tests that live subscribers see the price fall back after a retraction without its details
+func TestLiveFeedSeesRetractedPrice(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(0)
+	bidderA := GetTestUser(1)
+	bidderB := GetTestUser(9)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidderA)
+	require.NotNil(t, bidderB)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Live Retraction Clock",
+		"min_price":     100,
+		"current_price": 100,
+	})
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderA, productID, 110).Code)
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderB, productID, 120).Code)
+	_, err := env.Dependencies.OutboxRelay.RelayPending(env.Context)
+	require.NoError(t, err)
+
+	nextEvent := openTestLiveFeed(t, env, productID)
+	require.Equal(t, "snapshot", nextEvent())
+
+	topBid := findTestBid(t, env, productID, bidderB, 120)["id"].(string)
+	w := callBidsEndpoint(t, bidderB, productID, "bidId", topBid, map[string]interface{}{"reason": "wrong listing"}, env.Dependencies.ProductHandler.RetractBid)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	_, err = env.Dependencies.OutboxRelay.RelayPending(env.Context)
+	require.NoError(t, err)
+
+	assert.Equal(t, events.AuctionPriceUpdated, nextEvent(), "The retraction itself stays off the feed, its new price does not")
+
+	var price int64
+	err = env.Dependencies.Conn.QueryRow(env.Context,
+		"SELECT (payload->>'price')::BIGINT FROM outbox WHERE event_type = $1 AND aggregate_id = $2", events.AuctionPriceUpdated, productID).Scan(&price)
+	require.NoError(t, err)
+	assert.EqualValues(t, 110, price)
+}
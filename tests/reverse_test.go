@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReverseAuction tests that sellers bid a buyer's request down and the lowest bid wins
+func TestReverseAuction(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	buyer := GetTestUser(4)
+	supplierA := GetTestUser(2)
+	supplierB := GetTestUser(3)
+	require.NotNil(t, buyer)
+	require.NotNil(t, supplierA)
+	require.NotNil(t, supplierB)
+
+	productID := createTestProduct(t, env, buyer, map[string]interface{}{
+		"title":         "Procurement Request",
+		"min_price":     460,
+		"current_price": 500,
+		"auction_type":  "reverse",
+	})
+	product := getTestProduct(t, env, productID)
+	assert.Equal(t, "buyer", product["owner_role"])
+	assert.EqualValues(t, 495, product["next_max_bid"], "Prices from 100 go down by 5")
+	assert.Nil(t, product["next_min_bid"])
+
+	w := placeTestBid(t, env, supplierA, productID, 497)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "BID_ABOVE_INCREMENT")
+	assert.Contains(t, w.Body.String(), "495")
+
+	w = placeTestBid(t, env, buyer, productID, 400)
+	assert.Equal(t, http.StatusForbidden, w.Code, "The buyer owns the request and cannot bid on it")
+
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, supplierA, productID, 480).Code)
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, supplierB, productID, 470).Code)
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, supplierA, productID, 460).Code)
+	assert.EqualValues(t, 460, getTestProduct(t, env, productID)["current_price"])
+
+	bids := getTestProductBids(t, env, productID)
+	require.Len(t, bids, 3)
+	for _, bid := range bids {
+		assert.Equal(t, "seller", bid["bidder_role"])
+		assert.Equal(t, bid["price"] == float64(460), bid["leading"], "Only the lowest bid should lead")
+	}
+
+	// The buyer's listings show them as the buyer
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/products/seller/%s", buyer.UserID), nil)
+	req = addSellerIDToContext(req, buyer.UserID.String())
+	w = httptest.NewRecorder()
+	env.Dependencies.ProductHandler.ProductsBySellerID(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	found := false
+	for _, p := range response["data"].(map[string]interface{})["products"].([]interface{}) {
+		listed := p.(map[string]interface{})
+		if listed["id"] == productID {
+			found = true
+			assert.Equal(t, "buyer", listed["owner_role"])
+		}
+	}
+	assert.True(t, found, "Request should be listed for its owner")
+
+	endTestAuction(t, env, productID)
+	product = getTestProduct(t, env, productID)
+	assert.Equal(t, supplierA.UserID.String(), product["sold_to"], "Lowest bidder should win")
+	assert.EqualValues(t, 460, product["current_price"])
+}
+
+// TestReverseAuctionOverBudget tests that a request whose lowest bid is above the buyer's budget ends unsold
+func TestReverseAuctionOverBudget(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	buyer := GetTestUser(4)
+	supplierA := GetTestUser(2)
+	supplierB := GetTestUser(3)
+	require.NotNil(t, buyer)
+	require.NotNil(t, supplierA)
+	require.NotNil(t, supplierB)
+
+	productID := createTestProduct(t, env, buyer, map[string]interface{}{
+		"title":         "Tight Budget Request",
+		"min_price":     400,
+		"current_price": 500,
+		"auction_type":  "reverse",
+	})
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, supplierA, productID, 480).Code)
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, supplierB, productID, 470).Code)
+
+	// The lowest bid misses the budget
+	endTestAuction(t, env, productID)
+	product := getTestProduct(t, env, productID)
+	assert.Equal(t, "ended", product["status"])
+	assert.Nil(t, product["sold_to"], "A bid above the budget should not win")
+	assert.Nil(t, product["sold_at"])
+}
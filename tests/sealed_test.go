@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getTestProductBids lists the bids of a product through the handler
+func getTestProductBids(t *testing.T, env *TestEnv, productID string) []map[string]interface{} {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/products/%s/bids", productID), nil)
+	req = addProductIDToContext(req, productID)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.GetProductBids(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "Bids should be readable")
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	var bids []map[string]interface{}
+	for _, bid := range response["data"].(map[string]interface{})["bids"].([]interface{}) {
+		bids = append(bids, bid.(map[string]interface{}))
+	}
+	return bids
+}
+
+// endTestAuction moves the end of an auction into the past and runs settlement
+func endTestAuction(t *testing.T, env *TestEnv, productID string) {
+	_, err := env.Dependencies.Conn.Exec(env.Context, "UPDATE products SET ends_at = NOW() - INTERVAL '1 second' WHERE id = $1", productID)
+	require.NoError(t, err)
+	_, err = env.Dependencies.Services.ProductService.SettleEndedAuctions(env.Context)
+	require.NoError(t, err)
+}
+
+// TestVickreyAuction tests hidden revisable bids and second-price settlement
+func TestVickreyAuction(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(9)
+	bidderA := GetTestUser(0)
+	bidderB := GetTestUser(1)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidderA)
+	require.NotNil(t, bidderB)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Vickrey Tender",
+		"min_price":     10,
+		"current_price": 10,
+		"auction_type":  "vickrey",
+	})
+
+	w := placeTestBid(t, env, bidderA, productID, 5)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "BID_BELOW_INCREMENT")
+
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderA, productID, 50).Code)
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderB, productID, 30).Code)
+	// A sealed bid can be revised, even downwards, instead of stacking consecutive bids
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderA, productID, 60).Code, "Bidder should revise their bid")
+
+	product := getTestProduct(t, env, productID)
+	assert.EqualValues(t, 10, product["current_price"], "Sealed bids should not move the current price")
+	bids := getTestProductBids(t, env, productID)
+	require.Len(t, bids, 2, "Each bidder should have a single bid")
+	for _, bid := range bids {
+		assert.Nil(t, bid["price"], "Amounts should be hidden until the close")
+	}
+
+	endTestAuction(t, env, productID)
+
+	product = getTestProduct(t, env, productID)
+	assert.Equal(t, bidderA.UserID.String(), product["sold_to"], "Highest bidder should win")
+	assert.EqualValues(t, 30, product["current_price"], "Winner should pay the second highest bid")
+	assert.NotNil(t, product["closed_at"])
+	for _, bid := range getTestProductBids(t, env, productID) {
+		assert.NotNil(t, bid["price"], "Amounts should be revealed after the close")
+	}
+
+	w = placeTestBid(t, env, bidderB, productID, 100)
+	assert.Equal(t, http.StatusConflict, w.Code, "Closed auctions should reject bids")
+}
+
+// TestSealedFirstPriceAuction tests first-price settlement and that bid events never leak amounts
+func TestSealedFirstPriceAuction(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(9)
+	bidderA := GetTestUser(0)
+	bidderB := GetTestUser(1)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidderA)
+	require.NotNil(t, bidderB)
+
+	// Buy now is an english auction feature
+	payloadBytes, err := json.Marshal(map[string]interface{}{
+		"title":         "Sealed Buy Now",
+		"images":        uploadTestImages(t, env, seller, "test_image_1.png"),
+		"min_price":     10,
+		"current_price": 10,
+		"buy_now_price": 100,
+		"auction_type":  "sealed_first_price",
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req = addProductAuthContext(req, seller)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.CreateProduct(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_BUY_NOW_PRICE")
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "First Price Tender",
+		"min_price":     10,
+		"current_price": 10,
+		"auction_type":  "sealed_first_price",
+	})
+
+	var mu sync.Mutex
+	var received []events.Event
+	env.Dependencies.Bus.Subscribe(func(ctx context.Context, e events.Event) error {
+		if e.AggregateID.String() != productID {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, e)
+		return nil
+	}, events.BidPlaced, events.AuctionClosed)
+
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderA, productID, 40).Code)
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderB, productID, 70).Code)
+	endTestAuction(t, env, productID)
+
+	product := getTestProduct(t, env, productID)
+	assert.Equal(t, bidderB.UserID.String(), product["sold_to"])
+	assert.EqualValues(t, 70, product["current_price"], "Winner should pay their own bid")
+
+	_, err = env.Dependencies.OutboxRelay.RelayPending(env.Context)
+	require.NoError(t, err)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 3)
+	for _, e := range received {
+		if e.Type == events.BidPlaced {
+			assert.NotContains(t, string(e.Payload), "bid_amount", "Sealed bid events should not carry the amount")
+		}
+	}
+	assert.Equal(t, events.AuctionClosed, received[2].Type)
+	assert.Contains(t, string(received[2].Payload), `"price":70`)
+}
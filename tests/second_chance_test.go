@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// offerTestSecondChance makes a second-chance offer for bidID as seller through the handler
+func offerTestSecondChance(t *testing.T, env *TestEnv, seller *TestUser, productID string, body map[string]interface{}) *httptest.ResponseRecorder {
+	payloadBytes, err := json.Marshal(body)
+	require.NoError(t, err, "Should marshal payload")
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/products/%s/second-chance", productID), bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req = addProductIDToContext(req, productID)
+	req = addProductAuthContext(req, seller)
+	w := httptest.NewRecorder()
+	env.Dependencies.SecondChanceHandler.CreateSecondChanceOffer(w, req)
+	return w
+}
+
+// answerTestSecondChance accepts or declines an offer as user through handler
+func answerTestSecondChance(user *TestUser, offerID string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/second-chance-offers/%s", offerID), nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("offerId", offerID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+// pendingTestSecondChance returns the open offer of productID waiting for user, or nil
+func pendingTestSecondChance(t *testing.T, env *TestEnv, user *TestUser, productID string) map[string]interface{} {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/second-chance-offers", nil)
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	env.Dependencies.SecondChanceHandler.ListMySecondChanceOffers(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	offers, _ := response["data"].(map[string]interface{})["offers"].([]interface{})
+	for _, o := range offers {
+		offer := o.(map[string]interface{})
+		if offer["product_id"] == productID {
+			return offer
+		}
+	}
+	return nil
+}
+
+// createTestUnsoldAuction ends an english auction below its reserve with a bid from every bidder
+// and returns the product and the bid ID of every bidder
+func createTestUnsoldAuction(t *testing.T, env *TestEnv, seller *TestUser, bids map[*TestUser]int) (string, map[*TestUser]string) {
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Reserve Not Met Guitar",
+		"min_price":     1000,
+		"current_price": 100,
+	})
+	for bidder, amount := range bids {
+		require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, productID, amount).Code)
+	}
+	endTestAuction(t, env, productID)
+	product := getTestProduct(t, env, productID)
+	require.Equal(t, "ended", product["status"])
+	require.Nil(t, product["sold_to"], "Reserve was not met")
+
+	bidIDs := map[*TestUser]string{}
+	for _, bid := range getTestProductBids(t, env, productID) {
+		for bidder := range bids {
+			if bid["user_id"] == bidder.UserID.String() {
+				bidIDs[bidder] = bid["id"].(string)
+			}
+		}
+	}
+	require.Len(t, bidIDs, len(bids))
+	return productID, bidIDs
+}
+
+// TestSecondChanceAutoAdvance tests that a declined offer moves on to the next runner-up who can buy at their bid
+func TestSecondChanceAutoAdvance(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(6)
+	top := GetTestUser(5)
+	second := GetTestUser(7)
+	require.NotNil(t, seller)
+	require.NotNil(t, top)
+	require.NotNil(t, second)
+	handler := env.Dependencies.SecondChanceHandler
+
+	productID, bidIDs := createTestUnsoldAuction(t, env, seller, map[*TestUser]int{top: 400, second: 300})
+
+	w := offerTestSecondChance(t, env, top, productID, map[string]interface{}{"bid_id": bidIDs[second]})
+	assert.Equal(t, http.StatusForbidden, w.Code, "Only the seller can make offers")
+
+	w = offerTestSecondChance(t, env, seller, productID, map[string]interface{}{"bid_id": bidIDs[top], "expires_in_hours": 100})
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Offers stay open for at most 72 hours")
+
+	w = offerTestSecondChance(t, env, seller, productID, map[string]interface{}{"bid_id": bidIDs[top], "auto_advance": true})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = offerTestSecondChance(t, env, seller, productID, map[string]interface{}{"bid_id": bidIDs[second]})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "SECOND_CHANCE_PENDING")
+
+	offer := pendingTestSecondChance(t, env, top, productID)
+	require.NotNil(t, offer)
+	assert.EqualValues(t, 400, offer["price"])
+
+	w = answerTestSecondChance(second, offer["id"].(string), handler.AcceptSecondChanceOffer)
+	assert.Equal(t, http.StatusNotFound, w.Code, "Offers can only be answered by their bidder")
+
+	w = answerTestSecondChance(top, offer["id"].(string), handler.DeclineSecondChanceOffer)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = answerTestSecondChance(top, offer["id"].(string), handler.AcceptSecondChanceOffer)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "OFFER_NOT_PENDING")
+
+	// The offer moved on to the next best bidder
+	next := pendingTestSecondChance(t, env, second, productID)
+	require.NotNil(t, next)
+	assert.EqualValues(t, 300, next["price"])
+
+	w = answerTestSecondChance(second, next["id"].(string), handler.AcceptSecondChanceOffer)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	product := getTestProduct(t, env, productID)
+	assert.Equal(t, second.UserID.String(), product["sold_to"])
+	assert.EqualValues(t, 300, product["current_price"])
+	order, ok := getTestProductOrders(t, env, seller, productID)[second.UserID.String()]
+	require.True(t, ok, "Accepting should create an order")
+	assert.EqualValues(t, 300, order["total_price"])
+
+	w = offerTestSecondChance(t, env, seller, productID, map[string]interface{}{"bid_id": bidIDs[top]})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "SECOND_CHANCE_UNAVAILABLE")
+}
+
+// TestSecondChanceExpiry tests that unanswered offers expire and can no longer be accepted
+func TestSecondChanceExpiry(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(6)
+	bidder := GetTestUser(8)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+
+	productID, bidIDs := createTestUnsoldAuction(t, env, seller, map[*TestUser]int{bidder: 250})
+
+	w := offerTestSecondChance(t, env, seller, productID, map[string]interface{}{"bid_id": bidIDs[bidder], "expires_in_hours": 1})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	offer := pendingTestSecondChance(t, env, bidder, productID)
+	require.NotNil(t, offer)
+
+	_, err := env.Dependencies.Conn.Exec(env.Context, "UPDATE second_chance_offers SET expires_at = NOW() - INTERVAL '1 second' WHERE id = $1", offer["id"])
+	require.NoError(t, err)
+	_, err = env.Dependencies.Services.SecondChanceService.ExpireOffers(env.Context)
+	require.NoError(t, err)
+
+	assert.Nil(t, pendingTestSecondChance(t, env, bidder, productID))
+	w = answerTestSecondChance(bidder, offer["id"].(string), env.Dependencies.SecondChanceHandler.AcceptSecondChanceOffer)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "OFFER_NOT_PENDING")
+	assert.Nil(t, getTestProduct(t, env, productID)["sold_to"])
+}
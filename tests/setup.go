@@ -246,6 +246,9 @@ func setTestEnvVars(dbURL, minioEndpoint, redisEndpoint string) {
 	os.Setenv("ACCESS_TOKEN_SECRET", "test-access-secret-key-for-testing")
 	os.Setenv("REFRESH_TOKEN_SECRET", "test-refresh-secret-key-for-testing")
 
+	// Webhook receivers are httptest servers on loopback
+	os.Setenv("WEBHOOK_ALLOW_LOOPBACK", "true")
+
 	// Server
 	os.Setenv("SERVER_HOST", "localhost")
 	os.Setenv("SERVER_PORT", "8080")
@@ -258,9 +261,7 @@ func (env *TestEnv) Cleanup() error {
 	// Close dependencies
 	if env.Dependencies != nil {
 		if env.Dependencies.Conn != nil {
-			if err := env.Dependencies.Conn.Close(env.Context); err != nil {
-				errs = append(errs, fmt.Errorf("failed to close db connection: %w", err))
-			}
+			env.Dependencies.Conn.Close()
 		}
 		if env.Dependencies.Cache != nil {
 			if err := env.Dependencies.Cache.Close(); err != nil {
@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callShippingEndpoint calls an order or address handler as user with the given URL parameter, the body is only sent when given
+func callShippingEndpoint(t *testing.T, user *TestUser, param string, id string, body map[string]interface{}, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body != nil {
+		payloadBytes, err := json.Marshal(body)
+		require.NoError(t, err, "Should marshal payload")
+		req = httptest.NewRequest(http.MethodPost, "/api/v1/", bytes.NewReader(payloadBytes))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req = httptest.NewRequest(http.MethodPost, "/api/v1/", nil)
+	}
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(param, id)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+// decodeTestData returns the data object of a successful response
+func decodeTestData(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	return response["data"].(map[string]interface{})
+}
+
+// TestAddressBook tests that the address book keeps exactly one default address
+func TestAddressBook(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	user := GetTestUser(4)
+	require.NotNil(t, user)
+	handler := env.Dependencies.AddressHandler
+	address := map[string]interface{}{
+		"name":        "Ada Buyer",
+		"line1":       "1 Test Street",
+		"city":        "Berlin",
+		"postal_code": "10115",
+		"country":     "de",
+	}
+
+	w := callShippingEndpoint(t, user, "addressId", "", address, handler.CreateAddress)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	first := decodeTestData(t, w)["address"].(map[string]interface{})
+	assert.Equal(t, "DE", first["country"])
+
+	address["line1"] = "2 Test Street"
+	address["is_default"] = true
+	w = callShippingEndpoint(t, user, "addressId", "", address, handler.CreateAddress)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	second := decodeTestData(t, w)["address"].(map[string]interface{})
+	assert.Equal(t, true, second["is_default"])
+
+	address["country"] = "D1"
+	w = callShippingEndpoint(t, user, "addressId", first["id"].(string), address, handler.UpdateAddress)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_ADDRESS")
+
+	defaults := func() []string {
+		w := callShippingEndpoint(t, user, "addressId", "", nil, handler.ListAddresses)
+		require.Equal(t, http.StatusOK, w.Code)
+		var ids []string
+		for _, a := range decodeTestData(t, w)["addresses"].([]interface{}) {
+			if a.(map[string]interface{})["is_default"].(bool) {
+				ids = append(ids, a.(map[string]interface{})["id"].(string))
+			}
+		}
+		return ids
+	}
+	assert.Equal(t, []string{second["id"].(string)}, defaults())
+
+	// Deleting the default promotes the oldest address
+	w = callShippingEndpoint(t, user, "addressId", second["id"].(string), nil, handler.DeleteAddress)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, defaults(), 1)
+	w = callShippingEndpoint(t, user, "addressId", second["id"].(string), nil, handler.DeleteAddress)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// TestOrderShippingAndFulfilment tests regional shipping on the invoice, changing it before payment and the fulfilment states
+func TestOrderShippingAndFulfilment(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(3)
+	buyer := GetTestUser(4)
+	require.NotNil(t, seller)
+	require.NotNil(t, buyer)
+	orders := env.Dependencies.OrderHandler
+
+	w := callShippingEndpoint(t, buyer, "addressId", "", map[string]interface{}{
+		"name":        "Ada Buyer",
+		"line1":       "3 Shipping Lane",
+		"city":        "Hamburg",
+		"postal_code": "20095",
+		"country":     "DE",
+		"is_default":  true,
+	}, env.Dependencies.AddressHandler.CreateAddress)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	addressID := decodeTestData(t, w)["address"].(map[string]interface{})["id"].(string)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Shipped Gramophone",
+		"min_price":     100,
+		"current_price": 100,
+		"shipping_options": []map[string]interface{}{
+			{"method": "pickup"},
+			{"method": "flat_rate", "cost": 1000},
+			{"method": "regional", "region": "de", "cost": 300},
+		},
+	})
+	options := map[string]string{}
+	for _, o := range getTestProductData(t, env, productID)["shipping_options"].([]interface{}) {
+		option := o.(map[string]interface{})
+		options[option["method"].(string)] = option["id"].(string)
+	}
+	require.Len(t, options, 3)
+
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, buyer, productID, 150).Code)
+	endTestAuction(t, env, productID)
+
+	invoice := findTestInvoice(t, env, buyer, "buyer", productID)
+	require.NotNil(t, invoice)
+	assert.Equal(t, float64(300), invoice["shipping"], "The regional rate of the default address applies")
+	assert.Equal(t, float64(450), invoice["total"])
+	orderID := invoice["order_id"].(string)
+
+	w = callShippingEndpoint(t, seller, "orderId", orderID, map[string]interface{}{"carrier": "DHL", "tracking_number": "DE123"}, orders.ShipOrder)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "ORDER_NOT_PAID")
+	w = callShippingEndpoint(t, seller, "orderId", orderID, map[string]interface{}{"shipping_option_id": options["pickup"]}, orders.SetOrderShipping)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Switching to pickup reprices the invoice
+	w = callShippingEndpoint(t, buyer, "orderId", orderID, map[string]interface{}{"shipping_option_id": options["pickup"]}, orders.SetOrderShipping)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, float64(150), findTestInvoice(t, env, buyer, "buyer", productID)["amount_due"])
+	w = callShippingEndpoint(t, buyer, "orderId", orderID, map[string]interface{}{"shipping_option_id": options["flat_rate"]}, orders.SetOrderShipping)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "ADDRESS_REQUIRED")
+	w = callShippingEndpoint(t, buyer, "orderId", orderID, map[string]interface{}{"shipping_option_id": options["flat_rate"], "address_id": addressID}, orders.SetOrderShipping)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	order := decodeTestData(t, w)["order"].(map[string]interface{})
+	assert.Equal(t, "Hamburg", order["ship_to_city"])
+	assert.Equal(t, float64(1150), findTestInvoice(t, env, buyer, "buyer", productID)["amount_due"])
+
+	w = callInvoiceEndpoint(t, buyer, invoice["id"].(string), map[string]interface{}{"payment_token": "tok_visa"}, env.Dependencies.InvoiceHandler.PayInvoice)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = callShippingEndpoint(t, buyer, "orderId", orderID, map[string]interface{}{"shipping_option_id": options["pickup"]}, orders.SetOrderShipping)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "SHIPPING_LOCKED")
+
+	// Sellers ship, buyers confirm the delivery
+	w = callShippingEndpoint(t, buyer, "orderId", orderID, map[string]interface{}{"carrier": "DHL", "tracking_number": "DE123"}, orders.ShipOrder)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = callShippingEndpoint(t, buyer, "orderId", orderID, nil, orders.ConfirmDelivery)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = callShippingEndpoint(t, seller, "orderId", orderID, map[string]interface{}{"carrier": "DHL", "tracking_number": "DE123"}, orders.ShipOrder)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "shipped", decodeTestData(t, w)["order"].(map[string]interface{})["fulfilment_status"])
+	w = callShippingEndpoint(t, seller, "orderId", orderID, nil, orders.ConfirmDelivery)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = callShippingEndpoint(t, buyer, "orderId", orderID, nil, orders.ConfirmDelivery)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "delivered", decodeTestData(t, w)["order"].(map[string]interface{})["fulfilment_status"])
+
+	var emitted int
+	err := env.Dependencies.Conn.QueryRow(env.Context,
+		"SELECT COUNT(*) FROM outbox WHERE event_type IN ('order.shipped', 'order.delivered') AND payload->>'order_id' = $1",
+		orderID).Scan(&emitted)
+	require.NoError(t, err)
+	assert.Equal(t, 2, emitted)
+}
+
+// getTestProductData fetches a product through the handler and returns the whole data object
+func getTestProductData(t *testing.T, env *TestEnv, productID string) map[string]interface{} {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/products/%s", productID), nil)
+	req = addProductIDToContext(req, productID)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.GetProductByID(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "Product should be readable")
+	return decodeTestData(t, w)
+}
@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/itsDrac/e-auc/internal/cache"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func productEndsAt(t *testing.T, product map[string]interface{}) time.Time {
+	endsAt, err := time.Parse(time.RFC3339Nano, product["ends_at"].(string))
+	require.NoError(t, err)
+	return endsAt
+}
+
+// openTestLiveFeed subscribes anonymously to the live feed of a product and returns a function that waits for the
+// name of its next event, or returns "" once the feed times out
+func openTestLiveFeed(t *testing.T, env *TestEnv, productID string) func() string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		env.Dependencies.ProductHandler.LiveFeed(w, addProductIDToContext(r, productID))
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(env.Context, 10*time.Second)
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	eventTypes := make(chan string, 10)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if name, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+				eventTypes <- name
+			}
+		}
+		close(eventTypes)
+	}()
+
+	return func() string {
+		select {
+		case name := <-eventTypes:
+			return name
+		case <-ctx.Done():
+			return ""
+		}
+	}
+}
+
+// TestSoftCloseExtendsAuction tests that late bids extend the auction up to the configured cap
+func TestSoftCloseExtendsAuction(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(9)
+	bidderA := GetTestUser(0)
+	bidderB := GetTestUser(1)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidderA)
+	require.NotNil(t, bidderB)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":                        "Soft Close Product",
+		"min_price":                    10,
+		"current_price":                10,
+		"ends_at":                      time.Now().Add(2 * time.Minute).UTC().Format(time.RFC3339),
+		"soft_close_window_minutes":    5,
+		"soft_close_extension_minutes": 3,
+		"max_extensions":               1,
+	})
+	endsAt := productEndsAt(t, getTestProduct(t, env, productID))
+
+	// A bid inside the window pushes the end out
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderA, productID, 20).Code)
+	product := getTestProduct(t, env, productID)
+	assert.WithinDuration(t, endsAt.Add(3*time.Minute), productEndsAt(t, product), time.Second)
+	assert.EqualValues(t, 1, product["extension_count"])
+
+	// The cap stops further extensions
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidderB, productID, 30).Code)
+	product = getTestProduct(t, env, productID)
+	assert.WithinDuration(t, endsAt.Add(3*time.Minute), productEndsAt(t, product), time.Second)
+	assert.EqualValues(t, 1, product["extension_count"])
+
+	// Bids after the end are rejected
+	_, err := env.Dependencies.Conn.Exec(env.Context, "UPDATE products SET ends_at = NOW() - INTERVAL '1 second' WHERE id = $1", productID)
+	require.NoError(t, err)
+	w := placeTestBid(t, env, bidderA, productID, 40)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "AUCTION_ENDED")
+}
+
+// TestSoftCloseIgnoresEarlyBids tests that bids outside the window leave the end time alone
+func TestSoftCloseIgnoresEarlyBids(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(9)
+	bidder := GetTestUser(0)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":                        "Early Bid Product",
+		"min_price":                    10,
+		"current_price":                10,
+		"ends_at":                      time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		"soft_close_window_minutes":    5,
+		"soft_close_extension_minutes": 3,
+	})
+	endsAt := productEndsAt(t, getTestProduct(t, env, productID))
+
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, productID, 20).Code)
+	product := getTestProduct(t, env, productID)
+	assert.True(t, endsAt.Equal(productEndsAt(t, product)))
+	assert.EqualValues(t, 0, product["extension_count"])
+
+	// End times in the past are refused on creation
+	payload := map[string]interface{}{
+		"title":         "Past Product",
+		"min_price":     10,
+		"current_price": 10,
+		"ends_at":       time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+		"images":        []string{"unused.png"},
+	}
+	payloadBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req = addProductAuthContext(req, seller)
+	w := httptest.NewRecorder()
+	env.Dependencies.ProductHandler.CreateProduct(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_END_TIME")
+}
+
+// TestLiveFeedBroadcastsExtension tests that live subscribers see the new end time of an extended auction
+func TestLiveFeedBroadcastsExtension(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(9)
+	bidder := GetTestUser(1)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":                        "Live Product",
+		"min_price":                    10,
+		"current_price":                10,
+		"ends_at":                      time.Now().Add(time.Minute).UTC().Format(time.RFC3339),
+		"soft_close_window_minutes":    2,
+		"soft_close_extension_minutes": 2,
+	})
+
+	nextEvent := openTestLiveFeed(t, env, productID)
+	require.Equal(t, "snapshot", nextEvent())
+
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, productID, 20).Code)
+	_, err := env.Dependencies.OutboxRelay.RelayPending(env.Context)
+	require.NoError(t, err)
+
+	assert.Equal(t, events.AuctionExtended, nextEvent())
+	assert.Equal(t, events.BidPlaced, nextEvent())
+}
+
+// TestLiveFeedDropsPrivateEvents tests that events meant for the parties of a sale never reach anonymous live subscribers
+func TestLiveFeedDropsPrivateEvents(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(9)
+	bidder := GetTestUser(1)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Private Events Product",
+		"min_price":     10,
+		"current_price": 10,
+	})
+	nextEvent := openTestLiveFeed(t, env, productID)
+	require.Equal(t, "snapshot", nextEvent())
+
+	// Published straight on the product's channel, as if an emitter got its aggregate wrong
+	offer, err := events.New(events.OfferMade, uuid.MustParse(productID), events.OfferMadeData{
+		ProductID: uuid.MustParse(productID),
+		SellerID:  seller.UserID,
+		BuyerID:   bidder.UserID,
+		MadeBy:    "buyer",
+		Price:     5,
+	})
+	require.NoError(t, err)
+	msg, err := json.Marshal(offer)
+	require.NoError(t, err)
+	require.NoError(t, env.Dependencies.Cache.Publish(env.Context, cache.EventChannel(productID), string(msg)))
+
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, productID, 20).Code)
+	_, err = env.Dependencies.OutboxRelay.RelayPending(env.Context)
+	require.NoError(t, err)
+
+	assert.Equal(t, events.BidPlaced, nextEvent(), "The offer is dropped, the bid after it comes through")
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/itsDrac/e-auc/internal/payments"
+	"github.com/itsDrac/e-auc/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postTestWalletTransaction deposits or withdraws amount as user through handler, the key is only sent when given.
+// Deposits are charged to a payment token the fake provider accepts
+func postTestWalletTransaction(t *testing.T, user *TestUser, key string, amount int, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	payloadBytes, err := json.Marshal(map[string]interface{}{"amount": amount, "payment_token": "tok_visa"})
+	require.NoError(t, err, "Should marshal payload")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/me/wallet", bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+// getTestWallet fetches the wallet of user through the handler
+func getTestWallet(t *testing.T, env *TestEnv, user *TestUser) map[string]interface{} {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me/wallet", nil)
+	req = addProductAuthContext(req, user)
+	w := httptest.NewRecorder()
+	env.Dependencies.WalletHandler.GetWallet(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	return response["data"].(map[string]interface{})
+}
+
+// TestWalletDepositsAndWithdrawals tests idempotent deposits and withdrawals limited to available funds
+func TestWalletDepositsAndWithdrawals(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	user := GetTestUser(8)
+	require.NotNil(t, user)
+	handler := env.Dependencies.WalletHandler
+	before := getTestWallet(t, env, user)
+	available := before["available"].(float64)
+	depositKey := uuid.NewString()
+
+	w := postTestWalletTransaction(t, user, "", 1000, handler.Deposit)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "MISSING_IDEMPOTENCY_KEY")
+
+	// Nothing is deposited unless the payment method is charged
+	_, err := env.Dependencies.Services.WalletService.Deposit(env.Context, user.UserID, 1000, uuid.NewString(), payments.FakeDeclineToken)
+	assert.ErrorIs(t, err, service.ErrPaymentDeclined)
+	assert.Equal(t, available, getTestWallet(t, env, user)["available"].(float64))
+
+	w = postTestWalletTransaction(t, user, depositKey, 1000, handler.Deposit)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	first := w.Body.String()
+
+	// Retried deposits are recorded once
+	w = postTestWalletTransaction(t, user, depositKey, 1000, handler.Deposit)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.JSONEq(t, first, w.Body.String())
+	w = postTestWalletTransaction(t, user, depositKey, 2000, handler.Deposit)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "IDEMPOTENCY_KEY_REUSED")
+
+	wallet := getTestWallet(t, env, user)
+	assert.Equal(t, available+1000, wallet["available"].(float64))
+
+	w = postTestWalletTransaction(t, user, uuid.NewString(), int(available)+1001, handler.Withdraw)
+	assert.Equal(t, http.StatusPaymentRequired, w.Code)
+	assert.Contains(t, w.Body.String(), "INSUFFICIENT_FUNDS")
+
+	w = postTestWalletTransaction(t, user, uuid.NewString(), 400, handler.Withdraw)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	wallet = getTestWallet(t, env, user)
+	assert.Equal(t, available+600, wallet["available"].(float64))
+	assert.GreaterOrEqual(t, len(wallet["transactions"].([]interface{})), 2)
+}
+
+// TestWalletHoldsAndRelease tests that held funds cannot be withdrawn until they are released
+func TestWalletHoldsAndRelease(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	user := GetTestUser(8)
+	require.NotNil(t, user)
+	svc := env.Dependencies.Services.WalletService
+	ctx := env.Context
+	reference := uuid.New()
+
+	_, err := svc.Deposit(ctx, user.UserID, 500, uuid.NewString(), "tok_visa")
+	require.NoError(t, err)
+	before, err := svc.GetWallet(ctx, user.UserID)
+	require.NoError(t, err)
+
+	holdKey := uuid.NewString()
+	_, err = svc.Hold(ctx, user.UserID, before.Available+1, holdKey, &reference)
+	assert.Error(t, err, "Holds cannot exceed the available funds")
+	_, err = svc.Hold(ctx, user.UserID, before.Available, holdKey, &reference)
+	require.NoError(t, err)
+
+	wallet, err := svc.GetWallet(ctx, user.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), wallet.Available)
+	assert.Equal(t, before.Held+before.Available, wallet.Held)
+	_, err = svc.Withdraw(ctx, user.UserID, 1, uuid.NewString())
+	assert.Error(t, err, "Held funds cannot be withdrawn")
+
+	_, err = svc.Release(ctx, user.UserID, before.Available, uuid.NewString(), &reference)
+	require.NoError(t, err)
+	wallet, err = svc.GetWallet(ctx, user.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, before, wallet)
+
+	// Postings of every journal entry balance
+	var unbalanced int
+	err = env.Dependencies.Conn.QueryRow(ctx, `SELECT COUNT(*) FROM (
+		SELECT entry_id FROM postings GROUP BY entry_id
+		HAVING SUM(CASE direction WHEN 'debit' THEN amount ELSE -amount END) <> 0
+	) t`).Scan(&unbalanced)
+	require.NoError(t, err)
+	assert.Equal(t, 0, unbalanced)
+}
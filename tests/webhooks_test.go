@@ -0,0 +1,250 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/itsDrac/e-auc/internal/events"
+	"github.com/itsDrac/e-auc/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver records every request posted to it and answers with the configured status
+type webhookReceiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []receivedWebhook
+}
+
+type receivedWebhook struct {
+	Header http.Header
+	Body   []byte
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	rcv.requests = append(rcv.requests, receivedWebhook{Header: r.Header.Clone(), Body: body})
+	w.WriteHeader(rcv.status)
+}
+
+func (rcv *webhookReceiver) received() []receivedWebhook {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	return append([]receivedWebhook(nil), rcv.requests...)
+}
+
+// addWebhookParamsToContext adds webhookId and deliveryId URL params to chi context
+func addWebhookParamsToContext(req *http.Request, webhookID, deliveryID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("webhookId", webhookID)
+	if deliveryID != "" {
+		rctx.URLParams.Add("deliveryId", deliveryID)
+	}
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(ctx)
+}
+
+// registerTestWebhook registers a webhook for the seller and returns its ID and signing secret
+func registerTestWebhook(t *testing.T, env *TestEnv, seller *TestUser, url string, eventTypes ...string) (string, string) {
+	payloadBytes, err := json.Marshal(map[string]interface{}{
+		"url":         url,
+		"event_types": eventTypes,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req = addProductAuthContext(req, seller)
+	w := httptest.NewRecorder()
+	env.Dependencies.WebhookHandler.CreateWebhook(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, "Webhook registration should succeed")
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	data := response["data"].(map[string]interface{})
+	webhook := data["webhook"].(map[string]interface{})
+	assert.NotContains(t, webhook, "secret", "Secret should only be returned at the top level")
+	return webhook["id"].(string), data["secret"].(string)
+}
+
+// TestWebhookDelivery tests that a bid is delivered as a signed payload to the seller's endpoint
+func TestWebhookDelivery(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(1)
+	bidder := GetTestUser(2)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+
+	receiver := &webhookReceiver{status: http.StatusOK}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	_, secret := registerTestWebhook(t, env, seller, server.URL, events.BidPlaced)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Webhook Product",
+		"min_price":     10,
+		"current_price": 10,
+	})
+	w := placeTestBid(t, env, bidder, productID, 25)
+	require.Equal(t, http.StatusOK, w.Code, "Bid should succeed")
+
+	_, err := env.Dependencies.OutboxRelay.RelayPending(env.Context)
+	require.NoError(t, err)
+	_, err = env.Dependencies.Services.WebhookService.DispatchDue(env.Context)
+	require.NoError(t, err)
+
+	requests := receiver.received()
+	require.Len(t, requests, 1, "Receiver should get exactly one delivery")
+	delivery := requests[0]
+
+	assert.Equal(t, events.BidPlaced, delivery.Header.Get(service.WebhookEventHeader))
+	timestamp := delivery.Header.Get(service.WebhookTimestampHeader)
+	expected := "sha256=" + service.SignWebhookPayload(secret, timestamp, delivery.Body)
+	assert.Equal(t, expected, delivery.Header.Get(service.WebhookSignatureHeader), "Signature should verify with the endpoint secret")
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(delivery.Body, &payload))
+	assert.Equal(t, events.BidPlaced, payload["type"])
+	data := payload["data"].(map[string]interface{})
+	assert.Equal(t, productID, data["product_id"])
+	assert.EqualValues(t, 25, data["bid_amount"])
+}
+
+// TestWebhookInternalAddressRejected tests that endpoints inside the server's network cannot be registered
+func TestWebhookInternalAddressRejected(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(1)
+	require.NotNil(t, seller)
+
+	for _, url := range []string{
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.5/hook",
+		"https://192.168.1.10:8443/hook",
+		"http://[fd00::1]/hook",
+		"http://[::ffff:172.16.0.1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		payloadBytes, err := json.Marshal(map[string]interface{}{
+			"url":         url,
+			"event_types": []string{events.BidPlaced},
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", bytes.NewReader(payloadBytes))
+		req.Header.Set("Content-Type", "application/json")
+		req = addProductAuthContext(req, seller)
+		w := httptest.NewRecorder()
+		env.Dependencies.WebhookHandler.CreateWebhook(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+		assert.Contains(t, w.Body.String(), "WEBHOOK_URL_NOT_ALLOWED", url)
+	}
+}
+
+// TestWebhookRetryAndRedelivery tests that failed deliveries are logged and can be redelivered manually
+func TestWebhookRetryAndRedelivery(t *testing.T) {
+	env := GetTestEnv()
+	require.NotNil(t, env, "Test environment should be initialized")
+
+	seller := GetTestUser(3)
+	bidder := GetTestUser(4)
+	require.NotNil(t, seller)
+	require.NotNil(t, bidder)
+
+	receiver := &webhookReceiver{status: http.StatusInternalServerError}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	webhookID, _ := registerTestWebhook(t, env, seller, server.URL, events.BidPlaced)
+
+	productID := createTestProduct(t, env, seller, map[string]interface{}{
+		"title":         "Flaky Webhook Product",
+		"min_price":     10,
+		"current_price": 10,
+	})
+	require.Equal(t, http.StatusOK, placeTestBid(t, env, bidder, productID, 15).Code)
+
+	_, err := env.Dependencies.OutboxRelay.RelayPending(env.Context)
+	require.NoError(t, err)
+	_, err = env.Dependencies.Services.WebhookService.DispatchDue(env.Context)
+	require.NoError(t, err)
+	require.Len(t, receiver.received(), 1)
+
+	// The failed attempt shows up in the delivery log and is scheduled for a retry
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/webhooks/%s/deliveries", webhookID), nil)
+	req = addWebhookParamsToContext(req, webhookID, "")
+	req = addProductAuthContext(req, seller)
+	w := httptest.NewRecorder()
+	env.Dependencies.WebhookHandler.ListDeliveries(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var listResp map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&listResp))
+	deliveries := listResp["data"].(map[string]interface{})["deliveries"].([]interface{})
+	require.Len(t, deliveries, 1)
+	delivery := deliveries[0].(map[string]interface{})
+	assert.Equal(t, "pending", delivery["status"])
+	assert.EqualValues(t, 1, delivery["attempts"])
+	assert.EqualValues(t, http.StatusInternalServerError, delivery["last_response_code"])
+	deliveryID := delivery["id"].(string)
+
+	// Backoff keeps the retry out of the next dispatch
+	_, err = env.Dependencies.Services.WebhookService.DispatchDue(env.Context)
+	require.NoError(t, err)
+	require.Len(t, receiver.received(), 1, "Retry should wait for the backoff")
+
+	// Manual redelivery sends it again straight away
+	receiver.mu.Lock()
+	receiver.status = http.StatusNoContent
+	receiver.mu.Unlock()
+
+	req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/webhooks/%s/deliveries/%s/redeliver", webhookID, deliveryID), nil)
+	req = addWebhookParamsToContext(req, webhookID, deliveryID)
+	req = addProductAuthContext(req, seller)
+	w = httptest.NewRecorder()
+	env.Dependencies.WebhookHandler.RedeliverDelivery(w, req)
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	_, err = env.Dependencies.Services.WebhookService.DispatchDue(env.Context)
+	require.NoError(t, err)
+	require.Len(t, receiver.received(), 2)
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/webhooks/%s/deliveries/%s", webhookID, deliveryID), nil)
+	req = addWebhookParamsToContext(req, webhookID, deliveryID)
+	req = addProductAuthContext(req, seller)
+	w = httptest.NewRecorder()
+	env.Dependencies.WebhookHandler.GetDelivery(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var getResp map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&getResp))
+	delivery = getResp["data"].(map[string]interface{})["delivery"].(map[string]interface{})
+	assert.Equal(t, "succeeded", delivery["status"])
+	attemptLog := delivery["attempt_log"].([]interface{})
+	require.Len(t, attemptLog, 2)
+	assert.EqualValues(t, http.StatusInternalServerError, attemptLog[0].(map[string]interface{})["response_code"])
+	assert.EqualValues(t, http.StatusNoContent, attemptLog[1].(map[string]interface{})["response_code"])
+
+	// Other sellers cannot see the delivery
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/webhooks/%s/deliveries/%s", webhookID, deliveryID), nil)
+	req = addWebhookParamsToContext(req, webhookID, deliveryID)
+	req = addProductAuthContext(req, bidder)
+	w = httptest.NewRecorder()
+	env.Dependencies.WebhookHandler.GetDelivery(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}